@@ -0,0 +1,296 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Package audit checks the security hardening of ELF executables and shared
+// libraries, like the checksec tool, using the headers, segments, dynamic
+// section and symbols read by golf.
+package audit
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+import (
+	"eureka/golf"
+)
+
+// RelRO values denote how much of the data relocated by the dynamic loader is
+// made read-only after relocation.
+type RelRO string
+
+const (
+	// There is no PT_GNU_RELRO segment.
+	RelRONone RelRO = "none"
+
+	// The PT_GNU_RELRO segment is made read-only, but the GOT entries of
+	// functions are resolved lazily and remain writable.
+	RelROPartial RelRO = "partial"
+
+	// All symbols are bound at load time, so that the whole GOT is made
+	// read-only.
+	RelROFull RelRO = "full"
+)
+
+// PIE values denote whether the file is loaded at a random address.
+type PIE string
+
+const (
+	// The file is an executable of type ET_EXEC, loaded at a fixed address.
+	PIENone PIE = "none"
+
+	// The file is a position independent executable.
+	PIEEnabled PIE = "pie"
+
+	// The file is a shared library, which is position independent.
+	PIEDSO PIE = "dso"
+)
+
+// Report is the result of auditing an ELF file. The lists in a report are
+// empty rather than nil, so that they are encoded as empty JSON arrays.
+type Report struct {
+	FileName string `json:"file"`
+
+	RelRO RelRO `json:"relro"`
+
+	// True if the file has a PT_GNU_STACK segment which is not executable.
+	// Files without such a segment get an executable stack on most
+	// machines.
+	NXStack bool `json:"nx_stack"`
+
+	PIE PIE `json:"pie"`
+
+	// True if the file refers to the stack protector functions, like
+	// '__stack_chk_fail'.
+	StackCanary bool `json:"stack_canary"`
+
+	// The checked variants of library functions, like '__memcpy_chk', which
+	// the file refers to. They are used by files built with
+	// _FORTIFY_SOURCE.
+	Fortified     []string `json:"fortified"`
+	FortifySource bool     `json:"fortify_source"`
+
+	// The control-flow protection features marked in the GNU properties of
+	// the file: x86 indirect branch tracking and shadow stack, and AArch64
+	// branch target identification and pointer authentication.
+	IBT   bool `json:"ibt"`
+	SHSTK bool `json:"shstk"`
+	BTI   bool `json:"bti"`
+	PAC   bool `json:"pac"`
+
+	// The search paths in the DT_RPATH and DT_RUNPATH entries.
+	RPath   []string `json:"rpath"`
+	RunPath []string `json:"runpath"`
+
+	// The problems found with the search paths. See RPathIssues.
+	RPathIssues []string `json:"rpath_issues"`
+}
+
+// Returns true if the file has all the hardening features which apply to
+// files of any machine: full RELRO, a non-executable stack, position
+// independence, stack canaries, and search paths without issues.
+func (report *Report) Hardened() bool {
+	return report.RelRO == RelROFull && report.NXStack && report.PIE != PIENone &&
+		report.StackCanary && len(report.RPathIssues) == 0
+}
+
+// Names of the stack protector symbols. The guard variable is referred to
+// by static executables and on machines which do not use TLS for the guard.
+var stackChkSymbols = map[string]bool{
+	"__stack_chk_fail":       true,
+	"__stack_chk_fail_local": true,
+	"__stack_chk_guard":      true,
+}
+
+// Returns true if the symbol name is that of a checked function of
+// _FORTIFY_SOURCE, like '__memcpy_chk' or '__printf_chk'.
+func isFortified(name string) bool {
+	return strings.HasPrefix(name, "__") && strings.HasSuffix(name, "_chk") &&
+		len(name) > len("___chk") && !stackChkSymbols[name]
+}
+
+// Directories which anyone can write to. Libraries in them can be planted by
+// other users.
+var worldWritableDirs = []string{"/tmp", "/var/tmp", "/dev/shm"}
+
+// Returns the problems with the search paths of a DT_RPATH or DT_RUNPATH
+// entry named tagName. Empty and relative paths are resolved relative to the
+// current directory of the process, and world writable directories can be
+// written to by anyone. Paths relative to $ORIGIN, the directory of the file,
+// are fine.
+func RPathIssues(tagName string, paths []string) []string {
+	var issues []string
+	for _, path := range paths {
+		switch {
+		case path == "":
+			issues = append(issues, fmt.Sprintf(
+				"%s has an empty path, which is the current directory.", tagName))
+		case strings.HasPrefix(path, "$ORIGIN") || strings.HasPrefix(path, "${ORIGIN}"):
+		case !filepath.IsAbs(path):
+			issues = append(issues, fmt.Sprintf(
+				"%s has a relative path '%s'.", tagName, path))
+		default:
+			for _, dir := range worldWritableDirs {
+				clean := filepath.Clean(path)
+				if clean == dir || strings.HasPrefix(clean, dir+"/") {
+					issues = append(issues, fmt.Sprintf(
+						"%s has a world writable path '%s'.", tagName, path))
+					break
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// Splits a search path string into its paths. An empty string has no paths.
+func splitSearchPath(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	return strings.Split(s, ":")
+}
+
+// Returns the audit report of the ELF file. Only executables and shared
+// libraries can be audited.
+func Audit(elf *golf.ELF) (*Report, error) {
+	fileType := elf.Header().Type()
+	if fileType != golf.TypeExecutable && fileType != golf.TypeShared {
+		return nil, fmt.Errorf("Only executables and shared libraries can be audited.")
+	}
+
+	dyn, err := elf.Dynamic()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading the dynamic section.\n%s", err.Error())
+	}
+	if dyn == nil {
+		dyn = new(golf.Dynamic)
+	}
+
+	report := new(Report)
+
+	hasRelRO := false
+	for _, segHdr := range elf.ProgHdrTbl() {
+		switch segHdr.Type() {
+		case golf.SegTypeGnuRelRO:
+			hasRelRO = true
+		case golf.SegTypeGnuStack:
+			report.NXStack = segHdr.Flags()&golf.SegFlagsExecutable == 0
+		}
+	}
+
+	bindNow := dyn.Has(golf.DynTagBindNow) || dyn.Flags&golf.DynFlagBindNow != 0 ||
+		dyn.Flags1&golf.DynFlag1Now != 0
+	switch {
+	case !hasRelRO:
+		report.RelRO = RelRONone
+	case bindNow:
+		report.RelRO = RelROFull
+	default:
+		report.RelRO = RelROPartial
+	}
+
+	// Older linkers do not mark position independent executables with
+	// DF_1_PIE, but they have a DT_DEBUG entry for debuggers unlike shared
+	// libraries, as checksec assumes. An interpreter does not make a file an
+	// executable; libc.so.6 has one for example.
+	switch {
+	case fileType == golf.TypeExecutable:
+		report.PIE = PIENone
+	case dyn.Flags1&golf.DynFlag1PIE != 0 || dyn.Has(golf.DynTagDebug):
+		report.PIE = PIEEnabled
+	default:
+		report.PIE = PIEDSO
+	}
+
+	err = report.auditSymbols(elf)
+	if err != nil {
+		return nil, err
+	}
+
+	features, err := elf.GnuFeatures()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading GNU properties.\n%s", err.Error())
+	}
+	report.IBT, report.SHSTK = features.X86IBT, features.X86SHSTK
+	report.BTI, report.PAC = features.AArch64BTI, features.AArch64PAC
+
+	report.RPath = splitSearchPath(dyn.RPath)
+	report.RunPath = splitSearchPath(dyn.RunPath)
+	report.RPathIssues = append([]string{}, RPathIssues("DT_RPATH", report.RPath)...)
+	report.RPathIssues = append(report.RPathIssues, RPathIssues("DT_RUNPATH", report.RunPath)...)
+	if len(report.RPath) > 0 && len(report.RunPath) == 0 {
+		// DT_RPATH takes precedence over LD_LIBRARY_PATH, and applies to
+		// the dependencies of the file too.
+		report.RPathIssues = append(report.RPathIssues, "DT_RPATH is used instead of DT_RUNPATH.")
+	}
+
+	return report, nil
+}
+
+// Looks for the stack protector and _FORTIFY_SOURCE functions in the dynamic
+// symbols, and in the symbol table of files which are not stripped.
+func (report *Report) auditSymbols(elf *golf.ELF) error {
+	dynSymbols, err := elf.DynamicSymbols()
+	if err != nil {
+		return fmt.Errorf("Error reading dynamic symbols.\n%s", err.Error())
+	}
+	symbols, err := elf.Symbols()
+	if err != nil {
+		return fmt.Errorf("Error reading symbols.\n%s", err.Error())
+	}
+
+	report.Fortified = []string{}
+	fortified := make(map[string]bool)
+	for _, syms := range [][]golf.ResolvedSymbol{dynSymbols, symbols} {
+		for _, sym := range syms {
+			if stackChkSymbols[sym.Name] {
+				report.StackCanary = true
+			} else if isFortified(sym.Name) {
+				fortified[sym.Name] = true
+			}
+		}
+	}
+
+	for name := range fortified {
+		report.Fortified = append(report.Fortified, name)
+	}
+	sort.Strings(report.Fortified)
+	report.FortifySource = len(report.Fortified) > 0
+
+	return nil
+}
+
+// Returns the audit report of the ELF file whose path is fileName.
+func AuditFile(fileName string) (*Report, error) {
+	elf, err := golf.Read(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer elf.Close()
+
+	report, err := Audit(elf)
+	if err != nil {
+		return nil, fmt.Errorf("Error auditing '%s'.\n%s", fileName, err.Error())
+	}
+
+	report.FileName = fileName
+	return report, nil
+}
@@ -0,0 +1,148 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAuditHardened(t *testing.T) {
+	report, err := AuditFile("test_data/hardened_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if report.RelRO != RelROFull || !report.NXStack || report.PIE != PIEEnabled ||
+		!report.StackCanary || !report.FortifySource || !report.Hardened() {
+		t.Errorf("Wrong report of hardened executable: %v", report)
+		return
+	}
+	if !reflect.DeepEqual(report.Fortified, []string{"__strcpy_chk"}) {
+		t.Errorf("Wrong fortified functions: %v", report.Fortified)
+		return
+	}
+	if report.IBT || report.SHSTK || len(report.RPath) != 0 || len(report.RPathIssues) != 0 {
+		t.Errorf("Wrong CET features or search paths: %v", report)
+	}
+}
+
+func TestAuditInsecure(t *testing.T) {
+	report, err := AuditFile("test_data/insecure_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if report.RelRO != RelRONone || report.NXStack || report.PIE != PIENone ||
+		report.StackCanary || report.FortifySource || report.Hardened() {
+		t.Errorf("Wrong report of insecure executable: %v", report)
+		return
+	}
+
+	expectedRPath := []string{"/tmp/golf", ".", "", "$ORIGIN/lib"}
+	expectedIssues := []string{
+		"DT_RPATH has a world writable path '/tmp/golf'.",
+		"DT_RPATH has a relative path '.'.",
+		"DT_RPATH has an empty path, which is the current directory.",
+		"DT_RPATH is used instead of DT_RUNPATH.",
+	}
+	if !reflect.DeepEqual(report.RPath, expectedRPath) ||
+		!reflect.DeepEqual(report.RPathIssues, expectedIssues) {
+		t.Errorf("Wrong search paths or issues: %q %q", report.RPath, report.RPathIssues)
+	}
+}
+
+func TestAuditOthers(t *testing.T) {
+	report, err := AuditFile("../golf/test_data/linux_x86_64_cet.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if !report.IBT || !report.SHSTK || report.BTI || report.PAC {
+		t.Errorf("Wrong CET features: %v", report)
+		return
+	}
+
+	report, err = AuditFile("../golf/test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if report.RelRO != RelROPartial || !report.NXStack || report.PIE != PIENone {
+		t.Errorf("Wrong report of non-PIE executable: %v", report)
+		return
+	}
+
+	report, err = AuditFile("../golf/test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if report.RelRO != RelROFull || report.PIE != PIEDSO ||
+		!reflect.DeepEqual(report.RunPath, []string{"$ORIGIN/../lib", "/opt/golf/lib"}) ||
+		len(report.RPathIssues) != 0 {
+		t.Errorf("Wrong report of shared library: %v", report)
+		return
+	}
+
+	// A shared library with an interpreter, like libc.so.6.
+	report, err = AuditFile("test_data/interp_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if report.PIE != PIEDSO {
+		t.Errorf("Wrong PIE of shared library with an interpreter: %s", report.PIE)
+		return
+	}
+
+	_, err = AuditFile("../golf/test_data/linux_x86.o")
+	if err == nil {
+		t.Errorf("Expected an error auditing a relocatable file.")
+	}
+}
+
+func TestAuditJSON(t *testing.T) {
+	report, err := AuditFile("test_data/hardened_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if fields["file"] != "test_data/hardened_x86_64.exe" || fields["relro"] != "full" ||
+		fields["pie"] != "pie" || fields["nx_stack"] != true {
+		t.Errorf("Wrong JSON report: %s", data)
+		return
+	}
+	if rpath, ok := fields["rpath"].([]interface{}); !ok || len(rpath) != 0 {
+		t.Errorf("Wrong JSON report: %s", data)
+	}
+}
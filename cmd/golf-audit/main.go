@@ -0,0 +1,117 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Command golf-audit reports the security hardening of ELF executables and
+// shared libraries.
+//
+// Usage:
+//
+//	golf-audit [-json] [-strict] FILE...
+//
+// With -json, the reports are printed as a JSON array. The exit status is 1 if
+// any of the files could not be audited, and 2 if -strict is given and any of
+// the files is not fully hardened.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+import (
+	"eureka/audit"
+)
+
+var (
+	jsonOutput = flag.Bool("json", false, "Print the reports as a JSON array.")
+	strict     = flag.Bool("strict", false, "Exit with status 2 if any file is not fully hardened.")
+)
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func printReport(report *audit.Report) {
+	fmt.Printf("%s:\n", report.FileName)
+	fmt.Printf("  RELRO:          %s\n", report.RelRO)
+	fmt.Printf("  NX stack:       %s\n", yesNo(report.NXStack))
+	fmt.Printf("  PIE:            %s\n", report.PIE)
+	fmt.Printf("  Stack canary:   %s\n", yesNo(report.StackCanary))
+	fmt.Printf("  FORTIFY_SOURCE: %s", yesNo(report.FortifySource))
+	if report.FortifySource {
+		fmt.Printf(" (%s)", strings.Join(report.Fortified, ", "))
+	}
+	fmt.Printf("\n")
+	fmt.Printf("  IBT/SHSTK:      %s/%s\n", yesNo(report.IBT), yesNo(report.SHSTK))
+	fmt.Printf("  BTI/PAC:        %s/%s\n", yesNo(report.BTI), yesNo(report.PAC))
+	if len(report.RPath) > 0 {
+		fmt.Printf("  RPATH:          %s\n", strings.Join(report.RPath, ":"))
+	}
+	if len(report.RunPath) > 0 {
+		fmt.Printf("  RUNPATH:        %s\n", strings.Join(report.RunPath, ":"))
+	}
+	for _, issue := range report.RPathIssues {
+		fmt.Printf("  Warning: %s\n", issue)
+	}
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-json] [-strict] FILE...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	status := 0
+	reports := make([]*audit.Report, 0, flag.NArg())
+	for _, fileName := range flag.Args() {
+		report, err := audit.AuditFile(fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			status = 1
+			continue
+		}
+		reports = append(reports, report)
+		if *strict && !report.Hardened() && status == 0 {
+			status = 2
+		}
+	}
+
+	if *jsonOutput {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", data)
+	} else {
+		for _, report := range reports {
+			printReport(report)
+		}
+	}
+
+	os.Exit(status)
+}
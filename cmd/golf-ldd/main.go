@@ -0,0 +1,164 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Command golf-ldd prints the shared library dependencies of ELF executables
+// and shared libraries like ldd, without running them.
+//
+// Usage:
+//
+//	golf-ldd [-sysroot DIR] [-library-path PATHS] [-conf FILE] [-tree] [-v] FILE...
+//
+// The library path is a list of directories separated by ':', searched like
+// those of LD_LIBRARY_PATH. The paths of the library path and the ld.so.conf
+// file are paths under the sysroot, and /etc/ld.so.conf is read if -conf is
+// not given and the sysroot has it. Symbolic links in the sysroot are resolved
+// within the sysroot, even if their targets are absolute. With -tree, the
+// dependencies of each object are printed below it, with how they were found.
+// With -v, the searched directories and the skipped files of the dependencies
+// which are not found are printed too. The exit status is 1 if any of the files could not be read,
+// and 2 if any dependency is not found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+import (
+	"eureka/deps"
+)
+
+var (
+	sysroot     = flag.String("sysroot", "", "The directory under which the libraries are looked up.")
+	libraryPath = flag.String("library-path", "", "Directories searched like those of LD_LIBRARY_PATH.")
+	confFile    = flag.String("conf", "", "The ld.so.conf file listing library directories.")
+	tree        = flag.Bool("tree", false, "Print the dependencies as a tree.")
+	verbose     = flag.Bool("v", false, "Print why dependencies are not found.")
+)
+
+// Prints the searched directories and the skipped files of an edge which is
+// not resolved, indented by indent.
+func printMissing(edge *deps.Edge, indent string) {
+	if !*verbose {
+		return
+	}
+
+	fmt.Printf("%s%s\n", indent, edge.Missing)
+	for _, dir := range edge.Searched {
+		fmt.Printf("%s  searched %s\n", indent, dir)
+	}
+	for _, skipped := range edge.Skipped {
+		fmt.Printf("%s  skipped %s\n", indent, skipped)
+	}
+}
+
+// Prints each loaded object and each dependency not found once, in the order
+// in which they are loaded, like ldd.
+func printList(graph *deps.Graph) {
+	printed := make(map[string]bool)
+	for _, obj := range graph.Objects {
+		for _, edge := range obj.Needed {
+			if edge.To == nil && !printed[edge.Name] {
+				fmt.Printf("\t%s => not found\n", edge.Name)
+				printMissing(edge, "\t\t")
+			} else if edge.To != nil && edge.To.Loader == obj && edge.To.Name == edge.Name {
+				fmt.Printf("\t%s => %s\n", edge.Name, edge.To.Path)
+			}
+			printed[edge.Name] = true
+		}
+	}
+
+	if interp := graph.Root.Interp; interp != "" {
+		fmt.Printf("\t%s\n", interp)
+	}
+}
+
+// Prints the dependencies of obj, and recursively theirs, indented by depth.
+// The dependencies of objects already printed are not printed again.
+func printTree(obj *deps.Object, depth int, printed map[*deps.Object]bool) {
+	printed[obj] = true
+	indent := strings.Repeat("  ", depth)
+	for _, edge := range obj.Needed {
+		if edge.To == nil {
+			fmt.Printf("%s%s => not found\n", indent, edge.Name)
+			printMissing(edge, indent+"  ")
+			continue
+		}
+
+		fmt.Printf("%s%s => %s [%s]\n", indent, edge.Name, edge.To.Path, edge.Source)
+		if !printed[edge.To] {
+			printTree(edge.To, depth+1, printed)
+		}
+	}
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr,
+			"Usage: %s [-sysroot DIR] [-library-path PATHS] [-conf FILE] [-tree] [-v] FILE...\n",
+			os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	resolver := &deps.Resolver{
+		Sysroot:     *sysroot,
+		LibraryPath: deps.SplitSearchPath(*libraryPath),
+	}
+	var err error
+	if *confFile != "" {
+		err = resolver.LoadConf(*confFile)
+	} else {
+		err = resolver.LoadDefaultConf()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(1)
+	}
+
+	status := 0
+	for _, fileName := range flag.Args() {
+		graph, err := resolver.Resolve(fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			status = 1
+			continue
+		}
+
+		switch {
+		case *tree:
+			fmt.Printf("%s\n", fileName)
+			printTree(graph.Root, 1, make(map[*deps.Object]bool))
+		case flag.NArg() > 1:
+			fmt.Printf("%s:\n", fileName)
+			fallthrough
+		default:
+			printList(graph)
+		}
+
+		if len(graph.Missing()) > 0 && status == 0 {
+			status = 2
+		}
+	}
+
+	os.Exit(status)
+}
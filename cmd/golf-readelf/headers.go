@@ -0,0 +1,472 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+import (
+	"eureka/golf"
+)
+
+// Prints a field of the file header with its label padded like readelf does.
+func printField(label string, format string, args ...interface{}) {
+	fmt.Printf("  %-34s %s\n", label+":", fmt.Sprintf(format, args...))
+}
+
+func classStr(class golf.ELFClass) string {
+	switch class {
+	case golf.Class32:
+		return "ELF32"
+	case golf.Class64:
+		return "ELF64"
+	case golf.ClassNone:
+		return "none"
+	}
+	return fmt.Sprintf("<unknown: %x>", uint8(class))
+}
+
+func endianessStr(endianess golf.ELFEndianess) string {
+	switch endianess {
+	case golf.LittleEndian:
+		return "2's complement, little endian"
+	case golf.BigEndian:
+		return "2's complement, big endian"
+	case 0:
+		return "none"
+	}
+	return fmt.Sprintf("<unknown: %x>", uint8(endianess))
+}
+
+// Returns the type of the file. Like readelf, shared objects marked with
+// DF_1_PIE are reported as position independent executables.
+func fileTypeStr(elf *golf.ELF) string {
+	fileType := elf.Header().Type()
+	if fileType == golf.TypeShared {
+		dyn, err := elf.Dynamic()
+		if err == nil && dyn != nil && dyn.Flags1&golf.DynFlag1PIE != 0 {
+			return "DYN (Position-Independent Executable file)"
+		}
+	}
+	return fileType.String()
+}
+
+var mipsArchStr = map[golf.MIPSArch]string{
+	golf.MIPSArch1:    "mips1",
+	golf.MIPSArch2:    "mips2",
+	golf.MIPSArch3:    "mips3",
+	golf.MIPSArch4:    "mips4",
+	golf.MIPSArch5:    "mips5",
+	golf.MIPSArch32:   "mips32",
+	golf.MIPSArch64:   "mips64",
+	golf.MIPSArch32R2: "mips32r2",
+	golf.MIPSArch64R2: "mips64r2",
+	golf.MIPSArch32R6: "mips32r6",
+	golf.MIPSArch64R6: "mips64r6",
+}
+
+// Returns the decoded machine specific flags of the file header, each
+// preceded by ', '. Only the flags of the more common machines are decoded.
+func headerFlagsStr(header golf.ELFHeader) string {
+	var names []string
+	flags := header.Flags()
+	switch header.Machine() {
+	case golf.MachineARM:
+		arm := golf.ARMFlags(flags)
+		if v := arm.EABIVersion(); v != 0 {
+			names = append(names, fmt.Sprintf("Version%d EABI", v))
+		}
+		if arm&golf.ARMFlagBE8 != 0 {
+			names = append(names, "BE8")
+		}
+		if arm&golf.ARMFlagFloatSoft != 0 {
+			names = append(names, "soft-float ABI")
+		}
+		if arm&golf.ARMFlagFloatHard != 0 {
+			names = append(names, "hard-float ABI")
+		}
+	case golf.MachineMIPS:
+		mips := golf.MIPSFlags(flags)
+		for _, f := range []struct {
+			flag golf.MIPSFlags
+			name string
+		}{
+			{golf.MIPSFlagNoReorder, "noreorder"},
+			{golf.MIPSFlagPIC, "pic"},
+			{golf.MIPSFlagCPIC, "cpic"},
+			{golf.MIPSFlagXGOT, "xgot"},
+		} {
+			if mips&f.flag != 0 {
+				names = append(names, f.name)
+			}
+		}
+		switch mips & golf.MIPSFlagABIMask {
+		case golf.MIPSFlagABIO32:
+			names = append(names, "o32")
+		case golf.MIPSFlagABIO64:
+			names = append(names, "o64")
+		case golf.MIPSFlagABIEABI32:
+			names = append(names, "eabi32")
+		case golf.MIPSFlagABIEABI64:
+			names = append(names, "eabi64")
+		}
+		if arch, exists := mipsArchStr[mips.Arch()]; exists {
+			names = append(names, arch)
+		}
+		if mips&golf.MIPSFlagNaN2008 != 0 {
+			names = append(names, "nan2008")
+		}
+		if mips&golf.MIPSFlagFP64 != 0 {
+			names = append(names, "fp64")
+		}
+		if mips&golf.MIPSFlag32BitMode != 0 {
+			names = append(names, "32bitmode")
+		}
+	case golf.MachinePowerPC64:
+		if v := golf.PPC64Flags(flags).ABIVersion(); v != 0 {
+			names = append(names, fmt.Sprintf("abiv%d", v))
+		}
+	case golf.MachineRISCV:
+		riscv := golf.RISCVFlags(flags)
+		if riscv&golf.RISCVFlagRVC != 0 {
+			names = append(names, "RVC")
+		}
+		switch riscv.FloatABI() {
+		case golf.RISCVFloatABISoft:
+			names = append(names, "soft-float ABI")
+		case golf.RISCVFloatABISingle:
+			names = append(names, "single-float ABI")
+		case golf.RISCVFloatABIDouble:
+			names = append(names, "double-float ABI")
+		case golf.RISCVFloatABIQuad:
+			names = append(names, "quad-float ABI")
+		}
+		if riscv&golf.RISCVFlagRVE != 0 {
+			names = append(names, "RVE")
+		}
+		if riscv&golf.RISCVFlagTSO != 0 {
+			names = append(names, "TSO")
+		}
+	}
+
+	if len(names) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(names, ", ")
+}
+
+func printFileHeader(elf *golf.ELF) error {
+	header := elf.Header()
+	ident := header.ELFIdent()
+
+	fmt.Printf("ELF Header:\n")
+	fmt.Printf("  Magic:   ")
+	magic := []byte{ident.MagicNumber[0], ident.MagicNumber[1], ident.MagicNumber[2],
+		ident.MagicNumber[3], byte(ident.Class), byte(ident.Endianess), ident.ELFVersion,
+		byte(ident.ABI), ident.ABIVersion}
+	magic = append(magic, ident.Padding[:]...)
+	for _, b := range magic {
+		fmt.Printf("%02x ", b)
+	}
+	fmt.Printf("\n")
+
+	printField("Class", "%s", classStr(ident.Class))
+	printField("Data", "%s", endianessStr(ident.Endianess))
+	if ident.ELFVersion == 1 {
+		printField("Version", "%d (current)", ident.ELFVersion)
+	} else {
+		printField("Version", "%d <unknown>", ident.ELFVersion)
+	}
+	printField("OS/ABI", "%s", ident.ABI)
+	printField("ABI Version", "%d", ident.ABIVersion)
+	printField("Type", "%s", fileTypeStr(elf))
+	printField("Machine", "%s", header.Machine())
+	printField("Version", "0x%x", header.Version())
+	printField("Entry point address", "0x%x", header.EntryPoint())
+	printField("Start of program headers", "%d (bytes into file)", header.ProgHdrTblOffset())
+	printField("Start of section headers", "%d (bytes into file)", header.SectHdrTblOffset())
+	printField("Flags", "0x%x%s", header.Flags(), headerFlagsStr(header))
+	printField("Size of this header", "%d (bytes)", header.HeaderSize())
+	printField("Size of program headers", "%d (bytes)", header.ProgHdrTblEntrySize())
+	printField("Number of program headers", "%d", len(elf.ProgHdrTbl()))
+	printField("Size of section headers", "%d (bytes)", header.SectHdrTblEntrySize())
+	if header.SectHdrCount() == 0 && len(elf.SectHdrTbl()) > 0 {
+		printField("Number of section headers", "0 (%d)", len(elf.SectHdrTbl()))
+	} else {
+		printField("Number of section headers", "%d", header.SectHdrCount())
+	}
+	if header.StrTblIndex() == golf.SectIndexSectNameTblExt {
+		printField("Section header string table index",
+			"%d (%d)", header.StrTblIndex(), elf.SectNameTblIndex())
+	} else {
+		printField("Section header string table index", "%d", header.StrTblIndex())
+	}
+
+	return nil
+}
+
+// Returns true if the OS specific section flags of the GNU ABI apply to the
+// file.
+func gnuSectFlags(elf *golf.ELF) bool {
+	abi := elf.Header().ELFIdent().ABI
+	return abi == golf.ABISystemV || abi == golf.ABIGnu || abi == golf.ABIFreeBSD
+}
+
+const (
+	sectFlagGnuRetain   = uint64(0x00200000)
+	sectFlagGnuMBind    = uint64(0x01000000)
+	sectFlagX86_64Large = uint64(0x10000000)
+	sectFlagARMPureCode = uint64(0x20000000)
+	sectFlagExclude     = uint64(0x80000000)
+)
+
+var sectFlagChars = map[uint64]byte{
+	golf.SectFlagWrite:           'W',
+	golf.SectFlagAlloc:           'A',
+	golf.SectFlagExecInstr:       'X',
+	golf.SectFlagMerge:           'M',
+	golf.SectFlagStrings:         'S',
+	golf.SectFlagInfoLink:        'I',
+	golf.SectFlagLinkOrder:       'L',
+	golf.SectFlagOSNonConforming: 'O',
+	golf.SectFlagGroup:           'G',
+	golf.SectFlagTLS:             'T',
+	golf.SectFlagCompressed:      'C',
+	sectFlagExclude:              'E',
+}
+
+// Returns the section flags as the letters in the key printed by
+// printSectFlagsKey, ordered by the bits of the flags.
+func sectFlagsStr(elf *golf.ELF, flags uint64) string {
+	var s []byte
+	machine := elf.Header().Machine()
+	for flags != 0 {
+		flag := flags & -flags
+		flags &^= flag
+
+		if c, exists := sectFlagChars[flag]; exists {
+			s = append(s, c)
+			continue
+		}
+		switch {
+		case flag == sectFlagGnuMBind && gnuSectFlags(elf):
+			s = append(s, 'D')
+		case flag == sectFlagGnuRetain && gnuSectFlags(elf):
+			s = append(s, 'R')
+		case flag&golf.SectFlagMaskOS != 0:
+			s = append(s, 'o')
+		case flag == sectFlagX86_64Large && machine == golf.MachineX86_64:
+			s = append(s, 'l')
+		case flag == sectFlagARMPureCode && machine == golf.MachineARM:
+			s = append(s, 'y')
+		case flag&golf.SectFlagMaskProc != 0:
+			s = append(s, 'p')
+		default:
+			s = append(s, 'x')
+		}
+	}
+
+	return string(s)
+}
+
+func printSectFlagsKey(elf *golf.ELF) {
+	fmt.Printf("Key to Flags:\n")
+	fmt.Printf("  W (write), A (alloc), X (execute), M (merge), S (strings), I (info),\n")
+	fmt.Printf("  L (link order), O (extra OS processing required), G (group), T (TLS),\n")
+	fmt.Printf("  C (compressed), x (unknown), o (OS specific), E (exclude),\n")
+	fmt.Printf("  ")
+	abi := elf.Header().ELFIdent().ABI
+	if abi == golf.ABIGnu || abi == golf.ABIFreeBSD {
+		fmt.Printf("R (retain), ")
+	}
+	switch elf.Header().Machine() {
+	case golf.MachineX86_64:
+		fmt.Printf("D (mbind), l (large), p (processor specific)\n")
+	case golf.MachineARM:
+		fmt.Printf("D (mbind), y (purecode), p (processor specific)\n")
+	default:
+		fmt.Printf("D (mbind), p (processor specific)\n")
+	}
+}
+
+func printSectionHeaders(elf *golf.ELF) error {
+	sections := elf.Sections()
+	header := elf.Header()
+	if len(sections) == 0 {
+		fmt.Printf("\nThere are no sections in this file.\n")
+		return nil
+	}
+
+	if !fileHeader {
+		fmt.Printf("There are %d section headers, starting at offset 0x%x:\n",
+			len(sections), header.SectHdrTblOffset())
+	}
+	fmt.Printf("\n%s:\n", plural(len(sections), "Section Header", "Section Headers"))
+
+	machine := header.Machine()
+	if is32(elf) {
+		fmt.Printf("  [Nr] Name              Type            Addr     Off    Size   ES Flg Lk Inf Al\n")
+	} else {
+		fmt.Printf("  [Nr] Name              Type             Address           Offset\n")
+		fmt.Printf("       Size              EntSize          Flags  Link  Info  Align\n")
+	}
+	for i, section := range sections {
+		hdr := section.SectHdr()
+		name := truncate(section.Name(), 17)
+		typeName := golf.SectTypeStr(machine, hdr.Type())
+		flags := sectFlagsStr(elf, hdr.Flags())
+		if is32(elf) {
+			fmt.Printf("  [%2d] %-17.17s %-15.15s %08x %06x %06x %02x %3s %2d %3d %2d\n",
+				i, name, typeName, hdr.Address(), hdr.Offset(), hdr.Size(), hdr.EntrySize(),
+				flags, hdr.Link(), hdr.Info(), hdr.Alignment())
+		} else {
+			fmt.Printf("  [%2d] %-17.17s %-16.16s %016x  %08x\n",
+				i, name, typeName, hdr.Address(), hdr.Offset())
+			fmt.Printf("       %016x  %016x %3s      %2d   %3d     %d\n",
+				hdr.Size(), hdr.EntrySize(), flags, hdr.Link(), hdr.Info(), hdr.Alignment())
+		}
+	}
+
+	printSectFlagsKey(elf)
+	return nil
+}
+
+// Returns true if the section is in the segment as per the strict rules which
+// readelf uses to map sections to segments.
+func sectionInSegment(sect golf.SectHdr, seg golf.SegHdr) bool {
+	segType := seg.Type()
+	isTLS := sect.Flags()&golf.SectFlagTLS != 0
+	isAlloc := sect.Flags()&golf.SectFlagAlloc != 0
+	isNoBits := sect.Type() == golf.SectTypeNoBits
+
+	// The .tbss section occupies no space in segments other than PT_TLS.
+	if isTLS && isNoBits && segType != golf.SegTypeTLS {
+		return false
+	}
+	size := sect.Size()
+
+	if isTLS {
+		if segType != golf.SegTypeTLS && segType != golf.SegTypeGnuRelRO &&
+			segType != golf.SegTypeLoad {
+			return false
+		}
+	} else if segType == golf.SegTypeTLS || segType == golf.SegTypeProgHdr {
+		return false
+	}
+
+	if !isAlloc {
+		switch segType {
+		case golf.SegTypeLoad, golf.SegTypeDynamic, golf.SegTypeGnuEHFrame,
+			golf.SegTypeGnuStack, golf.SegTypeGnuRelRO:
+			return false
+		}
+	}
+
+	if !isNoBits {
+		if sect.Offset() < seg.Offset() || sect.Offset()-seg.Offset() > seg.FileSize()-1 ||
+			sect.Offset()-seg.Offset()+size > seg.FileSize() {
+			return false
+		}
+	}
+	if isAlloc {
+		if sect.Address() < seg.VirtualAddress() ||
+			sect.Address()-seg.VirtualAddress() > seg.MemSize()-1 ||
+			sect.Address()-seg.VirtualAddress()+size > seg.MemSize() {
+			return false
+		}
+	}
+
+	// Empty sections at the start or end of dynamic and note segments are not
+	// in them.
+	if (segType == golf.SegTypeDynamic || segType == golf.SegTypeNote) &&
+		sect.Size() == 0 && seg.MemSize() != 0 {
+		inFile := isNoBits ||
+			(sect.Offset() > seg.Offset() && sect.Offset()-seg.Offset() < seg.FileSize())
+		inMemory := !isAlloc || (sect.Address() > seg.VirtualAddress() &&
+			sect.Address()-seg.VirtualAddress() < seg.MemSize())
+		return inFile && inMemory
+	}
+
+	return true
+}
+
+func printProgramHeaders(elf *golf.ELF) error {
+	header := elf.Header()
+	progHdrTbl := elf.ProgHdrTbl()
+	if len(progHdrTbl) == 0 {
+		fmt.Printf("\nThere are no program headers in this file.\n")
+		return nil
+	}
+
+	if !fileHeader {
+		fmt.Printf("\nElf file type is %s\n", fileTypeStr(elf))
+		fmt.Printf("Entry point 0x%x\n", header.EntryPoint())
+		fmt.Printf("There are %d program headers, starting at offset %d\n",
+			len(progHdrTbl), header.ProgHdrTblOffset())
+	}
+	fmt.Printf("\n%s:\n", plural(len(progHdrTbl), "Program Header", "Program Headers"))
+
+	interp, err := elf.Interpreter()
+	if err != nil {
+		return err
+	}
+
+	machine := header.Machine()
+	if is32(elf) {
+		fmt.Printf("  Type           Offset   VirtAddr   PhysAddr   FileSiz MemSiz  Flg Align\n")
+	} else {
+		fmt.Printf("  Type           Offset             VirtAddr           PhysAddr\n")
+		fmt.Printf("                 FileSiz            MemSiz              Flags  Align\n")
+	}
+	for _, seg := range progHdrTbl {
+		typeName := golf.SegTypeStr(machine, golf.SegType(seg.Type()))
+		flags := golf.SegFlags(seg.Flags())
+		if is32(elf) {
+			fmt.Printf("  %-14s 0x%06x 0x%08x 0x%08x 0x%05x 0x%05x %s 0x%x\n",
+				typeName, seg.Offset(), seg.VirtualAddress(), seg.PhysicalAddress(),
+				seg.FileSize(), seg.MemSize(), flags, seg.Alignment())
+		} else {
+			fmt.Printf("  %-14s 0x%016x 0x%016x 0x%016x\n",
+				typeName, seg.Offset(), seg.VirtualAddress(), seg.PhysicalAddress())
+			fmt.Printf("                 0x%016x 0x%016x  %s    0x%x\n",
+				seg.FileSize(), seg.MemSize(), flags, seg.Alignment())
+		}
+		if seg.Type() == golf.SegTypeInterp && interp != "" {
+			fmt.Printf("      [Requesting program interpreter: %s]\n", interp)
+		}
+	}
+
+	sections := elf.Sections()
+	if len(sections) == 0 {
+		return nil
+	}
+	fmt.Printf("\n Section to Segment mapping:\n")
+	fmt.Printf("  Segment Sections...\n")
+	for i, seg := range progHdrTbl {
+		fmt.Printf("   %02d     ", i)
+		for _, section := range sections[1:] {
+			if sectionInSegment(section.SectHdr(), seg) {
+				fmt.Printf("%s ", section.Name())
+			}
+		}
+		fmt.Printf("\n")
+	}
+
+	return nil
+}
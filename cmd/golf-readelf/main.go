@@ -0,0 +1,243 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Command golf-readelf displays information about ELF files in the format of
+// the readelf tool of GNU Binutils.
+//
+// Usage:
+//
+//	golf-readelf [-h] [-S] [-l] [-s] [--dyn-syms] [-d] [-n] [-r]
+//	    [-x SECTION]... FILE...
+//
+// Short options can be combined as in 'golf-readelf -hSl FILE', and the long
+// options of readelf, like --file-header, are accepted as well. Sections to
+// dump with -x are named or numbered. The output follows the default, not the
+// wide, format of readelf. The exit status is 1 if any of the files could not
+// be read.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+import (
+	"eureka/golf"
+)
+
+// sectionList is the value of the repeatable -x flag.
+type sectionList []string
+
+func (l *sectionList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *sectionList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+var (
+	fileHeader     bool
+	sectionHeaders bool
+	programHeaders bool
+	symbols        bool
+	dynSyms        bool
+	dynamic        bool
+	notes          bool
+	relocs         bool
+	hexDumps       sectionList
+)
+
+func init() {
+	flag.BoolVar(&fileHeader, "h", false, "Display the ELF file header.")
+	flag.BoolVar(&fileHeader, "file-header", false, "Same as -h.")
+	flag.BoolVar(&sectionHeaders, "S", false, "Display the section headers.")
+	flag.BoolVar(&sectionHeaders, "section-headers", false, "Same as -S.")
+	flag.BoolVar(&sectionHeaders, "sections", false, "Same as -S.")
+	flag.BoolVar(&programHeaders, "l", false, "Display the program headers.")
+	flag.BoolVar(&programHeaders, "program-headers", false, "Same as -l.")
+	flag.BoolVar(&programHeaders, "segments", false, "Same as -l.")
+	flag.BoolVar(&symbols, "s", false, "Display the symbol tables.")
+	flag.BoolVar(&symbols, "symbols", false, "Same as -s.")
+	flag.BoolVar(&symbols, "syms", false, "Same as -s.")
+	flag.BoolVar(&dynSyms, "dyn-syms", false, "Display the dynamic symbol table.")
+	flag.BoolVar(&dynamic, "d", false, "Display the dynamic section.")
+	flag.BoolVar(&dynamic, "dynamic", false, "Same as -d.")
+	flag.BoolVar(&notes, "n", false, "Display the notes.")
+	flag.BoolVar(&notes, "notes", false, "Same as -n.")
+	flag.BoolVar(&relocs, "r", false, "Display the relocations.")
+	flag.BoolVar(&relocs, "relocs", false, "Same as -r.")
+	flag.Var(&hexDumps, "x", "Dump the contents of a section, by name or number, as bytes.")
+	flag.Var(&hexDumps, "hex-dump", "Same as -x.")
+}
+
+// Returns true if the flag, without its leading dashes, takes a value.
+func takesValue(name string) bool {
+	return name == "x" || name == "hex-dump"
+}
+
+// Returns the arguments with combined short options, like '-hSl' or
+// '-x.text', split into separate flags which the flag package can parse. As
+// with readelf, options can follow the file names, which are moved after all
+// the options.
+func expandArgs(args []string) []string {
+	var expanded, files []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			files = append(files, args[i+1:]...)
+			return append(expanded, append([]string{"--"}, files...)...)
+		case len(arg) < 2 || arg[0] != '-':
+			files = append(files, arg)
+		case strings.Contains(arg, "=") || flag.Lookup(strings.TrimLeft(arg, "-")) != nil:
+			expanded = append(expanded, arg)
+			if takesValue(strings.TrimLeft(arg, "-")) && !strings.Contains(arg, "=") &&
+				i+1 < len(args) {
+				i++
+				expanded = append(expanded, args[i])
+			}
+		case arg[1] == '-':
+			expanded = append(expanded, arg)
+		default:
+			for j := 1; j < len(arg); j++ {
+				expanded = append(expanded, "-"+arg[j:j+1])
+				if !takesValue(arg[j : j+1]) {
+					continue
+				}
+				if j+1 < len(arg) {
+					expanded = append(expanded, arg[j+1:])
+				} else if i+1 < len(args) {
+					i++
+					expanded = append(expanded, args[i])
+				}
+				break
+			}
+		}
+	}
+
+	if len(files) > 0 {
+		expanded = append(expanded, "--")
+	}
+	return append(expanded, files...)
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr,
+			"Usage: %s [-h] [-S] [-l] [-s] [--dyn-syms] [-d] [-n] [-r] [-x SECTION]... FILE...\n",
+			os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.CommandLine.Parse(expandArgs(os.Args[1:]))
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if !fileHeader && !sectionHeaders && !programHeaders && !symbols && !dynSyms &&
+		!dynamic && !notes && !relocs && len(hexDumps) == 0 {
+		fmt.Fprintf(os.Stderr, "Nothing to display.\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, fileName := range flag.Args() {
+		if flag.NArg() > 1 {
+			fmt.Printf("\nFile: %s\n", fileName)
+		}
+
+		err := display(fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: Error: %s\n", os.Args[0], err.Error())
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Displays the selected information about the ELF file, in the order in which
+// readelf displays it.
+func display(fileName string) error {
+	elf, err := golf.Read(fileName)
+	if err != nil {
+		return fmt.Errorf("Unable to read '%s'.\n%s", fileName, err.Error())
+	}
+	defer elf.Close()
+
+	steps := []struct {
+		enabled bool
+		print   func(elf *golf.ELF) error
+	}{
+		{fileHeader, printFileHeader},
+		{sectionHeaders, printSectionHeaders},
+		{programHeaders, printProgramHeaders},
+		{dynamic, printDynamic},
+		{relocs, printRelocs},
+		{symbols || dynSyms, printSymbols},
+		{len(hexDumps) > 0, printHexDumps},
+		{notes, printNotes},
+	}
+	for _, step := range steps {
+		if !step.enabled {
+			continue
+		}
+		err = step.print(elf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Prints a warning to stderr.
+func warn(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s: Warning: %s\n", os.Args[0], fmt.Sprintf(format, args...))
+}
+
+// Returns the singular or plural form of a noun, like readelf does for the
+// number of entries in tables.
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// Returns true if the ELF file is a 32-bit file.
+func is32(elf *golf.ELF) bool {
+	return elf.Header().ELFIdent().Class == golf.Class32
+}
+
+// Returns the name of the section for display, in which names longer than
+// width are truncated and marked with '[...]'.
+func truncate(name string, width int) string {
+	if len(name) <= width {
+		return name
+	}
+	keep := width - 5
+	if keep < 0 {
+		keep = 0
+	}
+	return name[:keep] + "[...]"
+}
@@ -0,0 +1,101 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The golden files in 'test_data' are the output of readelf of GNU Binutils
+// 2.40 with the same arguments. The '*_relocs.o' files have a relocation of
+// each type from 0 up for their machines, except 'mips64_relocs.o', whose
+// relocations combine up to three types.
+var goldenTests = []struct {
+	args   []string
+	golden string
+}{
+	{[]string{"-hSldrs", "../../golf/test_data/linux_x86_64.so"}, "linux_x86_64.so.golden"},
+	{[]string{"-Srs", "../../golf/test_data/linux_x86.o"}, "linux_x86.o.golden"},
+	{
+		[]string{"--dyn-syms", "-r", "../../deps/test_data/sysroot/usr/bin/symapp"},
+		"symapp.golden",
+	},
+	{[]string{"-r", "../../golf/test_data/relr_x86_64.so"}, "relr_x86_64.so.golden"},
+	{[]string{"-r", "test_data/riscv64_relocs.o"}, "riscv64_relocs.o.golden"},
+	{[]string{"-r", "test_data/ppc64_relocs.o"}, "ppc64_relocs.o.golden"},
+	{[]string{"-r", "test_data/mips_relocs.o"}, "mips_relocs.o.golden"},
+	{[]string{"-r", "test_data/s390x_relocs.o"}, "s390x_relocs.o.golden"},
+	{[]string{"-r", "test_data/mips64_relocs.o"}, "mips64_relocs.o.golden"},
+	{
+		[]string{"-hSldrs", "--dyn-syms", "../../audit/test_data/hardened_x86_64.exe"},
+		"hardened_x86_64.exe.golden",
+	},
+}
+
+// Returns what golf-readelf prints to stdout for the arguments, which should
+// name a single file.
+func run(args []string) (string, error) {
+	fileHeader, sectionHeaders, programHeaders = false, false, false
+	symbols, dynSyms, dynamic, notes, relocs = false, false, false, false, false
+	hexDumps = nil
+	err := flag.CommandLine.Parse(expandArgs(args))
+	if err != nil {
+		return "", err
+	}
+
+	out, err := ioutil.TempFile("", "golf-readelf")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(out.Name())
+
+	stdout := os.Stdout
+	os.Stdout = out
+	err = display(flag.Arg(0))
+	os.Stdout = stdout
+	out.Close()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := ioutil.ReadFile(out.Name())
+	return string(data), err
+}
+
+func TestGolden(t *testing.T) {
+	for _, test := range goldenTests {
+		output, err := run(test.args)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+
+		expected, err := ioutil.ReadFile(filepath.Join("test_data", test.golden))
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if output != string(expected) {
+			t.Errorf("Output of '%s' differs from '%s'.", strings.Join(test.args, " "), test.golden)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+import (
+	"eureka/golf"
+)
+
+var gnuNoteTypeStr = map[golf.NoteType]string{
+	golf.NoteTypeGnuABITag:        "NT_GNU_ABI_TAG (ABI version tag)",
+	golf.NoteTypeGnuHWCap:         "NT_GNU_HWCAP (DSO-supplied software HWCAP info)",
+	golf.NoteTypeGnuBuildID:       "NT_GNU_BUILD_ID (unique build ID bitstring)",
+	golf.NoteTypeGnuGoldVersion:   "NT_GNU_GOLD_VERSION (gold version)",
+	golf.NoteTypeGnuPropertyType0: "NT_GNU_PROPERTY_TYPE_0",
+}
+
+// The types of the notes in core files, which are owned by "CORE" and
+// "LINUX".
+var coreNoteTypeStr = map[golf.NoteType]string{
+	1:          "NT_PRSTATUS (prstatus structure)",
+	2:          "NT_FPREGSET (floating point registers)",
+	3:          "NT_PRPSINFO (prpsinfo structure)",
+	4:          "NT_TASKSTRUCT (task structure)",
+	6:          "NT_AUXV (auxiliary vector)",
+	0x202:      "NT_X86_XSTATE (x86 XSAVE extended state)",
+	0x46494c45: "NT_FILE (mapped files)",
+	0x53494749: "NT_SIGINFO (siginfo_t data)",
+}
+
+var gnuABIOSStr = map[golf.GnuABIOS]string{
+	golf.GnuABIOSLinux:    "Linux",
+	golf.GnuABIOSHurd:     "Hurd",
+	golf.GnuABIOSSolaris:  "Solaris",
+	golf.GnuABIOSFreeBSD:  "FreeBSD",
+	golf.GnuABIOSNetBSD:   "NetBSD",
+	golf.GnuABIOSSyllable: "Syllable",
+}
+
+// Returns the names of the bits set in value.
+func bitNames(value uint32, names map[uint32]string) []string {
+	var s []string
+	for bit := uint32(1); bit != 0; bit <<= 1 {
+		if value&bit == 0 {
+			continue
+		}
+		if name, exists := names[bit]; exists {
+			s = append(s, name)
+		} else {
+			s = append(s, fmt.Sprintf("<unknown: %x>", bit))
+		}
+	}
+	return s
+}
+
+var x86ISANames = map[uint32]string{
+	1 << 0: "x86-64-baseline",
+	1 << 1: "x86-64-v2",
+	1 << 2: "x86-64-v3",
+	1 << 3: "x86-64-v4",
+}
+
+var x86Feature2Names = map[uint32]string{
+	1 << 0: "x86", 1 << 1: "x87", 1 << 2: "MMX", 1 << 3: "XMM", 1 << 4: "YMM",
+	1 << 5: "ZMM", 1 << 6: "FXSR", 1 << 7: "XSAVE", 1 << 8: "XSAVEOPT", 1 << 9: "XSAVEC",
+	1 << 10: "TMM", 1 << 11: "MASK",
+}
+
+// Returns the description of a property of a NT_GNU_PROPERTY_TYPE_0 note.
+func gnuPropertyStr(elf *golf.ELF, prop golf.GnuProperty) string {
+	machine := elf.Header().Machine()
+	if prop.Type >= golf.GnuPropertyStartProcSpecific && len(prop.Data) == 4 {
+		value := elf.Endianess().Uint32(prop.Data)
+		list := func(names []string) string {
+			if len(names) == 0 {
+				return "<None>"
+			}
+			return strings.Join(names, ", ")
+		}
+
+		switch {
+		case machine == golf.MachineX86 || machine == golf.MachineX86_64:
+			switch prop.Type {
+			case golf.GnuPropertyX86Feature1And:
+				return "x86 feature: " + list(bitNames(value, map[uint32]string{
+					golf.GnuPropertyX86Feature1IBT:   "IBT",
+					golf.GnuPropertyX86Feature1SHSTK: "SHSTK",
+				}))
+			case golf.GnuPropertyX86ISA1Needed:
+				return "x86 ISA needed: " + list(bitNames(value, x86ISANames))
+			case golf.GnuPropertyX86ISA1Used:
+				return "x86 ISA used: " + list(bitNames(value, x86ISANames))
+			case golf.GnuPropertyX86Feature2Needed:
+				return "x86 feature needed: " + list(bitNames(value, x86Feature2Names))
+			case golf.GnuPropertyX86Feature2Used:
+				return "x86 feature used: " + list(bitNames(value, x86Feature2Names))
+			}
+		case machine == golf.MachineAArch64 && prop.Type == golf.GnuPropertyAArch64Feature1And:
+			return "AArch64 feature: " + list(bitNames(value, map[uint32]string{
+				golf.GnuPropertyAArch64Feature1BTI: "BTI",
+				golf.GnuPropertyAArch64Feature1PAC: "PAC",
+			}))
+		}
+	}
+
+	switch {
+	case prop.Type == golf.GnuPropertyStackSize && len(prop.Data) == int(elf.AddressSize()):
+		var size uint64
+		if len(prop.Data) == 8 {
+			size = elf.Endianess().Uint64(prop.Data)
+		} else {
+			size = uint64(elf.Endianess().Uint32(prop.Data))
+		}
+		return fmt.Sprintf("stack size: %#x", size)
+	case prop.Type == golf.GnuPropertyNoCopyOnProtected && len(prop.Data) == 0:
+		return "no copy on protected"
+	case prop.Type >= golf.GnuPropertyStartProcSpecific &&
+		prop.Type <= golf.GnuPropertyEndProcSpecific:
+		return fmt.Sprintf("<processor-specific type 0x%x data: %s>",
+			uint32(prop.Type), hexBytes(prop.Data))
+	case prop.Type >= golf.GnuPropertyStartAppSpecific:
+		return fmt.Sprintf("<application-specific type 0x%x data: %s>",
+			uint32(prop.Type), hexBytes(prop.Data))
+	}
+	return fmt.Sprintf("<unknown type 0x%x data: %s>", uint32(prop.Type), hexBytes(prop.Data))
+}
+
+// Returns the bytes in hex, each followed by a space.
+func hexBytes(data []byte) string {
+	var s strings.Builder
+	for _, b := range data {
+		fmt.Fprintf(&s, "%02x ", b)
+	}
+	return s.String()
+}
+
+func printNote(elf *golf.ELF, note *golf.Note) {
+	var typeName string
+	var known bool
+	switch note.Name {
+	case golf.NoteNameGnu:
+		typeName, known = gnuNoteTypeStr[note.Type]
+	case "CORE", "LINUX":
+		typeName, known = coreNoteTypeStr[note.Type]
+	}
+	if !known {
+		typeName = fmt.Sprintf("Unknown note type: (0x%08x)", uint32(note.Type))
+	}
+	fmt.Printf("  %-20s 0x%08x\t%s\n", note.Name, len(note.Desc), typeName)
+
+	endianess := elf.Endianess()
+	switch {
+	case note.Name == golf.NoteNameGnu && note.Type == golf.NoteTypeGnuBuildID:
+		fmt.Printf("    Build ID: %x\n", note.Desc)
+		return
+	case note.Name == golf.NoteNameGnu && note.Type == golf.NoteTypeGnuABITag:
+		tag, err := golf.DecodeGnuABITag(note.Desc, endianess)
+		if err != nil {
+			break
+		}
+		os, exists := gnuABIOSStr[tag.OS]
+		if !exists {
+			os = "Unknown"
+		}
+		fmt.Printf("    OS: %s, ABI: %d.%d.%d\n", os, tag.Major, tag.Minor, tag.Patch)
+		return
+	case note.Name == golf.NoteNameGnu && note.Type == golf.NoteTypeGnuGoldVersion:
+		fmt.Printf("    Version: %s\n", strings.TrimRight(string(note.Desc), "\x00"))
+		return
+	case note.Name == golf.NoteNameGnu && note.Type == golf.NoteTypeGnuPropertyType0:
+		props, err := golf.DecodeGnuProperties(
+			note.Desc, elf.Header().ELFIdent().Class, endianess)
+		if err != nil {
+			fmt.Printf("      Properties: <corrupt GNU_PROPERTY_TYPE, size = %#x>\n", len(note.Desc))
+			return
+		}
+		for i, prop := range props {
+			if i == 0 {
+				fmt.Printf("      Properties: %s\n", gnuPropertyStr(elf, prop))
+			} else {
+				fmt.Printf("\t%s\n", gnuPropertyStr(elf, prop))
+			}
+		}
+		return
+	case known && note.Name == "CORE":
+		return
+	}
+
+	if len(note.Desc) > 0 {
+		fmt.Printf("   description data: %s\n", hexBytes(note.Desc))
+	}
+}
+
+// Prints the notes in the note sections, or in the note segments if the file
+// has no sections.
+func printNotes(elf *golf.ELF) error {
+	endianess := elf.Endianess()
+	sections := elf.Sections()
+	if len(sections) > 0 {
+		for _, section := range sections {
+			hdr := section.SectHdr()
+			if hdr.Type() != golf.SectTypeNotes {
+				continue
+			}
+			data, err := section.Data()
+			if err != nil {
+				return err
+			}
+			notes, err := golf.ReadNotes(data, hdr.Alignment(), endianess)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\nDisplaying notes found in: %s\n", section.Name())
+			fmt.Printf("  Owner                Data size \tDescription\n")
+			for _, note := range notes {
+				printNote(elf, note)
+			}
+		}
+		return nil
+	}
+
+	for _, seg := range elf.ProgHdrTbl() {
+		if seg.Type() != golf.SegTypeNote {
+			continue
+		}
+		data, err := elf.SegData(seg)
+		if err != nil {
+			return err
+		}
+		notes, err := golf.ReadNotes(data, seg.Alignment(), endianess)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\nDisplaying notes found at file offset 0x%08x with length 0x%08x:\n",
+			seg.Offset(), seg.FileSize())
+		fmt.Printf("  Owner                Data size \tDescription\n")
+		for _, note := range notes {
+			printNote(elf, note)
+		}
+	}
+
+	return nil
+}
+
+// Returns true if the section at index is selected by name or number with
+// the -x selector.
+func isSelected(section *golf.Section, index int, selector string) bool {
+	if n, err := strconv.Atoi(selector); err == nil {
+		return n == index
+	}
+	return section.Name() == selector
+}
+
+// Returns true if any relocation section of a relocatable file applies to
+// the section.
+func hasRelocs(elf *golf.ELF, section *golf.Section) bool {
+	if elf.Header().Type() != golf.TypeRelocatable {
+		return false
+	}
+
+	sections := elf.Sections()
+	for _, s := range sections {
+		hdr := s.SectHdr()
+		if hdr.Type() != golf.SectTypeRel && hdr.Type() != golf.SectTypeRelA {
+			continue
+		}
+		if hdr.Info() < uint32(len(sections)) && sections[hdr.Info()] == section {
+			return true
+		}
+	}
+	return false
+}
+
+func printHexDump(elf *golf.ELF, section *golf.Section) error {
+	hdr := section.SectHdr()
+	if hdr.Type() == golf.SectTypeNoBits || hdr.Size() == 0 {
+		fmt.Printf("Section '%s' has no data to dump.\n", section.Name())
+		return nil
+	}
+
+	// Like readelf without -z, compressed sections are dumped as they are in
+	// the file.
+	data, err := section.RawData()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nHex dump of section '%s':\n", section.Name())
+	if hasRelocs(elf, section) {
+		fmt.Printf(" NOTE: This section has relocations against it, " +
+			"but these have NOT been applied to this dump.\n")
+	}
+	for offset := 0; offset < len(data); offset += 16 {
+		line := data[offset:]
+		if len(line) > 16 {
+			line = line[:16]
+		}
+
+		fmt.Printf("  0x%08x ", hdr.Address()+uint64(offset))
+		for i := 0; i < 16; i++ {
+			if i < len(line) {
+				fmt.Printf("%02x", line[i])
+			} else {
+				fmt.Printf("  ")
+			}
+			if i%4 == 3 {
+				fmt.Printf(" ")
+			}
+		}
+		for _, b := range line {
+			if b >= ' ' && b < 0x7f {
+				fmt.Printf("%c", b)
+			} else {
+				fmt.Printf(".")
+			}
+		}
+		fmt.Printf("\n")
+	}
+	fmt.Printf("\n")
+
+	return nil
+}
+
+// Dumps the sections selected with -x in the order of their headers, like
+// readelf does, and warns about the selectors which match no section.
+func printHexDumps(elf *golf.ELF) error {
+	matched := make([]bool, len(hexDumps))
+	for i, section := range elf.Sections() {
+		selected := false
+		for j, selector := range hexDumps {
+			if isSelected(section, i, selector) {
+				selected = true
+				matched[j] = true
+			}
+		}
+		if !selected {
+			continue
+		}
+
+		err := printHexDump(elf, section)
+		if err != nil {
+			return err
+		}
+	}
+
+	for j, selector := range hexDumps {
+		if matched[j] {
+			continue
+		}
+		if _, err := strconv.Atoi(selector); err == nil {
+			warn("Section %s was not dumped because it does not exist!", selector)
+		} else {
+			warn("Section '%s' was not dumped because it does not exist", selector)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,497 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+import (
+	"eureka/golf"
+)
+
+// Returns the section index of a symbol as printed in the Ndx column.
+func sectIndexStr(index uint32) string {
+	switch {
+	case index == uint32(golf.SectIndexUndefined):
+		return "UND"
+	case index == uint32(golf.SectIndexAbsSym):
+		return "ABS"
+	case index == uint32(golf.SectIndexCommonSym):
+		return "COM"
+	case index >= uint32(golf.SectIndexStartProcSpecific) &&
+		index <= uint32(golf.SectIndexEndProcSpecific):
+		return fmt.Sprintf("PRC[0x%04x]", index)
+	case index >= uint32(golf.SectIndexStartOSSpecific) &&
+		index <= uint32(golf.SectIndexEndOSSpecific):
+		return fmt.Sprintf("OS [0x%04x]", index)
+	case index >= uint32(golf.SectIndexStartReserved) &&
+		index <= uint32(golf.SectIndexEndReserved):
+		return fmt.Sprintf("RSV[0x%04x]", index)
+	}
+	return fmt.Sprintf("%d", index)
+}
+
+// Returns the name of a symbol, or the name of its section for section
+// symbols without a name.
+func symbolName(elf *golf.ELF, sym *golf.ResolvedSymbol) string {
+	if sym.Name == "" && sym.Type == golf.SymTypeSection {
+		sections := elf.Sections()
+		if sym.SectIndex < uint32(len(sections)) {
+			return sections[sym.SectIndex].Name()
+		}
+	}
+	return sym.Name
+}
+
+// Returns the version of a dynamic symbol as a suffix of its name, like
+// '@@GOLF_2.0' for the default version of a defined symbol, or
+// '@GLIBC_2.14 (5)' for a version required from a needed library, with its
+// version index.
+func versionSuffix(sym *golf.ResolvedSymbol, versionIndex uint16, withIndex bool) string {
+	switch {
+	case sym.Version == "":
+		return ""
+	case sym.SectIndex == uint32(golf.SectIndexAbsSym) && sym.Name == sym.Version:
+		// The symbols of version definitions are not shown with their
+		// versions.
+		return ""
+	case sym.VersionFile != "":
+		if withIndex {
+			return fmt.Sprintf("@%s (%d)", sym.Version, versionIndex&golf.VerIndexMask)
+		}
+		return "@" + sym.Version
+	case sym.VersionHidden:
+		return "@" + sym.Version
+	}
+	return "@@" + sym.Version
+}
+
+// Returns the size of a symbol as printed in the Size column.
+func symbolSizeStr(size uint64) string {
+	if size > 99999 {
+		return fmt.Sprintf("%#x", size)
+	}
+	return fmt.Sprintf("%5d", size)
+}
+
+func printSymbolTable(elf *golf.ELF, section *golf.Section) error {
+	var symbols []golf.ResolvedSymbol
+	var versionIndeces []uint16
+	var err error
+	if section.SectHdr().Type() == golf.SectTypeDynSym {
+		symbols, err = elf.DynamicSymbols()
+		if err == nil {
+			versionIndeces, err = elf.SymbolVersionIndeces()
+		}
+	} else {
+		symbols, err = elf.Symbols()
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nSymbol table '%s' contains %d %s:\n",
+		section.Name(), len(symbols), plural(len(symbols), "entry", "entries"))
+	if is32(elf) {
+		fmt.Printf("   Num:    Value  Size Type    Bind   Vis      Ndx Name\n")
+	} else {
+		fmt.Printf("   Num:    Value          Size Type    Bind   Vis      Ndx Name\n")
+	}
+	for i := range symbols {
+		sym := &symbols[i]
+		var versionIndex uint16
+		if i < len(versionIndeces) {
+			versionIndex = versionIndeces[i]
+		}
+
+		name := symbolName(elf, sym)
+		suffix := versionSuffix(sym, versionIndex, true)
+		// The name is truncated such that the version fits in the column.
+		if len(name)+len(suffix) > 21 {
+			name = truncate(name, 21-len(suffix))
+		}
+
+		if is32(elf) {
+			fmt.Printf("%6d: %08x", i, sym.Value)
+		} else {
+			fmt.Printf("%6d: %016x", i, sym.Value)
+		}
+		fmt.Printf(" %s %-7s %-6s %-7s %4s %s%s\n",
+			symbolSizeStr(sym.Size), sym.Type, sym.Binding, sym.Visibility,
+			sectIndexStr(sym.SectIndex), name, suffix)
+	}
+
+	return nil
+}
+
+// Prints the dynamic symbol table with --dyn-syms, and all symbol tables with
+// -s, in the order of their sections.
+func printSymbols(elf *golf.ELF) error {
+	if len(elf.Sections()) == 0 {
+		if !symbols {
+			return nil
+		}
+		fmt.Printf("\nDynamic symbol information is not available for displaying symbols.\n")
+		return nil
+	}
+
+	for _, section := range elf.Sections() {
+		switch section.SectHdr().Type() {
+		case golf.SectTypeDynSym:
+		case golf.SectTypeSymTab:
+			if !symbols {
+				continue
+			}
+		default:
+			continue
+		}
+
+		err := printSymbolTable(elf, section)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dynamic tags whose values are sizes in bytes.
+var dynSizeTags = map[golf.DynTag]bool{
+	golf.DynTagPltRelSize:       true,
+	golf.DynTagRelASize:         true,
+	golf.DynTagRelAEntSize:      true,
+	golf.DynTagStrSize:          true,
+	golf.DynTagSymEntSize:       true,
+	golf.DynTagRelSize:          true,
+	golf.DynTagRelEntSize:       true,
+	golf.DynTagInitArraySize:    true,
+	golf.DynTagFiniArraySize:    true,
+	golf.DynTagPreInitArraySize: true,
+	golf.DynTag(35):             true, // DT_RELRSZ
+	golf.DynTag(37):             true, // DT_RELRENT
+}
+
+// Dynamic tags whose values are counts.
+var dynCountTags = map[golf.DynTag]bool{
+	golf.DynTagVerDefNum:  true,
+	golf.DynTagVerNeedNum: true,
+	golf.DynTagRelACount:  true,
+	golf.DynTagRelCount:   true,
+}
+
+var dynFlagNames = []struct {
+	flag golf.DynFlag
+	name string
+}{
+	{golf.DynFlagOrigin, "ORIGIN"},
+	{golf.DynFlagSymbolic, "SYMBOLIC"},
+	{golf.DynFlagTextRel, "TEXTREL"},
+	{golf.DynFlagBindNow, "BIND_NOW"},
+	{golf.DynFlagStaticTLS, "STATIC_TLS"},
+}
+
+var dynFlag1Names = []struct {
+	flag golf.DynFlag1
+	name string
+}{
+	{golf.DynFlag1Now, "NOW"},
+	{golf.DynFlag1Global, "GLOBAL"},
+	{golf.DynFlag1Group, "GROUP"},
+	{golf.DynFlag1NoDelete, "NODELETE"},
+	{golf.DynFlag1LoadFltr, "LOADFLTR"},
+	{golf.DynFlag1InitFirst, "INITFIRST"},
+	{golf.DynFlag1NoOpen, "NOOPEN"},
+	{golf.DynFlag1Origin, "ORIGIN"},
+	{golf.DynFlag1Direct, "DIRECT"},
+	{golf.DynFlag1Trans, "TRANS"},
+	{golf.DynFlag1Interpose, "INTERPOSE"},
+	{golf.DynFlag1NoDefLib, "NODEFLIB"},
+	{golf.DynFlag1NoDump, "NODUMP"},
+	{golf.DynFlag1ConfAlt, "CONFALT"},
+	{golf.DynFlag1EndFiltee, "ENDFILTEE"},
+	{golf.DynFlag1DispRelDne, "DISPRELDNE"},
+	{golf.DynFlag1DispRelPnd, "DISPRELPND"},
+	{golf.DynFlag1NoDirect, "NODIRECT"},
+	{golf.DynFlag1IgnMulDef, "IGNMULDEF"},
+	{golf.DynFlag1NoKSyms, "NOKSYMS"},
+	{golf.DynFlag1NoHdr, "NOHDR"},
+	{golf.DynFlag1Edited, "EDITED"},
+	{golf.DynFlag1NoReloc, "NORELOC"},
+	{golf.DynFlag1SymIntpose, "SYMINTPOSE"},
+	{golf.DynFlag1GlobAudit, "GLOBAUDIT"},
+	{golf.DynFlag1Singleton, "SINGLETON"},
+	{golf.DynFlag1Stub, "STUB"},
+	{golf.DynFlag1PIE, "PIE"},
+}
+
+// Returns the value of a dynamic entry as printed in the Name/Value column.
+// The string values are consumed from strs in the order of the entries.
+func dynValueStr(entry golf.DynEntry, strs map[golf.DynTag][]string) string {
+	str := func() string {
+		values := strs[entry.Tag]
+		if len(values) == 0 {
+			return ""
+		}
+		strs[entry.Tag] = values[1:]
+		return values[0]
+	}
+
+	switch {
+	case entry.Tag == golf.DynTagNeeded:
+		return fmt.Sprintf("Shared library: [%s]", str())
+	case entry.Tag == golf.DynTagSOName:
+		return fmt.Sprintf("Library soname: [%s]", str())
+	case entry.Tag == golf.DynTagRPath:
+		return fmt.Sprintf("Library rpath: [%s]", str())
+	case entry.Tag == golf.DynTagRunPath:
+		return fmt.Sprintf("Library runpath: [%s]", str())
+	case entry.Tag == golf.DynTagPltRel:
+		return golf.DynTag(entry.Value).String()
+	case entry.Tag == golf.DynTagFlags:
+		var names []string
+		for _, f := range dynFlagNames {
+			if golf.DynFlag(entry.Value)&f.flag != 0 {
+				names = append(names, f.name)
+			}
+		}
+		return strings.Join(names, " ")
+	case entry.Tag == golf.DynTagFlags1:
+		s := "Flags:"
+		for _, f := range dynFlag1Names {
+			if golf.DynFlag1(entry.Value)&f.flag != 0 {
+				s += " " + f.name
+			}
+		}
+		return s
+	case dynSizeTags[entry.Tag]:
+		return fmt.Sprintf("%d (bytes)", entry.Value)
+	case dynCountTags[entry.Tag]:
+		return fmt.Sprintf("%d", entry.Value)
+	}
+	return fmt.Sprintf("0x%x", entry.Value)
+}
+
+// Returns the file offset of the dynamic section, or of the dynamic segment
+// if the file has no sections.
+func dynamicOffset(elf *golf.ELF) uint64 {
+	for _, section := range elf.Sections() {
+		if section.SectHdr().Type() == golf.SectTypeDynamic {
+			return section.SectHdr().Offset()
+		}
+	}
+	for _, seg := range elf.ProgHdrTbl() {
+		if seg.Type() == golf.SegTypeDynamic {
+			return seg.Offset()
+		}
+	}
+	return 0
+}
+
+func printDynamic(elf *golf.ELF) error {
+	dyn, err := elf.Dynamic()
+	if err != nil {
+		return err
+	}
+	if dyn == nil {
+		fmt.Printf("\nThere is no dynamic section in this file.\n")
+		return nil
+	}
+
+	// The string values of the entries with the same tag are in the order of
+	// the entries.
+	strs := map[golf.DynTag][]string{
+		golf.DynTagNeeded:  dyn.Needed,
+		golf.DynTagSOName:  {dyn.SOName},
+		golf.DynTagRPath:   {dyn.RPath},
+		golf.DynTagRunPath: {dyn.RunPath},
+	}
+
+	entries := append(dyn.Entries[:len(dyn.Entries):len(dyn.Entries)], golf.DynEntry{})
+	fmt.Printf("\nDynamic section at offset 0x%x contains %d %s:\n",
+		dynamicOffset(elf), len(entries), plural(len(entries), "entry", "entries"))
+	fmt.Printf("  Tag        Type                         Name/Value\n")
+	width := 19
+	for _, entry := range entries {
+		if is32(elf) {
+			fmt.Printf(" 0x%08x", uint64(entry.Tag))
+			width = 27
+		} else {
+			fmt.Printf(" 0x%016x", uint64(entry.Tag))
+		}
+		typeName := entry.Tag.String()
+		pad := width - len(typeName)
+		if pad < 1 {
+			pad = 1
+		}
+		fmt.Printf(" (%s)%*s%s\n", typeName, pad, " ", dynValueStr(entry, strs))
+	}
+
+	return nil
+}
+
+// Prints the name of a relocation type in the Type column, or a placeholder
+// with its value if the type is unknown. Like readelf, names are truncated
+// to the width of the column but the placeholder is not.
+func printRelocType(machine golf.MachineArch, t golf.RelocType) {
+	if s := golf.RelocTypeStr(machine, t); s != "" {
+		fmt.Printf("%-17.17s", s)
+		return
+	}
+	fmt.Printf("unrecognized: %-7x", uint32(t))
+}
+
+func printRelocTbl(elf *golf.ELF, tbl *golf.RelocTbl) {
+	hdr := tbl.Section.SectHdr()
+	isRelA := hdr.Type() == golf.SectTypeRelA
+	fmt.Printf("\nRelocation section '%s' at offset 0x%x contains %d %s:\n",
+		tbl.Section.Name(), hdr.Offset(), len(tbl.Relocs),
+		plural(len(tbl.Relocs), "entry", "entries"))
+
+	switch {
+	case is32(elf) && isRelA:
+		fmt.Printf(" Offset     Info    Type            Sym.Value  Sym. Name + Addend\n")
+	case is32(elf):
+		fmt.Printf(" Offset     Info    Type            Sym.Value  Sym. Name\n")
+	case isRelA:
+		fmt.Printf("  Offset          Info           Type           Sym. Value    Sym. Name + Addend\n")
+	default:
+		fmt.Printf("  Offset          Info           Type           Sym. Value    Sym. Name\n")
+	}
+
+	// Versions are printed only for the symbols of the dynamic symbol table.
+	linksDynSym := false
+	sections := elf.Sections()
+	if link := hdr.Link(); link < uint32(len(sections)) {
+		linksDynSym = sections[link].SectHdr().Type() == golf.SectTypeDynSym
+	}
+
+	// The types of 64-bit MIPS relocations combine up to three types, which
+	// readelf prints separately, the second and third on lines of their own.
+	machine := elf.Header().Machine()
+	mips64 := machine == golf.MachineMIPS && !is32(elf)
+	for _, reloc := range tbl.Relocs {
+		if is32(elf) {
+			info := reloc.SymIndex<<8 | uint32(reloc.Type)
+			fmt.Printf("%08x  %08x ", reloc.Offset, info)
+		} else {
+			info := uint64(reloc.SymIndex)<<32 | uint64(reloc.Type)
+			fmt.Printf("%012x  %012x ", reloc.Offset, info)
+		}
+		if mips64 {
+			printRelocType(machine, reloc.Type&0xff)
+		} else {
+			printRelocType(machine, reloc.Type)
+		}
+
+		if reloc.Sym != nil {
+			name := truncate(symbolName(elf, reloc.Sym), 22)
+			if name == "" {
+				name = "<null>"
+			}
+			if linksDynSym {
+				name += versionSuffix(reloc.Sym, 0, false)
+			}
+			if is32(elf) {
+				fmt.Printf(" %08x   %s", reloc.Sym.Value, name)
+			} else {
+				fmt.Printf(" %016x %s", reloc.Sym.Value, name)
+			}
+			if isRelA {
+				if reloc.Addend < 0 {
+					fmt.Printf(" - %x", uint64(-reloc.Addend))
+				} else {
+					fmt.Printf(" + %x", reloc.Addend)
+				}
+			}
+		} else if isRelA {
+			if is32(elf) {
+				fmt.Printf("%12s", "")
+			} else {
+				fmt.Printf("%20s", "")
+			}
+			if reloc.Addend < 0 {
+				fmt.Printf("-%x", uint64(-reloc.Addend))
+			} else {
+				fmt.Printf("%x", reloc.Addend)
+			}
+		}
+		fmt.Printf("\n")
+
+		if mips64 {
+			fmt.Printf("                    Type2: ")
+			printRelocType(machine, reloc.Type>>8&0xff)
+			fmt.Printf("\n                    Type3: ")
+			printRelocType(machine, reloc.Type>>16&0xff)
+			fmt.Printf("\n")
+		}
+	}
+}
+
+// Prints the offsets of the relative relocations in a SHT_RELR section, like
+// readelf of binutils 2.40.
+func printRelrTbl(elf *golf.ELF, tbl *golf.RelrTbl) {
+	hdr := tbl.Section.SectHdr()
+	entries := hdr.Size() / 8
+	if is32(elf) {
+		entries = hdr.Size() / 4
+	}
+	fmt.Printf("\nRelocation section '%s' at offset 0x%x contains %d %s:\n",
+		tbl.Section.Name(), hdr.Offset(), entries, plural(int(entries), "entry", "entries"))
+	fmt.Printf("  %d offset%s\n", len(tbl.Offsets), plural(len(tbl.Offsets), "", "s"))
+	for _, offset := range tbl.Offsets {
+		if is32(elf) {
+			fmt.Printf("%08x\n", offset)
+		} else {
+			fmt.Printf("%016x\n", offset)
+		}
+	}
+}
+
+func printRelocs(elf *golf.ELF) error {
+	// Like readelf, empty relocation sections are not printed.
+	printed := false
+	for _, section := range elf.Sections() {
+		if section.SectHdr().Size() == 0 {
+			continue
+		}
+
+		switch section.SectHdr().Type() {
+		case golf.SectTypeRel, golf.SectTypeRelA:
+			tbl, err := elf.RelocTbl(section)
+			if err != nil {
+				return err
+			}
+			printRelocTbl(elf, tbl)
+		case golf.SectTypeRelr:
+			tbl, err := elf.RelrTbl(section)
+			if err != nil {
+				return err
+			}
+			printRelrTbl(elf, tbl)
+		default:
+			continue
+		}
+		printed = true
+	}
+	if !printed {
+		fmt.Printf("\nThere are no relocations in this file.\n")
+	}
+
+	return nil
+}
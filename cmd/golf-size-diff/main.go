@@ -0,0 +1,201 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Command golf-size-diff compares the sizes of two builds of an ELF binary,
+// and reports the growth and shrinkage of their sections, symbols, compile
+// units, or other dimensions of golf-size.
+//
+// Usage:
+//
+//	golf-size-diff [-d DIMENSIONS] [-demangle] [-n ROWS] [-max-growth BYTES]
+//	    [-max-vm-growth BYTES] [-max-growth-percent PERCENT]
+//	    [-max-row-growth BYTES] [-json] OLD NEW
+//
+// The dimensions are a comma separated list. The compileunits and sourcefiles
+// dimensions are skipped, with a note, if either file has no debug info in the
+// file or in a separate debug file, or has debug info which cannot be read. Symbols are matched by name, and by their
+// demangled names with -demangle. With -json, the diffs are printed as a JSON
+// array with all their rows. The exit status is 2 if the growth of
+// the new build exceeds any of the limits, which are printed to stderr.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+import (
+	"eureka/size"
+)
+
+var (
+	dimensions = flag.String("d",
+		strings.Join([]string{
+			string(size.DimSections), string(size.DimSymbols), string(size.DimCompUnits)}, ","),
+		"A comma separated list of dimensions: sections, segments, symbols, compileunits "+
+			"and sourcefiles.")
+	demangleNames = flag.Bool("demangle", false, "Match symbols by their demangled names.")
+	limit         = flag.Int("n", 20, "The number of rows to print. 0 prints all rows.")
+	maxGrowth     = flag.Int64("max-growth", -1,
+		"The number of bytes by which the file can grow. -1 is no limit.")
+	maxVMGrowth = flag.Int64("max-vm-growth", -1,
+		"The number of bytes by which the VM size can grow. -1 is no limit.")
+	maxGrowthPercent = flag.Float64("max-growth-percent", -1,
+		"The percentage by which the file can grow. -1 is no limit.")
+	maxRowGrowth = flag.Int64("max-row-growth", -1,
+		"The number of bytes by which any row can grow. -1 is no limit.")
+	jsonOutput = flag.Bool("json", false, "Print the diffs as a JSON array.")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr,
+			"Usage: %s [-d DIMENSIONS] [-demangle] [-n ROWS] [-max-growth BYTES] "+
+				"[-max-vm-growth BYTES] [-max-growth-percent PERCENT] [-max-row-growth BYTES] "+
+				"[-json] OLD NEW\n",
+			os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	thresholds := size.Thresholds{
+		FileGrowth:        *maxGrowth,
+		VMGrowth:          *maxVMGrowth,
+		FileGrowthPercent: *maxGrowthPercent,
+		RowGrowth:         *maxRowGrowth,
+	}
+
+	var dims []size.Dimension
+	for _, dim := range strings.Split(*dimensions, ",") {
+		dims = append(dims, size.Dimension(dim))
+	}
+	diffs, err := diffDimensions(flag.Arg(0), flag.Arg(1), dims)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(1)
+	}
+
+	var violations []string
+	seen := make(map[string]bool)
+	for _, diff := range diffs {
+		// The limits on the totals are violated alike by the diffs of all
+		// dimensions.
+		for _, v := range diff.Exceeds(thresholds) {
+			if !seen[v] {
+				seen[v] = true
+				violations = append(violations, v)
+			}
+		}
+	}
+
+	if *jsonOutput {
+		data, err := json.MarshalIndent(diffs, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", data)
+	} else {
+		for i, diff := range diffs {
+			if i > 0 {
+				fmt.Printf("\n")
+			}
+			diff.WriteTable(os.Stdout, *limit)
+		}
+	}
+
+	if len(violations) > 0 {
+		for _, v := range violations {
+			fmt.Fprintf(os.Stderr, "%s\n", v)
+		}
+		os.Exit(2)
+	}
+}
+
+// Returns the diffs of the files along the dimensions. The dimensions which
+// need DWARF are skipped, with a note printed to stderr, if either file has no
+// debug info, or has debug info which cannot be read.
+func diffDimensions(
+	oldFileName, newFileName string, dims []size.Dimension) ([]*size.Diff, error) {
+	var diffs []*size.Diff
+	for _, dim := range dims {
+		needsDWARF := dim == size.DimCompUnits || dim == size.DimSourceFiles
+		if needsDWARF {
+			exists, err := hasDebugInfo(dim, oldFileName, newFileName)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				continue
+			}
+		}
+
+		diff, err := diffFiles(oldFileName, newFileName, dim)
+		if err != nil && needsDWARF {
+			fmt.Fprintf(os.Stderr, "Skipping %s: %s\n", dim, err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+// Returns false if either file has no debug info, in which case a note is
+// printed to stderr.
+func hasDebugInfo(dim size.Dimension, fileNames ...string) (bool, error) {
+	for _, fileName := range fileNames {
+		exists, err := size.HasDebugInfo(fileName)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			fmt.Fprintf(os.Stderr, "Skipping %s: '%s' has no debug info.\n", dim, fileName)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Returns the diff of the files along the dimension.
+func diffFiles(oldFileName, newFileName string, dim size.Dimension) (*size.Diff, error) {
+	old, err := size.Analyze(oldFileName, dim)
+	if err != nil {
+		return nil, err
+	}
+	new, err := size.Analyze(newFileName, dim)
+	if err != nil {
+		return nil, err
+	}
+
+	if *demangleNames && dim == size.DimSymbols {
+		old.Demangle()
+		new.Demangle()
+	}
+
+	return size.DiffReports(old, new)
+}
@@ -0,0 +1,55 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"testing"
+)
+
+import (
+	"eureka/size"
+)
+
+// 'diff_new_zstd_x86_64.exe' is 'diff_new_x86_64.exe' with its debug sections
+// compressed with zstd, for which no decompressor is registered.
+func TestDiffUnreadableDWARF(t *testing.T) {
+	dims := []size.Dimension{size.DimSections, size.DimSymbols, size.DimCompUnits}
+	diffs, err := diffDimensions(
+		"../../size/test_data/diff_old_x86_64.exe",
+		"../../size/test_data/diff_new_zstd_x86_64.exe",
+		dims)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The compile units are skipped.
+	if len(diffs) != 2 {
+		t.Errorf("Wrong number of diffs: %d", len(diffs))
+		return
+	}
+	for i, diff := range diffs {
+		if diff.Dimension != dims[i] {
+			t.Errorf("Wrong dimension of diff %d: %s", i, diff.Dimension)
+			return
+		}
+		if len(diff.Rows) == 0 {
+			t.Errorf("Expected rows in the %s diff.", diff.Dimension)
+			return
+		}
+	}
+}
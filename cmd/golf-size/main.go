@@ -0,0 +1,103 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Command golf-size reports the sizes of the sections, segments, symbols,
+// compile units or source files of ELF files.
+//
+// Usage:
+//
+//	golf-size [-d DIMENSION] [-s file|vm|both|name] [-n ROWS] [-json] FILE...
+//
+// The dimension is one of sections, segments, symbols, compileunits and
+// sourcefiles. With -json, the reports are printed as a JSON array with all
+// their rows.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+import (
+	"eureka/size"
+)
+
+var (
+	dimension = flag.String("d", string(size.DimSections),
+		"The dimension: sections, segments, symbols, compileunits or sourcefiles.")
+	sortKey = flag.String("s", string(size.SortByBoth),
+		"Sort by file size, vm size, the larger of both, or name.")
+	limit      = flag.Int("n", 20, "The number of rows to print. 0 prints all rows.")
+	jsonOutput = flag.Bool("json", false, "Print the reports as a JSON array.")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr,
+			"Usage: %s [-d DIMENSION] [-s file|vm|both|name] [-n ROWS] [-json] FILE...\n",
+			os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	key := size.SortKey(*sortKey)
+	switch key {
+	case size.SortByFileSize, size.SortByVMSize, size.SortByBoth, size.SortByName:
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown sort key '%s'.\n", *sortKey)
+		os.Exit(1)
+	}
+
+	status := 0
+	reports := make([]*size.Report, 0, flag.NArg())
+	for _, fileName := range flag.Args() {
+		report, err := size.Analyze(fileName, size.Dimension(*dimension))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			status = 1
+			continue
+		}
+		report.Sort(key)
+		reports = append(reports, report)
+	}
+
+	if *jsonOutput {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", data)
+	} else {
+		for i, report := range reports {
+			if len(reports) > 1 {
+				if i > 0 {
+					fmt.Printf("\n")
+				}
+				fmt.Printf("%s:\n", report.FileName)
+			}
+			report.WriteTable(os.Stdout, *limit)
+		}
+	}
+
+	os.Exit(status)
+}
@@ -0,0 +1,121 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Command golf-symcheck checks that the undefined dynamic symbols of ELF
+// executables and shared libraries, and of their dependencies, are defined
+// with matching versions by the resolved dependencies.
+//
+// Usage:
+//
+//	golf-symcheck [-sysroot DIR] [-library-path PATHS] [-conf FILE] [-warnings] FILE...
+//
+// The dependencies are resolved as with golf-ldd, which takes the same
+// -sysroot, -library-path and -conf options. Missing dependencies, undefined
+// symbols and versions which are not found are printed as errors. With
+// -warnings, undefined weak symbols, interposed symbols and symbols bound to
+// weak definitions are printed too. The exit status is 1 if any of the files
+// could not be read, and 2 if any error is found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+import (
+	"eureka/deps"
+)
+
+var (
+	sysroot     = flag.String("sysroot", "", "The directory under which the libraries are looked up.")
+	libraryPath = flag.String("library-path", "", "Directories searched like those of LD_LIBRARY_PATH.")
+	confFile    = flag.String("conf", "", "The ld.so.conf file listing library directories.")
+	warnings    = flag.Bool("warnings", false, "Print the issues which are not errors.")
+)
+
+// Prints the errors, and the warnings if asked for, in the dependency graph of
+// fileName. Returns true if there are errors.
+func check(resolver *deps.Resolver, fileName string) (bool, error) {
+	graph, err := resolver.Resolve(fileName)
+	if err != nil {
+		return false, err
+	}
+	issues, err := deps.CheckSymbols(graph)
+	if err != nil {
+		return false, err
+	}
+
+	failed := false
+	for _, edge := range graph.Missing() {
+		fmt.Printf("%s: error: %s: not found (required by %s)\n", fileName, edge.Name, edge.From.Path)
+		failed = true
+	}
+	for _, issue := range issues {
+		if issue.Fatal {
+			fmt.Printf("%s: error: %s\n", fileName, issue)
+			failed = true
+		} else if *warnings {
+			fmt.Printf("%s: warning: %s\n", fileName, issue)
+		}
+	}
+
+	return failed, nil
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr,
+			"Usage: %s [-sysroot DIR] [-library-path PATHS] [-conf FILE] [-warnings] FILE...\n",
+			os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	resolver := &deps.Resolver{
+		Sysroot:     *sysroot,
+		LibraryPath: deps.SplitSearchPath(*libraryPath),
+	}
+	var err error
+	if *confFile != "" {
+		err = resolver.LoadConf(*confFile)
+	} else {
+		err = resolver.LoadDefaultConf()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(1)
+	}
+
+	status := 0
+	for _, fileName := range flag.Args() {
+		failed, err := check(resolver, fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			status = 1
+			continue
+		}
+		if failed && status == 0 {
+			status = 2
+		}
+	}
+
+	os.Exit(status)
+}
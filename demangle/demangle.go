@@ -0,0 +1,1049 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Package demangle demangles C++ symbol names mangled as specified by the
+// Itanium C++ ABI, which is used by GCC and Clang on ELF platforms. The names
+// are printed like the c++filt tool prints them. Names with expressions, like
+// those in decltype types or template arguments which are not literals, are
+// not supported.
+package demangle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Returns the demangled form of a mangled C++ name, like 'foo(char const*)'
+// for '_Z3fooPKc'. An error is returned if the name is not a mangled name, or
+// uses a part of the mangling grammar which is not supported.
+func Demangle(name string) (result string, err error) {
+	if !strings.HasPrefix(name, "_Z") {
+		return "", fmt.Errorf("'%s' is not a mangled C++ name.", name)
+	}
+
+	// The parser panics with a demangleError on errors, so that they need not
+	// be checked at every step of the recursive descent.
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(demangleError)
+			if !ok {
+				panic(r)
+			}
+			result, err = "", fmt.Errorf("Error demangling '%s': %s", name, string(e))
+		}
+	}()
+
+	st := &state{in: name, pos: 2}
+	result = st.encoding()
+	if st.pos < len(st.in) {
+		result += cloneSuffix(st, st.in[st.pos:])
+	}
+
+	return result, nil
+}
+
+// Returns the demangled form of the name if it is a mangled C++ name which can
+// be demangled, and the name itself otherwise.
+func Filter(name string) string {
+	demangled, err := Demangle(name)
+	if err != nil {
+		return name
+	}
+	return demangled
+}
+
+type demangleError string
+
+// A demangled type. The declarator of a type derived from it, like a pointer
+// to it, goes between the left and right parts. For example, the left part of
+// the function type 'void (int)' is 'void ' and the right part is '(int)', so
+// that a pointer to it is 'void (*)(int)'.
+type typ struct {
+	left  string
+	right string
+
+	// True for function and array types, whose derived declarators are
+	// parenthesized.
+	wrap bool
+
+	// True for function types, whose qualifiers follow their parameters.
+	fn bool
+
+	// The kind of a reference type, for collapsing references to references.
+	ref refKind
+
+	// The types of an argument pack, which are expanded where the pack is
+	// used. A pack is derived from by deriving from each of its types.
+	pack   []typ
+	isPack bool
+}
+
+type refKind uint8
+
+const (
+	refNone   = refKind(0)
+	refLValue = refKind(1)
+	refRValue = refKind(2)
+)
+
+func (t typ) String() string {
+	if t.isPack {
+		var strs []string
+		for _, elem := range t.pack {
+			if s := elem.String(); s != "" {
+				strs = append(strs, s)
+			}
+		}
+		return strings.Join(strs, ", ")
+	}
+	return t.left + t.right
+}
+
+// Returns the type derived from t by f, or from each type of t if it is an
+// argument pack.
+func mapPack(t typ, f func(typ) typ) typ {
+	if !t.isPack {
+		return f(t)
+	}
+
+	result := typ{isPack: true}
+	for _, elem := range t.pack {
+		result.pack = append(result.pack, f(elem))
+	}
+	return result
+}
+
+// A substitution candidate.
+type sub struct {
+	t typ
+
+	// The last unqualified name of a name, used for the names of
+	// constructors and destructors.
+	last string
+}
+
+// The demangled form of the name of an encoding.
+type nameInfo struct {
+	s    string
+	last string
+
+	// True if the last component of the name has template arguments.
+	template bool
+
+	// True for constructors, destructors and conversion operators, whose
+	// return types are not mangled even if they are templates.
+	noReturn bool
+
+	// The qualifiers of a member function, like ' const'.
+	quals string
+}
+
+type state struct {
+	in  string
+	pos int
+
+	subs []sub
+
+	// The template arguments to which template parameters refer.
+	tmplArgs []typ
+
+	// The depth of nested types being parsed. Template arguments of names
+	// within types do not change tmplArgs.
+	typeDepth int
+}
+
+func (st *state) fail(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	panic(demangleError(fmt.Sprintf("%s at offset %d", msg, st.pos)))
+}
+
+func (st *state) peek() byte {
+	if st.pos >= len(st.in) {
+		return 0
+	}
+	return st.in[st.pos]
+}
+
+func (st *state) peekAt(i int) byte {
+	if st.pos+i >= len(st.in) {
+		return 0
+	}
+	return st.in[st.pos+i]
+}
+
+func (st *state) consume(prefix string) bool {
+	if strings.HasPrefix(st.in[st.pos:], prefix) {
+		st.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (st *state) expect(prefix string) {
+	if !st.consume(prefix) {
+		st.fail("expected '%s'", prefix)
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLower(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+// Reads a non-negative decimal number.
+func (st *state) number() int {
+	start := st.pos
+	for isDigit(st.peek()) {
+		st.pos++
+	}
+	if start == st.pos {
+		st.fail("expected a number")
+	}
+
+	n, err := strconv.Atoi(st.in[start:st.pos])
+	if err != nil {
+		st.fail("invalid number")
+	}
+	return n
+}
+
+// Reads a decimal number which is negative if prefixed with 'n', and returns
+// it as a string.
+func (st *state) signedNumber() string {
+	neg := st.consume("n")
+	n := strconv.Itoa(st.number())
+	if neg {
+		return "-" + n
+	}
+	return n
+}
+
+// Reads a sequence id in base 36, as in 'S<seq-id>_' and 'T<seq-id>_'. An
+// absent id is 0, and a present one is its value plus one.
+func (st *state) seqID() int {
+	if st.consume("_") {
+		return 0
+	}
+
+	n := 0
+	for {
+		c := st.peek()
+		switch {
+		case isDigit(c):
+			n = n*36 + int(c-'0')
+		case c >= 'A' && c <= 'Z':
+			n = n*36 + int(c-'A') + 10
+		case c == '_':
+			st.pos++
+			return n + 1
+		default:
+			st.fail("invalid sequence id")
+		}
+		st.pos++
+	}
+}
+
+func (st *state) addSub(t typ, last string) {
+	st.subs = append(st.subs, sub{t, last})
+}
+
+// Reads an <encoding>.
+//
+//	<encoding> ::= <name> <bare-function-type>
+//	           ::= <name>
+//	           ::= <special-name>
+func (st *state) encoding() string {
+	return st.encodingOf(true)
+}
+
+// Reads an encoding, and prints the return type of a function template if
+// withReturn is true.
+func (st *state) encodingOf(withReturn bool) string {
+	c := st.peek()
+	if (c == 'T' && st.peekAt(1) != '_' && !isDigit(st.peekAt(1))) || (c == 'G' && st.peekAt(1) != 0) {
+		return st.specialName()
+	}
+
+	n := st.name()
+	if st.pos >= len(st.in) || st.peek() == 'E' || st.peek() == '.' {
+		return n.s
+	}
+
+	var ret string
+	if n.template && !n.noReturn {
+		ret = st.typ().String() + " "
+		if !withReturn {
+			ret = ""
+		}
+	}
+
+	return ret + n.s + "(" + st.paramTypes() + ")" + n.quals
+}
+
+// Returns true at the end of the parameter types of a function, which is the
+// end of the encoding, or the ref-qualifier or end of a function type.
+func (st *state) atParamsEnd() bool {
+	c := st.peek()
+	return st.pos >= len(st.in) || c == 'E' || c == '.' ||
+		((c == 'R' || c == 'O') && st.peekAt(1) == 'E')
+}
+
+// Reads the parameter types of a function.
+func (st *state) paramTypes() string {
+	if st.peek() == 'v' {
+		st.pos++
+		if st.atParamsEnd() {
+			return ""
+		}
+		st.fail("unexpected parameter after void")
+	}
+
+	var params []string
+	for !st.atParamsEnd() {
+		if param := st.typ().String(); param != "" {
+			params = append(params, param)
+		}
+	}
+	return strings.Join(params, ", ")
+}
+
+// Reads a call offset of a thunk.
+func (st *state) callOffset() {
+	switch {
+	case st.consume("h"):
+		st.signedNumber()
+		st.expect("_")
+	case st.consume("v"):
+		st.signedNumber()
+		st.expect("_")
+		st.signedNumber()
+		st.expect("_")
+	default:
+		st.fail("invalid call offset")
+	}
+}
+
+// Reads a <special-name>.
+//
+//	<special-name> ::= TV <type>, TT <type>, TI <type>, TS <type>, Th, Tv, Tc,
+//	                   TH <name>, TW <name>, TC, GV <name>, GR <name>, GTt
+func (st *state) specialName() string {
+	switch {
+	case st.consume("TV"):
+		return "vtable for " + st.typ().String()
+	case st.consume("TT"):
+		return "VTT for " + st.typ().String()
+	case st.consume("TI"):
+		return "typeinfo for " + st.typ().String()
+	case st.consume("TS"):
+		return "typeinfo name for " + st.typ().String()
+	case st.consume("Th"):
+		st.signedNumber()
+		st.expect("_")
+		return "non-virtual thunk to " + st.encoding()
+	case st.consume("Tv"):
+		st.signedNumber()
+		st.expect("_")
+		st.signedNumber()
+		st.expect("_")
+		return "virtual thunk to " + st.encoding()
+	case st.consume("Tc"):
+		st.callOffset()
+		st.callOffset()
+		return "covariant return thunk to " + st.encoding()
+	case st.consume("TH"):
+		return "TLS init function for " + st.name().s
+	case st.consume("TW"):
+		return "TLS wrapper function for " + st.name().s
+	case st.consume("TC"):
+		derived := st.typ().String()
+		st.number()
+		st.expect("_")
+		base := st.typ().String()
+		return "construction vtable for " + base + "-in-" + derived
+	case st.consume("GV"):
+		return "guard variable for " + st.name().s
+	case st.consume("GR"):
+		name := st.name().s
+		n := 0
+		if st.peek() != '_' {
+			n = st.seqID()
+		} else {
+			st.pos++
+		}
+		return fmt.Sprintf("reference temporary #%d for %s", n, name)
+	case st.consume("GTt"):
+		return "transaction clone for " + st.encoding()
+	}
+
+	st.fail("unsupported special name")
+	return ""
+}
+
+// Reads a <name>.
+//
+//	<name> ::= <nested-name>
+//	       ::= <unscoped-name>
+//	       ::= <unscoped-template-name> <template-args>
+//	       ::= <local-name>
+func (st *state) name() nameInfo {
+	switch c := st.peek(); {
+	case c == 'N':
+		return st.nestedName()
+	case c == 'Z':
+		return st.localName()
+	case c == 'S' && st.peekAt(1) == 't':
+		st.pos += 2
+		n := st.unqualifiedName("")
+		n.s = "std::" + n.s
+		return st.maybeTemplate(n)
+	case c == 'S':
+		s := st.substitution()
+		if st.peek() != 'I' {
+			st.fail("expected template arguments after a substitution in a name")
+		}
+		args, _ := st.templateArgs()
+		return nameInfo{s: s.t.String() + args, last: s.last, template: true}
+	}
+
+	return st.maybeTemplate(st.unqualifiedName(""))
+}
+
+// Reads the template arguments of an unscoped template name, if any.
+func (st *state) maybeTemplate(n nameInfo) nameInfo {
+	if st.peek() == 'I' {
+		st.addSub(typ{left: n.s}, n.last)
+		args, _ := st.templateArgs()
+		n.s = appendArgs(n.s, args)
+		n.template = true
+	}
+	return n
+}
+
+// Returns the name followed by its template arguments. Like c++filt, a space
+// separates the arguments from an operator name ending with '<', like
+// 'operator<< <T>'.
+func appendArgs(name, args string) string {
+	if strings.HasSuffix(name, "<") {
+		return name + " " + args
+	}
+	return name + args
+}
+
+// Reads a <nested-name>.
+//
+//	<nested-name> ::= N [<CV-qualifiers>] [<ref-qualifier>] <prefix> <unqualified-name> E
+//	              ::= N [<CV-qualifiers>] [<ref-qualifier>] <template-prefix> <template-args> E
+func (st *state) nestedName() nameInfo {
+	st.expect("N")
+
+	var quals string
+	cv := st.cvQualifiers()
+	if cv != "" {
+		quals = cv
+	}
+	if st.consume("R") {
+		quals += " &"
+	} else if st.consume("O") {
+		quals += " &&"
+	}
+
+	var n nameInfo
+	n.quals = quals
+	first := true
+	for !st.consume("E") {
+		if st.pos >= len(st.in) {
+			st.fail("unterminated nested name")
+		}
+
+		n.template = false
+		c := st.peek()
+		switch {
+		case c == 'S' && st.peekAt(1) == 't':
+			st.pos += 2
+			n.s, n.last = "std", "std"
+			first = false
+			continue
+		case c == 'S':
+			if !first {
+				st.fail("unexpected substitution in a nested name")
+			}
+			s := st.substitution()
+			n.s, n.last = s.t.String(), s.last
+		case c == 'I':
+			if first {
+				st.fail("unexpected template arguments in a nested name")
+			}
+			args, _ := st.templateArgs()
+			n.s = appendArgs(n.s, args)
+			n.template = true
+		case c == 'T':
+			t := st.templateParam()
+			n.s, n.last = t.String(), t.String()
+			st.addSub(t, n.last)
+		default:
+			var prefix string
+			if !first {
+				prefix = n.s + "::"
+			}
+			uq := st.unqualifiedName(n.last)
+			n.s, n.last = prefix+uq.s, uq.last
+			n.noReturn = uq.noReturn
+		}
+		first = false
+
+		if st.peek() != 'E' && c != 'S' && c != 'T' {
+			st.addSub(typ{left: n.s}, n.last)
+		}
+	}
+
+	return n
+}
+
+// Reads a <local-name>.
+//
+//	<local-name> ::= Z <encoding> E <entity name> [<discriminator>]
+//	             ::= Z <encoding> E s [<discriminator>]
+func (st *state) localName() nameInfo {
+	st.expect("Z")
+	// Like c++filt, the return type of the enclosing function is not
+	// printed.
+	enc := st.encodingOf(false)
+	st.expect("E")
+
+	var n nameInfo
+	if st.consume("s") {
+		n = nameInfo{s: enc + "::string literal"}
+	} else {
+		n = st.name()
+		n.s = enc + "::" + n.s
+	}
+
+	// <discriminator> ::= _ <digit> | __ <number> _
+	if st.consume("__") {
+		st.number()
+		st.expect("_")
+	} else if st.peek() == '_' && isDigit(st.peekAt(1)) {
+		st.pos += 2
+	}
+
+	return n
+}
+
+// Reads an unqualified name. The last unqualified name of the enclosing name
+// is the name of constructors and destructors.
+func (st *state) unqualifiedName(enclosing string) nameInfo {
+	var n nameInfo
+
+	c := st.peek()
+	switch {
+	case isDigit(c):
+		n.s = st.sourceName()
+		n.last = n.s
+	case c == 'C' && (isDigit(st.peekAt(1)) || st.peekAt(1) == 'I'):
+		st.pos++
+		if st.consume("I") {
+			// An inheriting constructor names its base class.
+			st.pos++
+			st.typ()
+		} else {
+			st.pos++
+		}
+		n.s, n.last, n.noReturn = enclosing, enclosing, true
+	case c == 'D' && isDigit(st.peekAt(1)):
+		st.pos += 2
+		n.s, n.last, n.noReturn = "~"+enclosing, enclosing, true
+	case c == 'U' && st.peekAt(1) == 't':
+		st.pos += 2
+		n.s = fmt.Sprintf("{unnamed type#%d}", st.discriminatorNumber())
+	case c == 'U' && st.peekAt(1) == 'l':
+		st.pos += 2
+		var params []string
+		for !st.consume("E") {
+			if st.pos >= len(st.in) {
+				st.fail("unterminated lambda")
+			}
+			t := st.typ().String()
+			if t != "void" {
+				params = append(params, t)
+			}
+		}
+		n.s = fmt.Sprintf("{lambda(%s)#%d}", strings.Join(params, ", "), st.discriminatorNumber())
+	case c == 'L':
+		// A name with internal linkage.
+		st.pos++
+		n.s = st.sourceName()
+		n.last = n.s
+	case isLower(c):
+		n = st.operatorName()
+	default:
+		st.fail("unsupported unqualified name")
+	}
+
+	// <abi-tag> ::= B <source-name>
+	for st.peek() == 'B' {
+		st.pos++
+		n.s += "[abi:" + st.sourceName() + "]"
+	}
+
+	return n
+}
+
+// Reads the number of an unnamed type or a lambda, '[<number>] _', and returns
+// it counting from 1.
+func (st *state) discriminatorNumber() int {
+	if st.consume("_") {
+		return 1
+	}
+	n := st.number()
+	st.expect("_")
+	return n + 2
+}
+
+// <source-name> ::= <positive length number> <identifier>
+func (st *state) sourceName() string {
+	n := st.number()
+	if n <= 0 || st.pos+n > len(st.in) {
+		st.fail("invalid source name length")
+	}
+
+	s := st.in[st.pos : st.pos+n]
+	st.pos += n
+	if strings.HasPrefix(s, "_GLOBAL_") && len(s) > 9 && strings.ContainsAny(s[8:9], "._$") &&
+		s[9] == 'N' {
+		return "(anonymous namespace)"
+	}
+	return s
+}
+
+var operators = map[string]string{
+	"nw": "new", "na": "new[]", "dl": "delete", "da": "delete[]",
+	"ps": "+", "ng": "-", "ad": "&", "de": "*", "co": "~",
+	"pl": "+", "mi": "-", "ml": "*", "dv": "/", "rm": "%", "an": "&", "or": "|", "eo": "^",
+	"aS": "=", "pL": "+=", "mI": "-=", "mL": "*=", "dV": "/=", "rM": "%=", "aN": "&=",
+	"oR": "|=", "eO": "^=", "ls": "<<", "rs": ">>", "lS": "<<=", "rS": ">>=",
+	"eq": "==", "ne": "!=", "lt": "<", "gt": ">", "le": "<=", "ge": ">=", "ss": "<=>",
+	"nt": "!", "aa": "&&", "oo": "||", "pp": "++", "mm": "--", "cm": ",", "pm": "->*",
+	"pt": "->", "cl": "()", "ix": "[]", "qu": "?", "aw": "co_await",
+}
+
+// Reads an operator name, like 'pl' for 'operator+'.
+func (st *state) operatorName() nameInfo {
+	if st.pos+2 > len(st.in) {
+		st.fail("truncated operator name")
+	}
+	code := st.in[st.pos : st.pos+2]
+	st.pos += 2
+
+	switch {
+	case code == "cv":
+		return nameInfo{s: "operator " + st.typ().String(), noReturn: true}
+	case code == "li":
+		return nameInfo{s: "operator\"\" " + st.sourceName()}
+	case code[0] == 'v' && isDigit(code[1]):
+		return nameInfo{s: "operator " + st.sourceName()}
+	}
+
+	op, exists := operators[code]
+	if !exists {
+		st.fail("unknown operator '%s'", code)
+	}
+	if isLower(op[0]) {
+		return nameInfo{s: "operator " + op}
+	}
+	return nameInfo{s: "operator" + op}
+}
+
+// The standard substitutions other than 'St', with their unqualified names.
+// Like c++filt, the full names are used rather than the typedefs, like
+// 'std::string'.
+var stdSubs = map[byte][2]string{
+	'a': {"std::allocator", "allocator"},
+	'b': {"std::basic_string", "basic_string"},
+	's': {"std::basic_string<char, std::char_traits<char>, std::allocator<char> >", "basic_string"},
+	'i': {"std::basic_istream<char, std::char_traits<char> >", "basic_istream"},
+	'o': {"std::basic_ostream<char, std::char_traits<char> >", "basic_ostream"},
+	'd': {"std::basic_iostream<char, std::char_traits<char> >", "basic_iostream"},
+}
+
+// <substitution> ::= S <seq-id> _ | S_ | Sa | Sb | Ss | Si | So | Sd
+func (st *state) substitution() sub {
+	st.expect("S")
+
+	if std, exists := stdSubs[st.peek()]; exists {
+		st.pos++
+		return sub{typ{left: std[0]}, std[1]}
+	}
+
+	i := st.seqID()
+	if i >= len(st.subs) {
+		st.fail("invalid substitution index %d", i)
+	}
+	return st.subs[i]
+}
+
+// <template-param> ::= T_ | T <number> _
+func (st *state) templateParam() typ {
+	st.expect("T")
+	i := st.seqID()
+	if i >= len(st.tmplArgs) {
+		st.fail("invalid template parameter index %d", i)
+	}
+	return st.tmplArgs[i]
+}
+
+// <template-args> ::= I <template-arg>+ E
+func (st *state) templateArgs() (string, []typ) {
+	st.expect("I")
+
+	var args []typ
+	var strs []string
+	for !st.consume("E") {
+		if st.pos >= len(st.in) {
+			st.fail("unterminated template arguments")
+		}
+		arg := st.templateArg()
+		args = append(args, arg)
+		if s := arg.String(); s != "" {
+			strs = append(strs, s)
+		}
+	}
+	if st.typeDepth == 0 {
+		st.tmplArgs = args
+	}
+
+	// Like c++filt, a space separates the closing brackets of nested
+	// template arguments, unless the last argument is an empty pack.
+	s := strings.Join(strs, ", ")
+	if len(args) > 0 && strings.HasSuffix(args[len(args)-1].String(), ">") {
+		s += " "
+	}
+	return "<" + s + ">", args
+}
+
+// <template-arg> ::= <type> | <expr-primary> | J <template-arg>* E
+func (st *state) templateArg() typ {
+	switch st.peek() {
+	case 'L':
+		return typ{left: st.exprPrimary()}
+	case 'J':
+		st.pos++
+		var packElems []typ
+		for !st.consume("E") {
+			if st.pos >= len(st.in) {
+				st.fail("unterminated argument pack")
+			}
+			packElems = append(packElems, st.templateArg())
+		}
+		return typ{pack: packElems, isPack: true}
+	case 'X':
+		st.fail("expressions are not supported")
+	}
+
+	st.typeDepth++
+	defer func() { st.typeDepth-- }()
+	return st.typ()
+}
+
+// Literal suffixes of integer types.
+var literalSuffixes = map[string]string{
+	"int": "", "unsigned int": "u", "long": "l", "unsigned long": "ul",
+	"long long": "ll", "unsigned long long": "ull",
+}
+
+// Reads an <expr-primary>.
+//
+//	<expr-primary> ::= L <type> <value number> E
+//	               ::= L <mangled-name> E
+func (st *state) exprPrimary() string {
+	st.expect("L")
+
+	if st.consume("_Z") {
+		enc := st.encoding()
+		st.expect("E")
+		return enc
+	}
+
+	st.typeDepth++
+	t := st.typ().String()
+	st.typeDepth--
+
+	start := st.pos
+	for st.peek() != 'E' {
+		if st.pos >= len(st.in) {
+			st.fail("unterminated literal")
+		}
+		st.pos++
+	}
+	value := st.in[start:st.pos]
+	st.pos++
+	if strings.HasPrefix(value, "n") {
+		value = "-" + value[1:]
+	}
+
+	if t == "bool" && value == "0" {
+		return "false"
+	} else if t == "bool" && value == "1" {
+		return "true"
+	} else if suffix, exists := literalSuffixes[t]; exists {
+		return value + suffix
+	}
+	return "(" + t + ")" + value
+}
+
+var builtinTypes = map[byte]string{
+	'v': "void", 'w': "wchar_t", 'b': "bool", 'c': "char", 'a': "signed char",
+	'h': "unsigned char", 's': "short", 't': "unsigned short", 'i': "int",
+	'j': "unsigned int", 'l': "long", 'm': "unsigned long", 'x': "long long",
+	'y': "unsigned long long", 'n': "__int128", 'o': "unsigned __int128",
+	'f': "float", 'd': "double", 'e': "long double", 'g': "__float128", 'z': "...",
+}
+
+var builtinDTypes = map[byte]string{
+	'd': "decimal64", 'e': "decimal128", 'f': "decimal32", 'h': "half",
+	'i': "char32_t", 's': "char16_t", 'u': "char8_t", 'a': "auto",
+	'c': "decltype(auto)", 'n': "decltype(nullptr)",
+}
+
+// Reads CV-qualifiers and returns them in the order in which they are printed.
+func (st *state) cvQualifiers() string {
+	var quals string
+	restrict := st.consume("r")
+	volatile := st.consume("V")
+	if st.consume("K") {
+		quals += " const"
+	}
+	if volatile {
+		quals += " volatile"
+	}
+	if restrict {
+		quals += " restrict"
+	}
+	return quals
+}
+
+// Returns the type derived from t with a declarator, like '*'.
+func derive(t typ, declarator string) typ {
+	return mapPack(t, func(t typ) typ {
+		if t.wrap {
+			left := t.left
+			if !strings.HasSuffix(left, " ") {
+				left += " "
+			}
+			return typ{left: left + "(" + declarator, right: ")" + t.right}
+		}
+		return typ{left: t.left + declarator, right: t.right}
+	})
+}
+
+// Returns the reference type of kind ref to t. A reference to a reference is
+// an lvalue reference, unless both are rvalue references.
+func reference(t typ, ref refKind) typ {
+	return mapPack(t, func(t typ) typ {
+		if t.ref == refLValue || (t.ref == refRValue && ref == refRValue) {
+			return t
+		}
+		if t.ref == refRValue {
+			t.left = strings.TrimSuffix(t.left, "&")
+			return t
+		}
+
+		declarator := "&"
+		if ref == refRValue {
+			declarator = "&&"
+		}
+		r := derive(t, declarator)
+		r.ref = ref
+		return r
+	})
+}
+
+// Reads a <type>.
+//
+//	<type> ::= <builtin-type> | <qualified-type> | <function-type>
+//	       ::= <class-enum-type> | <array-type> | <pointer-to-member-type>
+//	       ::= <template-param> | <template-template-param> <template-args>
+//	       ::= <substitution> | P <type> | R <type> | O <type> | C <type>
+//	       ::= G <type> | Dp <type>
+func (st *state) typ() typ {
+	st.typeDepth++
+	defer func() { st.typeDepth-- }()
+
+	c := st.peek()
+	if b, exists := builtinTypes[c]; exists {
+		st.pos++
+		return typ{left: b}
+	}
+
+	var t typ
+	last := ""
+	switch c {
+	case 'u':
+		st.pos++
+		t = typ{left: st.sourceName()}
+	case 'D':
+		d := st.peekAt(1)
+		if b, exists := builtinDTypes[d]; exists {
+			st.pos += 2
+			return typ{left: b}
+		}
+		switch d {
+		case 'p':
+			st.pos += 2
+			t = st.typ()
+		case 'F':
+			st.pos += 2
+			n := st.number()
+			st.expect("_")
+			return typ{left: fmt.Sprintf("_Float%d", n)}
+		default:
+			st.fail("unsupported type")
+		}
+	case 'r', 'V', 'K':
+		quals := st.cvQualifiers()
+		t = mapPack(st.typ(), func(inner typ) typ {
+			if inner.fn {
+				inner.right += quals
+				return inner
+			}
+			// Like c++filt, qualifiers which a template argument already
+			// has are not repeated.
+			if strings.HasSuffix(inner.left, quals) {
+				return inner
+			}
+			return typ{left: inner.left + quals, right: inner.right, wrap: inner.wrap}
+		})
+	case 'P':
+		st.pos++
+		t = derive(st.typ(), "*")
+	case 'R':
+		st.pos++
+		t = reference(st.typ(), refLValue)
+	case 'O':
+		st.pos++
+		t = reference(st.typ(), refRValue)
+	case 'C':
+		st.pos++
+		t = derive(st.typ(), " _Complex")
+	case 'G':
+		st.pos++
+		t = derive(st.typ(), " _Imaginary")
+	case 'F':
+		st.pos++
+		st.consume("Y")
+		ret := st.typ()
+		params := st.paramTypes()
+		refQual := ""
+		if st.consume("R") {
+			refQual = " &"
+		} else if st.consume("O") {
+			refQual = " &&"
+		}
+		st.expect("E")
+		t = typ{left: ret.String() + " ", right: "(" + params + ")" + refQual, wrap: true, fn: true}
+	case 'A':
+		st.pos++
+		dim := ""
+		if isDigit(st.peek()) {
+			dim = strconv.Itoa(st.number())
+		}
+		st.expect("_")
+		elem := st.typ()
+		if strings.HasPrefix(elem.right, " [") {
+			t = typ{left: elem.left, right: " [" + dim + "]" + elem.right[1:], wrap: true}
+		} else {
+			t = typ{left: elem.left, right: " [" + dim + "]" + elem.right, wrap: true}
+		}
+	case 'M':
+		st.pos++
+		class := st.typ().String()
+		qualified := strings.IndexByte("rVK", st.peek()) >= 0
+		member := st.typ()
+		if qualified && member.fn {
+			// Only the unqualified type of a member function is a
+			// substitution candidate.
+			st.subs = st.subs[:len(st.subs)-1]
+		}
+		if member.wrap {
+			t = derive(member, class+"::*")
+		} else {
+			t = typ{left: member.left + " " + class + "::*", right: member.right}
+		}
+	case 'T':
+		t = st.templateParam()
+		if st.peek() == 'I' {
+			st.addSub(t, "")
+			args, _ := st.templateArgs()
+			t = typ{left: t.String() + args}
+		}
+	case 'S':
+		if st.peekAt(1) == 't' {
+			n := st.name()
+			t, last = typ{left: n.s}, n.last
+			break
+		}
+
+		s := st.substitution()
+		if st.peek() != 'I' {
+			return s.t
+		}
+		args, _ := st.templateArgs()
+		t, last = typ{left: s.t.String() + args}, s.last
+	case 'N', 'Z', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		n := st.name()
+		t, last = typ{left: n.s}, n.last
+	default:
+		st.fail("unsupported type")
+	}
+
+	st.addSub(t, last)
+	return t
+}
+
+// Returns the demangled form of the suffixes which GCC appends to the names of
+// clones of functions, like ' [clone .isra.0]' for '.isra.0'.
+func cloneSuffix(st *state, suffix string) string {
+	var result string
+	for suffix != "" {
+		if suffix[0] != '.' || len(suffix) == 1 {
+			st.fail("unexpected trailing characters")
+		}
+
+		// A clone is a '.' followed by a name, and then by numbers like
+		// '.0' which are part of the same clone.
+		end := strings.IndexByte(suffix[1:], '.') + 1
+		if end == 0 {
+			end = len(suffix)
+		}
+		for end < len(suffix) && end+1 < len(suffix) && isDigit(suffix[end+1]) {
+			next := strings.IndexByte(suffix[end+1:], '.')
+			if next < 0 {
+				end = len(suffix)
+			} else {
+				end += next + 1
+			}
+		}
+
+		result += " [clone " + suffix[:end] + "]"
+		suffix = suffix[end:]
+	}
+
+	return result
+}
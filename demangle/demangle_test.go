@@ -0,0 +1,84 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package demangle
+
+import (
+	"testing"
+)
+
+func TestDemangle(t *testing.T) {
+	// The expected names are those printed by c++filt.
+	cases := []struct {
+		mangled   string
+		demangled string
+	}{
+		{"_Z3fooPKc", "foo(char const*)"},
+		{"_ZNK1A1fEv", "A::f() const"},
+		{"_ZN1AC2Ev", "A::A()"},
+		{"_ZN1AD0Ev", "A::~A()"},
+		{"_ZN12_GLOBAL__N_11fEv", "(anonymous namespace)::f()"},
+		{"_ZN9__gnu_cxx13new_allocatorIcED2Ev", "__gnu_cxx::new_allocator<char>::~new_allocator()"},
+		{"_ZNSt6vectorIiSaIiEE9push_backERKi",
+			"std::vector<int, std::allocator<int> >::push_back(int const&)"},
+		{"_ZNKSs4findERKSsm",
+			"std::basic_string<char, std::char_traits<char>, std::allocator<char> >::find(" +
+				"std::basic_string<char, std::char_traits<char>, std::allocator<char> > const&, " +
+				"unsigned long) const"},
+		{"_ZN4llvmlsINS_10BasicBlockEEERNS_11raw_ostreamES3_PKNS_15DomTreeNodeBaseIT_EE",
+			"llvm::raw_ostream& llvm::operator<< <llvm::BasicBlock>(llvm::raw_ostream&, " +
+				"llvm::DomTreeNodeBase<llvm::BasicBlock> const*)"},
+		{"_Z1fPFviEPA10_i", "f(void (*)(int), int (*) [10])"},
+		{"_Z1fM1AKFivE", "f(int (A::*)() const)"},
+		{"_Z1fIJiRcEEvDpOT_", "void f<int, char&>(int&&, char&)"},
+		{"_Z1fILi3EEvv", "void f<3>()"},
+		{"_Z1fIJEEvv", "void f<>()"},
+		{"_Z1fDn", "f(decltype(nullptr))"},
+		{"_ZZ4mainE1x", "main::x"},
+		{"_ZZNSt8__detail18__to_chars_10_implIjEEvPcjT_E8__digits",
+			"std::__detail::__to_chars_10_impl<unsigned int>(char*, unsigned int, unsigned int)::__digits"},
+		{"_ZTVN4llvm11raw_ostreamE", "vtable for llvm::raw_ostream"},
+		{"_ZTSSt9exception", "typeinfo name for std::exception"},
+		{"_ZGVZ4mainE1x", "guard variable for main::x"},
+		{"_ZN1A1fEv.isra.0", "A::f() [clone .isra.0]"},
+	}
+
+	for _, c := range cases {
+		demangled, err := Demangle(c.mangled)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if demangled != c.demangled {
+			t.Errorf("Wrong demangled form of '%s'.\nExpected: %s\nGot: %s",
+				c.mangled, c.demangled, demangled)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	// Names which are not mangled, are malformed, or use expressions are
+	// returned as they are.
+	for _, name := range []string{"main", "_Z", "_ZN1A", "_Z1fIXadL_Z1gvEEEvv"} {
+		if s := Filter(name); s != name {
+			t.Errorf("Name '%s' was filtered to '%s'.", name, s)
+		}
+	}
+
+	if s := Filter("_Z1fv"); s != "f()" {
+		t.Errorf("Wrong filtered name: %s", s)
+	}
+}
@@ -0,0 +1,132 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package deps
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The path of the configuration file of the dynamic loader.
+const DefaultConfFile = "/etc/ld.so.conf"
+
+// Returns the directories listed in the ld.so.conf file fileName, in the
+// order in which they are listed, and with the directories of included files
+// in place of the include directives. The file name, the included patterns
+// and the returned directories are paths under sysroot; an empty sysroot is
+// the root directory. Relative include patterns are relative to the directory
+// of the including file, as with ldconfig. The 'hwcap' directives are
+// ignored.
+func ParseConf(sysroot, fileName string) ([]string, error) {
+	dirs := []string{}
+	err := parseConf(sysroot, fileName, make(map[string]bool), &dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	return dirs, nil
+}
+
+// Appends the directories listed in the ld.so.conf file fileName to dirs.
+// Files already in seen are not parsed again, so that include cycles end.
+func parseConf(sysroot, fileName string, seen map[string]bool, dirs *[]string) error {
+	fileName = filepath.Clean(fileName)
+	if seen[fileName] {
+		return nil
+	}
+	seen[fileName] = true
+
+	file, err := os.Open(hostPath(sysroot, fileName))
+	if err != nil {
+		return fmt.Errorf("Unable to open '%s'.\n%s", fileName, err.Error())
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		if comment := strings.IndexByte(line, '#'); comment >= 0 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "include":
+			if len(fields) == 1 {
+				return fmt.Errorf("Missing pattern of include in '%s' line %d.", fileName, lineNum)
+			}
+			for _, pattern := range fields[1:] {
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(filepath.Dir(fileName), pattern)
+				}
+				err = parseConfInclude(sysroot, pattern, seen, dirs)
+				if err != nil {
+					return fmt.Errorf(
+						"Error including '%s' in '%s' line %d.\n%s",
+						pattern, fileName, lineNum, err.Error())
+				}
+			}
+		case "hwcap":
+		default:
+			// Old configuration files can give the type of the libraries in a
+			// directory as in '/usr/lib/libc5=libc5'.
+			dir := strings.TrimSpace(line)
+			if eq := strings.IndexByte(dir, '='); eq >= 0 {
+				dir = dir[:eq]
+			}
+			*dirs = append(*dirs, filepath.Clean(dir))
+		}
+	}
+	err = scanner.Err()
+	if err != nil {
+		return fmt.Errorf("Error reading '%s'.\n%s", fileName, err.Error())
+	}
+
+	return nil
+}
+
+// Parses the files matching the glob pattern, which is a path under sysroot,
+// in the sorted order of their names.
+func parseConfInclude(sysroot, pattern string, seen map[string]bool, dirs *[]string) error {
+	matches, err := filepath.Glob(hostPath(sysroot, pattern))
+	if err != nil {
+		return err
+	}
+
+	for _, match := range matches {
+		fileName := match
+		if sysroot != "" {
+			rel, err := filepath.Rel(sysroot, match)
+			if err != nil {
+				return err
+			}
+			fileName = "/" + rel
+		}
+		err = parseConf(sysroot, fileName, seen, dirs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
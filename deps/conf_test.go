@@ -0,0 +1,79 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package deps
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseConf(t *testing.T) {
+	dirs, err := ParseConf("test_data/sysroot", DefaultConfFile)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if !reflect.DeepEqual(dirs, []string{"/opt/conf/lib"}) {
+		t.Errorf("Wrong directories of ld.so.conf: %q", dirs)
+	}
+}
+
+func TestParseConfIncludes(t *testing.T) {
+	root, err := ioutil.TempDir("", "deps")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		"ld.so.conf": "/usr/local/lib # Local libraries\n" +
+			"include conf.d/*.conf\n" +
+			"hwcap 0 nosegneg\n" +
+			"/usr/lib/libc5=libc5\n",
+		"conf.d/a.conf": "/opt/a/lib/\n\ninclude /ld.so.conf\n",
+		"conf.d/b.conf": "  /opt/b/lib\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err = ioutil.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	dirs, err := ParseConf(root, "/ld.so.conf")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	expected := []string{"/usr/local/lib", "/opt/a/lib", "/opt/b/lib", "/usr/lib/libc5"}
+	if !reflect.DeepEqual(dirs, expected) {
+		t.Errorf("Wrong directories of ld.so.conf: %q", dirs)
+		return
+	}
+
+	_, err = ParseConf(root, "/missing.conf")
+	if err == nil {
+		t.Errorf("Missing ld.so.conf was parsed.")
+	}
+}
@@ -0,0 +1,599 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Package deps resolves the shared library dependencies of ELF executables
+// and shared libraries the way the GNU dynamic loader does, like ldd, but by
+// reading the files with golf instead of running them.
+package deps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+import (
+	"eureka/golf"
+)
+
+// Source values denote how the file of a dependency was found.
+type Source string
+
+const (
+	// The needed name contains a '/' and is the path of the file.
+	SourcePath Source = "path"
+
+	// An object already loaded has the needed name as its SONAME or was
+	// loaded by the same name.
+	SourceLoaded Source = "loaded"
+
+	// The file was found in a directory of the DT_RPATH entry of the object
+	// needing it, or of one of the objects which caused that object to be
+	// loaded.
+	SourceRPath Source = "DT_RPATH"
+
+	// The file was found in a directory of the library path of the
+	// resolver, which is the LD_LIBRARY_PATH of the dynamic loader.
+	SourceLibraryPath Source = "LD_LIBRARY_PATH"
+
+	// The file was found in a directory of the DT_RUNPATH entry of the
+	// object needing it.
+	SourceRunPath Source = "DT_RUNPATH"
+
+	// The file was found in a directory listed in ld.so.conf.
+	SourceConf Source = "ld.so.conf"
+
+	// The file was found in a default directory of the dynamic loader.
+	SourceDefault Source = "default"
+)
+
+// Object is an executable or shared library in a dependency graph.
+type Object struct {
+	// The path of the file. It includes the sysroot of the resolver.
+	Path string
+
+	// The absolute path of the file with its symbolic links resolved. Links
+	// under the sysroot are resolved within the sysroot. See realPath.
+	File string
+
+	// The DT_NEEDED name by which the object was first loaded. It is empty
+	// for the root of the graph.
+	Name string
+
+	SOName string
+
+	// The search paths in the DT_RPATH and DT_RUNPATH entries, before
+	// expanding $ORIGIN.
+	RPath   []string
+	RunPath []string
+
+	// The program interpreter named by the PT_INTERP segment, if any.
+	Interp string
+
+	// The dependencies in the order of the DT_NEEDED entries.
+	Needed []*Edge
+
+	// The object whose dependency caused this object to be loaded. It is nil
+	// for the root of the graph.
+	Loader *Object
+
+	fileType  golf.ELFType
+	class     golf.ELFClass
+	endianess golf.ELFEndianess
+	machine   golf.MachineArch
+	noDefLib  bool
+	needed    []string
+}
+
+// Edge is a dependency of an object on a DT_NEEDED name.
+type Edge struct {
+	Name string
+	From *Object
+
+	// The object to which the name resolved, or nil if the name could not be
+	// resolved.
+	To *Object
+
+	// How the file of To was found.
+	Source Source
+
+	// The directories searched for the file, in the order in which they were
+	// searched. They include the sysroot of the resolver.
+	Searched []string
+
+	// Files which were found but skipped, with the reasons for skipping
+	// them. The dynamic loader skips files of other machines for example.
+	Skipped []string
+
+	// The reason the name could not be resolved. It is empty if To is not
+	// nil.
+	Missing string
+}
+
+// Graph is the dependency graph of an executable or shared library.
+type Graph struct {
+	Root *Object
+
+	// The objects of the graph in the order in which the dynamic loader loads
+	// them, which is breadth first starting with Root. It is also the order
+	// in which the dynamic loader looks up symbols.
+	Objects []*Object
+
+	// The program interpreter of Root, if it could be read. The dynamic
+	// loader is loaded before the dependencies, so that dependencies on its
+	// SONAME resolve to it. It is in Objects only if it is a dependency.
+	Interp *Object
+}
+
+// Returns the edges of the graph which could not be resolved, in the order
+// of the objects needing them.
+func (graph *Graph) Missing() []*Edge {
+	var missing []*Edge
+	for _, obj := range graph.Objects {
+		for _, edge := range obj.Needed {
+			if edge.To == nil {
+				missing = append(missing, edge)
+			}
+		}
+	}
+
+	return missing
+}
+
+// Resolver resolves dependencies like the GNU dynamic loader. The search
+// paths of the resolver are paths under Sysroot.
+type Resolver struct {
+	// The directory under which the files are looked up. An empty sysroot is
+	// the root directory.
+	Sysroot string
+
+	// The directories searched like those of LD_LIBRARY_PATH.
+	LibraryPath []string
+
+	// The directories listed in ld.so.conf. The dynamic loader searches them
+	// through the cache built by ldconfig, which is not read by the
+	// resolver.
+	ConfPaths []string
+
+	// The default directories of the dynamic loader, searched last. If nil,
+	// '/lib' and '/usr/lib' are searched, preceded by '/lib64' and
+	// '/usr/lib64' for 64-bit files.
+	DefaultPaths []string
+}
+
+// Reads the directories listed in the ld.so.conf file fileName, a path under
+// the sysroot, into ConfPaths.
+func (r *Resolver) LoadConf(fileName string) error {
+	dirs, err := ParseConf(r.Sysroot, fileName)
+	if err != nil {
+		return err
+	}
+
+	r.ConfPaths = dirs
+	return nil
+}
+
+// Reads the directories listed in the default ld.so.conf under the sysroot
+// into ConfPaths, if the sysroot has such a file.
+func (r *Resolver) LoadDefaultConf() error {
+	if _, err := os.Stat(hostPath(r.Sysroot, DefaultConfFile)); os.IsNotExist(err) {
+		return nil
+	}
+
+	return r.LoadConf(DefaultConfFile)
+}
+
+// The number of symbolic links followed in a path before giving up, like the
+// limit of Linux.
+const maxSymlinks = 40
+
+// Returns the path of the file at path under sysroot. Relative paths are
+// relative to the current directory and not to sysroot.
+func hostPath(sysroot, path string) string {
+	if sysroot == "" || !filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(sysroot, path)
+}
+
+// Splits a search path string, like the value of LD_LIBRARY_PATH, into its
+// paths. An empty string has no paths.
+func SplitSearchPath(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, ":")
+}
+
+// Returns the dependency graph of the executable or shared library fileName.
+// An error is returned only if the file itself cannot be read; dependencies
+// which cannot be resolved are edges of the graph without a target.
+func (r *Resolver) Resolve(fileName string) (*Graph, error) {
+	file, err := realPath(r.Sysroot, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read '%s'.\n%s", fileName, err.Error())
+	}
+	root, err := readObject(fileName, file)
+	if err != nil {
+		return nil, err
+	}
+	if root.fileType != golf.TypeExecutable && root.fileType != golf.TypeShared {
+		return nil, fmt.Errorf("'%s' is not an executable or a shared library.", fileName)
+	}
+
+	res := &resolution{
+		resolver: r,
+		graph:    &Graph{Root: root, Objects: []*Object{root}},
+		byName:   make(map[string]*Object),
+		byFile:   make(map[string]*Object),
+	}
+	res.register(root)
+	if root.Interp != "" {
+		path := hostPath(r.Sysroot, root.Interp)
+		var interp *Object
+		file, err := realPath(r.Sysroot, path)
+		if err == nil {
+			interp, err = readObject(path, file)
+		}
+		if err == nil && interp.fileType == golf.TypeShared &&
+			incompatibility(root, interp) == "" {
+			interp.Name = root.Interp
+			res.register(interp)
+			res.graph.Interp = interp
+		}
+	}
+
+	// The objects appended while walking the list are walked too, which makes
+	// the walk breadth first.
+	for i := 0; i < len(res.graph.Objects); i++ {
+		obj := res.graph.Objects[i]
+		for _, name := range obj.needed {
+			obj.Needed = append(obj.Needed, res.resolve(obj, name))
+		}
+	}
+
+	return res.graph, nil
+}
+
+// Reads the parts of the ELF file at path needed to resolve its
+// dependencies. The file is read from file, the path with its symbolic links
+// resolved.
+func readObject(fileName, file string) (*Object, error) {
+	elf, err := golf.Read(file)
+	if err != nil {
+		return nil, err
+	}
+	defer elf.Close()
+
+	ident := elf.Header().ELFIdent()
+	obj := &Object{
+		Path:      fileName,
+		File:      file,
+		fileType:  elf.Header().Type(),
+		class:     ident.Class,
+		endianess: ident.Endianess,
+		machine:   elf.Header().Machine(),
+	}
+
+	obj.Interp, err = elf.Interpreter()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading '%s'.\n%s", fileName, err.Error())
+	}
+
+	dyn, err := elf.Dynamic()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Error reading the dynamic section of '%s'.\n%s", fileName, err.Error())
+	}
+	if dyn == nil {
+		return obj, nil
+	}
+
+	obj.SOName = dyn.SOName
+	obj.RPath = SplitSearchPath(dyn.RPath)
+	obj.RunPath = SplitSearchPath(dyn.RunPath)
+	obj.noDefLib = dyn.Flags1&golf.DynFlag1NoDefLib != 0
+	obj.needed = dyn.Needed
+
+	return obj, nil
+}
+
+// resolution holds the state of resolving the dependencies of a file.
+type resolution struct {
+	resolver *Resolver
+	graph    *Graph
+
+	// The loaded objects by the names by which they were loaded and by their
+	// SONAMEs, and by the paths of their files with symbolic links resolved.
+	byName map[string]*Object
+	byFile map[string]*Object
+}
+
+// Records the names and the file of a loaded object.
+func (res *resolution) register(obj *Object) {
+	if obj.Name != "" {
+		res.byName[obj.Name] = obj
+	}
+	if obj.SOName != "" {
+		res.byName[obj.SOName] = obj
+	}
+	res.byFile[obj.File] = obj
+}
+
+// Returns the absolute path of the file at path with its symbolic links
+// resolved, so that the same file is loaded only once through different
+// links to it. The links of a path under the sysroot are resolved as if the
+// sysroot were the root directory: absolute targets are paths under the
+// sysroot, and '..' in the sysroot is the sysroot itself. The links of other
+// paths are resolved as usual. An error for which os.IsNotExist is true is
+// returned if the file does not exist.
+func realPath(sysroot, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	root := "/"
+	if sysroot != "" {
+		if absRoot, err := filepath.Abs(sysroot); err == nil {
+			rel, err := filepath.Rel(absRoot, abs)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+				root, abs = absRoot, "/"+rel
+			}
+		}
+	}
+
+	// The path walked so far, which does not have links, and the components
+	// left to walk.
+	resolved := "/"
+	remaining := strings.Split(abs, "/")
+	links := 0
+	for len(remaining) > 0 {
+		name := remaining[0]
+		remaining = remaining[1:]
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, name)
+		info, err := os.Lstat(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", fmt.Errorf("Too many levels of symbolic links in '%s'.", path)
+		}
+		target, err := os.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			resolved = "/"
+		}
+		remaining = append(strings.Split(target, "/"), remaining...)
+	}
+
+	return filepath.Join(root, resolved), nil
+}
+
+// searchDir is a directory to search for dependencies, and the source of the
+// files found in it.
+type searchDir struct {
+	path   string
+	source Source
+}
+
+// Returns the edge of the dependency of obj on the DT_NEEDED name.
+func (res *resolution) resolve(obj *Object, name string) *Edge {
+	edge := &Edge{Name: name, From: obj}
+	if loaded, ok := res.byName[name]; ok {
+		edge.To, edge.Source = loaded, SourceLoaded
+		res.addInterp(obj, loaded)
+		return edge
+	}
+
+	if strings.Contains(name, "/") {
+		path := hostPath(res.resolver.Sysroot, name)
+		edge.To, edge.Missing = res.load(obj, name, path)
+		switch {
+		case edge.To != nil:
+			edge.Source = SourcePath
+		case edge.Missing == "":
+			edge.Missing = fmt.Sprintf("'%s' does not exist.", path)
+		}
+		return edge
+	}
+
+	for _, dir := range res.searchDirs(obj) {
+		edge.Searched = append(edge.Searched, dir.path)
+		to, skipped := res.load(obj, name, filepath.Join(dir.path, name))
+		if to != nil {
+			edge.To, edge.Source = to, dir.source
+			return edge
+		}
+		if skipped != "" {
+			edge.Skipped = append(edge.Skipped, skipped)
+		}
+	}
+
+	edge.Missing = fmt.Sprintf("'%s' was not found in any of the search paths.", name)
+	return edge
+}
+
+// Returns the object of the file at path, reading it unless it is already
+// loaded. If the file does not exist, then nil is returned with an empty
+// string. If the file cannot be loaded as a dependency of obj, then nil is
+// returned with the reason.
+func (res *resolution) load(obj *Object, name, path string) (*Object, string) {
+	file, err := realPath(res.resolver.Sysroot, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ""
+		}
+		return nil, fmt.Sprintf("'%s': %s", path, err.Error())
+	}
+
+	if loaded, ok := res.byFile[file]; ok {
+		res.byName[name] = loaded
+		res.addInterp(obj, loaded)
+		return loaded, ""
+	}
+
+	dep, err := readObject(path, file)
+	if err != nil {
+		return nil, strings.Replace(err.Error(), "\n", " ", -1)
+	}
+	if dep.fileType != golf.TypeShared {
+		return nil, fmt.Sprintf("'%s' is not a shared library.", path)
+	}
+	if reason := incompatibility(res.graph.Root, dep); reason != "" {
+		return nil, fmt.Sprintf("'%s' is incompatible: %s", path, reason)
+	}
+
+	dep.Name, dep.Loader = name, obj
+	res.register(dep)
+	res.graph.Objects = append(res.graph.Objects, dep)
+	return dep, ""
+}
+
+// Adds the program interpreter to the objects of the graph when it is first
+// found to be a dependency of obj.
+func (res *resolution) addInterp(obj, dep *Object) {
+	if dep == res.graph.Interp && dep.Loader == nil {
+		dep.Loader = obj
+		res.graph.Objects = append(res.graph.Objects, dep)
+	}
+}
+
+// Returns why the dependency cannot be loaded in the process of root, or an
+// empty string if it can be.
+func incompatibility(root, dep *Object) string {
+	switch {
+	case dep.class != root.class:
+		return fmt.Sprintf("%d-bit file, expected %d-bit.", bits(dep.class), bits(root.class))
+	case dep.endianess != root.endianess:
+		return fmt.Sprintf(
+			"%s endian file, expected %s endian.",
+			endianessStr(dep.endianess), endianessStr(root.endianess))
+	case dep.machine != root.machine:
+		return fmt.Sprintf("machine %s, expected %s.", dep.machine, root.machine)
+	}
+
+	return ""
+}
+
+func bits(class golf.ELFClass) int {
+	if class == golf.Class32 {
+		return 32
+	}
+	return 64
+}
+
+func endianessStr(endianess golf.ELFEndianess) string {
+	if endianess == golf.BigEndian {
+		return "big"
+	}
+	return "little"
+}
+
+// Returns the directories in which the dependencies of obj are searched, in
+// the order of the dynamic loader: the DT_RPATH of obj and of its loaders if
+// obj has no DT_RUNPATH, the library path, the DT_RUNPATH of obj, and unless
+// obj is marked with DF_1_NODEFLIB, the ld.so.conf and default directories.
+// The DT_RPATH of an object is ignored if it also has a DT_RUNPATH.
+func (res *resolution) searchDirs(obj *Object) []searchDir {
+	r := res.resolver
+	var dirs []searchDir
+	add := func(owner *Object, paths []string, source Source) {
+		for _, path := range paths {
+			if dir, ok := expandPath(r.Sysroot, owner, path); ok {
+				dirs = append(dirs, searchDir{dir, source})
+			}
+		}
+	}
+
+	if len(obj.RunPath) == 0 {
+		for loader := obj; loader != nil; loader = loader.Loader {
+			if len(loader.RunPath) == 0 {
+				add(loader, loader.RPath, SourceRPath)
+			}
+		}
+	}
+	// $ORIGIN in LD_LIBRARY_PATH is the directory of the executable.
+	add(res.graph.Root, r.LibraryPath, SourceLibraryPath)
+	add(obj, obj.RunPath, SourceRunPath)
+	if obj.noDefLib {
+		return dirs
+	}
+
+	add(obj, r.ConfPaths, SourceConf)
+	defaultPaths := r.DefaultPaths
+	if defaultPaths == nil {
+		defaultPaths = []string{"/lib", "/usr/lib"}
+		if res.graph.Root.class == golf.Class64 {
+			defaultPaths = append([]string{"/lib64", "/usr/lib64"}, defaultPaths...)
+		}
+	}
+	add(obj, defaultPaths, SourceDefault)
+
+	return dirs
+}
+
+// Returns the directory of a search path of owner, with the dynamic string
+// tokens expanded: $ORIGIN is the directory of owner and $LIB is 'lib64' for
+// 64-bit files and 'lib' otherwise. The second return value is false if the
+// path has a token which is not supported, like $PLATFORM, in which case the
+// path is not searched. An empty path is the current directory.
+func expandPath(sysroot string, owner *Object, path string) (string, bool) {
+	lib := "lib"
+	if owner.class == golf.Class64 {
+		lib = "lib64"
+	}
+
+	tokens := []string{"${ORIGIN}", "$ORIGIN", "${LIB}", "$LIB"}
+	unsupported := path
+	for _, token := range tokens {
+		unsupported = strings.Replace(unsupported, token, "", -1)
+	}
+	if strings.Contains(unsupported, "$") {
+		return "", false
+	}
+
+	origin := filepath.Dir(owner.Path)
+	dir := strings.NewReplacer(
+		tokens[0], origin, tokens[1], origin, tokens[2], lib, tokens[3], lib).Replace(path)
+	switch {
+	case dir == "":
+		return ".", true
+	case strings.HasPrefix(path, tokens[0]) || strings.HasPrefix(path, tokens[1]):
+		// The directory of owner is already under the sysroot.
+		return filepath.Clean(dir), true
+	}
+
+	return filepath.Clean(hostPath(sysroot, dir)), true
+}
@@ -0,0 +1,258 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package deps
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+import (
+	"eureka/golf"
+)
+
+const sysroot = "test_data/sysroot"
+
+// Returns a resolver of the test sysroot with the directories of its
+// ld.so.conf.
+func testResolver() (*Resolver, error) {
+	r := &Resolver{Sysroot: sysroot}
+	return r, r.LoadConf(DefaultConfFile)
+}
+
+// Returns a description of an edge as '<name> <source> <path>', or
+// '<name> missing' if it is not resolved.
+func edgeStr(edge *Edge) string {
+	if edge.To == nil {
+		return edge.Name + " missing"
+	}
+	return edge.Name + " " + string(edge.Source) + " " + strings.TrimPrefix(edge.To.Path, sysroot)
+}
+
+func checkGraph(t *testing.T, graph *Graph, expected map[string][]string) bool {
+	if len(graph.Objects) != len(expected) {
+		t.Errorf("Wrong number of objects: %d", len(graph.Objects))
+		return false
+	}
+	for _, obj := range graph.Objects {
+		var edges []string
+		for _, edge := range obj.Needed {
+			edges = append(edges, edgeStr(edge))
+		}
+		path := strings.TrimPrefix(obj.Path, sysroot)
+		if !reflect.DeepEqual(edges, expected[path]) {
+			t.Errorf("Wrong dependencies of '%s': %q", path, edges)
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestResolveRunPath(t *testing.T) {
+	r, err := testResolver()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	graph, err := r.Resolve(sysroot + "/usr/bin/app")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The DT_RUNPATH of the executable does not apply to the dependencies of
+	// liba.so.
+	expected := map[string][]string{
+		"/usr/bin/app": []string{
+			"liba.so DT_RUNPATH /usr/lib/app/liba.so",
+			"libconf.so ld.so.conf /opt/conf/lib/libconf.so",
+			"libmissing.so missing",
+		},
+		"/usr/lib/app/liba.so":     []string{"libb.so.1 missing"},
+		"/opt/conf/lib/libconf.so": nil,
+	}
+	if !checkGraph(t, graph, expected) {
+		return
+	}
+	if graph.Root.Interp != "/lib64/ld-linux-x86-64.so.2" ||
+		!reflect.DeepEqual(graph.Root.RunPath, []string{"$ORIGIN/../lib/app"}) {
+		t.Errorf("Wrong interpreter or DT_RUNPATH: %s %q", graph.Root.Interp, graph.Root.RunPath)
+		return
+	}
+
+	conf := graph.Root.Needed[1]
+	if len(conf.Skipped) != 1 ||
+		conf.Skipped[0] != "'"+sysroot+"/usr/lib/app/libconf.so' is incompatible: "+
+			"32-bit file, expected 64-bit." {
+		t.Errorf("Wrong skipped files: %q", conf.Skipped)
+		return
+	}
+
+	missing := graph.Missing()
+	if len(missing) != 2 || missing[0].Name != "libmissing.so" ||
+		missing[0].Missing != "'libmissing.so' was not found in any of the search paths." {
+		t.Errorf("Wrong missing dependencies: %v", missing)
+		return
+	}
+	expectedSearched := []string{
+		sysroot + "/usr/lib/app", sysroot + "/opt/conf/lib",
+		sysroot + "/lib64", sysroot + "/usr/lib64", sysroot + "/lib", sysroot + "/usr/lib",
+	}
+	if !reflect.DeepEqual(missing[0].Searched, expectedSearched) {
+		t.Errorf("Wrong searched directories: %q", missing[0].Searched)
+	}
+}
+
+func TestResolveRPath(t *testing.T) {
+	r, err := testResolver()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	graph, err := r.Resolve(sysroot + "/usr/bin/app_rpath")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The DT_RPATH of the executable applies to the dependencies of liba.so.
+	expected := map[string][]string{
+		"/usr/bin/app_rpath": []string{
+			"liba.so DT_RPATH /usr/lib/app/liba.so",
+			"libconf.so ld.so.conf /opt/conf/lib/libconf.so",
+			"libmissing.so missing",
+		},
+		"/usr/lib/app/liba.so":     []string{"libb.so.1 DT_RPATH /usr/lib/app/libb.so.1"},
+		"/opt/conf/lib/libconf.so": nil,
+		"/usr/lib/app/libb.so.1":   nil,
+	}
+	if !checkGraph(t, graph, expected) {
+		return
+	}
+	if graph.Objects[3].Name != "libb.so.1" || graph.Objects[3].Loader != graph.Objects[1] {
+		t.Errorf("Wrong load order: %v", graph.Objects)
+	}
+}
+
+func TestResolveLibraryPath(t *testing.T) {
+	r, err := testResolver()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	r.LibraryPath = []string{"/opt/override"}
+
+	// The library path takes precedence over DT_RUNPATH, but not over
+	// DT_RPATH.
+	expected := map[string]string{
+		"app":       "liba.so LD_LIBRARY_PATH /opt/override/liba.so",
+		"app_rpath": "liba.so DT_RPATH /usr/lib/app/liba.so",
+	}
+	for exe, edge := range expected {
+		graph, err := r.Resolve(sysroot + "/usr/bin/" + exe)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if edgeStr(graph.Root.Needed[0]) != edge {
+			t.Errorf("Wrong dependency of '%s': %s", exe, edgeStr(graph.Root.Needed[0]))
+			return
+		}
+	}
+
+	r = &Resolver{Sysroot: sysroot, DefaultPaths: []string{"/opt/conf/lib"}}
+	graph, err := r.Resolve(sysroot + "/usr/bin/app")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if edgeStr(graph.Root.Needed[1]) != "libconf.so default /opt/conf/lib/libconf.so" {
+		t.Errorf("Wrong dependency on default path: %s", edgeStr(graph.Root.Needed[1]))
+	}
+}
+
+func TestResolveSymlinks(t *testing.T) {
+	// The links in '/opt/links' point to the libraries in the sysroot with
+	// an absolute target, and with a relative one climbing above the root.
+	r := &Resolver{Sysroot: sysroot, LibraryPath: []string{"/opt/links"}}
+	graph, err := r.Resolve(sysroot + "/usr/bin/app")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	expected := []string{
+		"liba.so LD_LIBRARY_PATH /opt/links/liba.so",
+		"libconf.so LD_LIBRARY_PATH /opt/links/libconf.so",
+	}
+	for i, edge := range expected {
+		if edgeStr(graph.Root.Needed[i]) != edge {
+			t.Errorf("Wrong dependency through a link: %s", edgeStr(graph.Root.Needed[i]))
+			return
+		}
+	}
+
+	root, err := filepath.Abs(sysroot)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	liba, libconf := graph.Root.Needed[0].To, graph.Root.Needed[1].To
+	if liba.File != root+"/usr/lib/app/liba.so" || libconf.File != root+"/opt/conf/lib/libconf.so" {
+		t.Errorf("Wrong files of links: '%s' '%s'", liba.File, libconf.File)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	obj := &Object{Path: "/root/usr/bin/app", class: golf.Class64}
+	tests := []struct {
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"$ORIGIN/../lib", "/root/usr/lib", true},
+		{"${ORIGIN}", "/root/usr/bin", true},
+		{"/usr/$LIB/app", "/root/usr/lib64/app", true},
+		{"/usr/${LIB}", "/root/usr/lib64", true},
+		{"/opt/$PLATFORM", "", false},
+		{"lib", "lib", true},
+		{"", ".", true},
+	}
+	for _, test := range tests {
+		dir, ok := expandPath("/root", obj, test.path)
+		if dir != test.expected || ok != test.ok {
+			t.Errorf("Wrong expansion of '%s': '%s' %v", test.path, dir, ok)
+		}
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	r := new(Resolver)
+	_, err := r.Resolve("test_data/sysroot/etc/ld.so.conf")
+	if err == nil {
+		t.Errorf("Dependencies of a non-ELF file were resolved.")
+		return
+	}
+
+	_, err = r.Resolve("../golf/test_data/linux_x86.o")
+	if err == nil {
+		t.Errorf("Dependencies of an object file were resolved.")
+	}
+}
@@ -0,0 +1,377 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package deps
+
+import (
+	"fmt"
+	"strings"
+)
+
+import (
+	"eureka/golf"
+)
+
+// IssueKind values denote the kinds of problems found by CheckSymbols.
+type IssueKind string
+
+const (
+	// A reference, which is not weak, to a symbol which no object defines
+	// with a matching version.
+	IssueUnresolved IssueKind = "unresolved"
+
+	// A weak reference to a symbol which no object defines. The address of
+	// the symbol is zero at run time.
+	IssueWeakUnresolved IssueKind = "weak-unresolved"
+
+	// A version required from a needed library is not defined by it.
+	IssueVersionNotFound IssueKind = "version-not-found"
+
+	// Versions are required from a needed library which has no version
+	// definitions.
+	IssueNoVersionInfo IssueKind = "no-version-info"
+
+	// A referenced symbol is defined by more than one object. References
+	// bind to the first definition in the lookup order, which interposes the
+	// others. Symbols which the executable copies with copy relocations are
+	// not reported, as the copy and the definition in the library are the
+	// same data.
+	IssueInterposed IssueKind = "interposed"
+
+	// A reference binds to a weak definition although a later object in the
+	// lookup order has a global definition. Unlike the static linker, the
+	// dynamic loader does not prefer global definitions to weak ones. Such
+	// symbols are not reported as interposed too.
+	IssueWeakDefinition IssueKind = "weak-definition"
+)
+
+// Definition is the definition of a symbol by an object of a dependency
+// graph.
+type Definition struct {
+	Object *Object
+	Symbol golf.ResolvedSymbol
+}
+
+// Issue is a problem with binding the symbols of a dependency graph.
+type Issue struct {
+	Kind IssueKind
+
+	// The object with the reference or the version requirement. For
+	// interposed symbols and weak definitions, it is the first object
+	// referring to the symbol.
+	Object *Object
+
+	// The name of the symbol. It is empty for the issues of versions.
+	Symbol string
+
+	// The required version, if any.
+	Version string
+
+	// The needed library which is required to provide the version.
+	VersionFile string
+
+	// The definitions of the symbol in the lookup order. References bind to
+	// the first one.
+	Definitions []Definition
+
+	// True if the dynamic loader fails to load the file, or to bind the
+	// symbol when it is first used with lazy binding.
+	Fatal bool
+}
+
+// Returns a description of the issue like the error messages of the dynamic
+// loader.
+func (issue *Issue) String() string {
+	name := issue.Symbol
+	if issue.Version != "" {
+		name += "@" + issue.Version
+	}
+
+	switch issue.Kind {
+	case IssueUnresolved:
+		return fmt.Sprintf("undefined symbol: %s (%s)", name, issue.Object.Path)
+	case IssueWeakUnresolved:
+		return fmt.Sprintf("undefined weak symbol: %s (%s)", name, issue.Object.Path)
+	case IssueVersionNotFound:
+		return fmt.Sprintf(
+			"version '%s' not found in %s (required by %s)",
+			issue.Version, issue.VersionFile, issue.Object.Path)
+	case IssueNoVersionInfo:
+		return fmt.Sprintf(
+			"no version information available in %s (required by %s)",
+			issue.VersionFile, issue.Object.Path)
+	}
+
+	var paths []string
+	for _, def := range issue.Definitions {
+		paths = append(paths, def.Object.Path)
+	}
+	if issue.Kind == IssueWeakDefinition {
+		return fmt.Sprintf(
+			"%s binds to a weak definition before a global one: %s",
+			name, strings.Join(paths, ", "))
+	}
+	return fmt.Sprintf("%s is defined by more than one object: %s", name, strings.Join(paths, ", "))
+}
+
+// Weak references which the startup files of the toolchain add to most
+// executables and shared libraries, and which are expected to be unresolved.
+var toolchainWeakRefs = map[string]bool{
+	"__gmon_start__":              true,
+	"_ITM_deregisterTMCloneTable": true,
+	"_ITM_registerTMCloneTable":   true,
+	"_Jv_RegisterClasses":         true,
+	"__deregister_frame_info":     true,
+	"__register_frame_info":       true,
+}
+
+// Returns true if the weak reference to the symbol name is expected to be
+// unresolved. Besides the references of the startup files, these are the
+// references to the transactional memory runtime, libitm, whose functions
+// start with '_ITM_' and whose transactional clones start with '_ZGTt'.
+func expectedWeakRef(name string) bool {
+	return toolchainWeakRefs[name] || strings.HasPrefix(name, "_ITM_") ||
+		strings.HasPrefix(name, "_ZGTt")
+}
+
+// symbolTable holds the dynamic symbols and versions of an object.
+type symbolTable struct {
+	symbols []golf.ResolvedSymbol
+
+	// The indeces of the defined symbols which can be bound to, by name.
+	defined map[string][]int
+
+	// True if the object has a '.gnu.version' section.
+	versioned bool
+
+	verDefs  map[string]bool
+	verNeeds []golf.VerNeed
+
+	// The symbols to which copy relocations refer.
+	copied map[string]bool
+}
+
+// Reads the dynamic symbols and versions of the file of obj.
+func readSymbolTable(obj *Object) (*symbolTable, error) {
+	elf, err := golf.Read(obj.File)
+	if err != nil {
+		return nil, err
+	}
+	defer elf.Close()
+
+	tbl := &symbolTable{
+		defined: make(map[string][]int),
+		verDefs: make(map[string]bool),
+		copied:  make(map[string]bool),
+	}
+	tbl.symbols, err = elf.DynamicSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading dynamic symbols of '%s'.\n%s", obj.Path, err.Error())
+	}
+	for i, sym := range tbl.symbols {
+		if sym.Name == "" || !sym.IsDefined() || sym.Binding == golf.SymBindingLocal {
+			continue
+		}
+		if sym.Visibility == golf.SymVisibilityHidden ||
+			sym.Visibility == golf.SymVisibilityInternal {
+			continue
+		}
+		tbl.defined[sym.Name] = append(tbl.defined[sym.Name], i)
+	}
+
+	indeces, err := elf.SymbolVersionIndeces()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading symbol versions of '%s'.\n%s", obj.Path, err.Error())
+	}
+	tbl.versioned = len(indeces) > 0
+
+	defs, err := elf.VersionDefinitions()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Error reading version definitions of '%s'.\n%s", obj.Path, err.Error())
+	}
+	for _, def := range defs {
+		if def.Flags&golf.VerFlagBase == 0 {
+			tbl.verDefs[def.Name] = true
+		}
+	}
+
+	tbl.verNeeds, err = elf.VersionRequirements()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Error reading version requirements of '%s'.\n%s", obj.Path, err.Error())
+	}
+
+	relocs, err := elf.DynamicRelocs()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Error reading dynamic relocations of '%s'.\n%s", obj.Path, err.Error())
+	}
+	machine := elf.Header().Machine()
+	for _, reloc := range relocs {
+		if reloc.Sym != nil && strings.HasSuffix(golf.RelocTypeStr(machine, reloc.Type), "_COPY") {
+			tbl.copied[reloc.Sym.Name] = true
+		}
+	}
+
+	return tbl, nil
+}
+
+// Returns the definition of the symbol name in tbl which a reference with
+// the version binds to, or nil if there is none. As with the dynamic loader,
+// a versioned reference binds to a definition with the same version or
+// without a version, and a reference without a version binds to a definition
+// whose version is not hidden. Definitions in objects without versions match
+// any reference.
+func (tbl *symbolTable) lookup(name, version string) *golf.ResolvedSymbol {
+	for _, i := range tbl.defined[name] {
+		sym := &tbl.symbols[i]
+		switch {
+		case !tbl.versioned:
+		case version != "" && sym.Version != version && sym.Version != "":
+			continue
+		case version == "" && sym.VersionHidden:
+			continue
+		}
+		return sym
+	}
+
+	return nil
+}
+
+// Returns the problems with binding the undefined dynamic symbols of the
+// objects of the graph to the definitions in the graph, in the lookup order
+// of the dynamic loader, and with the versions required from the needed
+// libraries. Needed libraries which are missing from the graph are not
+// reported; they are the missing edges of the graph. Neither are the weak
+// references which the toolchain adds and which are expected to be
+// unresolved, like '__gmon_start__'.
+func CheckSymbols(graph *Graph) ([]*Issue, error) {
+	tbls := make([]*symbolTable, len(graph.Objects))
+	for i, obj := range graph.Objects {
+		tbl, err := readSymbolTable(obj)
+		if err != nil {
+			return nil, err
+		}
+		tbls[i] = tbl
+	}
+
+	var issues []*Issue
+	reported := make(map[string]bool)
+	for i, obj := range graph.Objects {
+		issues = append(issues, checkVersions(graph, tbls, obj, tbls[i])...)
+
+		for _, ref := range tbls[i].symbols {
+			if ref.Name == "" || ref.IsDefined() || ref.Binding == golf.SymBindingLocal {
+				continue
+			}
+
+			var defs []Definition
+			for j, tbl := range tbls {
+				if sym := tbl.lookup(ref.Name, ref.Version); sym != nil {
+					defs = append(defs, Definition{graph.Objects[j], *sym})
+				}
+			}
+
+			issue := &Issue{
+				Object:      obj,
+				Symbol:      ref.Name,
+				Version:     ref.Version,
+				VersionFile: ref.VersionFile,
+				Definitions: defs,
+			}
+			if len(defs) == 0 {
+				issue.Kind, issue.Fatal = IssueUnresolved, true
+				if ref.Binding == golf.SymBindingWeak {
+					if expectedWeakRef(ref.Name) {
+						continue
+					}
+					issue.Kind, issue.Fatal = IssueWeakUnresolved, false
+				}
+				issues = append(issues, issue)
+				continue
+			}
+
+			key := ref.Name + "@" + ref.Version
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+
+			// The copy made by a copy relocation is not another definition.
+			others := defs[1:]
+			if defs[0].Object == graph.Root && tbls[0].copied[ref.Name] && len(others) > 0 {
+				others = others[1:]
+			}
+			if len(others) == 0 {
+				continue
+			}
+			issue.Kind = IssueInterposed
+			if defs[0].Symbol.Binding == golf.SymBindingWeak {
+				for _, def := range others {
+					if def.Symbol.Binding == golf.SymBindingGlobal {
+						issue.Kind = IssueWeakDefinition
+						break
+					}
+				}
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues, nil
+}
+
+// Returns the problems with the versions which obj requires from its needed
+// libraries.
+func checkVersions(graph *Graph, tbls []*symbolTable, obj *Object, tbl *symbolTable) []*Issue {
+	var issues []*Issue
+	for _, need := range tbl.verNeeds {
+		needed := -1
+		for i, dep := range graph.Objects {
+			if dep.Name == need.File || dep.SOName == need.File {
+				needed = i
+				break
+			}
+		}
+		if needed < 0 {
+			continue
+		}
+
+		if len(tbls[needed].verDefs) == 0 {
+			issues = append(issues, &Issue{
+				Kind:        IssueNoVersionInfo,
+				Object:      obj,
+				VersionFile: need.File,
+			})
+			continue
+		}
+		for _, version := range need.Versions {
+			if tbls[needed].verDefs[version.Name] {
+				continue
+			}
+			issues = append(issues, &Issue{
+				Kind:        IssueVersionNotFound,
+				Object:      obj,
+				Version:     version.Name,
+				VersionFile: need.File,
+				Fatal:       version.Flags&golf.VerFlagWeak == 0,
+			})
+		}
+	}
+
+	return issues
+}
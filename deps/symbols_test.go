@@ -0,0 +1,103 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package deps
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Returns the issues of the dependency graph of the executable exe of the
+// test sysroot, as '<kind> <symbol>@<version> <fatal>'.
+func checkSymbols(exe string) ([]string, []*Issue, error) {
+	r, err := testResolver()
+	if err != nil {
+		return nil, nil, err
+	}
+	graph, err := r.Resolve(sysroot + "/usr/bin/" + exe)
+	if err != nil {
+		return nil, nil, err
+	}
+	issues, err := CheckSymbols(graph)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var strs []string
+	for _, issue := range issues {
+		fatal := ""
+		if issue.Fatal {
+			fatal = " fatal"
+		}
+		strs = append(strs, string(issue.Kind)+" "+issue.Symbol+"@"+issue.Version+fatal)
+	}
+	return strs, issues, nil
+}
+
+func TestCheckSymbols(t *testing.T) {
+	strs, issues, err := checkSymbols("symapp")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The copy relocation of v_data is not an interposition, and app_callback
+	// of libv.so.1 binds to the executable.
+	expected := []string{
+		"version-not-found @V_3 fatal",
+		"interposed dup_func@V_2",
+		"weak-unresolved opt_func@",
+		"unresolved v3_func@V_3 fatal",
+		"unresolved gone_func@V_2 fatal",
+		"weak-definition weak_func@V_2",
+	}
+	if !reflect.DeepEqual(strs, expected) {
+		t.Errorf("Wrong issues: %q", strs)
+		return
+	}
+
+	if issues[0].String() != "version 'V_3' not found in libv.so.1 (required by "+
+		sysroot+"/usr/bin/symapp)" {
+		t.Errorf("Wrong description of issue: %s", issues[0])
+		return
+	}
+	defs := issues[5].Definitions
+	if len(defs) != 2 || !strings.HasSuffix(defs[0].Object.Path, "/libv.so.1") ||
+		!strings.HasSuffix(defs[1].Object.Path, "/libw.so") ||
+		issues[5].String() != "weak_func@V_2 binds to a weak definition before a global one: "+
+			sysroot+"/usr/lib/libv.so.1, "+sysroot+"/usr/lib/libw.so" {
+		t.Errorf("Wrong definitions: %s", issues[5])
+	}
+}
+
+func TestCheckSymbolsMissingLibrary(t *testing.T) {
+	strs, issues, err := checkSymbols("app")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	expected := []string{"unresolved missing_func@ fatal", "unresolved b_func@ fatal"}
+	if !reflect.DeepEqual(strs, expected) {
+		t.Errorf("Wrong issues: %q", strs)
+		return
+	}
+	if !strings.HasSuffix(issues[1].Object.Path, "/liba.so") {
+		t.Errorf("Wrong object of undefined symbol: %s", issues[1].Object.Path)
+	}
+}
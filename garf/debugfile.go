@@ -0,0 +1,194 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package garf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+import (
+	"eureka/golf"
+)
+
+// The root directories under which LoadDwData searches for separate debug
+// files of ELF files which do not have debug info.
+var DebugRoots = []string{"/usr/lib/debug"}
+
+// Returns the path of the separate debug file of the ELF file read from
+// fileName, or an empty string if it is not found. The debug file is searched
+// for in the following order, like GDB does:
+//
+//  1. If the ELF file has a build-id, the file '.build-id/xx/yyyy.debug' under
+//     each of the roots, where xx is the first byte of the build-id and yyyy
+//     the rest, in hex. The build-id of the debug file should match.
+//
+//  2. If the ELF file has a '.gnu_debuglink' section, the linked file in the
+//     directory of the ELF file, in its '.debug' subdirectory, and in the same
+//     directory under each of the roots. The CRC32 of the debug file should
+//     match the one recorded in the link.
+func FindDebugFile(elf *golf.ELF, fileName string, roots []string) (string, error) {
+	buildID, err := elf.BuildID()
+	if err != nil {
+		return "", fmt.Errorf("Error reading build-id of '%s'.\n%s", fileName, err.Error())
+	}
+	if len(buildID) > 1 {
+		id := hex.EncodeToString(buildID)
+		for _, root := range roots {
+			path := filepath.Join(root, ".build-id", id[:2], id[2:]+".debug")
+			if hasBuildID(path, buildID) {
+				return path, nil
+			}
+		}
+	}
+
+	name, crc, err := elf.GnuDebugLink()
+	if err != nil {
+		return "", fmt.Errorf("Error reading debug link of '%s'.\n%s", fileName, err.Error())
+	}
+	if name == "" {
+		return "", nil
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(fileName))
+	if err != nil {
+		return "", err
+	}
+	paths := []string{filepath.Join(dir, name), filepath.Join(dir, ".debug", name)}
+	for _, root := range roots {
+		paths = append(paths, filepath.Join(root, dir, name))
+	}
+	for _, path := range paths {
+		if !isSameFile(path, fileName) && hasCRC(path, crc) {
+			return path, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Returns true if the file at path is an ELF file with the build-id.
+func hasBuildID(path string, buildID []byte) bool {
+	elf, err := golf.Read(path)
+	if err != nil {
+		return false
+	}
+	defer elf.Close()
+
+	id, err := elf.BuildID()
+	return err == nil && bytes.Equal(id, buildID)
+}
+
+// Returns true if the CRC32 of the contents of the file at path is crc.
+func hasCRC(path string, crc uint32) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	hash := crc32.NewIEEE()
+	_, err = io.Copy(hash, file)
+	return err == nil && hash.Sum32() == crc
+}
+
+func isSameFile(path1, path2 string) bool {
+	info1, err := os.Stat(path1)
+	if err != nil {
+		return false
+	}
+	info2, err := os.Stat(path2)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(info1, info2)
+}
+
+// Loads the DWARF data from the ELF file whose path is given by fileName. If
+// the file does not have debug info, then it is loaded from the separate
+// debug file found by FindDebugFile under the roots, if any. The symbols and
+// other data of the ELF file are available from the ELFData of the returned
+// DwData. No DwData is returned along with an error.
+func LoadDwDataWithRoots(fileName string, roots []string) (*DwData, error) {
+	elf, err := golf.Read(fileName)
+	if err != nil {
+		err = fmt.Errorf("Error loading ELF info from '%s'.\n%s", fileName, err.Error())
+		return nil, err
+	}
+
+	dwData, err := NewDwData(elf)
+	if err != nil {
+		elf.Close()
+		return nil, err
+	}
+	dwData.fileName = fileName
+	dwData.ownsELF = true
+
+	if dwData.hasDebugInfo() {
+		return dwData, nil
+	}
+
+	// Errors finding the debug file, like a malformed '.gnu_debuglink',
+	// are treated as if there were no debug file.
+	debugFileName, err := FindDebugFile(elf, fileName, roots)
+	if err != nil || debugFileName == "" {
+		return dwData, nil
+	}
+
+	debugELF, err := golf.Read(debugFileName)
+	if err != nil {
+		elf.Close()
+		err = fmt.Errorf(
+			"Error loading debug file '%s' of '%s'.\n%s", debugFileName, fileName, err.Error())
+		return nil, err
+	}
+
+	dwData.debugELF = debugELF
+	dwData.debugFileName = debugFileName
+	dwData.ownsDebugELF = true
+	return dwData, nil
+}
+
+// Loads the DWARF data from debugELF, the separate debug file of elf. The
+// symbols and other data of elf are available from the ELFData of the
+// returned DwData. Both ELF files are owned by the caller and are not closed
+// by the DwData.
+func NewDwDataWithDebugFile(elf *golf.ELF, debugELF *golf.ELF) (*DwData, error) {
+	dwData, err := NewDwData(elf)
+	if err != nil {
+		return nil, err
+	}
+	if debugELF == nil {
+		return nil, fmt.Errorf("Cannot load DWARF data from a nil debug file.")
+	}
+
+	dwData.debugELF = debugELF
+	return dwData, nil
+}
+
+// Returns true if the ELF file holding the DWARF sections has a '.debug_info'
+// section.
+func (d *DwData) hasDebugInfo() bool {
+	_, exists := d.debugSections(".debug_info")
+	return exists
+}
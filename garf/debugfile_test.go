@@ -0,0 +1,258 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package garf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+import (
+	"eureka/golf"
+)
+
+// The build-id of 'test_data/single_cu_linux_x86_64.exe'.
+const singleCUBuildID = "ae3d9f1b6e44f5719b5acfd55b7eaec793b2a2e9"
+
+// Writes the debug file of 'test_data/single_cu_linux_x86_64.exe' to
+// debugFileName, and the stripped file to strippedFileName. The stripped file
+// is linked to the debug file if link is true.
+func writeSeparateDebugFile(debugFileName, strippedFileName string, link bool) error {
+	const fileName = "test_data/single_cu_linux_x86_64.exe"
+
+	err := os.MkdirAll(filepath.Dir(debugFileName), 0755)
+	if err != nil {
+		return err
+	}
+
+	elf, err := golf.Read(fileName)
+	if err != nil {
+		return err
+	}
+	err = elf.OnlyKeepDebug()
+	if err == nil {
+		err = elf.WriteFile(debugFileName, 0644)
+	}
+	elf.Close()
+	if err != nil {
+		return err
+	}
+
+	elf, err = golf.Read(fileName)
+	if err != nil {
+		return err
+	}
+	defer elf.Close()
+
+	err = elf.StripDebug()
+	if err == nil && link {
+		err = elf.AddGnuDebugLink(debugFileName)
+	}
+	if err != nil {
+		return err
+	}
+
+	return elf.WriteFile(strippedFileName, 0755)
+}
+
+func TestDebugFileBuildID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "garf")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, "debug")
+	debugFileName := filepath.Join(
+		root, ".build-id", singleCUBuildID[:2], singleCUBuildID[2:]+".debug")
+	strippedFileName := filepath.Join(dir, "single_cu.exe")
+	err = writeSeparateDebugFile(debugFileName, strippedFileName, false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	dwData, err := LoadDwDataWithRoots(strippedFileName, []string{dir, root})
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer dwData.Close()
+
+	if dwData.DebugFileName() != debugFileName || dwData.DebugELFData() == dwData.ELFData() {
+		t.Errorf("Wrong debug file '%s'.", dwData.DebugFileName())
+		return
+	}
+
+	// The DWARF comes from the debug file, and the symbols from the stripped
+	// file, whose allocated sections have data.
+	compUnits, err := dwData.CompUnits()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(compUnits) != 1 {
+		t.Errorf("Wrong number of comp units: %d", len(compUnits))
+		return
+	}
+	syms, err := dwData.ELFData().LookupSymbol("main")
+	if err != nil || len(syms) == 0 {
+		t.Errorf("Symbol 'main' is not found in the stripped file.")
+		return
+	}
+	text := dwData.ELFData().SectMap()[".text"][0]
+	if text.SectHdr().Type() != golf.SectTypeProgBits {
+		t.Errorf("The text of the stripped file does not have data.")
+		return
+	}
+
+	// The debug file is not found without the root.
+	dwData, err = LoadDwDataWithRoots(strippedFileName, []string{dir})
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer dwData.Close()
+	if dwData.DebugFileName() != "" || dwData.hasDebugInfo() {
+		t.Errorf("Found a debug file outside of the roots.")
+	}
+}
+
+func TestDebugFileDebugLink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "garf")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// The debug file in the '.debug' subdirectory. It is moved around after
+	// the stripped file is linked to it.
+	debugFileName := filepath.Join(dir, ".debug", "single_cu.debug")
+	strippedFileName := filepath.Join(dir, "bin", "single_cu.exe")
+	err = os.Mkdir(filepath.Join(dir, "bin"), 0755)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	err = writeSeparateDebugFile(debugFileName, strippedFileName, true)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	elf, err := golf.Read(strippedFileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	binDir, err := filepath.Abs(filepath.Join(dir, "bin"))
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	root := filepath.Join(dir, "root")
+	candidates := []string{
+		filepath.Join(binDir, "single_cu.debug"),
+		filepath.Join(binDir, ".debug", "single_cu.debug"),
+		filepath.Join(root, binDir, "single_cu.debug"),
+	}
+	for _, candidate := range candidates {
+		err = os.MkdirAll(filepath.Dir(candidate), 0755)
+		if err == nil {
+			err = os.Rename(debugFileName, candidate)
+		}
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		debugFileName = candidate
+
+		found, err := FindDebugFile(elf, strippedFileName, []string{root})
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if found != candidate {
+			t.Errorf("Debug file '%s' is not found: '%s'", candidate, found)
+			return
+		}
+	}
+
+	// A debug file whose CRC does not match the link is ignored.
+	err = ioutil.WriteFile(debugFileName, []byte("not the debug file"), 0644)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	found, err := FindDebugFile(elf, strippedFileName, []string{root})
+	if err != nil || found != "" {
+		t.Errorf("Found a debug file whose CRC does not match: '%s'", found)
+	}
+}
+
+func TestDebugFileMalformedLink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "garf")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	debugFileName := filepath.Join(dir, "single_cu.debug")
+	strippedFileName := filepath.Join(dir, "single_cu.exe")
+	err = writeSeparateDebugFile(debugFileName, strippedFileName, true)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// A debug link without the CRC.
+	elf, err := golf.Read(strippedFileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	elf.SectMap()[golf.NameGnuDebugLink][0].SetData([]byte("single_cu.debug\x00"))
+	err = elf.WriteFile(strippedFileName, 0755)
+	elf.Close()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The malformed link is treated as if there were no debug file.
+	dwData, err := LoadDwDataWithRoots(strippedFileName, nil)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer dwData.Close()
+	if dwData.DebugFileName() != "" || dwData.hasDebugInfo() {
+		t.Errorf("Found a debug file with a malformed link.")
+		return
+	}
+	syms, err := dwData.ELFData().LookupSymbol("main")
+	if err != nil || len(syms) == 0 {
+		t.Errorf("Symbol 'main' is not found in the stripped file.")
+	}
+}
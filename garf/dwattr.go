@@ -37,6 +37,7 @@ func (d *DwData) readAttr(
 	var err error
 
 	attr.Name = at
+	attr.Form = form
 
 	switch at {
 	case DW_AT_sibling:
@@ -221,6 +222,8 @@ func (d *DwData) readAttr(
 		attr.Value, err = d.readAttrUint32(u, r, form, en)
 	case DW_AT_decl_line:
 		attr.Value, err = d.readAttrUint32(u, r, form, en)
+	case DW_AT_decl_column:
+		attr.Value, err = d.readAttrUint32(u, r, form, en)
 	case DW_AT_declaration:
 		attr.Value, err = d.readAttrFlag(u, r, form, en)
 	case DW_AT_encoding:
@@ -313,6 +316,12 @@ func (d *DwData) readAttr(
 		attr.Value, err = d.readAttrFlag(u, r, form, en)
 	case DW_AT_linkage_name:
 		attr.Value, err = d.readAttrStr(u, r, form, en)
+	case DW_AT_call_column:
+		attr.Value, err = d.readAttrUint32(u, r, form, en)
+	case DW_AT_call_file:
+		attr.Value, err = d.readAttrUint32(u, r, form, en)
+	case DW_AT_call_line:
+		attr.Value, err = d.readAttrUint32(u, r, form, en)
 
 	// GNU extension attributes
 	case DW_AT_GNU_tail_call:
@@ -330,7 +339,24 @@ func (d *DwData) readAttr(
 				DwFormStr[form], DwAtStr[DW_AT_GNU_call_site_value])
 		}
 	default:
-		attr.Value, err = d.readAttrByteSlice(u, r, form, en)
+		// Attributes which are not known, like those of vendor extensions,
+		// are read according to their form.
+		switch {
+		case form.IsFixedWidthConst() || form.IsUnsignedVarWidthConst():
+			attr.Value, err = d.readAttrUint64(u, r, form, en)
+		case form.IsSignedVarWidthConst():
+			attr.Value, err = d.readAttrInt64(u, r, form, en)
+		case form.IsAddress() || form == DW_FORM_sec_offset:
+			attr.Value, err = d.readAttrUint64(u, r, form, en)
+		case form.IsFlag():
+			attr.Value, err = d.readAttrFlag(u, r, form, en)
+		case form.IsString():
+			attr.Value, err = d.readAttrStr(u, r, form, en)
+		case form.IsCompUnitRef():
+			attr.Value, err = d.readAttrRef(u, r, form, en)
+		default:
+			attr.Value, err = d.readAttrByteSlice(u, r, form, en)
+		}
 	}
 
 	return attr, err
@@ -346,14 +372,14 @@ func (d *DwData) readAttrStr(
 			return "", err
 		}
 		return string(str), nil
-	case DW_FORM_strp:
+	case DW_FORM_strp, DW_FORM_line_strp:
 		var offset uint64
 		if u.Format == DwFormat32 {
 			var offset32 uint32
 
 			err := binary.Read(r, en, &offset32)
 			if err != nil {
-				err = fmt.Errorf("Error reading .debug_str 32-bit offset.", err)
+				err = fmt.Errorf("Error reading 32-bit string offset.\n%s", err.Error())
 				return "", err
 			}
 
@@ -361,25 +387,30 @@ func (d *DwData) readAttrStr(
 		} else {
 			err := binary.Read(r, en, &offset)
 			if err != nil {
-				err = fmt.Errorf("Error reading .debug_str 64-bit offset.", err)
+				err = fmt.Errorf("Error reading 64-bit string offset.\n%s", err.Error())
 				return "", err
 			}
 		}
 
-		debugStrTbl, err := d.DebugStr()
+		var strTbl *DebugStrTbl
+		var err error
+		if form == DW_FORM_strp {
+			strTbl, err = d.DebugStr()
+		} else {
+			strTbl, err = d.DebugLineStr()
+		}
 		if err != nil {
-			return "", fmt.Errorf("Error reading .debug_str.", err)
+			return "", fmt.Errorf("Error reading string table.\n%s", err.Error())
 		}
 
-		str, err := debugStrTbl.ReadStr(offset)
+		str, err := strTbl.ReadStr(offset)
 		if err != nil {
-			return "", fmt.Errorf("Error reading string: %s.", err.Error)
+			return "", fmt.Errorf("Error reading string.\n%s", err.Error())
 		}
 
 		return str, nil
 	default:
-		err := fmt.Errorf(
-			fmt.Sprintf("Cannot read data of form %d as string data.", form), nil)
+		err := fmt.Errorf("Cannot read data of form %d as string data.", form)
 		return "", err
 	}
 }
@@ -105,7 +105,7 @@ func (f DwForm) IsRef() bool {
 
 func (f DwForm) IsString() bool {
 	switch f {
-	case DW_FORM_string, DW_FORM_strp, DW_FORM_strx, DW_FORM_str_sup:
+	case DW_FORM_string, DW_FORM_strp, DW_FORM_strx, DW_FORM_str_sup, DW_FORM_line_strp:
 		return true
 	default:
 		return false
@@ -51,8 +51,7 @@ func (d *DwData) readLineNumberInfo(u *DwUnit) (*LnInfo, error) {
 		return nil, err
 	}
 
-	elf := d.ELFData()
-	debugLineSect, exists := elf.SectMap()[".debug_line"]
+	debugLineSect, exists := d.debugSections(".debug_line")
 	if !exists {
 		err = fmt.Errorf("Cannot read line number info as .debug_line section is missing.")
 		return nil, err
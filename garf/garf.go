@@ -53,6 +53,9 @@ type AbbrevTable map[uint64]AbbrevEntry
 type Attribute struct {
 	Name  DwAt
 	Value interface{}
+
+	// The form in which the value is encoded in the .debug_info section.
+	Form DwForm
 }
 
 type DIE struct {
@@ -237,6 +240,10 @@ type DwUnit struct {
 	// DIETree method.
 	dieTree *DIE
 
+	// The DIE of this unit without its children. Will be nil until a call to
+	// the UnitDIE method.
+	unitDIE *DIE
+
 	// The line number program for this unit. Will be nil until a call to the
 	// LnInfo method.
 	lnInfo *LnInfo
@@ -252,6 +259,22 @@ func (u *DwUnit) DIETree() (*DIE, error) {
 	return u.dieTree, err
 }
 
+// Returns the DIE of the unit with its attributes, but without its children.
+// Only the DIE is read, and not the complete DIE tree, unless the DIE tree
+// has already been read.
+func (u *DwUnit) UnitDIE() (*DIE, error) {
+	if u.dieTree != nil {
+		return u.dieTree, nil
+	}
+	if u.unitDIE != nil {
+		return u.unitDIE, nil
+	}
+
+	var err error
+	u.unitDIE, err = u.Parent.readUnitDIE(u)
+	return u.unitDIE, err
+}
+
 func (u *DwUnit) LineNumberInfo() (*LnInfo, error) {
 	if u.lnInfo != nil {
 		return u.lnInfo, nil
@@ -262,7 +285,8 @@ func (u *DwUnit) LineNumberInfo() (*LnInfo, error) {
 	return u.lnInfo, err
 }
 
-// DebugStrTbl encapsulates the data in the .debug_str section.
+// DebugStrTbl encapsulates the data in the .debug_str or .debug_line_str
+// section.
 type DebugStrTbl struct {
 	data []byte
 }
@@ -287,33 +311,66 @@ func (t *DebugStrTbl) ReadStr(offset uint64) (string, error) {
 }
 
 type DwData struct {
-	fileName    string
-	elf         *golf.ELF
-	debugStrTbl *DebugStrTbl
-	compUnits   []*DwUnit
-	typeUnits   []*DwUnit
+	fileName string
+	elf      *golf.ELF
+	ownsELF  bool
+
+	// The ELF file holding the DWARF sections. It is elf itself, unless the
+	// debug info is in a separate debug file.
+	debugELF      *golf.ELF
+	debugFileName string
+	ownsDebugELF  bool
+
+	debugStrTbl     *DebugStrTbl
+	debugLineStrTbl *DebugStrTbl
+	compUnits       []*DwUnit
+	typeUnits       []*DwUnit
 
 	// Mapping from offset into the .debug_info section to the DIE at that
 	// offset.
 	dieMap map[uint64]*DIE
 }
 
+// Loads the DWARF data from the ELF file whose path is given by fileName. If
+// the file does not have debug info, then it is loaded from its separate
+// debug file under the DebugRoots, as done by LoadDwDataWithRoots.
 func LoadDwData(fileName string) (*DwData, error) {
-	dwData := new(DwData)
-	var err error
+	return LoadDwDataWithRoots(fileName, DebugRoots)
+}
 
-	dwData.fileName = fileName
-	dwData.elf, err = golf.Read(fileName)
-	if err != nil {
-		err = fmt.Errorf("Error loading ELF info from '%s'.\n%s", fileName, err.Error())
-		return nil, err
+// Loads the DWARF data from an already parsed ELF file. The returned DwData
+// does not have a file name associated with it.
+func NewDwData(elf *golf.ELF) (*DwData, error) {
+	if elf == nil {
+		return nil, fmt.Errorf("Cannot load DWARF data from a nil ELF.")
 	}
 
+	dwData := new(DwData)
+	dwData.elf = elf
+	dwData.debugELF = elf
 	dwData.dieMap = make(map[uint64]*DIE)
 
 	return dwData, nil
 }
 
+// Closes the ELF file, and the separate debug file, if they were opened by
+// LoadDwData. The ELF files passed to NewDwData are owned by the caller and
+// are not closed.
+func (d *DwData) Close() error {
+	var err error
+	if d.ownsDebugELF {
+		err = d.debugELF.Close()
+	}
+	if d.ownsELF {
+		elfErr := d.elf.Close()
+		if err == nil {
+			err = elfErr
+		}
+	}
+
+	return err
+}
+
 func (d *DwData) ELFData() *golf.ELF {
 	return d.elf
 }
@@ -322,20 +379,45 @@ func (d *DwData) FileName() string {
 	return d.fileName
 }
 
+// Returns the ELF file holding the DWARF sections. It is the same as that
+// returned by ELFData unless the debug info is in a separate debug file.
+func (d *DwData) DebugELFData() *golf.ELF {
+	return d.debugELF
+}
+
+// Returns the path of the separate debug file, or an empty string if the
+// debug info is not in a separate debug file or the file name is not known.
+func (d *DwData) DebugFileName() string {
+	return d.debugFileName
+}
+
+// Returns the sections with the given DWARF section name. If there are no
+// such sections, then the sections of the same name compressed in the legacy
+// GNU format, like '.zdebug_info' for '.debug_info', are returned.
+func (d *DwData) debugSections(name string) ([]*golf.Section, bool) {
+	sectMap := d.debugELF.SectMap()
+	sections, exists := sectMap[name]
+	if exists {
+		return sections, true
+	}
+
+	sections, exists = sectMap[".z"+name[1:]]
+	return sections, exists
+}
+
 func (d *DwData) AbbrevTable(offset uint64) (AbbrevTable, error) {
-	sectMap := d.elf.SectMap()
-	sections, exists := sectMap[".debug_abbrev"]
+	sections, exists := d.debugSections(".debug_abbrev")
 	if !exists {
-		return nil, fmt.Errorf(".debug_abbrev section is not present.", nil)
+		return nil, fmt.Errorf(".debug_abbrev section is not present.")
 	}
 
 	if len(sections) > 1 {
-		return nil, fmt.Errorf("More than one .debug_abbrev sections.", nil)
+		return nil, fmt.Errorf("More than one .debug_abbrev sections.")
 	}
 
 	reader, err := sections[0].NewReader()
 	if err != nil {
-		return nil, fmt.Errorf("Error fetching .debug_abbrev reader.", err)
+		return nil, fmt.Errorf("Error fetching .debug_abbrev reader.\n%s", err.Error())
 	}
 
 	_, err = reader.Seek(int64(offset), 0)
@@ -347,7 +429,7 @@ func (d *DwData) AbbrevTable(offset uint64) (AbbrevTable, error) {
 	for true {
 		abbrevCode, err := leb128.ReadUnsigned(reader)
 		if err != nil {
-			return nil, fmt.Errorf("Error reading abbreviation code.", nil)
+			return nil, fmt.Errorf("Error reading abbreviation code.")
 		}
 		if abbrevCode == NullAbbrevEntry {
 			break
@@ -411,19 +493,19 @@ func (d *DwData) CompUnits() ([]*DwUnit, error) {
 		return d.compUnits, nil
 	}
 
-	sectMap := d.elf.SectMap()
-	sections, exists := sectMap[".debug_info"]
+	sections, exists := d.debugSections(".debug_info")
 	if !exists {
-		return nil, fmt.Errorf(".debug_info section is not present.", nil)
+		return nil, fmt.Errorf(".debug_info section is not present.")
 	}
 
 	if len(sections) > 1 {
-		return nil, fmt.Errorf("More than one .debug_info sections.", nil)
+		return nil, fmt.Errorf("More than one .debug_info sections.")
 	}
 
 	reader, err := sections[0].NewReader()
 	if err != nil {
-		return nil, fmt.Errorf("Error fetching .debug_info section reader.", err)
+		return nil, fmt.Errorf(
+			"Error fetching .debug_info section reader.\n%s", err.Error())
 	}
 
 	d.compUnits = make([]*DwUnit, 0)
@@ -442,8 +524,8 @@ func (d *DwData) CompUnits() ([]*DwUnit, error) {
 		err := binary.Read(reader, en, &size32)
 		if err != nil {
 			err = fmt.Errorf(
-				"Error reading first 32 bits of length of a unit in .debug_info.",
-				err)
+				"Error reading first 32 bits of length of a unit in .debug_info.\n%s",
+				err.Error())
 			return nil, err
 		}
 
@@ -454,8 +536,8 @@ func (d *DwData) CompUnits() ([]*DwUnit, error) {
 			err := binary.Read(reader, en, &size64)
 			if err != nil {
 				err = fmt.Errorf(
-					"Error reading 64-bit length of a unit in .debug_info.",
-					err)
+					"Error reading 64-bit length of a unit in .debug_info.\n%s",
+					err.Error())
 				return nil, err
 			}
 
@@ -468,16 +550,29 @@ func (d *DwData) CompUnits() ([]*DwUnit, error) {
 		var version uint16
 		err = binary.Read(reader, en, &version)
 		if err != nil {
-			err = fmt.Errorf("Error reading version of a unit in .debug_info.", err)
+			err = fmt.Errorf(
+				"Error reading version of a unit in .debug_info.\n%s", err.Error())
 			return nil, err
 		}
 
 		unitType := DW_UT_compile
+		var addrSize byte
 		if version >= 5 {
 			err = binary.Read(reader, en, &unitType)
 			if err != nil {
 				err = fmt.Errorf(
-					"Error reading unit type of a unit in .debug_info.", err)
+					"Error reading unit type of a unit in .debug_info.\n%s",
+					err.Error())
+				return nil, err
+			}
+
+			// Since DWARF 5, the address size comes before the debug abbrev
+			// offset.
+			err = binary.Read(reader, en, &addrSize)
+			if err != nil {
+				err = fmt.Errorf(
+					"Error reading address size from a unit header in .debug_info.\n%s",
+					err.Error())
 				return nil, err
 			}
 		}
@@ -488,7 +583,8 @@ func (d *DwData) CompUnits() ([]*DwUnit, error) {
 			err = binary.Read(reader, en, &offset)
 			if err != nil {
 				err = fmt.Errorf(
-					"Error reading 32-bit debug abbrev offset of a unit.", err)
+					"Error reading 32-bit debug abbrev offset of a unit.\n%s",
+					err.Error())
 				return nil, err
 			}
 
@@ -497,33 +593,34 @@ func (d *DwData) CompUnits() ([]*DwUnit, error) {
 			err = binary.Read(reader, en, &debugAbbrevOffset)
 			if err != nil {
 				err = fmt.Errorf(
-					"Error reading 64-bit debug abbrev offset of a unit.", err)
+					"Error reading 64-bit debug abbrev offset of a unit.\n%s",
+					err.Error())
 				return nil, err
 			}
 		}
 
-		var addrSize byte
-		err = binary.Read(reader, en, &addrSize)
-		if err != nil {
-			err = fmt.Errorf(
-				"Error reading address size from a unit header in .debug_info.",
-				err)
-			return nil, err
+		if version < 5 {
+			err = binary.Read(reader, en, &addrSize)
+			if err != nil {
+				err = fmt.Errorf(
+					"Error reading address size from a unit header in .debug_info.\n%s",
+					err.Error())
+				return nil, err
+			}
 		}
 
-		if unitType == DW_UT_type {
-		} else {
+		// The size of the unit includes the initial length field.
+		size := length + 4
+		if format == DwFormat64 {
+			size = length + 12
+		}
+
+		if unitType != DW_UT_type {
 			cu := new(DwUnit)
 
 			cu.Parent = d
 			cu.Type = unitType
-
-			if format == DwFormat64 {
-				cu.size = length + 12
-			} else {
-				cu.size = length + 4
-			}
-
+			cu.size = size
 			cu.Format = format
 			cu.Version = version
 			cu.headerOffset = headerOffset
@@ -532,8 +629,8 @@ func (d *DwData) CompUnits() ([]*DwUnit, error) {
 			cu.dataOffset = uint64(reader.Size() - int64(reader.Len()))
 			cu.abbrevTable = nil
 			d.compUnits = append(d.compUnits, cu)
-			reader.Seek(int64(cu.size+headerOffset), 0)
 		}
+		reader.Seek(int64(size+headerOffset), 0)
 	}
 
 	return d.compUnits, nil
@@ -544,19 +641,18 @@ func (d *DwData) DebugStr() (*DebugStrTbl, error) {
 		return d.debugStrTbl, nil
 	}
 
-	sectMap := d.elf.SectMap()
-	debugStrSections, exists := sectMap[".debug_str"]
+	debugStrSections, exists := d.debugSections(".debug_str")
 	if !exists {
-		return nil, fmt.Errorf(".debug_str section is not present.", nil)
+		return nil, fmt.Errorf(".debug_str section is not present.")
 	}
 
 	if len(debugStrSections) > 1 {
-		return nil, fmt.Errorf("More than one .debug_str sections.", nil)
+		return nil, fmt.Errorf("More than one .debug_str sections.")
 	}
 
 	debugStrData, err := debugStrSections[0].Data()
 	if err != nil {
-		return nil, fmt.Errorf("Error fetching .debug_str data.", err)
+		return nil, fmt.Errorf("Error fetching .debug_str data.\n%s", err.Error())
 	}
 
 	d.debugStrTbl = new(DebugStrTbl)
@@ -564,20 +660,46 @@ func (d *DwData) DebugStr() (*DebugStrTbl, error) {
 	return d.debugStrTbl, nil
 }
 
+// Returns the strings in the .debug_line_str section, which DWARF 5 has for
+// the file names referred to by the line number info and the unit DIEs.
+func (d *DwData) DebugLineStr() (*DebugStrTbl, error) {
+	if d.debugLineStrTbl != nil {
+		return d.debugLineStrTbl, nil
+	}
+
+	sections, exists := d.debugSections(".debug_line_str")
+	if !exists {
+		return nil, fmt.Errorf(".debug_line_str section is not present.")
+	}
+
+	if len(sections) > 1 {
+		return nil, fmt.Errorf("More than one .debug_line_str sections.")
+	}
+
+	data, err := sections[0].Data()
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching .debug_line_str data.\n%s", err.Error())
+	}
+
+	d.debugLineStrTbl = new(DebugStrTbl)
+	d.debugLineStrTbl.data = data
+	return d.debugLineStrTbl, nil
+}
+
 func (d *DwData) readDIETree(u *DwUnit, offset uint64) (*DIE, error) {
-	sectMap := d.elf.SectMap()
-	sections, exists := sectMap[".debug_info"]
+	sections, exists := d.debugSections(".debug_info")
 	if !exists {
-		return nil, fmt.Errorf(".debug_info section is not present.", nil)
+		return nil, fmt.Errorf(".debug_info section is not present.")
 	}
 
 	if len(sections) > 1 {
-		return nil, fmt.Errorf("More than one .debug_info sections.", nil)
+		return nil, fmt.Errorf("More than one .debug_info sections.")
 	}
 
 	reader, err := sections[0].NewReader()
 	if err != nil {
-		return nil, fmt.Errorf("Error fetching .debug_info section reader.", err)
+		return nil, fmt.Errorf(
+			"Error fetching .debug_info section reader.\n%s", err.Error())
 	}
 
 	_, err = reader.Seek(int64(offset), 0)
@@ -590,6 +712,73 @@ func (d *DwData) readDIETree(u *DwUnit, offset uint64) (*DIE, error) {
 	return d.readDIETreeHelper(u, reader, d.elf.Endianess(), nil)
 }
 
+func (d *DwData) readUnitDIE(u *DwUnit) (*DIE, error) {
+	sections, exists := d.debugSections(".debug_info")
+	if !exists {
+		return nil, fmt.Errorf(".debug_info section is not present.")
+	}
+
+	if len(sections) > 1 {
+		return nil, fmt.Errorf("More than one .debug_info sections.")
+	}
+
+	reader, err := sections[0].NewReader()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Error fetching .debug_info section reader.\n%s", err.Error())
+	}
+
+	_, err = reader.Seek(int64(u.dataOffset), 0)
+	if err != nil {
+		err = fmt.Errorf(
+			"Error seeking to the DIE offset to read the unit DIE.\n%s", err.Error())
+		return nil, err
+	}
+
+	if u.abbrevTable == nil {
+		u.abbrevTable, err = d.AbbrevTable(u.debugAbbrevOffset)
+		if err != nil {
+			err = fmt.Errorf(
+				"Error getting abbrev table while reading a unit DIE.\n%s",
+				err.Error())
+			return nil, err
+		}
+	}
+
+	abbrevCode, err := leb128.ReadUnsigned(reader)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Error reading abbrev code of a unit DIE.\n%s", err.Error())
+	}
+
+	abbrevEntry, exists := u.abbrevTable[abbrevCode]
+	if !exists {
+		return nil, fmt.Errorf("Invalid abbrev code %d for a unit DIE.", abbrevCode)
+	}
+
+	// The DIE is not registered in the DIE map as its children are not
+	// read.
+	die := new(DIE)
+	die.Tag = abbrevEntry.Tag
+	die.Unit = u
+	die.startOffset = u.dataOffset
+	die.Attributes = make(map[DwAt]Attribute)
+	en := d.elf.Endianess()
+	for _, attrForm := range abbrevEntry.AttrForms {
+		attr, err := d.readAttr(u, reader, attrForm.Name, attrForm.Form, en)
+		if err != nil {
+			err = fmt.Errorf(
+				"Error reading value of attribute %s of the unit DIE.\n%s",
+				DwAtStr[attrForm.Name], err.Error())
+			return nil, err
+		}
+		die.Attributes[attr.Name] = attr
+	}
+	die.endOffset = uint64(reader.Size() - int64(reader.Len()))
+
+	return die, nil
+}
+
 func (d *DwData) readDIETreeHelper(
 	u *DwUnit, r *bytes.Reader, en binary.ByteOrder, parent *DIE) (*DIE, error) {
 	// This is the DIE's offset in .debug_info section.
@@ -616,7 +805,7 @@ func (d *DwData) readDIETreeHelper(
 
 	abbrevCode, err := leb128.ReadUnsigned(r)
 	if err != nil {
-		return nil, fmt.Errorf("Error reading abbrev code of a DIE.", err)
+		return nil, fmt.Errorf("Error reading abbrev code of a DIE.\n%s", err.Error())
 	}
 
 	// Return if its a NULL entry
@@ -626,7 +815,7 @@ func (d *DwData) readDIETreeHelper(
 
 	abbrevEntry, exists := u.abbrevTable[abbrevCode]
 	if !exists {
-		return nil, fmt.Errorf("Invalid abbrev code for a DIE.", nil)
+		return nil, fmt.Errorf("Invalid abbrev code %d for a DIE.", abbrevCode)
 	}
 
 	die = new(DIE)
@@ -665,7 +854,7 @@ func (d *DwData) readDIETreeHelper(
 		if err != nil {
 			delete(d.dieMap, offset)
 			err = fmt.Errorf(
-				"Error reading child DIE tree of tag %x at offset %x.\n%s",
+				"Error reading child DIE tree of tag %s at offset %x.\n%s",
 				DwTagStr[abbrevEntry.Tag], offset, err.Error())
 			return nil, err
 		}
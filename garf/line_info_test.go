@@ -169,3 +169,38 @@ func TestLineInfoMultipleCU(t *testing.T) {
 		return
 	}
 }
+
+func TestLineInfoRISCV64(t *testing.T) {
+	dwData, err := LoadDwData("../golf/test_data/linux_riscv64.o")
+	if err != nil {
+		t.Errorf("Error loading DWARF from file.\n%s", err.Error())
+		return
+	}
+
+	compUnits, err := dwData.CompUnits()
+	if err != nil {
+		t.Errorf("Error reading comp units.\n%s", err.Error())
+		return
+	}
+	if len(compUnits) != 1 || compUnits[0].AddressSize != 8 {
+		t.Errorf("Wrong comp units.")
+		return
+	}
+
+	// The program sets 64-bit addresses, whose size is inferred from the
+	// class of the ELF file.
+	lnInfo, err := compUnits[0].LineNumberInfo()
+	if err != nil {
+		t.Errorf("Error getting comp unit line number info.\n%s", err.Error())
+		return
+	}
+
+	if len(lnInfo.Files) != 1 || lnInfo.Files[0].Path != "riscv64.s" {
+		t.Errorf("Wrong file entries.")
+		return
+	}
+
+	if len(lnInfo.Program) == 0 {
+		t.Errorf("Empty line number program.")
+	}
+}
@@ -23,8 +23,7 @@ import (
 )
 
 func (d *DwData) readLocList(u *DwUnit, offset uint64, en binary.ByteOrder) (LocList, error) {
-	sectMap := d.elf.SectMap()
-	s, exists := sectMap[".debug_loc"]
+	s, exists := d.debugSections(".debug_loc")
 	if !exists {
 		return nil, fmt.Errorf(".debug_loc section missing in ELF data.")
 	}
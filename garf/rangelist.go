@@ -59,8 +59,7 @@ func (e RangeListEntryEndOfList) RangeListEntryType() RangeListEntryType {
 type RangeList []RangeListEntry
 
 func (d *DwData) readRangeList(u *DwUnit, offset uint64, en binary.ByteOrder) (RangeList, error) {
-	sectMap := d.elf.SectMap()
-	s, exists := sectMap[".debug_ranges"]
+	s, exists := d.debugSections(".debug_ranges")
 	if !exists {
 		return nil, fmt.Errorf(".debug_ranges section missing in ELF data.")
 	}
@@ -104,7 +103,7 @@ func (d *DwData) readRangeList(u *DwUnit, offset uint64, en binary.ByteOrder) (R
 			if end32 == math.MaxUint32 {
 				end = math.MaxUint64
 			} else {
-				end = uint64(begin32)
+				end = uint64(end32)
 			}
 		} else {
 			err = binary.Read(r, en, &begin)
@@ -137,3 +136,85 @@ func (d *DwData) readRangeList(u *DwUnit, offset uint64, en binary.ByteOrder) (R
 
 	return rangeList, nil
 }
+
+// AddressRange is the half open range of addresses [Low, High).
+type AddressRange struct {
+	Low  uint64
+	High uint64
+}
+
+// Returns the value of an attribute holding an address or a constant.
+func attrUint64(die *DIE, at DwAt) (uint64, bool) {
+	attr, exists := die.Attributes[at]
+	if !exists {
+		return 0, false
+	}
+
+	switch v := attr.Value.(type) {
+	case uint64:
+		return v, true
+	case int64:
+		return uint64(v), true
+	case uint32:
+		return uint64(v), true
+	}
+
+	return 0, false
+}
+
+// Returns the address ranges covered by the DIE, as given by its DW_AT_low_pc
+// and DW_AT_high_pc attributes, or its DW_AT_ranges attribute. A DW_AT_high_pc
+// of a constant form is an offset from DW_AT_low_pc. The entries of a range
+// list are relative to the DW_AT_low_pc of the compile unit, unless a base
+// address selection entry precedes them. Empty ranges are dropped, and a DIE
+// without these attributes covers no addresses.
+func (die *DIE) AddressRanges() ([]AddressRange, error) {
+	var ranges []AddressRange
+
+	if attr, exists := die.Attributes[DW_AT_ranges]; exists {
+		rangeList, ok := attr.Value.(RangeList)
+		if !ok {
+			return nil, fmt.Errorf("Unknown value type of DW_AT_ranges attr.")
+		}
+
+		var base uint64
+		if die.Unit != nil {
+			unitDIE, err := die.Unit.UnitDIE()
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Error reading the DIE of the unit of a range list.\n%s", err.Error())
+			}
+			base, _ = attrUint64(unitDIE, DW_AT_low_pc)
+		}
+
+		for _, entry := range rangeList {
+			switch e := entry.(type) {
+			case RangeListEntryBaseAddrSelection:
+				base = uint64(e)
+			case RangeListEntryNormal:
+				if e.End > e.Begin {
+					ranges = append(ranges, AddressRange{base + e.Begin, base + e.End})
+				}
+			}
+		}
+
+		return ranges, nil
+	}
+
+	low, exists := attrUint64(die, DW_AT_low_pc)
+	if !exists {
+		return nil, nil
+	}
+	high, exists := attrUint64(die, DW_AT_high_pc)
+	if !exists {
+		return nil, nil
+	}
+	if !die.Attributes[DW_AT_high_pc].Form.IsAddress() {
+		high += low
+	}
+	if high > low {
+		ranges = append(ranges, AddressRange{low, high})
+	}
+
+	return ranges, nil
+}
@@ -110,3 +110,74 @@ func TestRangeLists(t *testing.T) {
 
 	_ = rangeList[2].(RangeListEntryEndOfList)
 }
+
+func TestAddressRanges(t *testing.T) {
+	dwData, err := LoadDwData("test_data/multiple_cu_linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	compUnits, err := dwData.CompUnits()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	die, err := compUnits[0].DIETree()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	ranges, err := die.AddressRanges()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(ranges) != 1 || ranges[0] != (AddressRange{0x400400, 0x400419}) {
+		t.Errorf("Wrong address ranges of comp unit 0: %v", ranges)
+		return
+	}
+
+	ranges, err = die.Children[0].Children[0].AddressRanges()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	expected := []AddressRange{{0x400404, 0x40040e}, {0x400412, 0x400419}}
+	if len(ranges) != 2 || ranges[0] != expected[0] || ranges[1] != expected[1] {
+		t.Errorf("Wrong address ranges of a lexical block DIE: %v", ranges)
+		return
+	}
+
+	// The DW_AT_high_pc of the other units is an offset from DW_AT_low_pc.
+	expected = []AddressRange{{0x400510, 0x40051c}, {0x400520, 0x400524}}
+	for i, u := range compUnits[1:] {
+		die, err := u.DIETree()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		ranges, err := die.AddressRanges()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if len(ranges) != 1 || ranges[0] != expected[i] {
+			t.Errorf("Wrong address ranges of comp unit %d: %v", i+1, ranges)
+			return
+		}
+
+		// Declarations of functions and types cover no addresses.
+		for _, child := range die.Children {
+			if child.Tag == DW_TAG_subprogram && child.Attributes[DW_AT_declaration].Value == nil {
+				continue
+			}
+			ranges, err = child.AddressRanges()
+			if err != nil || len(ranges) != 0 {
+				t.Errorf("Wrong address ranges of a declaration in comp unit %d: %v", i+1, ranges)
+				return
+			}
+		}
+	}
+}
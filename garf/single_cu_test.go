@@ -17,9 +17,16 @@
 package garf
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
+import (
+	"eureka/golf"
+)
+
 func TestDebugInfoSingleCU(t *testing.T) {
 	dwData, err := LoadDwData("test_data/single_cu_linux_x86_64.exe")
 	if err != nil {
@@ -205,3 +212,175 @@ func TestDebugInfoSingleCU(t *testing.T) {
 		}
 	}
 }
+
+func TestNewDwData(t *testing.T) {
+	elf, err := golf.Read("test_data/single_cu_linux_x86_64.exe")
+	if err != nil {
+		t.Errorf("Error reading ELF file.\n%s", err.Error())
+		return
+	}
+	defer elf.Close()
+
+	dwData, err := NewDwData(elf)
+	if err != nil {
+		t.Errorf("Error loading DWARF from ELF.\n%s", err.Error())
+		return
+	}
+
+	if dwData.ELFData() != elf {
+		t.Errorf("DWARF data does not refer to the ELF it was loaded from.")
+	}
+
+	compUnits, err := dwData.CompUnits()
+	if err != nil {
+		t.Errorf("Error reading comp units.\n%s", err.Error())
+		return
+	}
+	if len(compUnits) != 1 {
+		t.Errorf("Wrong number of comp units: %d", len(compUnits))
+	}
+}
+
+func TestCompressedDebugSections(t *testing.T) {
+	fileNames := []string{
+		"test_data/single_cu_linux_x86_64_zlib.exe",
+		"test_data/single_cu_linux_x86_64_zlib_gnu.exe",
+	}
+	for _, fileName := range fileNames {
+		dwData, err := LoadDwData(fileName)
+		if err != nil {
+			t.Errorf("Error loading DWARF from '%s'.\n%s", fileName, err.Error())
+			return
+		}
+		defer dwData.Close()
+
+		compUnits, err := dwData.CompUnits()
+		if err != nil {
+			t.Errorf("Error reading comp units from '%s'.\n%s", fileName, err.Error())
+			return
+		}
+		if len(compUnits) != 1 {
+			t.Errorf("Wrong number of comp units in '%s': %d", fileName, len(compUnits))
+			return
+		}
+
+		die, err := compUnits[0].DIETree()
+		if err != nil {
+			t.Errorf("Error fetching DIE tree from '%s'.\n%s", fileName, err.Error())
+			return
+		}
+		if die.Tag != DW_TAG_compile_unit || len(die.Children) != 2 {
+			t.Errorf("Wrong DIE tree in '%s'.", fileName)
+			return
+		}
+
+		_, err = compUnits[0].LineNumberInfo()
+		if err != nil {
+			t.Errorf("Error reading line number info from '%s'.\n%s", fileName, err.Error())
+			return
+		}
+	}
+}
+
+func TestStrippedAndDebugFiles(t *testing.T) {
+	const fileName = "test_data/single_cu_linux_x86_64.exe"
+	dir, err := ioutil.TempDir("", "garf")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	debugFileName := filepath.Join(dir, "single_cu.debug")
+	strippedFileName := filepath.Join(dir, "single_cu.exe")
+
+	elf, err := golf.Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	err = elf.OnlyKeepDebug()
+	if err == nil {
+		err = elf.WriteFile(debugFileName, 0644)
+	}
+	elf.Close()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	elf, err = golf.Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	err = elf.StripDebug()
+	if err == nil {
+		err = elf.AddGnuDebugLink(debugFileName)
+	}
+	if err == nil {
+		err = elf.WriteFile(strippedFileName, 0755)
+	}
+	elf.Close()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	dwData, err := LoadDwData(debugFileName)
+	if err != nil {
+		t.Errorf("Error loading DWARF from the debug file.\n%s", err.Error())
+		return
+	}
+	defer dwData.Close()
+
+	compUnits, err := dwData.CompUnits()
+	if err != nil {
+		t.Errorf("Error reading comp units from the debug file.\n%s", err.Error())
+		return
+	}
+	if len(compUnits) != 1 {
+		t.Errorf("Wrong number of comp units in the debug file: %d", len(compUnits))
+		return
+	}
+	_, err = compUnits[0].LineNumberInfo()
+	if err != nil {
+		t.Errorf("Error reading line number info from the debug file.\n%s", err.Error())
+		return
+	}
+
+	strippedELF, err := golf.Read(strippedFileName)
+	if err != nil {
+		t.Errorf("Error loading the stripped file.\n%s", err.Error())
+		return
+	}
+	defer strippedELF.Close()
+	stripped, err := NewDwData(strippedELF)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	compUnits, err = stripped.CompUnits()
+	if err == nil && len(compUnits) != 0 {
+		t.Errorf("The stripped file has comp units.")
+		return
+	}
+
+	// The debug file is found through the debug link of the stripped file.
+	linked, err := LoadDwDataWithRoots(strippedFileName, nil)
+	if err != nil {
+		t.Errorf("Error loading the stripped file.\n%s", err.Error())
+		return
+	}
+	defer linked.Close()
+
+	if linked.DebugFileName() != debugFileName {
+		t.Errorf("Wrong debug file '%s' of the stripped file.", linked.DebugFileName())
+		return
+	}
+	compUnits, err = linked.CompUnits()
+	if err != nil || len(compUnits) != 1 {
+		t.Errorf("Comp units of the stripped file are not read from the debug file.")
+	}
+}
@@ -0,0 +1,99 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package garf
+
+import (
+	"testing"
+)
+
+// 'test_data/mixed_dwarf_linux_x86_64.exe' is built from three compile units:
+// 'tls.c' with DWARF 4 and a TLS variable, 'add.c' with DWARF 5, and 'main.c'
+// with DWARF 5 and 'main' in '.text.startup', so that its unit has a range
+// list in '.debug_rnglists'.
+func TestUnitDIE(t *testing.T) {
+	dwData, err := LoadDwData("test_data/mixed_dwarf_linux_x86_64.exe")
+	if err != nil {
+		t.Errorf("Error loading DWARF from file.\n%s", err.Error())
+		return
+	}
+
+	compUnits, err := dwData.CompUnits()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(compUnits) != 3 {
+		t.Errorf("Wrong number of compile units: %d", len(compUnits))
+		return
+	}
+
+	expected := []struct {
+		version uint16
+		name    string
+		low     uint64
+		high    uint64
+	}{
+		{4, "tls.c", 0x1149, 0x115d},
+		{5, "add.c", 0x115d, 0x1161},
+	}
+	for i, e := range expected {
+		u := compUnits[i]
+		if u.Version != e.version || u.AddressSize != 8 {
+			t.Errorf("Wrong header of compile unit %d.", i)
+			return
+		}
+
+		die, err := u.UnitDIE()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if die.Tag != DW_TAG_compile_unit || len(die.Children) != 0 {
+			t.Errorf("Wrong DIE of compile unit %d.", i)
+			return
+		}
+		if name, _ := die.Attributes[DW_AT_name].Value.(string); name != e.name {
+			t.Errorf("Wrong name of compile unit %d: '%s'", i, name)
+			return
+		}
+
+		ranges, err := die.AddressRanges()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if len(ranges) != 1 || ranges[0].Low != e.low || ranges[0].High != e.high {
+			t.Errorf("Wrong address ranges of compile unit %d: %v", i, ranges)
+			return
+		}
+	}
+
+	// The TLS variable in 'tls.c' has a DWARF expression which cannot be
+	// read, but the unit DIE can be read without it.
+	_, err = compUnits[0].DIETree()
+	if err == nil {
+		t.Errorf("Expected an error reading the DIE tree of 'tls.c'.")
+		return
+	}
+
+	// Range lists of DWARF 5 are not supported.
+	_, err = compUnits[2].UnitDIE()
+	if err == nil {
+		t.Errorf("Expected an error reading the unit DIE of 'main.c'.")
+		return
+	}
+}
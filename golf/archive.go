@@ -0,0 +1,474 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// The magic string at the start of archives.
+	ArchiveMagic = "!<arch>\n"
+
+	// The magic string at the start of GNU thin archives, whose members are
+	// not stored in the archive but refer to files next to it.
+	ThinArchiveMagic = "!<thin>\n"
+)
+
+// The layout of an archive member header. All fields are ASCII text padded
+// with spaces.
+//
+//	========================================
+//	|| Field Name    ||  Size  |  Offset  ||
+//	========================================
+//	|| name          ||   16   |    0     ||
+//	|| date          ||   12   |    16    ||
+//	|| uid           ||   6    |    28    ||
+//	|| gid           ||   6    |    34    ||
+//	|| mode (octal)  ||   8    |    40    ||
+//	|| size          ||   10   |    48    ||
+//	|| magic "`\n"   ||   2    |    58    ||
+//	========================================
+const (
+	archiveHdrSize  = 60
+	archiveHdrMagic = "`\n"
+)
+
+// Names of the special members of System V and GNU archives.
+const (
+	archiveSymTblName   = "/"
+	archiveSymTbl64Name = "/SYM64/"
+	archiveLongNameTbl  = "//"
+)
+
+// ArchiveMember is a member of a Unix ar archive.
+type ArchiveMember struct {
+	// The name of the member. For members of thin archives, it is the path
+	// of the member file relative to the directory of the archive.
+	Name string
+
+	// The modification time in seconds since the epoch, the owner and group
+	// ids, and the file mode. Deterministic archives have them all zero,
+	// except for the mode.
+	Date int64
+	UID  int
+	GID  int
+	Mode uint32
+
+	// The size of the member data in bytes.
+	Size int64
+
+	// The offset of the member data in the archive. It is zero for members
+	// of thin archives, whose data is not in the archive.
+	Offset int64
+
+	// The offset of the member header in the archive.
+	HeaderOffset int64
+
+	archive *Archive
+}
+
+// ArchiveSymbol is an entry of the symbol index of an archive.
+type ArchiveSymbol struct {
+	Name string
+
+	// The member which defines the symbol.
+	Member *ArchiveMember
+}
+
+// Archive encapsulates the members and symbol index of a Unix ar archive, a
+// static library. System V and GNU archives, including GNU thin archives, are
+// supported.
+type Archive struct {
+	// True if the archive is a GNU thin archive.
+	Thin bool
+
+	// The members of the archive in the order in which they are stored. The
+	// symbol index and the long name table are not included.
+	Members []*ArchiveMember
+
+	// The symbol index of the archive. It is nil if the archive does not
+	// have one.
+	Symbols []ArchiveSymbol
+
+	// The directory relative to which the members of thin archives are
+	// found.
+	dir string
+
+	reader io.ReaderAt
+	size   int64
+	closer io.Closer
+}
+
+// Reads in the archive whose path is given by fileName. The file is kept
+// open so that members can be read on demand. Call Close on the returned
+// archive to close it.
+func ReadArchive(fileName string) (*Archive, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to open file '%s'.\n%s", fileName, err.Error())
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Unable to stat '%s'.\n%s", fileName, err.Error())
+	}
+
+	archive, err := NewArchive(file, fileInfo.Size())
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Error reading archive '%s'.\n%s", fileName, err.Error())
+	}
+
+	archive.dir = filepath.Dir(fileName)
+	archive.closer = file
+	return archive, nil
+}
+
+// Reads an archive from the reader r. The value size is the size of the
+// archive in bytes. The reader is retained by the returned archive to read
+// members on demand. Members of thin archives read this way are found
+// relative to the current directory.
+func NewArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	magic := make([]byte, len(ArchiveMagic))
+	_, err := r.ReadAt(magic, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading archive magic string.\n%s", err.Error())
+	}
+
+	archive := new(Archive)
+	switch string(magic) {
+	case ArchiveMagic:
+	case ThinArchiveMagic:
+		archive.Thin = true
+	default:
+		return nil, fmt.Errorf("Invalid archive magic string %q.", magic)
+	}
+	archive.reader = r
+	archive.size = size
+
+	err = archive.readMembers()
+	if err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// Returns true if the data read from r starts with an archive magic string.
+func IsArchive(r io.ReaderAt) bool {
+	magic := make([]byte, len(ArchiveMagic))
+	_, err := r.ReadAt(magic, 0)
+	if err != nil {
+		return false
+	}
+
+	return string(magic) == ArchiveMagic || string(magic) == ThinArchiveMagic
+}
+
+// Closes the archive file if the archive was read using ReadArchive. ELF
+// objects opened from members of regular archives read through the archive,
+// and cannot be used after the archive is closed.
+func (archive *Archive) Close() error {
+	if archive.closer == nil {
+		return nil
+	}
+
+	err := archive.closer.Close()
+	archive.closer = nil
+	return err
+}
+
+// Returns the first member which defines the symbol in the symbol index of
+// the archive, or nil if there is no such member.
+func (archive *Archive) LookupSymbol(name string) *ArchiveMember {
+	for _, sym := range archive.Symbols {
+		if sym.Name == name {
+			return sym.Member
+		}
+	}
+
+	return nil
+}
+
+// Returns the member with the name, or nil if there is no such member. If
+// more than one member has the name, the first of them is returned.
+func (archive *Archive) Member(name string) *ArchiveMember {
+	for _, member := range archive.Members {
+		if member.Name == name {
+			return member
+		}
+	}
+
+	return nil
+}
+
+func (archive *Archive) readAt(offset int64, size int64) ([]byte, error) {
+	if offset < 0 || size < 0 || offset > archive.size || size > archive.size-offset {
+		err := fmt.Errorf(
+			"Data of size %d at offset 0x%x lies beyond the end of archive.", size, offset)
+		return nil, err
+	}
+
+	data := make([]byte, size)
+	_, err := archive.reader.ReadAt(data, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Parses the decimal or octal number in a header field padded with spaces.
+// Empty fields are zero.
+func parseArchiveField(field []byte, base int, name string) (int64, error) {
+	str := strings.TrimRight(string(field), " ")
+	if str == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(str, base, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("Invalid %s '%s' in archive member header.", name, str)
+	}
+
+	return value, nil
+}
+
+func (archive *Archive) readMembers() error {
+	var symTbl []byte
+	symTblIs64 := false
+	var longNames []byte
+
+	offset := int64(len(ArchiveMagic))
+	for offset < archive.size {
+		hdr, err := archive.readAt(offset, archiveHdrSize)
+		if err != nil {
+			return fmt.Errorf(
+				"Error reading archive member header at 0x%x.\n%s", offset, err.Error())
+		}
+		if string(hdr[58:60]) != archiveHdrMagic {
+			return fmt.Errorf("Invalid archive member header at 0x%x.", offset)
+		}
+
+		member := &ArchiveMember{HeaderOffset: offset, archive: archive}
+		member.Date, err = parseArchiveField(hdr[16:28], 10, "date")
+		if err != nil {
+			return err
+		}
+		uid, err := parseArchiveField(hdr[28:34], 10, "uid")
+		if err != nil {
+			return err
+		}
+		gid, err := parseArchiveField(hdr[34:40], 10, "gid")
+		if err != nil {
+			return err
+		}
+		mode, err := parseArchiveField(hdr[40:48], 8, "mode")
+		if err != nil {
+			return err
+		}
+		member.UID, member.GID, member.Mode = int(uid), int(gid), uint32(mode)
+		member.Size, err = parseArchiveField(hdr[48:58], 10, "size")
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimRight(string(hdr[:16]), " ")
+		special := name == archiveSymTblName || name == archiveSymTbl64Name ||
+			name == archiveLongNameTbl
+
+		// The data of members of thin archives is not in the archive, but
+		// that of the special members is.
+		dataSize := member.Size
+		if archive.Thin && !special {
+			dataSize = 0
+		} else {
+			member.Offset = offset + archiveHdrSize
+		}
+		if dataSize > archive.size-offset-archiveHdrSize {
+			return fmt.Errorf(
+				"Data of archive member at 0x%x lies beyond the end of archive.", offset)
+		}
+
+		switch {
+		case special:
+			data, err := archive.readAt(member.Offset, member.Size)
+			if err != nil {
+				return err
+			}
+			if name == archiveLongNameTbl {
+				longNames = data
+			} else {
+				symTbl = data
+				symTblIs64 = name == archiveSymTbl64Name
+			}
+		case strings.HasPrefix(name, "/"):
+			member.Name, err = archiveLongName(longNames, name[1:])
+			if err != nil {
+				return err
+			}
+			archive.Members = append(archive.Members, member)
+		default:
+			member.Name = strings.TrimSuffix(name, "/")
+			archive.Members = append(archive.Members, member)
+		}
+
+		// Member data is aligned to even offsets.
+		offset += archiveHdrSize + dataSize + dataSize%2
+	}
+
+	if symTbl != nil {
+		return archive.readSymbols(symTbl, symTblIs64)
+	}
+
+	return nil
+}
+
+// Returns the name at the offset, given in decimal, in the long name table
+// of GNU archives. Names in the table are terminated by "/\n".
+func archiveLongName(longNames []byte, offset string) (string, error) {
+	index, err := strconv.ParseUint(offset, 10, 32)
+	if err != nil || index >= uint64(len(longNames)) {
+		return "", fmt.Errorf("Invalid archive long name offset '%s'.", offset)
+	}
+
+	name := longNames[index:]
+	end := bytes.IndexByte(name, '\n')
+	if end < 0 {
+		return "", fmt.Errorf("Archive long name at offset %d is not terminated.", index)
+	}
+
+	return strings.TrimSuffix(string(name[:end]), "/"), nil
+}
+
+// Reads the symbol index of GNU and System V archives. It is a big endian
+// count of symbols, followed by as many offsets of the headers of the members
+// defining them, followed by the NULL terminated names of the symbols. The
+// count and offsets are 32-bit, or 64-bit in the '/SYM64/' variant.
+func (archive *Archive) readSymbols(data []byte, is64 bool) error {
+	wordSize := uint64(4)
+	word := func(b []byte) uint64 {
+		return uint64(binary.BigEndian.Uint32(b))
+	}
+	if is64 {
+		wordSize = 8
+		word = binary.BigEndian.Uint64
+	}
+
+	if uint64(len(data)) < wordSize {
+		return fmt.Errorf("Archive symbol index is too small.")
+	}
+	count := word(data)
+	if count > (uint64(len(data))-wordSize)/wordSize {
+		return fmt.Errorf("Invalid archive symbol count %d.", count)
+	}
+
+	membersAt := make(map[int64]*ArchiveMember, len(archive.Members))
+	for _, member := range archive.Members {
+		membersAt[member.HeaderOffset] = member
+	}
+
+	names := NewRawStrTbl(data[wordSize*(count+1):])
+	nameOffset := uint32(0)
+	archive.Symbols = make([]ArchiveSymbol, count)
+	for i := uint64(0); i < count; i++ {
+		sym := &archive.Symbols[i]
+
+		var err error
+		sym.Name, err = names.ReadStr(nameOffset)
+		if err != nil {
+			return fmt.Errorf("Error reading name of archive symbol %d.\n%s", i, err.Error())
+		}
+		nameOffset += uint32(len(sym.Name)) + 1
+
+		memberOffset := word(data[wordSize*(i+1):])
+		sym.Member = membersAt[int64(memberOffset)]
+		if sym.Member == nil {
+			err := fmt.Errorf(
+				"Archive symbol '%s' refers to an invalid member offset 0x%x.",
+				sym.Name, memberOffset)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the path of the file of a member of a thin archive.
+func (member *ArchiveMember) Path() string {
+	if filepath.IsAbs(member.Name) {
+		return member.Name
+	}
+
+	return filepath.Join(member.archive.dir, member.Name)
+}
+
+// Returns a reader over the data of a member of a regular archive. The data
+// is read from the archive in place.
+func (member *ArchiveMember) NewReader() (*io.SectionReader, error) {
+	if member.archive.Thin {
+		err := fmt.Errorf(
+			"Data of member '%s' is not in the thin archive. It is in '%s'.",
+			member.Name, member.Path())
+		return nil, err
+	}
+
+	return io.NewSectionReader(member.archive.reader, member.Offset, member.Size), nil
+}
+
+// Returns the data of the member. Members of thin archives are read from
+// their files.
+func (member *ArchiveMember) Data() ([]byte, error) {
+	if member.archive.Thin {
+		return ioutil.ReadFile(member.Path())
+	}
+
+	return member.archive.readAt(member.Offset, member.Size)
+}
+
+// Reads the member as an ELF file. Members of regular archives are read from
+// the archive in place, and the returned ELF is valid only as long as the
+// archive is open. Members of thin archives are read from their files, and
+// the returned ELF should be closed by the caller.
+func (member *ArchiveMember) Open() (*ELF, error) {
+	if member.archive.Thin {
+		return Read(member.Path())
+	}
+
+	r, err := member.NewReader()
+	if err != nil {
+		return nil, err
+	}
+
+	elf, err := NewFile(r, member.Size)
+	if err != nil {
+		err = fmt.Errorf("Error reading archive member '%s'.\n%s", member.Name, err.Error())
+		return nil, err
+	}
+
+	return elf, nil
+}
@@ -0,0 +1,169 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+// The members of 'test_data/libgolf.a' and 'test_data/libgolf_thin.a'. The
+// name of the second member does not fit in the member header, and is stored
+// in the long name table.
+var archiveMembers = []struct {
+	name    string
+	machine MachineArch
+}{
+	{"linux_x86.o", MachineX86},
+	{"linux_mips64el.o", MachineMIPS},
+	{"linux_riscv64.o", MachineRISCV},
+}
+
+func testArchive(t *testing.T, fileName string, thin bool) {
+	archive, err := ReadArchive(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer archive.Close()
+
+	if archive.Thin != thin || len(archive.Members) != len(archiveMembers) {
+		t.Errorf("Wrong kind or number of members of '%s'.", fileName)
+		return
+	}
+
+	for i, expected := range archiveMembers {
+		member := archive.Members[i]
+		if member.Name != expected.name || member.Mode != 0644 {
+			t.Errorf("Wrong member %d of '%s': %v", i, fileName, member)
+			return
+		}
+
+		orig, err := ioutil.ReadFile("test_data/" + expected.name)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		data, err := member.Data()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if member.Size != int64(len(orig)) || !bytes.Equal(data, orig) {
+			t.Errorf("Wrong data of member '%s' of '%s'.", member.Name, fileName)
+			return
+		}
+
+		elf, err := member.Open()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		defer elf.Close()
+		if elf.Header().Machine() != expected.machine {
+			t.Errorf("Wrong machine of member '%s' of '%s'.", member.Name, fileName)
+			return
+		}
+		_, err = elf.Symbols()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	if len(archive.Symbols) != 9 {
+		t.Errorf("Wrong number of symbols in '%s': %d", fileName, len(archive.Symbols))
+		return
+	}
+	if archive.LookupSymbol("exported_func") != archive.Members[0] ||
+		archive.LookupSymbol("golf_func") != archive.Members[1] ||
+		archive.LookupSymbol("golf_ext") != nil {
+		t.Errorf("Wrong symbol lookup in '%s'.", fileName)
+		return
+	}
+	if archive.Member("linux_riscv64.o") != archive.Members[2] {
+		t.Errorf("Wrong member lookup in '%s'.", fileName)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	testArchive(t, "test_data/libgolf.a", false)
+}
+
+func TestThinArchive(t *testing.T) {
+	testArchive(t, "test_data/libgolf_thin.a", true)
+
+	archive, err := ReadArchive("test_data/libgolf_thin.a")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer archive.Close()
+
+	_, err = archive.Members[0].NewReader()
+	if err == nil {
+		t.Errorf("Expected an error reading a member of a thin archive in place.")
+	}
+}
+
+// Returns the header of an archive member.
+func archiveHdr(name string, size int) []byte {
+	return []byte(fmt.Sprintf("%-16s%-12d%-6d%-6d%-8o%-10d`\n", name, 0, 0, 0, 0644, size))
+}
+
+func TestArchiveSym64(t *testing.T) {
+	obj, err := ioutil.ReadFile("test_data/linux_x86.o")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// A '/SYM64/' symbol index with one symbol, defined by the member which
+	// follows it.
+	symTbl := make([]byte, 16, 32)
+	symTbl = append(symTbl, "counter\x00"...)
+	binary.BigEndian.PutUint64(symTbl, 1)
+	memberOffset := len(ArchiveMagic) + archiveHdrSize + len(symTbl)
+	binary.BigEndian.PutUint64(symTbl[8:], uint64(memberOffset))
+
+	data := []byte(ArchiveMagic)
+	data = append(data, archiveHdr("/SYM64/", len(symTbl))...)
+	data = append(data, symTbl...)
+	data = append(data, archiveHdr("a.o/", len(obj))...)
+	data = append(data, obj...)
+
+	archive, err := NewArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(archive.Members) != 1 || archive.Members[0].Name != "a.o" ||
+		archive.LookupSymbol("counter") != archive.Members[0] {
+		t.Errorf("Wrong members or symbols of archive with a 64-bit symbol index.")
+		return
+	}
+
+	// Truncate the member data.
+	data = data[:len(data)-1]
+	_, err = NewArchive(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Errorf("Expected an error reading a truncated archive.")
+	}
+}
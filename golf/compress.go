@@ -0,0 +1,224 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Values of type CompressionType represent the algorithm used to compress
+// the data of a section.
+type CompressionType uint32
+
+const (
+	CompressionZlib              CompressionType = CompressionType(1)
+	CompressionZstd              CompressionType = CompressionType(2)
+	CompressionStartOSSpecific   CompressionType = CompressionType(0x60000000)
+	CompressionEndOSSpecific     CompressionType = CompressionType(0x6fffffff)
+	CompressionStartProcSpecific CompressionType = CompressionType(0x70000000)
+	CompressionEndProcSpecific   CompressionType = CompressionType(0x7fffffff)
+)
+
+const (
+	// The prefix of the names of sections compressed in the legacy GNU
+	// format, like '.zdebug_info'.
+	gnuCompressedPrefix = ".zdebug"
+
+	// The magic number at the beginning of the sections compressed in the
+	// legacy GNU format.
+	gnuCompressedMagic = "ZLIB"
+)
+
+// CompressionHdr is the header, Elf32_Chdr or Elf64_Chdr, which precedes the
+// compressed data of sections having the flag SectFlagCompressed. For
+// sections compressed in the legacy GNU format ('.zdebug_*' sections), it is
+// synthesized from the 12 byte "ZLIB" header of the section.
+type CompressionHdr struct {
+	Type CompressionType
+
+	// The size of the uncompressed data.
+	Size uint64
+
+	// The alignment of the uncompressed data.
+	Alignment uint64
+}
+
+// A Decompressor returns a reader which decompresses the data read from r.
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+var (
+	decompressorsMu sync.RWMutex
+	decompressors   = map[CompressionType]Decompressor{
+		CompressionZlib: func(r io.Reader) (io.ReadCloser, error) {
+			return zlib.NewReader(r)
+		},
+	}
+)
+
+// Registers a decompressor for a compression type. A zlib decompressor is
+// registered by default. As the standard library does not provide a zstd
+// decoder, a decompressor for CompressionZstd should be registered by programs
+// which need to read zstd compressed sections.
+func RegisterDecompressor(compressionType CompressionType, d Decompressor) {
+	decompressorsMu.Lock()
+	defer decompressorsMu.Unlock()
+
+	decompressors[compressionType] = d
+}
+
+func decompressor(compressionType CompressionType) Decompressor {
+	decompressorsMu.RLock()
+	defer decompressorsMu.RUnlock()
+
+	return decompressors[compressionType]
+}
+
+// Returns the canonical name of a section. For sections compressed in the
+// legacy GNU format, like '.zdebug_info', it is the name without the 'z', like
+// '.debug_info'. For all other sections, it is the name of the section.
+func CanonicalSectName(name string) string {
+	if strings.HasPrefix(name, gnuCompressedPrefix) {
+		return "." + name[2:]
+	}
+
+	return name
+}
+
+// Returns true if the data of the section is compressed, either because it
+// has the flag SectFlagCompressed, or because it is a '.zdebug_*' section
+// compressed in the legacy GNU format.
+func (section *Section) IsCompressed() bool {
+	if section.header.Type() == SectTypeNoBits {
+		return false
+	}
+
+	if section.header.Flags()&SectFlagCompressed != 0 {
+		return true
+	}
+
+	return strings.HasPrefix(section.name, gnuCompressedPrefix)
+}
+
+// Returns the compression header of the section. Returns nil if the section
+// is not compressed.
+func (section *Section) CompressionHdr() (*CompressionHdr, error) {
+	if !section.IsCompressed() {
+		return nil, nil
+	}
+
+	raw, err := section.RawData()
+	if err != nil {
+		return nil, err
+	}
+
+	hdr, _, err := section.readCompressionHdr(raw)
+	return hdr, err
+}
+
+// Reads the compression header from the raw data of the section. Returns the
+// header and the size of the header in the raw data.
+func (section *Section) readCompressionHdr(raw []byte) (*CompressionHdr, int, error) {
+	hdr := new(CompressionHdr)
+
+	if section.header.Flags()&SectFlagCompressed == 0 {
+		// Legacy GNU format: "ZLIB" followed by the big endian 64-bit
+		// uncompressed size.
+		if len(raw) < 12 || string(raw[:4]) != gnuCompressedMagic {
+			err := fmt.Errorf(
+				"Section '%s' does not have a valid ZLIB header.", section.name)
+			return nil, 0, err
+		}
+
+		hdr.Type = CompressionZlib
+		hdr.Size = binary.BigEndian.Uint64(raw[4:])
+		hdr.Alignment = section.header.Alignment()
+		return hdr, 12, nil
+	}
+
+	endianess := section.elf.Endianess()
+	if section.header.Class() == Class32 {
+		if len(raw) < 12 {
+			err := fmt.Errorf(
+				"Section '%s' is too small for a compression header.", section.name)
+			return nil, 0, err
+		}
+
+		hdr.Type = CompressionType(endianess.Uint32(raw))
+		hdr.Size = uint64(endianess.Uint32(raw[4:]))
+		hdr.Alignment = uint64(endianess.Uint32(raw[8:]))
+		return hdr, 12, nil
+	}
+
+	if len(raw) < 24 {
+		err := fmt.Errorf(
+			"Section '%s' is too small for a compression header.", section.name)
+		return nil, 0, err
+	}
+
+	// There is a 32-bit reserved field after the type in Elf64_Chdr.
+	hdr.Type = CompressionType(endianess.Uint32(raw))
+	hdr.Size = endianess.Uint64(raw[8:])
+	hdr.Alignment = endianess.Uint64(raw[16:])
+	return hdr, 24, nil
+}
+
+// Decompresses the raw data of a compressed section.
+func (section *Section) decompress(raw []byte) ([]byte, error) {
+	hdr, hdrSize, err := section.readCompressionHdr(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	d := decompressor(hdr.Type)
+	if d == nil {
+		err = fmt.Errorf(
+			"Unsupported compression type %d of section '%s'.", hdr.Type, section.name)
+		return nil, err
+	}
+
+	r, err := d(bytes.NewReader(raw[hdrSize:]))
+	if err != nil {
+		err = fmt.Errorf(
+			"Error decompressing section '%s'.\n%s", section.name, err.Error())
+		return nil, err
+	}
+	defer r.Close()
+
+	// Do not trust the size in the header for the allocation, but make sure
+	// that the data is of the size it claims to be.
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(r, int64(hdr.Size)+1))
+	if err != nil {
+		err = fmt.Errorf(
+			"Error decompressing section '%s'.\n%s", section.name, err.Error())
+		return nil, err
+	}
+	if uint64(n) != hdr.Size {
+		err = fmt.Errorf(
+			"Decompressed size of section '%s' does not match the size in its "+
+				"compression header.", section.name)
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,199 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func compareSectionData(t *testing.T, fileName, compressedFileName string, mapped bool) {
+	elf, err := Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	var compressed *ELF
+	if mapped {
+		compressed, err = ReadMapped(compressedFileName)
+	} else {
+		compressed, err = Read(compressedFileName)
+	}
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer compressed.Close()
+
+	sections := elf.Sections()
+	compressedSections := compressed.Sections()
+	if len(sections) != len(compressedSections) {
+		t.Errorf("Wrong number of sections in '%s'.", compressedFileName)
+		return
+	}
+
+	numCompressed := 0
+	for i, section := range compressedSections {
+		if !section.IsCompressed() {
+			continue
+		}
+		numCompressed++
+
+		hdr, err := section.CompressionHdr()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if hdr.Type != CompressionZlib || hdr.Size != sections[i].SectHdr().Size() {
+			t.Errorf("Wrong compression header for section %d: %+v", i, *hdr)
+			return
+		}
+
+		data, err := section.Data()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		expected, err := sections[i].Data()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if !bytes.Equal(data, expected) {
+			t.Errorf("Wrong decompressed data for section %d.", i)
+			return
+		}
+	}
+
+	if numCompressed == 0 {
+		t.Errorf("No compressed sections in '%s'.", compressedFileName)
+	}
+}
+
+func TestCompressedSections(t *testing.T) {
+	const fileName = "../garf/test_data/single_cu_linux_x86_64.exe"
+	compareSectionData(t, fileName, "../garf/test_data/single_cu_linux_x86_64_zlib.exe", false)
+	compareSectionData(t, fileName, "../garf/test_data/single_cu_linux_x86_64_zlib.exe", true)
+	compareSectionData(
+		t, fileName, "../garf/test_data/single_cu_linux_x86_64_zlib_gnu.exe", false)
+}
+
+func TestCompressedSections32(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_zlib.o")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	// Sizes of the uncompressed '.debug_info' and '.debug_abbrev' sections.
+	sizes := map[int]uint64{10: 0xfd, 12: 0xc5}
+	for index, size := range sizes {
+		section := elf.Sections()[index]
+		if !section.IsCompressed() {
+			t.Errorf("Section %d is not compressed.", index)
+			return
+		}
+
+		hdr, err := section.CompressionHdr()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if hdr.Type != CompressionZlib || hdr.Size != size || hdr.Alignment != 1 {
+			t.Errorf("Wrong compression header for section %d: %+v", index, *hdr)
+			return
+		}
+
+		data, err := section.Data()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if uint64(len(data)) != size {
+			t.Errorf("Wrong size of decompressed data of section %d.", index)
+			return
+		}
+
+		raw, err := section.RawData()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if uint64(len(raw)) != section.SectHdr().Size() {
+			t.Errorf("Wrong size of raw data of section %d.", index)
+			return
+		}
+	}
+}
+
+func TestCanonicalSectName(t *testing.T) {
+	names := map[string]string{
+		".zdebug_info": ".debug_info",
+		".debug_info":  ".debug_info",
+		".text":        ".text",
+	}
+	for name, canonical := range names {
+		if CanonicalSectName(name) != canonical {
+			t.Errorf("Wrong canonical name for '%s'.", name)
+		}
+	}
+}
+
+func TestRegisterDecompressor(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_zstd.o")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	section := elf.Sections()[10]
+	hdr, err := section.CompressionHdr()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if hdr.Type != CompressionZstd || hdr.Size != 0xfd {
+		t.Errorf("Wrong compression header: %+v", *hdr)
+		return
+	}
+
+	_, err = section.Data()
+	if err == nil {
+		t.Errorf("Expected an error decompressing zstd data without a decompressor.")
+		return
+	}
+
+	RegisterDecompressor(CompressionZstd, func(r io.Reader) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(make([]byte, 0xfd))), nil
+	})
+	defer RegisterDecompressor(CompressionZstd, nil)
+
+	data, err := section.Data()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(data) != 0xfd {
+		t.Errorf("Wrong size of decompressed data: %d", len(data))
+	}
+}
@@ -0,0 +1,611 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// Values of type AuxType represent the type of an entry in the auxiliary
+// vector of a process.
+type AuxType uint64
+
+const (
+	// Name of the owner of the notes in core files which describe the process
+	// and its threads.
+	NoteNameCore = "CORE"
+
+	// Name of the owner of the notes in core files which carry the Linux
+	// specific register sets of the threads.
+	NoteNameLinux = "LINUX"
+)
+
+// Types of notes owned by "CORE".
+const (
+	NoteTypeCorePrStatus   NoteType = NoteType(1)
+	NoteTypeCoreFPRegSet   NoteType = NoteType(2)
+	NoteTypeCorePrPsInfo   NoteType = NoteType(3)
+	NoteTypeCoreTaskStruct NoteType = NoteType(4)
+	NoteTypeCoreAuxV       NoteType = NoteType(6)
+	NoteTypeCoreSigInfo    NoteType = NoteType(0x53494749)
+	NoteTypeCoreFile       NoteType = NoteType(0x46494c45)
+)
+
+// Types of notes owned by "LINUX".
+const (
+	NoteTypeLinuxX86XState      NoteType = NoteType(0x202)
+	NoteTypeLinuxAArch64TLS     NoteType = NoteType(0x401)
+	NoteTypeLinuxAArch64SVE     NoteType = NoteType(0x405)
+	NoteTypeLinuxAArch64PACMask NoteType = NoteType(0x406)
+)
+
+const (
+	AuxTypeNull          AuxType = AuxType(0)
+	AuxTypeIgnore        AuxType = AuxType(1)
+	AuxTypeExecFD        AuxType = AuxType(2)
+	AuxTypePhdr          AuxType = AuxType(3)
+	AuxTypePhEnt         AuxType = AuxType(4)
+	AuxTypePhNum         AuxType = AuxType(5)
+	AuxTypePageSize      AuxType = AuxType(6)
+	AuxTypeBase          AuxType = AuxType(7)
+	AuxTypeFlags         AuxType = AuxType(8)
+	AuxTypeEntry         AuxType = AuxType(9)
+	AuxTypeNotELF        AuxType = AuxType(10)
+	AuxTypeUID           AuxType = AuxType(11)
+	AuxTypeEUID          AuxType = AuxType(12)
+	AuxTypeGID           AuxType = AuxType(13)
+	AuxTypeEGID          AuxType = AuxType(14)
+	AuxTypePlatform      AuxType = AuxType(15)
+	AuxTypeHWCap         AuxType = AuxType(16)
+	AuxTypeClockTick     AuxType = AuxType(17)
+	AuxTypeSecure        AuxType = AuxType(23)
+	AuxTypeBasePlatform  AuxType = AuxType(24)
+	AuxTypeRandom        AuxType = AuxType(25)
+	AuxTypeHWCap2        AuxType = AuxType(26)
+	AuxTypeExecFn        AuxType = AuxType(31)
+	AuxTypeSysInfo       AuxType = AuxType(32)
+	AuxTypeSysInfoEHdr   AuxType = AuxType(33)
+	AuxTypeMinSigStkSize AuxType = AuxType(51)
+)
+
+// Names of the general purpose registers in the NT_PRSTATUS notes of x86-64
+// core files, in the order in which they are saved.
+var X86_64RegNames = []string{
+	"r15", "r14", "r13", "r12", "rbp", "rbx", "r11", "r10", "r9", "r8",
+	"rax", "rcx", "rdx", "rsi", "rdi", "orig_rax", "rip", "cs", "eflags",
+	"rsp", "ss", "fs_base", "gs_base", "ds", "es", "fs", "gs",
+}
+
+// Names of the general purpose registers in the NT_PRSTATUS notes of AArch64
+// core files, in the order in which they are saved.
+var AArch64RegNames = []string{
+	"x0", "x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8", "x9", "x10",
+	"x11", "x12", "x13", "x14", "x15", "x16", "x17", "x18", "x19", "x20",
+	"x21", "x22", "x23", "x24", "x25", "x26", "x27", "x28", "x29", "x30",
+	"sp", "pc", "pstate",
+}
+
+// Returns the names of the general purpose registers in the NT_PRSTATUS notes
+// of core files of the machine. Returns nil if the registers of the machine
+// are not decoded.
+func RegNames(machine MachineArch) []string {
+	switch machine {
+	case MachineX86_64:
+		return X86_64RegNames
+	case MachineAArch64:
+		return AArch64RegNames
+	}
+
+	return nil
+}
+
+// Returns the names of the program counter and the stack pointer registers of
+// the machine.
+func pcSPRegNames(machine MachineArch) (string, string) {
+	switch machine {
+	case MachineX86_64:
+		return "rip", "rsp"
+	case MachineAArch64:
+		return "pc", "sp"
+	}
+
+	return "", ""
+}
+
+// coreWords reads the words of a core note descriptor. Words are 4 bytes in
+// ELF32 core files and 8 bytes in ELF64 core files.
+type coreWords struct {
+	desc      []byte
+	size      uint64
+	endianess binary.ByteOrder
+}
+
+func newCoreWords(desc []byte, class ELFClass, endianess binary.ByteOrder) coreWords {
+	size := uint64(4)
+	if class == Class64 {
+		size = 8
+	}
+
+	return coreWords{desc, size, endianess}
+}
+
+// Returns the word at offset.
+func (w coreWords) word(offset uint64) uint64 {
+	if w.size == 4 {
+		return uint64(w.endianess.Uint32(w.desc[offset:]))
+	}
+
+	return w.endianess.Uint64(w.desc[offset:])
+}
+
+// Returns the duration in the struct timeval at offset.
+func (w coreWords) timeval(offset uint64) time.Duration {
+	sec := int64(w.word(offset))
+	usec := int64(w.word(offset + w.size))
+	if w.size == 4 {
+		sec = int64(int32(sec))
+		usec = int64(int32(usec))
+	}
+
+	return time.Duration(sec)*time.Second + time.Duration(usec)*time.Microsecond
+}
+
+// Returns the string in the fixed size field, up to the first NULL byte.
+func cString(field []byte) string {
+	if i := bytes.IndexByte(field, 0); i >= 0 {
+		field = field[:i]
+	}
+
+	return string(field)
+}
+
+// PrStatus is the decoded descriptor of a NT_PRSTATUS note. There is one
+// such note for every thread of the process in a core file.
+type PrStatus struct {
+	// The signal number, the code and the errno of the signal received by the
+	// thread.
+	Signal int32
+	Code   int32
+	Errno  int32
+
+	// The current signal.
+	CurSig uint16
+
+	// The masks of the pending and the blocked signals.
+	SigPend uint64
+	SigHold uint64
+
+	// The thread id, and the process, process group and session ids.
+	PID  int32
+	PPID int32
+	PGrp int32
+	SID  int32
+
+	// The user and system time of the thread, and the cumulative user and
+	// system time of its children.
+	UserTime  time.Duration
+	SysTime   time.Duration
+	CUserTime time.Duration
+	CSysTime  time.Duration
+
+	// The general purpose registers, in the order given by RegNames. Nil if
+	// the registers of the machine are not decoded.
+	Regs []uint64
+
+	// True if the floating point registers of the thread are valid.
+	FPValid bool
+
+	machine MachineArch
+}
+
+// Decodes the descriptor of a NT_PRSTATUS note. The general purpose registers
+// are decoded only for the x86-64 and AArch64 machines.
+func DecodePrStatus(
+	desc []byte,
+	class ELFClass,
+	machine MachineArch,
+	endianess binary.ByteOrder) (*PrStatus, error) {
+	w := newCoreWords(desc, class, endianess)
+
+	// The signal information and the current signal take up 16 bytes. They
+	// are followed by two words for the signal masks, four 4 byte ids, four
+	// struct timevals and the registers.
+	regsOffset := 32 + 10*w.size
+	if uint64(len(desc)) < regsOffset {
+		return nil, fmt.Errorf("NT_PRSTATUS descriptor is too short.")
+	}
+
+	status := new(PrStatus)
+	status.machine = machine
+	status.Signal = int32(endianess.Uint32(desc))
+	status.Code = int32(endianess.Uint32(desc[4:]))
+	status.Errno = int32(endianess.Uint32(desc[8:]))
+	status.CurSig = endianess.Uint16(desc[12:])
+	status.SigPend = w.word(16)
+	status.SigHold = w.word(16 + w.size)
+
+	offset := 16 + 2*w.size
+	status.PID = int32(endianess.Uint32(desc[offset:]))
+	status.PPID = int32(endianess.Uint32(desc[offset+4:]))
+	status.PGrp = int32(endianess.Uint32(desc[offset+8:]))
+	status.SID = int32(endianess.Uint32(desc[offset+12:]))
+
+	offset += 16
+	status.UserTime = w.timeval(offset)
+	status.SysTime = w.timeval(offset + 2*w.size)
+	status.CUserTime = w.timeval(offset + 4*w.size)
+	status.CSysTime = w.timeval(offset + 6*w.size)
+
+	names := RegNames(machine)
+	if names == nil || class != Class64 {
+		return status, nil
+	}
+
+	fpValidOffset := regsOffset + uint64(len(names))*w.size
+	if uint64(len(desc)) < fpValidOffset+4 {
+		return nil, fmt.Errorf("NT_PRSTATUS descriptor is too short for the registers.")
+	}
+	status.Regs = make([]uint64, len(names))
+	for i := range names {
+		status.Regs[i] = w.word(regsOffset + uint64(i)*w.size)
+	}
+	status.FPValid = endianess.Uint32(desc[fpValidOffset:]) != 0
+
+	return status, nil
+}
+
+// Returns the value of the general purpose register with the name as listed
+// by RegNames. The boolean value is false if the register is not known.
+func (status *PrStatus) Reg(name string) (uint64, bool) {
+	for i, regName := range RegNames(status.machine) {
+		if regName == name && i < len(status.Regs) {
+			return status.Regs[i], true
+		}
+	}
+
+	return 0, false
+}
+
+// Returns the program counter of the thread. Returns 0 if the registers of
+// the machine are not decoded.
+func (status *PrStatus) PC() uint64 {
+	pc, _ := pcSPRegNames(status.machine)
+	value, _ := status.Reg(pc)
+	return value
+}
+
+// Returns the stack pointer of the thread. Returns 0 if the registers of the
+// machine are not decoded.
+func (status *PrStatus) SP() uint64 {
+	_, sp := pcSPRegNames(status.machine)
+	value, _ := status.Reg(sp)
+	return value
+}
+
+// PrPsInfo is the decoded descriptor of a NT_PRPSINFO note. It describes the
+// process of a core file.
+type PrPsInfo struct {
+	// The numeric and the character state of the process, like 'R' or 'S'.
+	State byte
+	SName byte
+
+	Zombie bool
+	Nice   int8
+	Flags  uint64
+
+	UID  uint32
+	GID  uint32
+	PID  int32
+	PPID int32
+	PGrp int32
+	SID  int32
+
+	// The file name of the executable, truncated to 15 characters.
+	FileName string
+
+	// The initial part of the command line, with the arguments separated by
+	// spaces.
+	Args string
+}
+
+// Decodes the descriptor of a NT_PRPSINFO note. In ELF32 core files, the user
+// and group ids are 16 bits long on some machines like x86 and ARM. The size
+// of the ids is deduced from the size of the descriptor.
+func DecodePrPsInfo(
+	desc []byte, class ELFClass, endianess binary.ByteOrder) (*PrPsInfo, error) {
+	w := newCoreWords(desc, class, endianess)
+
+	// The process ids, the file name and the arguments take up 112 bytes.
+	flagsOffset := alignUp(4, w.size)
+	idsOffset := flagsOffset + w.size
+	idSize := uint64(4)
+	if class == Class32 && len(desc) == 124 {
+		idSize = 2
+	}
+	pidOffset := idsOffset + 2*idSize
+	if uint64(len(desc)) < pidOffset+112 {
+		return nil, fmt.Errorf("NT_PRPSINFO descriptor is too short.")
+	}
+
+	info := new(PrPsInfo)
+	info.State = desc[0]
+	info.SName = desc[1]
+	info.Zombie = desc[2] != 0
+	info.Nice = int8(desc[3])
+	info.Flags = w.word(flagsOffset)
+	if idSize == 2 {
+		info.UID = uint32(endianess.Uint16(desc[idsOffset:]))
+		info.GID = uint32(endianess.Uint16(desc[idsOffset+2:]))
+	} else {
+		info.UID = endianess.Uint32(desc[idsOffset:])
+		info.GID = endianess.Uint32(desc[idsOffset+4:])
+	}
+	info.PID = int32(endianess.Uint32(desc[pidOffset:]))
+	info.PPID = int32(endianess.Uint32(desc[pidOffset+4:]))
+	info.PGrp = int32(endianess.Uint32(desc[pidOffset+8:]))
+	info.SID = int32(endianess.Uint32(desc[pidOffset+12:]))
+	info.FileName = cString(desc[pidOffset+16 : pidOffset+32])
+	info.Args = cString(desc[pidOffset+32 : pidOffset+112])
+
+	return info, nil
+}
+
+// AuxVEntry is a single entry of the auxiliary vector of a process.
+type AuxVEntry struct {
+	Type  AuxType
+	Value uint64
+}
+
+// Decodes the descriptor of a NT_AUXV note. The terminating AT_NULL entry is
+// not included in the returned entries.
+func DecodeAuxV(desc []byte, class ELFClass, endianess binary.ByteOrder) ([]AuxVEntry, error) {
+	w := newCoreWords(desc, class, endianess)
+
+	var entries []AuxVEntry
+	for offset := uint64(0); offset < uint64(len(desc)); offset += 2 * w.size {
+		if offset+2*w.size > uint64(len(desc)) {
+			return nil, fmt.Errorf("Truncated auxiliary vector entry at offset 0x%x.", offset)
+		}
+
+		var entry AuxVEntry
+		entry.Type = AuxType(w.word(offset))
+		entry.Value = w.word(offset + w.size)
+		if entry.Type == AuxTypeNull {
+			break
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// MappedFile describes a range of the address space of a process to which a
+// file was mapped.
+type MappedFile struct {
+	// The virtual address range [Start, End) of the mapping.
+	Start uint64
+	End   uint64
+
+	// The offset in the file of the data mapped at Start.
+	Offset uint64
+
+	// The path of the file.
+	Name string
+}
+
+// Decodes the descriptor of a NT_FILE note.
+func DecodeMappedFiles(
+	desc []byte, class ELFClass, endianess binary.ByteOrder) ([]MappedFile, error) {
+	w := newCoreWords(desc, class, endianess)
+	if uint64(len(desc)) < 2*w.size {
+		return nil, fmt.Errorf("NT_FILE descriptor is too short.")
+	}
+
+	// The number of mappings and the page size are followed by a triple of
+	// words for every mapping, and then the names of the files.
+	count := w.word(0)
+	pageSize := w.word(w.size)
+	namesOffset := 2*w.size + 3*w.size*count
+	if count > uint64(len(desc)) || namesOffset > uint64(len(desc)) {
+		return nil, fmt.Errorf("NT_FILE descriptor is too short for %d mappings.", count)
+	}
+
+	files := make([]MappedFile, count)
+	names := desc[namesOffset:]
+	for i := range files {
+		offset := 2*w.size + 3*w.size*uint64(i)
+		files[i].Start = w.word(offset)
+		files[i].End = w.word(offset + w.size)
+		files[i].Offset = w.word(offset+2*w.size) * pageSize
+
+		end := bytes.IndexByte(names, 0)
+		if end < 0 {
+			return nil, fmt.Errorf("Name of mapped file %d is not NULL terminated.", i)
+		}
+		files[i].Name = string(names[:end])
+		names = names[end+1:]
+	}
+
+	return files, nil
+}
+
+// SigInfo is the decoded descriptor of a NT_SIGINFO note. It describes the
+// signal which caused the core dump.
+type SigInfo struct {
+	Signal int32
+	Errno  int32
+	Code   int32
+
+	// The faulting address of signals like SIGSEGV, SIGBUS, SIGILL and SIGFPE.
+	Addr uint64
+
+	// The process id and the user id of the sender of signals sent by kill
+	// and similar system calls.
+	PID int32
+	UID uint32
+}
+
+// Decodes the descriptor of a NT_SIGINFO note.
+func DecodeSigInfo(desc []byte, class ELFClass, endianess binary.ByteOrder) (*SigInfo, error) {
+	w := newCoreWords(desc, class, endianess)
+
+	// The union of signal specific fields follows the signal number, errno
+	// and code, and is aligned to a word.
+	unionOffset := alignUp(12, w.size)
+	if uint64(len(desc)) < unionOffset+w.size {
+		return nil, fmt.Errorf("NT_SIGINFO descriptor is too short.")
+	}
+
+	info := new(SigInfo)
+	info.Signal = int32(endianess.Uint32(desc))
+	info.Errno = int32(endianess.Uint32(desc[4:]))
+	info.Code = int32(endianess.Uint32(desc[8:]))
+	info.Addr = w.word(unionOffset)
+	info.PID = int32(endianess.Uint32(desc[unionOffset:]))
+	info.UID = endianess.Uint32(desc[unionOffset+4:])
+
+	return info, nil
+}
+
+// CoreThread is a thread of the process of a core file.
+type CoreThread struct {
+	Status *PrStatus
+
+	// The notes with the other register sets of the thread, like
+	// NT_FPREGSET and NT_X86_XSTATE.
+	RegSets []*Note
+}
+
+// Core is the decoded content of the notes of a core file.
+type Core struct {
+	elf *ELF
+
+	// The threads of the process. The first thread is the one which received
+	// the signal that caused the core dump.
+	Threads []*CoreThread
+
+	// The process information. Nil if the core file does not have a
+	// NT_PRPSINFO note.
+	ProcessInfo *PrPsInfo
+
+	// The signal which caused the core dump. Nil if the core file does not
+	// have a NT_SIGINFO note.
+	SigInfo *SigInfo
+
+	AuxV        []AuxVEntry
+	MappedFiles []MappedFile
+}
+
+// Returns the decoded notes of the core file. An error is returned if the ELF
+// file is not a core file.
+func (elf *ELF) Core() (*Core, error) {
+	if elf.header.Type() != TypeCore {
+		return nil, fmt.Errorf("ELF file is not a core file.")
+	}
+
+	notes, err := elf.Notes()
+	if err != nil {
+		return nil, err
+	}
+
+	core := new(Core)
+	core.elf = elf
+	class := elf.header.ELFIdent().Class
+	machine := elf.header.Machine()
+	e := elf.Endianess()
+	var thread *CoreThread
+	for _, note := range notes {
+		if note.Name == NoteNameCore {
+			switch note.Type {
+			case NoteTypeCorePrStatus:
+				status, err := DecodePrStatus(note.Desc, class, machine, e)
+				if err != nil {
+					return nil, err
+				}
+				thread = &CoreThread{Status: status}
+				core.Threads = append(core.Threads, thread)
+				continue
+			case NoteTypeCorePrPsInfo:
+				core.ProcessInfo, err = DecodePrPsInfo(note.Desc, class, e)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			case NoteTypeCoreSigInfo:
+				core.SigInfo, err = DecodeSigInfo(note.Desc, class, e)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			case NoteTypeCoreAuxV:
+				core.AuxV, err = DecodeAuxV(note.Desc, class, e)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			case NoteTypeCoreFile:
+				core.MappedFiles, err = DecodeMappedFiles(note.Desc, class, e)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		// The register sets of a thread follow its NT_PRSTATUS note.
+		if thread != nil {
+			thread.RegSets = append(thread.RegSets, note)
+		}
+	}
+
+	return core, nil
+}
+
+// Returns the value of the entry of type auxType in the auxiliary vector. The
+// boolean value is false if there is no such entry.
+func (core *Core) AuxValue(auxType AuxType) (uint64, bool) {
+	for _, entry := range core.AuxV {
+		if entry.Type == auxType {
+			return entry.Value, true
+		}
+	}
+
+	return 0, false
+}
+
+// Returns the mapped file containing the virtual address addr. Returns nil if
+// no file is mapped at addr.
+func (core *Core) MappedFileAt(addr uint64) *MappedFile {
+	for i := range core.MappedFiles {
+		file := &core.MappedFiles[i]
+		if addr >= file.Start && addr < file.End {
+			return file
+		}
+	}
+
+	return nil
+}
+
+// Returns a MemReader over the memory of the process dumped in the core file.
+// Reading memory which was not dumped, like the code of mapped files which
+// was left out by the kernel, is an error.
+func (core *Core) NewMemReader() *MemReader {
+	return newMemReader(core.elf)
+}
@@ -0,0 +1,224 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// Returns the core file of a program which crashed with a SIGSEGV while a
+// second thread was waiting. The core file is stored compressed.
+func readTestCore() (*ELF, error) {
+	file, err := os.Open("test_data/linux_x86_64.core.gz")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFile(bytes.NewReader(data), int64(len(data)))
+}
+
+func TestCore(t *testing.T) {
+	elf, err := readTestCore()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(elf.Sections()) != 0 || len(elf.ProgHdrTbl()) != 27 {
+		t.Errorf("Wrong number of sections or segments in the core file.")
+		return
+	}
+
+	core, err := elf.Core()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(core.Threads) != 2 {
+		t.Errorf("Wrong number of threads: %d", len(core.Threads))
+		return
+	}
+	crashed := core.Threads[0]
+	status := crashed.Status
+	if status.Signal != 11 || status.CurSig != 11 || status.PID != 12380 ||
+		status.PPID != 12369 || len(status.Regs) != len(X86_64RegNames) {
+		t.Errorf("Wrong status of the crashed thread: %v", status)
+		return
+	}
+	if status.PC() != 0x401236 || status.SP() != 0x7ffc9fbb9c80 {
+		t.Errorf("Wrong registers of the crashed thread: 0x%x, 0x%x",
+			status.PC(), status.SP())
+		return
+	}
+	if len(crashed.RegSets) != 2 || crashed.RegSets[0].Type != NoteTypeCoreFPRegSet ||
+		crashed.RegSets[1].Type != NoteTypeLinuxX86XState {
+		t.Errorf("Wrong register sets of the crashed thread.")
+		return
+	}
+	if core.Threads[1].Status.PID != 12381 || core.Threads[1].Status.PC() != 0x7fa62e87ddf2 {
+		t.Errorf("Wrong status of the second thread.")
+		return
+	}
+
+	info := core.ProcessInfo
+	if info == nil || info.PID != 12380 || info.SName != 'R' ||
+		info.FileName != "crash" || info.Args != "./crash " {
+		t.Errorf("Wrong process information: %v", info)
+		return
+	}
+
+	// A SEGV_MAPERR fault at address 0x10.
+	sig := core.SigInfo
+	if sig == nil || sig.Signal != 11 || sig.Code != 1 || sig.Addr != 0x10 {
+		t.Errorf("Wrong signal information: %v", sig)
+		return
+	}
+
+	entry, ok := core.AuxValue(AuxTypeEntry)
+	if !ok || entry != 0x401090 {
+		t.Errorf("Wrong entry point in the auxiliary vector: 0x%x", entry)
+		return
+	}
+	pageSize, ok := core.AuxValue(AuxTypePageSize)
+	if !ok || pageSize != 0x1000 || len(core.AuxV) != 22 {
+		t.Errorf("Wrong auxiliary vector.")
+		return
+	}
+
+	if len(core.MappedFiles) != 15 {
+		t.Errorf("Wrong number of mapped files: %d", len(core.MappedFiles))
+		return
+	}
+	exe := core.MappedFileAt(status.PC())
+	if exe == nil || exe.Name != "/tmp/core/crash" || exe.Start != 0x401000 ||
+		exe.End != 0x402000 || exe.Offset != 0x1000 {
+		t.Errorf("Wrong mapped file at the crash address: %v", exe)
+		return
+	}
+	libc := core.MappedFileAt(core.Threads[1].Status.PC())
+	if libc == nil || libc.Name != "/usr/lib/x86_64-linux-gnu/libc.so.6" ||
+		libc.Offset != 0x26000 {
+		t.Errorf("Wrong mapped file at the PC of the second thread: %v", libc)
+		return
+	}
+	if core.MappedFileAt(0x10) != nil {
+		t.Errorf("Found a mapped file at 0x10.")
+	}
+}
+
+func TestCoreMemReader(t *testing.T) {
+	elf, err := readTestCore()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	core, err := elf.Core()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	r := core.NewMemReader()
+
+	// The global 'golf_marker' of the crashed program.
+	marker := []byte("golf core marker\x00")
+	data, err := r.Read(0x404060, uint64(len(marker)))
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if !bytes.Equal(data, marker) {
+		t.Errorf("Wrong data read at 0x404060: %v", data)
+		return
+	}
+
+	// The code of the program was not dumped.
+	_, err = r.Read(0x401236, 4)
+	if err == nil {
+		t.Errorf("Expected an error reading memory which was not dumped.")
+		return
+	}
+
+	// The stack of the second thread was dumped.
+	_, err = r.Read(core.Threads[1].Status.SP(), 64)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// A reader over the plain ELF file reads it as zeros.
+	_, err = elf.NewMemReader().Read(0x401236, 4)
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestDecodePrStatusAArch64(t *testing.T) {
+	desc := make([]byte, 392)
+	e := binary.LittleEndian
+	e.PutUint32(desc, 5)
+	e.PutUint16(desc[12:], 5)
+	e.PutUint32(desc[32:], 42)
+	e.PutUint64(desc[48:], 3)
+	e.PutUint64(desc[56:], 250000)
+	for i := range AArch64RegNames {
+		e.PutUint64(desc[112+8*i:], uint64(0x1000+i))
+	}
+	e.PutUint32(desc[384:], 1)
+
+	status, err := DecodePrStatus(desc, Class64, MachineAArch64, e)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if status.Signal != 5 || status.CurSig != 5 || status.PID != 42 ||
+		status.UserTime.Seconds() != 3.25 || !status.FPValid {
+		t.Errorf("Wrong AArch64 status: %v", status)
+		return
+	}
+	if status.PC() != 0x1000+32 || status.SP() != 0x1000+31 {
+		t.Errorf("Wrong AArch64 PC or SP: 0x%x, 0x%x", status.PC(), status.SP())
+		return
+	}
+	x30, ok := status.Reg("x30")
+	if !ok || x30 != 0x1000+30 {
+		t.Errorf("Wrong AArch64 x30: 0x%x", x30)
+		return
+	}
+
+	_, err = DecodePrStatus(desc[:300], Class64, MachineAArch64, e)
+	if err == nil {
+		t.Errorf("Expected an error decoding a truncated descriptor.")
+	}
+}
@@ -0,0 +1,461 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"fmt"
+)
+
+// Values of type DynTag represent the tags of the entries in the dynamic
+// section.
+type DynTag int64
+
+// Values of type DynFlag are the bits of the value of the DT_FLAGS entry.
+type DynFlag uint64
+
+// Values of type DynFlag1 are the bits of the value of the DT_FLAGS_1 entry.
+type DynFlag1 uint64
+
+const (
+	DynTagNull              DynTag = DynTag(0)
+	DynTagNeeded            DynTag = DynTag(1)
+	DynTagPltRelSize        DynTag = DynTag(2)
+	DynTagPltGot            DynTag = DynTag(3)
+	DynTagHash              DynTag = DynTag(4)
+	DynTagStrTab            DynTag = DynTag(5)
+	DynTagSymTab            DynTag = DynTag(6)
+	DynTagRelA              DynTag = DynTag(7)
+	DynTagRelASize          DynTag = DynTag(8)
+	DynTagRelAEntSize       DynTag = DynTag(9)
+	DynTagStrSize           DynTag = DynTag(10)
+	DynTagSymEntSize        DynTag = DynTag(11)
+	DynTagInit              DynTag = DynTag(12)
+	DynTagFini              DynTag = DynTag(13)
+	DynTagSOName            DynTag = DynTag(14)
+	DynTagRPath             DynTag = DynTag(15)
+	DynTagSymbolic          DynTag = DynTag(16)
+	DynTagRel               DynTag = DynTag(17)
+	DynTagRelSize           DynTag = DynTag(18)
+	DynTagRelEntSize        DynTag = DynTag(19)
+	DynTagPltRel            DynTag = DynTag(20)
+	DynTagDebug             DynTag = DynTag(21)
+	DynTagTextRel           DynTag = DynTag(22)
+	DynTagJmpRel            DynTag = DynTag(23)
+	DynTagBindNow           DynTag = DynTag(24)
+	DynTagInitArray         DynTag = DynTag(25)
+	DynTagFiniArray         DynTag = DynTag(26)
+	DynTagInitArraySize     DynTag = DynTag(27)
+	DynTagFiniArraySize     DynTag = DynTag(28)
+	DynTagRunPath           DynTag = DynTag(29)
+	DynTagFlags             DynTag = DynTag(30)
+	DynTagPreInitArray      DynTag = DynTag(32)
+	DynTagPreInitArraySize  DynTag = DynTag(33)
+	DynTagSymTabShndx       DynTag = DynTag(34)
+	DynTagStartOSSpecific   DynTag = DynTag(0x6000000d)
+	DynTagGnuHash           DynTag = DynTag(0x6ffffef5)
+	DynTagVerSym            DynTag = DynTag(0x6ffffff0)
+	DynTagRelACount         DynTag = DynTag(0x6ffffff9)
+	DynTagRelCount          DynTag = DynTag(0x6ffffffa)
+	DynTagFlags1            DynTag = DynTag(0x6ffffffb)
+	DynTagVerDef            DynTag = DynTag(0x6ffffffc)
+	DynTagVerDefNum         DynTag = DynTag(0x6ffffffd)
+	DynTagVerNeed           DynTag = DynTag(0x6ffffffe)
+	DynTagVerNeedNum        DynTag = DynTag(0x6fffffff)
+	DynTagEndOSSpecific     DynTag = DynTag(0x6ffff000)
+	DynTagStartProcSpecific DynTag = DynTag(0x70000000)
+	DynTagEndProcSpecific   DynTag = DynTag(0x7fffffff)
+)
+
+const (
+	DynFlagOrigin    DynFlag = DynFlag(0x1)
+	DynFlagSymbolic  DynFlag = DynFlag(0x2)
+	DynFlagTextRel   DynFlag = DynFlag(0x4)
+	DynFlagBindNow   DynFlag = DynFlag(0x8)
+	DynFlagStaticTLS DynFlag = DynFlag(0x10)
+)
+
+const (
+	DynFlag1Now        DynFlag1 = DynFlag1(0x1)
+	DynFlag1Global     DynFlag1 = DynFlag1(0x2)
+	DynFlag1Group      DynFlag1 = DynFlag1(0x4)
+	DynFlag1NoDelete   DynFlag1 = DynFlag1(0x8)
+	DynFlag1LoadFltr   DynFlag1 = DynFlag1(0x10)
+	DynFlag1InitFirst  DynFlag1 = DynFlag1(0x20)
+	DynFlag1NoOpen     DynFlag1 = DynFlag1(0x40)
+	DynFlag1Origin     DynFlag1 = DynFlag1(0x80)
+	DynFlag1Direct     DynFlag1 = DynFlag1(0x100)
+	DynFlag1Trans      DynFlag1 = DynFlag1(0x200)
+	DynFlag1Interpose  DynFlag1 = DynFlag1(0x400)
+	DynFlag1NoDefLib   DynFlag1 = DynFlag1(0x800)
+	DynFlag1NoDump     DynFlag1 = DynFlag1(0x1000)
+	DynFlag1ConfAlt    DynFlag1 = DynFlag1(0x2000)
+	DynFlag1EndFiltee  DynFlag1 = DynFlag1(0x4000)
+	DynFlag1DispRelDne DynFlag1 = DynFlag1(0x8000)
+	DynFlag1DispRelPnd DynFlag1 = DynFlag1(0x10000)
+	DynFlag1NoDirect   DynFlag1 = DynFlag1(0x20000)
+	DynFlag1IgnMulDef  DynFlag1 = DynFlag1(0x40000)
+	DynFlag1NoKSyms    DynFlag1 = DynFlag1(0x80000)
+	DynFlag1NoHdr      DynFlag1 = DynFlag1(0x100000)
+	DynFlag1Edited     DynFlag1 = DynFlag1(0x200000)
+	DynFlag1NoReloc    DynFlag1 = DynFlag1(0x400000)
+	DynFlag1SymIntpose DynFlag1 = DynFlag1(0x800000)
+	DynFlag1GlobAudit  DynFlag1 = DynFlag1(0x1000000)
+	DynFlag1Singleton  DynFlag1 = DynFlag1(0x2000000)
+	DynFlag1Stub       DynFlag1 = DynFlag1(0x4000000)
+	DynFlag1PIE        DynFlag1 = DynFlag1(0x8000000)
+)
+
+// DynEntry is an entry in the dynamic section.
+type DynEntry struct {
+	Tag DynTag
+
+	// The value of the entry. Depending on the tag, it is either an integer
+	// value, an address, or an offset into the dynamic string table.
+	Value uint64
+}
+
+// DynArray describes an array of addresses, like the init or fini arrays,
+// referred to by the dynamic section.
+type DynArray struct {
+	// The virtual address of the array.
+	Addr uint64
+
+	// The byte size of the array.
+	Size uint64
+}
+
+// DynRelTbl describes a relocation table referred to by the dynamic section.
+type DynRelTbl struct {
+	// The virtual address of the table.
+	Addr uint64
+
+	// The byte size of the table.
+	Size uint64
+
+	// The byte size of an entry in the table.
+	EntrySize uint64
+}
+
+// Dynamic encapsulates the decoded contents of the dynamic section. Addresses
+// and sizes of tables which are not present are zero.
+type Dynamic struct {
+	// All entries of the dynamic section in the order in which they appear,
+	// excluding the terminating DT_NULL entry.
+	Entries []DynEntry
+
+	// The names of the needed libraries in the order of the DT_NEEDED entries.
+	Needed []string
+
+	SOName  string
+	RPath   string
+	RunPath string
+
+	Flags  DynFlag
+	Flags1 DynFlag1
+
+	// The addresses of the init and fini functions.
+	Init uint64
+	Fini uint64
+
+	InitArray    DynArray
+	FiniArray    DynArray
+	PreInitArray DynArray
+
+	// The addresses of the dynamic symbol, string and hash tables.
+	SymTab       uint64
+	SymEntrySize uint64
+	StrTab       uint64
+	StrSize      uint64
+	Hash         uint64
+	GnuHash      uint64
+
+	RelA DynRelTbl
+	Rel  DynRelTbl
+
+	// The relocation table of the PLT. Its entry size is not available in the
+	// dynamic section; it is the entry size of the table RelA or Rel
+	// depending on PltRelType.
+	JmpRel DynRelTbl
+
+	// The type of relocations in the PLT relocation table. It is either
+	// DynTagRelA or DynTagRel.
+	PltRelType DynTag
+	PltGot     uint64
+
+	// The addresses of the symbol versioning tables and the number of entries
+	// in the version definition and version needed tables.
+	VerSym     uint64
+	VerDef     uint64
+	VerDefNum  uint64
+	VerNeed    uint64
+	VerNeedNum uint64
+}
+
+// Returns true if the dynamic section has an entry with the tag.
+func (dyn *Dynamic) Has(tag DynTag) bool {
+	for _, entry := range dyn.Entries {
+		if entry.Tag == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the decoded contents of the dynamic section. The entries are read
+// from the PT_DYNAMIC segment if present, and from the section of type
+// SectTypeDynamic otherwise. String values are looked up in the string table
+// referred to by the DT_STRTAB entry. If the ELF was edited, the sections are
+// preferred as the segments describe the layout of the output file. If the
+// ELF file has neither a dynamic segment nor a dynamic section, then nil is
+// returned with a nil error.
+func (elf *ELF) Dynamic() (*Dynamic, error) {
+	if elf.dynamic != nil {
+		return elf.dynamic, nil
+	}
+
+	data, strTblSect, err := elf.readDynamicData()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	dyn := new(Dynamic)
+	dyn.Entries, err = readDynEntries(data, elf.Header().ELFIdent())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range dyn.Entries {
+		switch entry.Tag {
+		case DynTagPltRelSize:
+			dyn.JmpRel.Size = entry.Value
+		case DynTagPltGot:
+			dyn.PltGot = entry.Value
+		case DynTagHash:
+			dyn.Hash = entry.Value
+		case DynTagStrTab:
+			dyn.StrTab = entry.Value
+		case DynTagSymTab:
+			dyn.SymTab = entry.Value
+		case DynTagRelA:
+			dyn.RelA.Addr = entry.Value
+		case DynTagRelASize:
+			dyn.RelA.Size = entry.Value
+		case DynTagRelAEntSize:
+			dyn.RelA.EntrySize = entry.Value
+		case DynTagStrSize:
+			dyn.StrSize = entry.Value
+		case DynTagSymEntSize:
+			dyn.SymEntrySize = entry.Value
+		case DynTagInit:
+			dyn.Init = entry.Value
+		case DynTagFini:
+			dyn.Fini = entry.Value
+		case DynTagRel:
+			dyn.Rel.Addr = entry.Value
+		case DynTagRelSize:
+			dyn.Rel.Size = entry.Value
+		case DynTagRelEntSize:
+			dyn.Rel.EntrySize = entry.Value
+		case DynTagPltRel:
+			dyn.PltRelType = DynTag(entry.Value)
+		case DynTagJmpRel:
+			dyn.JmpRel.Addr = entry.Value
+		case DynTagInitArray:
+			dyn.InitArray.Addr = entry.Value
+		case DynTagFiniArray:
+			dyn.FiniArray.Addr = entry.Value
+		case DynTagInitArraySize:
+			dyn.InitArray.Size = entry.Value
+		case DynTagFiniArraySize:
+			dyn.FiniArray.Size = entry.Value
+		case DynTagFlags:
+			dyn.Flags = DynFlag(entry.Value)
+		case DynTagPreInitArray:
+			dyn.PreInitArray.Addr = entry.Value
+		case DynTagPreInitArraySize:
+			dyn.PreInitArray.Size = entry.Value
+		case DynTagGnuHash:
+			dyn.GnuHash = entry.Value
+		case DynTagVerSym:
+			dyn.VerSym = entry.Value
+		case DynTagFlags1:
+			dyn.Flags1 = DynFlag1(entry.Value)
+		case DynTagVerDef:
+			dyn.VerDef = entry.Value
+		case DynTagVerDefNum:
+			dyn.VerDefNum = entry.Value
+		case DynTagVerNeed:
+			dyn.VerNeed = entry.Value
+		case DynTagVerNeedNum:
+			dyn.VerNeedNum = entry.Value
+		}
+	}
+
+	strTbl, err := elf.readDynStrTbl(dyn, strTblSect)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range dyn.Entries {
+		var str *string
+		switch entry.Tag {
+		case DynTagNeeded:
+			dyn.Needed = append(dyn.Needed, "")
+			str = &dyn.Needed[len(dyn.Needed)-1]
+		case DynTagSOName:
+			str = &dyn.SOName
+		case DynTagRPath:
+			str = &dyn.RPath
+		case DynTagRunPath:
+			str = &dyn.RunPath
+		default:
+			continue
+		}
+
+		if strTbl == nil {
+			err = fmt.Errorf(
+				"Dynamic entry with tag 0x%x needs a string table, but the "+
+					"string table at 0x%x is not found.",
+				entry.Tag, dyn.StrTab)
+			return nil, err
+		}
+
+		if entry.Value > uint64(^uint32(0)) {
+			return nil, fmt.Errorf("Invalid dynamic string offset 0x%x.", entry.Value)
+		}
+		*str, err = strTbl.ReadStr(uint32(entry.Value))
+		if err != nil {
+			err = fmt.Errorf(
+				"Error reading string of dynamic entry with tag 0x%x.\n%s",
+				entry.Tag, err.Error())
+			return nil, err
+		}
+	}
+
+	elf.dynamic = dyn
+	return dyn, nil
+}
+
+// Returns the raw data of the dynamic section and the section holding the
+// string table linked to the dynamic section, if any.
+func (elf *ELF) readDynamicData() ([]byte, *Section, error) {
+	var dynSect *Section
+	var strTblSect *Section
+	for _, section := range elf.sections {
+		if section.SectHdr().Type() == SectTypeDynamic {
+			dynSect = section
+			break
+		}
+	}
+	if dynSect != nil {
+		link := dynSect.SectHdr().Link()
+		if link != 0 && link < uint32(len(elf.sections)) {
+			strTblSect = elf.sections[link]
+		}
+	}
+
+	for _, segHdr := range elf.progHdrTbl {
+		if segHdr.Type() != SegTypeDynamic || (elf.origSections != nil && dynSect != nil) {
+			continue
+		}
+
+		data, err := elf.SegData(segHdr)
+		if err != nil {
+			err = fmt.Errorf("Error reading the dynamic segment.\n%s", err.Error())
+			return nil, nil, err
+		}
+
+		return data, strTblSect, nil
+	}
+
+	if dynSect == nil {
+		return nil, nil, nil
+	}
+
+	data, err := dynSect.Data()
+	if err != nil {
+		err = fmt.Errorf("Error reading the dynamic section.\n%s", err.Error())
+		return nil, nil, err
+	}
+
+	return data, strTblSect, nil
+}
+
+// Returns the dynamic string table. It is looked up using the
+// DT_STRTAB and DT_STRSZ entries, falling back to the string table section
+// linked to the dynamic section, which is preferred if the ELF was edited.
+// Returns nil if neither is found.
+func (elf *ELF) readDynStrTbl(dyn *Dynamic, strTblSect *Section) (*RawStrTbl, error) {
+	if dyn.StrTab != 0 && (elf.origSections == nil || strTblSect == nil) {
+		offset, err := elf.addrToOffset(dyn.StrTab, dyn.StrSize)
+		if err == nil {
+			data, err := elf.readAt(offset, dyn.StrSize)
+			if err != nil {
+				return nil, err
+			}
+			return NewRawStrTbl(data), nil
+		}
+	}
+
+	if strTblSect == nil {
+		return nil, nil
+	}
+
+	data, err := strTblSect.Data()
+	if err != nil {
+		err = fmt.Errorf("Error reading the dynamic string table.\n%s", err.Error())
+		return nil, err
+	}
+
+	return NewRawStrTbl(data), nil
+}
+
+func readDynEntries(data []byte, ident *ELFIdent) ([]DynEntry, error) {
+	endianess := endianMap[ident.Endianess]
+	if endianess == nil {
+		return nil, fmt.Errorf("Invalid endianess %d.", ident.Endianess)
+	}
+
+	entrySize := 16
+	if ident.Class == Class32 {
+		entrySize = 8
+	}
+
+	var entries []DynEntry
+	for offset := 0; offset+entrySize <= len(data); offset += entrySize {
+		var entry DynEntry
+		if ident.Class == Class32 {
+			entry.Tag = DynTag(int32(endianess.Uint32(data[offset:])))
+			entry.Value = uint64(endianess.Uint32(data[offset+4:]))
+		} else {
+			entry.Tag = DynTag(int64(endianess.Uint64(data[offset:])))
+			entry.Value = endianess.Uint64(data[offset+8:])
+		}
+
+		if entry.Tag == DynTagNull {
+			return entries, nil
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return nil, fmt.Errorf("Dynamic section is not terminated by a DT_NULL entry.")
+}
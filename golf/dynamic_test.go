@@ -0,0 +1,123 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"testing"
+)
+
+func TestDynamicExe(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	dyn, err := elf.Dynamic()
+	if err != nil {
+		t.Errorf("Error reading the dynamic section.\n%s", err.Error())
+		return
+	}
+	if dyn == nil {
+		t.Errorf("Dynamic section not found.")
+		return
+	}
+
+	if len(dyn.Entries) != 23 {
+		t.Errorf("Wrong number of dynamic entries %d, expecting 23.", len(dyn.Entries))
+	}
+	if len(dyn.Needed) != 1 || dyn.Needed[0] != "libc.so.6" {
+		t.Errorf("Wrong needed libraries %v.", dyn.Needed)
+	}
+	if dyn.SOName != "" || dyn.RPath != "" || dyn.RunPath != "" {
+		t.Errorf("Unexpected SONAME, RPATH or RUNPATH in an executable.")
+	}
+	if dyn.Init != 0x4003a8 || dyn.Fini != 0x400574 {
+		t.Errorf("Wrong init or fini address.")
+	}
+	if dyn.InitArray != (DynArray{0x600e10, 8}) || dyn.FiniArray != (DynArray{0x600e18, 8}) {
+		t.Errorf("Wrong init or fini array.")
+	}
+	if dyn.SymTab != 0x4002b8 || dyn.StrTab != 0x400300 || dyn.StrSize != 56 {
+		t.Errorf("Wrong dynamic symbol or string table.")
+	}
+	if dyn.GnuHash != 0x400298 || dyn.Hash != 0 {
+		t.Errorf("Wrong hash tables.")
+	}
+	if dyn.RelA != (DynRelTbl{0x400360, 24, 24}) {
+		t.Errorf("Wrong RELA table %v.", dyn.RelA)
+	}
+	if dyn.JmpRel != (DynRelTbl{0x400378, 48, 0}) || dyn.PltRelType != DynTagRelA {
+		t.Errorf("Wrong PLT relocation table %v.", dyn.JmpRel)
+	}
+	if dyn.VerNeed != 0x400340 || dyn.VerNeedNum != 1 || dyn.VerSym != 0x400338 {
+		t.Errorf("Wrong symbol versioning tables.")
+	}
+	if !dyn.Has(DynTagDebug) || dyn.Has(DynTagFlags) {
+		t.Errorf("Wrong result checking the presence of dynamic entries.")
+	}
+}
+
+func TestDynamicSharedLib(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	dyn, err := elf.Dynamic()
+	if err != nil {
+		t.Errorf("Error reading the dynamic section.\n%s", err.Error())
+		return
+	}
+
+	if dyn.SOName != "libgolf.so.1" {
+		t.Errorf("Wrong SONAME '%s'.", dyn.SOName)
+	}
+	if dyn.RunPath != "$ORIGIN/../lib:/opt/golf/lib" || dyn.RPath != "" {
+		t.Errorf("Wrong RUNPATH '%s'.", dyn.RunPath)
+	}
+	if dyn.Flags != DynFlagBindNow || dyn.Flags1 != DynFlag1Now {
+		t.Errorf("Wrong dynamic flags 0x%x and 0x%x.", dyn.Flags, dyn.Flags1)
+	}
+	if dyn.Hash != 0x228 || dyn.GnuHash != 0x270 {
+		t.Errorf("Wrong hash tables.")
+	}
+	if dyn.VerDef != 0x4e0 || dyn.VerDefNum != 3 {
+		t.Errorf("Wrong version definition table.")
+	}
+}
+
+func TestDynamicRelocatable(t *testing.T) {
+	elf, err := Read("test_data/linux_x86.o")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	dyn, err := elf.Dynamic()
+	if err != nil {
+		t.Errorf("Error reading the dynamic section.\n%s", err.Error())
+		return
+	}
+	if dyn != nil {
+		t.Errorf("Unexpected dynamic section in a relocatable file.")
+	}
+}
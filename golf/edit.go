@@ -0,0 +1,337 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"fmt"
+	"math"
+)
+
+// SectHdrFields holds the fields of a section header independent of the
+// class of the ELF file. It is used to create new section headers.
+type SectHdrFields struct {
+	NameIndex uint32
+	Type      SectType
+	Flags     uint64
+	Address   uint64
+	Offset    uint64
+	Size      uint64
+	Link      uint32
+	Info      uint32
+	Alignment uint64
+	EntrySize uint64
+}
+
+// SegHdrFields holds the fields of a segment header independent of the class
+// of the ELF file. It is used to create new segment headers.
+type SegHdrFields struct {
+	Type            uint32
+	Offset          uint64
+	VirtualAddress  uint64
+	PhysicalAddress uint64
+	FileSize        uint64
+	MemSize         uint64
+	Flags           uint32
+	Alignment       uint64
+}
+
+// Returns the fields of the section header.
+func SectHdrFieldsOf(hdr SectHdr) SectHdrFields {
+	return SectHdrFields{
+		NameIndex: hdr.NameIndex(),
+		Type:      hdr.Type(),
+		Flags:     hdr.Flags(),
+		Address:   hdr.Address(),
+		Offset:    hdr.Offset(),
+		Size:      hdr.Size(),
+		Link:      hdr.Link(),
+		Info:      hdr.Info(),
+		Alignment: hdr.Alignment(),
+		EntrySize: hdr.EntrySize(),
+	}
+}
+
+// Returns the fields of the segment header.
+func SegHdrFieldsOf(hdr SegHdr) SegHdrFields {
+	return SegHdrFields{
+		Type:            hdr.Type(),
+		Offset:          hdr.Offset(),
+		VirtualAddress:  hdr.VirtualAddress(),
+		PhysicalAddress: hdr.PhysicalAddress(),
+		FileSize:        hdr.FileSize(),
+		MemSize:         hdr.MemSize(),
+		Flags:           hdr.Flags(),
+		Alignment:       hdr.Alignment(),
+	}
+}
+
+// Returns a new section header of the class with the given fields. An error
+// is returned if a field does not fit in the header of a 32-bit ELF file.
+func NewSectHdr(class ELFClass, fields SectHdrFields) (SectHdr, error) {
+	switch class {
+	case Class32:
+		if !fitsUint32(fields.Flags, fields.Address, fields.Offset, fields.Size,
+			fields.Alignment, fields.EntrySize) {
+			return nil, fmt.Errorf("Section header fields do not fit in a 32-bit header.")
+		}
+
+		hdr := new(sectHdr32)
+		hdr.diskData.NameIndex = fields.NameIndex
+		hdr.diskData.Type = fields.Type
+		hdr.diskData.Flags = uint32(fields.Flags)
+		hdr.diskData.Addr = uint32(fields.Address)
+		hdr.diskData.Offset = uint32(fields.Offset)
+		hdr.diskData.Size = uint32(fields.Size)
+		hdr.diskData.Link = fields.Link
+		hdr.diskData.Info = fields.Info
+		hdr.diskData.AddrAlign = uint32(fields.Alignment)
+		hdr.diskData.EntSize = uint32(fields.EntrySize)
+		return hdr, nil
+	case Class64:
+		hdr := new(sectHdr64)
+		hdr.diskData.NameIndex = fields.NameIndex
+		hdr.diskData.Type = fields.Type
+		hdr.diskData.Flags = fields.Flags
+		hdr.diskData.Addr = fields.Address
+		hdr.diskData.Offset = fields.Offset
+		hdr.diskData.Size = fields.Size
+		hdr.diskData.Link = fields.Link
+		hdr.diskData.Info = fields.Info
+		hdr.diskData.AddrAlign = fields.Alignment
+		hdr.diskData.EntSize = fields.EntrySize
+		return hdr, nil
+	default:
+		return nil, fmt.Errorf("Invalid ELF class %d.", class)
+	}
+}
+
+// Returns a new segment header of the class with the given fields. An error
+// is returned if a field does not fit in the header of a 32-bit ELF file.
+func NewSegHdr(class ELFClass, fields SegHdrFields) (SegHdr, error) {
+	switch class {
+	case Class32:
+		if !fitsUint32(fields.Offset, fields.VirtualAddress, fields.PhysicalAddress,
+			fields.FileSize, fields.MemSize, fields.Alignment) {
+			return nil, fmt.Errorf("Segment header fields do not fit in a 32-bit header.")
+		}
+
+		hdr := new(segHdr32)
+		hdr.diskData.Type = fields.Type
+		hdr.diskData.Offset = uint32(fields.Offset)
+		hdr.diskData.VirtualAddress = uint32(fields.VirtualAddress)
+		hdr.diskData.PhysicalAddress = uint32(fields.PhysicalAddress)
+		hdr.diskData.FileSize = uint32(fields.FileSize)
+		hdr.diskData.MemSize = uint32(fields.MemSize)
+		hdr.diskData.Flags = fields.Flags
+		hdr.diskData.Alignment = uint32(fields.Alignment)
+		return hdr, nil
+	case Class64:
+		hdr := new(segHdr64)
+		hdr.diskData.Type = fields.Type
+		hdr.diskData.Offset = fields.Offset
+		hdr.diskData.VirtualAddress = fields.VirtualAddress
+		hdr.diskData.PhysicalAddress = fields.PhysicalAddress
+		hdr.diskData.FileSize = fields.FileSize
+		hdr.diskData.MemSize = fields.MemSize
+		hdr.diskData.Flags = fields.Flags
+		hdr.diskData.Alignment = fields.Alignment
+		return hdr, nil
+	default:
+		return nil, fmt.Errorf("Invalid ELF class %d.", class)
+	}
+}
+
+func fitsUint32(values ...uint64) bool {
+	for _, v := range values {
+		if v > math.MaxUint32 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Replaces the data of the section. The data is written as is by Write, and
+// the size of the section is updated to the size of the data, unless the
+// section is of type SectTypeNoBits. Note that for a compressed section, the
+// data should include the compression header, or the header of the section
+// should be updated using SetSectHdr to clear the flag SectFlagCompressed.
+func (section *Section) SetData(data []byte) {
+	section.elf.beginEdit()
+
+	section.data = data
+	section.modified = true
+}
+
+// Replaces the header of the section. The name index, and the offset of
+// sections which are not in a segment, are recomputed by Write.
+func (section *Section) SetSectHdr(hdr SectHdr) {
+	section.elf.beginEdit()
+
+	section.header = hdr
+	section.elf.sectHdrTbl[section.elf.sectionIndex(section)] = hdr
+}
+
+// Renames the section. The section name string table is rebuilt by Write if
+// any section is renamed.
+func (section *Section) SetName(name string) {
+	section.elf.beginEdit()
+
+	section.name = name
+	section.renamed = true
+	section.elf.sectMap = buildSectMap(section.elf.sections)
+}
+
+// Appends a new section with the header and data to the section header table.
+// The name index and offset in the header are computed by Write. The new
+// section is returned.
+func (elf *ELF) AddSection(name string, hdr SectHdr, data []byte) *Section {
+	elf.beginEdit()
+
+	section := newSection(name, hdr, elf)
+	section.origHeader = nil
+	section.data = data
+	section.modified = true
+	section.renamed = true
+
+	elf.sections = append(elf.sections, section)
+	elf.sectHdrTbl = append(elf.sectHdrTbl, hdr)
+	elf.sectMap = buildSectMap(elf.sections)
+	return section
+}
+
+// Removes the section from the section header table. The link and info fields
+// of the other section headers which refer to sections by index are updated,
+// and they are set to zero if they refer to the removed section. Symbol tables
+// are not updated.
+func (elf *ELF) RemoveSection(section *Section) error {
+	index := elf.sectionIndex(section)
+	if index <= 0 {
+		return fmt.Errorf("Section '%s' cannot be removed.", section.Name())
+	}
+	if uint32(index) == elf.sectNameTblIndex {
+		return fmt.Errorf("The section name string table cannot be removed.")
+	}
+
+	elf.beginEdit()
+
+	elf.sections = append(elf.sections[:index:index], elf.sections[index+1:]...)
+	elf.sectHdrTbl = append(elf.sectHdrTbl[:index:index], elf.sectHdrTbl[index+1:]...)
+	if elf.sectNameTblIndex > uint32(index) {
+		elf.sectNameTblIndex--
+	}
+
+	remap := func(i uint32) uint32 {
+		switch {
+		case i == uint32(index):
+			return 0
+		case i > uint32(index):
+			return i - 1
+		default:
+			return i
+		}
+	}
+
+	for i, s := range elf.sections {
+		fields := SectHdrFieldsOf(s.header)
+		link, info := fields.Link, fields.Info
+		if sectHdrLinksSection(fields.Type) {
+			fields.Link = remap(fields.Link)
+		}
+		if sectHdrInfoIsSection(fields.Type, fields.Flags) {
+			fields.Info = remap(fields.Info)
+		}
+		if fields.Link == link && fields.Info == info {
+			continue
+		}
+
+		hdr, err := NewSectHdr(s.header.Class(), fields)
+		if err != nil {
+			return err
+		}
+		s.header = hdr
+		elf.sectHdrTbl[i] = hdr
+	}
+
+	elf.sectMap = buildSectMap(elf.sections)
+	return nil
+}
+
+// Replaces the program header table. Write places the table at its original
+// offset if it fits there, and at the end of the file otherwise.
+func (elf *ELF) SetProgHdrTbl(progHdrTbl []SegHdr) {
+	elf.beginEdit()
+
+	elf.progHdrTbl = progHdrTbl
+}
+
+// Returns true if the link field of section headers of the type is a section
+// index.
+func sectHdrLinksSection(sectType SectType) bool {
+	switch sectType {
+	case SectTypeUnused, SectTypeProgBits, SectTypeNoBits, SectTypeNotes,
+		SectTypeStrTab, SectTypeInitArray, SectTypeFinalizeArray, SectTypePreInitArray:
+		return false
+	default:
+		return true
+	}
+}
+
+// Returns true if the info field of section headers of the type and flags is
+// a section index.
+func sectHdrInfoIsSection(sectType SectType, flags uint64) bool {
+	if flags&SectFlagInfoLink != 0 {
+		return true
+	}
+
+	return sectType == SectTypeRel || sectType == SectTypeRelA
+}
+
+// Returns the index of the section in the section header table, or -1 if the
+// section is not in the table.
+func (elf *ELF) sectionIndex(section *Section) int {
+	for i, s := range elf.sections {
+		if s == section {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Records the layout of the ELF file before the first edit, so that Write can
+// tell which parts of the file have moved, and invalidates all data decoded
+// from the sections.
+func (elf *ELF) beginEdit() {
+	if elf.origSections == nil {
+		elf.origSections = append([]*Section(nil), elf.sections...)
+	}
+
+	elf.symTab = nil
+	elf.dynSymTab = nil
+	elf.dynamic = nil
+	elf.hashTbls = nil
+}
+
+func buildSectMap(sections []*Section) SectMap {
+	sectMap := make(SectMap, len(sections))
+	for _, section := range sections {
+		sectMap[section.name] = append(sectMap[section.name], section)
+	}
+
+	return sectMap
+}
@@ -0,0 +1,151 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// Adds the ELF files in test_data, and their malformed variants, to the seed
+// corpus of f. The corpus in testdata/fuzz holds malformed files which once
+// made golf panic.
+func addSeedFiles(f *testing.F) {
+	fileNames, err := filepath.Glob("test_data/*")
+	if err != nil {
+		f.Fatal(err.Error())
+	}
+
+	for _, fileName := range fileNames {
+		// Compressed files and archives are not ELF files.
+		if ext := filepath.Ext(fileName); ext == ".gz" || ext == ".a" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			f.Fatal(err.Error())
+		}
+		f.Add(data)
+	}
+
+	files, err := malformedFiles()
+	if err != nil {
+		f.Fatal(err.Error())
+	}
+	for _, file := range files {
+		f.Add(file.data)
+	}
+}
+
+// Reads everything golf can decode from the ELF file in data. Errors are
+// expected for malformed files, but golf should not panic.
+func readAll(elf *ELF) {
+	for _, section := range elf.Sections() {
+		section.Data()
+	}
+	for _, segHdr := range elf.ProgHdrTbl() {
+		elf.SegData(segHdr)
+	}
+
+	elf.Symbols()
+	elf.DynamicSymbols()
+	elf.LookupSymbolByAddr(elf.Header().EntryPoint())
+	elf.LookupDynamicSymbol("main", "")
+	elf.CheckHashTbls()
+	elf.Dynamic()
+	elf.DynamicRelocs()
+	elf.RelocTbls()
+	elf.VersionDefinitions()
+	elf.VersionRequirements()
+	elf.SymbolVersionIndeces()
+	elf.Notes()
+	elf.GnuABITag()
+	elf.GnuFeatures()
+	elf.Interpreter()
+	elf.GnuDebugLink()
+	elf.Core()
+	elf.NewMemReader().Read(elf.Header().EntryPoint(), 16)
+}
+
+func FuzzRead(f *testing.F) {
+	addSeedFiles(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		Validate(bytes.NewReader(data), int64(len(data)))
+
+		elf, err := NewFile(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return
+		}
+
+		readAll(elf)
+	})
+}
+
+func FuzzWrite(f *testing.F) {
+	addSeedFiles(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		elf, err := NewFile(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return
+		}
+
+		var buf bytes.Buffer
+		err = elf.Write(&buf)
+		if err != nil {
+			return
+		}
+
+		// Files written by golf can be read back by golf.
+		_, err = NewFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Errorf("Error reading the written file.\n%s", err.Error())
+		}
+	})
+}
+
+func FuzzReadArchive(f *testing.F) {
+	fileNames, err := filepath.Glob("test_data/*.a")
+	if err != nil {
+		f.Fatal(err.Error())
+	}
+	for _, fileName := range fileNames {
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			f.Fatal(err.Error())
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		archive, err := NewArchive(bytes.NewReader(data), int64(len(data)))
+		if err != nil || archive.Thin {
+			return
+		}
+
+		for _, member := range archive.Members {
+			elf, err := member.Open()
+			if err == nil {
+				readAll(elf)
+			}
+		}
+	})
+}
@@ -18,8 +18,10 @@
 package golf
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -30,7 +32,47 @@ type ELF struct {
 	progHdrTbl       []SegHdr
 	sectHdrTbl       []SectHdr
 	sectMap          SectMap
+	sections         []*Section
 	sectNameTblIndex uint32
+
+	// Indexes over the symbol tables. They are nil until the symbols are read.
+	symTab    *symTabIndex
+	dynSymTab *symTabIndex
+
+	// The decoded dynamic section. It is nil until the dynamic section is
+	// read.
+	dynamic *Dynamic
+
+	// The decoded symbol hash tables. They are nil until the hash tables are
+	// read.
+	hashTbls *hashTbls
+
+	// The sections as read from the file. It is nil until the ELF is first
+	// edited.
+	origSections []*Section
+
+	// The offset of the loadable segment which holds the data moved out of
+	// its original place by edits of the dynamic properties. It is zero if
+	// there is no such segment.
+	patchSegOffset uint64
+
+	// The reader through which all data of the ELF file is read.
+	reader io.ReaderAt
+
+	// The size of the ELF file in bytes.
+	size int64
+
+	// The memory mapping of the ELF file. It is non-nil only if the ELF was
+	// read using ReadMapped, in which case section data are slices of it.
+	mapping mapping
+
+	// The closer to be closed when the ELF is closed. It is non-nil only if
+	// the ELF owns the underlying file, as is the case with Read and
+	// ReadMapped.
+	closer io.Closer
+
+	// Set to true after a call to Close.
+	closed bool
 }
 
 // Returns the ELF header.
@@ -43,16 +85,14 @@ func (elf *ELF) Endianess() binary.ByteOrder {
 	return endianMap[elf.Header().ELFIdent().Endianess]
 }
 
-// Returns the address size of the architecture in bytes.
+// Returns the address size of the architecture in bytes. It is inferred from
+// the class of the ELF file, as ILP32 ABIs of 64-bit machines, like x32 and
+// MIPS n32, use 32-bit ELF files. Returns 0 if the class is invalid.
 func (elf *ELF) AddressSize() uint8 {
-	switch elf.Header().Machine() {
-	case MachineX86:
-		return 4
-	case MachineARM:
+	switch elf.Header().ELFIdent().Class {
+	case Class32:
 		return 4
-	case MachineX86_64:
-		return 8
-	case MachineAArch64:
+	case Class64:
 		return 8
 	default:
 		return 0
@@ -77,6 +117,13 @@ func (elf *ELF) SectNameTblIndex() uint32 {
 	return elf.sectNameTblIndex
 }
 
+// Returns the sections of the ELF file in the order of their headers in the
+// section header table. That is, the section at index i in the returned slice
+// is the section whose header is at index i in the section header table.
+func (elf *ELF) Sections() []*Section {
+	return elf.sections
+}
+
 // Returns a mapping from section names to a slice of sections. Since multiple
 // sections can have the same name, each name maps to a slice of sections having
 // that same name.
@@ -84,38 +131,154 @@ func (elf *ELF) SectMap() SectMap {
 	return elf.sectMap
 }
 
+// Returns the size of the ELF file in bytes.
+func (elf *ELF) Size() int64 {
+	return elf.size
+}
+
+// Closes the underlying file if it was opened by Read, or releases the
+// memory mapping if the ELF was read using ReadMapped. For an ELF created by
+// NewFile, the caller owns the reader and it is not closed. Section data
+// which was not read before a call to Close cannot be read after it.
+//
+// If the ELF was read using ReadMapped, then all slices previously returned
+// by Section.Data, and readers returned by Section.NewReader, become invalid
+// after a call to Close.
+func (elf *ELF) Close() error {
+	if elf.closed {
+		return nil
+	}
+
+	elf.closed = true
+	elf.mapping = nil
+	if elf.closer == nil {
+		return nil
+	}
+
+	err := elf.closer.Close()
+	elf.closer = nil
+	return err
+}
+
+// Reads size bytes of raw data at offset in the ELF file. If the ELF is
+// memory mapped, the returned slice is a slice of the mapping.
+func (elf *ELF) readAt(offset uint64, size uint64) ([]byte, error) {
+	if elf.closed {
+		return nil, fmt.Errorf("Cannot read data after the ELF is closed.")
+	}
+
+	if offset > uint64(elf.size) || size > uint64(elf.size)-offset {
+		err := fmt.Errorf(
+			"Data of size %d at offset 0x%x lies beyond the end of file.", size, offset)
+		return nil, err
+	}
+
+	if elf.mapping != nil {
+		// The full slice expression caps the capacity so that appends by
+		// the caller do not scribble over the mapping.
+		return elf.mapping[offset : offset+size : offset+size], nil
+	}
+
+	data := make([]byte, size)
+	_, err := elf.reader.ReadAt(data, int64(offset))
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
 // Reads in an ELF file whose path is given by the string value fileName.
 // If successful, it returns a pointer to the ELF object and nil error.
 // If reading the file fails, then nil is returned along with the
 // appropriate error message.
-func Read(fileName string) (elf *ELF, err error) {
+//
+// The file is kept open so that section data can be read on demand. Call
+// Close on the returned ELF to close it.
+func Read(fileName string) (*ELF, error) {
 	file, err := os.Open(fileName)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to open file '%s'.\n%s", fileName, err.Error())
 	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Unable to stat '%s'.\n%s", fileName, err.Error())
+	}
+
+	elf, err := NewFile(file, fileInfo.Size())
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Error reading ELF file '%s'.\n%s", fileName, err.Error())
+	}
+
+	elf.closer = file
+	return elf, nil
+}
+
+// Reads in an ELF file whose path is given by the string value fileName by
+// memory mapping it. Section data of the returned ELF are slices of the
+// mapping and are not copied. The mapping stays alive until Close is called
+// on the returned ELF. Memory mapping is supported only on Unix-like
+// platforms.
+func ReadMapped(fileName string) (*ELF, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to open file '%s'.\n%s", fileName, err.Error())
+	}
+	// The mapping stays valid after the file is closed.
 	defer file.Close()
 
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to stat '%s'.\n%s", fileName, err.Error())
+	}
+
+	m, err := mapFile(file, fileInfo.Size())
+	if err != nil {
+		return nil, fmt.Errorf("Unable to map file '%s'.\n%s", fileName, err.Error())
+	}
+
+	elf, err := NewFile(bytes.NewReader(m), int64(len(m)))
+	if err != nil {
+		m.Close()
+		return nil, fmt.Errorf("Error reading ELF file '%s'.\n%s", fileName, err.Error())
+	}
+
+	elf.mapping = m
+	elf.closer = m
+	return elf, nil
+}
+
+// Reads an ELF file from the reader r. The value size is the size of the ELF
+// file in bytes. The reader is retained by the returned ELF object to read
+// section data on demand. Hence, it should remain valid for as long as the
+// ELF object is in use.
+func NewFile(r io.ReaderAt, size int64) (elf *ELF, err error) {
 	elf = new(ELF)
-	elf.header, err = readHeader(file)
+	elf.reader = r
+	elf.size = size
+
+	elf.header, err = readHeader(r, size)
 	if err != nil {
-		return nil, fmt.Errorf("Error reading header from '%s'.\n%s", fileName, err.Error())
+		return nil, fmt.Errorf("Error reading header.\n%s", err.Error())
 	}
 
-	sectHdrTbl, sectNameTblIndex, err := readSectHdrTbl(file, elf.header)
+	sectHdrTbl, sectNameTblIndex, err := readSectHdrTbl(r, size, elf.header)
 	if err != nil {
-		err := fmt.Errorf(
-			"Error reading section header table from '%s'.\n%s", fileName, err.Error())
+		err := fmt.Errorf("Error reading section header table.\n%s", err.Error())
 		return nil, err
 	}
 	elf.sectHdrTbl = sectHdrTbl
 	elf.sectNameTblIndex = sectNameTblIndex
 
-	elf.sectMap, err = readSectMap(file, sectHdrTbl, sectNameTblIndex)
+	elf.sectMap, elf.sections, err = readSectMap(elf, sectHdrTbl, sectNameTblIndex)
 	if err != nil {
 		return nil, err
 	}
 
-	elf.progHdrTbl, err = readSegHdrTbl(file, elf.header)
+	elf.progHdrTbl, err = readSegHdrTbl(r, size, elf.header)
 	if err != nil {
 		return nil, err
 	}
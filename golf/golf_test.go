@@ -0,0 +1,75 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestNewFile(t *testing.T) {
+	data, err := ioutil.ReadFile("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Errorf("Unable to read test file.\n%s", err.Error())
+		return
+	}
+
+	elf, err := NewFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Errorf("Reading an ELF file from memory failed.\n%s", err.Error())
+		return
+	}
+
+	testMagicNumber(t, elf)
+	testClass(t, elf)
+	testMachine(t, elf)
+
+	if elf.Size() != int64(len(data)) {
+		t.Errorf("Wrong ELF size %d.", elf.Size())
+	}
+
+	if len(elf.SectHdrTbl()) != 35 {
+		t.Errorf("Wrong number of section headers %d.", len(elf.SectHdrTbl()))
+	}
+
+	if len(elf.ProgHdrTbl()) != 9 {
+		t.Errorf("Wrong number of segment headers %d.", len(elf.ProgHdrTbl()))
+	}
+
+	interp, err := elf.SectMap()[".interp"][0].Data()
+	if err != nil {
+		t.Errorf("Unable to read .interp data.\n%s", err.Error())
+		return
+	}
+	if string(interp) != "/lib64/ld-linux-x86-64.so.2\x00" {
+		t.Errorf("Wrong .interp data '%s'.", string(interp))
+	}
+}
+
+func TestNewFileTruncated(t *testing.T) {
+	data, err := ioutil.ReadFile("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Errorf("Unable to read test file.\n%s", err.Error())
+		return
+	}
+
+	_, err = NewFile(bytes.NewReader(data[:0x1000]), 0x1000)
+	if err == nil {
+		t.Errorf("Expected an error reading a truncated ELF file.")
+	}
+}
@@ -0,0 +1,469 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"fmt"
+)
+
+// Returns the SysV ELF hash of name, as used in DT_HASH tables and in the
+// version sections.
+func ElfHash(name string) uint32 {
+	var h uint32
+	for i := 0; i < len(name); i++ {
+		h = (h << 4) + uint32(name[i])
+		g := h & 0xf0000000
+		if g != 0 {
+			h ^= g >> 24
+		}
+		h &^= g
+	}
+
+	return h
+}
+
+// Returns the GNU hash of name, as used in DT_GNU_HASH tables.
+func GnuHash(name string) uint32 {
+	h := uint32(5381)
+	for i := 0; i < len(name); i++ {
+		h = h*33 + uint32(name[i])
+	}
+
+	return h
+}
+
+// HashTbl is a SysV symbol hash table, as referred to by the DT_HASH entry
+// of the dynamic section.
+type HashTbl struct {
+	Buckets []uint32
+
+	// Chains[i] is the index of the next symbol in the chain of the symbol at
+	// index i in the dynamic symbol table. Its length is the number of symbols
+	// in the dynamic symbol table.
+	Chains []uint32
+}
+
+// GnuHashTbl is a GNU symbol hash table, as referred to by the DT_GNU_HASH
+// entry of the dynamic section.
+type GnuHashTbl struct {
+	// The index of the first symbol in the dynamic symbol table which is
+	// accessible through the hash table.
+	SymOffset uint32
+
+	// The words of the bloom filter. Each word is of the size of an address
+	// of the ELF class.
+	Bloom []uint64
+
+	// The shift count used to derive the second bloom filter bit.
+	BloomShift uint32
+
+	// Buckets[i] is the index of the first symbol in the dynamic symbol table
+	// whose hash modulo the number of buckets is i, or zero if there is no
+	// such symbol.
+	Buckets []uint32
+
+	// Chains[i] is the hash of the symbol at index SymOffset+i in the dynamic
+	// symbol table, with the least significant bit set if the symbol is the
+	// last one of its chain.
+	Chains []uint32
+
+	// The number of bits in each bloom filter word.
+	wordBits uint32
+}
+
+// Returns the indeces in the dynamic symbol table of the symbols whose names
+// are name, by walking the chain of the bucket of name.
+func (tbl *HashTbl) Lookup(name string, symbols []ResolvedSymbol) []uint32 {
+	if len(tbl.Buckets) == 0 {
+		return nil
+	}
+
+	var indeces []uint32
+	i := tbl.Buckets[ElfHash(name)%uint32(len(tbl.Buckets))]
+
+	// Bound the walk by the number of chain entries to protect against
+	// cyclic chains.
+	for n := 0; i != 0 && n < len(tbl.Chains); n++ {
+		if i >= uint32(len(tbl.Chains)) || i >= uint32(len(symbols)) {
+			break
+		}
+
+		if symbols[i].Name == name {
+			indeces = append(indeces, i)
+		}
+		i = tbl.Chains[i]
+	}
+
+	return indeces
+}
+
+// Returns true if the bloom filter of the table allows for a symbol with the
+// GNU hash h to be present in the table. A false return value means that the
+// symbol is definitely absent.
+func (tbl *GnuHashTbl) MayContain(h uint32) bool {
+	if len(tbl.Bloom) == 0 {
+		return false
+	}
+
+	word := tbl.Bloom[(h/tbl.wordBits)%uint32(len(tbl.Bloom))]
+	mask := uint64(1)<<(h%tbl.wordBits) | uint64(1)<<((h>>tbl.BloomShift)%tbl.wordBits)
+	return word&mask == mask
+}
+
+// Returns the indeces in the dynamic symbol table of the symbols whose names
+// are name. As the dynamic loader does, the bloom filter is checked first and
+// the chain of the bucket of name is walked only if the filter passes.
+func (tbl *GnuHashTbl) Lookup(name string, symbols []ResolvedSymbol) []uint32 {
+	h := GnuHash(name)
+	if len(tbl.Buckets) == 0 || !tbl.MayContain(h) {
+		return nil
+	}
+
+	var indeces []uint32
+	i := tbl.Buckets[h%uint32(len(tbl.Buckets))]
+	if i < tbl.SymOffset {
+		return nil
+	}
+
+	for ; i-tbl.SymOffset < uint32(len(tbl.Chains)) && i < uint32(len(symbols)); i++ {
+		chainHash := tbl.Chains[i-tbl.SymOffset]
+		if chainHash|1 == h|1 && symbols[i].Name == name {
+			indeces = append(indeces, i)
+		}
+
+		if chainHash&1 != 0 {
+			break
+		}
+	}
+
+	return indeces
+}
+
+type hashTbls struct {
+	hash    *HashTbl
+	gnuHash *GnuHashTbl
+}
+
+// Returns the SysV hash table referred to by the DT_HASH entry of the dynamic
+// section. If the ELF file does not have a dynamic section, then the section
+// of type SectTypeHashTab is read. Returns nil if there is no such table.
+func (elf *ELF) HashTbl() (*HashTbl, error) {
+	err := elf.readHashTbls()
+	if err != nil {
+		return nil, err
+	}
+
+	return elf.hashTbls.hash, nil
+}
+
+// Returns the GNU hash table referred to by the DT_GNU_HASH entry of the
+// dynamic section. If the ELF file does not have a dynamic section, then the
+// section of type SectTypeGnuHash is read. Returns nil if there is no such
+// table.
+func (elf *ELF) GnuHashTbl() (*GnuHashTbl, error) {
+	err := elf.readHashTbls()
+	if err != nil {
+		return nil, err
+	}
+
+	return elf.hashTbls.gnuHash, nil
+}
+
+// Looks up a symbol in the dynamic symbol table using the hash tables. The GNU
+// hash table is preferred over the SysV hash table, and the dynamic symbols
+// are scanned linearly only if there are no hash tables. As with the dynamic
+// loader, only defined symbols are considered. If version is empty, then the
+// first symbol with the name whose version is not hidden is returned.
+// Otherwise, the symbol with the name and version is returned. Returns nil if
+// there is no matching symbol.
+func (elf *ELF) LookupDynamicSymbol(name, version string) (*ResolvedSymbol, error) {
+	symbols, err := elf.DynamicSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	err = elf.readHashTbls()
+	if err != nil {
+		return nil, err
+	}
+
+	var indeces []uint32
+	switch {
+	case elf.hashTbls.gnuHash != nil:
+		indeces = elf.hashTbls.gnuHash.Lookup(name, symbols)
+	case elf.hashTbls.hash != nil:
+		indeces = elf.hashTbls.hash.Lookup(name, symbols)
+	default:
+		for i := 1; i < len(symbols); i++ {
+			if symbols[i].Name == name {
+				indeces = append(indeces, uint32(i))
+			}
+		}
+	}
+
+	for _, i := range indeces {
+		sym := &symbols[i]
+		if !sym.IsDefined() {
+			continue
+		}
+		if version == "" && !sym.VersionHidden {
+			return sym, nil
+		}
+		if version != "" && sym.Version == version {
+			return sym, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Verifies that the hash tables agree with the dynamic symbol table. Every
+// symbol in the dynamic symbol table should be reachable through the SysV hash
+// table, and every symbol at or beyond the symbol offset of the GNU hash table
+// should be in the chain of its bucket, with a matching hash, and should pass
+// the bloom filter. Returns an error describing the first inconsistency found.
+func (elf *ELF) CheckHashTbls() error {
+	symbols, err := elf.DynamicSymbols()
+	if err != nil {
+		return err
+	}
+
+	err = elf.readHashTbls()
+	if err != nil {
+		return err
+	}
+
+	if tbl := elf.hashTbls.hash; tbl != nil {
+		if len(tbl.Chains) != len(symbols) {
+			return fmt.Errorf(
+				"DT_HASH table has %d chain entries for %d dynamic symbols.",
+				len(tbl.Chains), len(symbols))
+		}
+
+		for i := 1; i < len(symbols); i++ {
+			if !containsIndex(tbl.Lookup(symbols[i].Name, symbols), uint32(i)) {
+				return fmt.Errorf(
+					"Dynamic symbol %d '%s' is not reachable through the DT_HASH table.",
+					i, symbols[i].Name)
+			}
+		}
+	}
+
+	if tbl := elf.hashTbls.gnuHash; tbl != nil {
+		if uint64(tbl.SymOffset)+uint64(len(tbl.Chains)) != uint64(len(symbols)) {
+			return fmt.Errorf(
+				"DT_GNU_HASH table covers %d symbols from index %d, but there are %d "+
+					"dynamic symbols.", len(tbl.Chains), tbl.SymOffset, len(symbols))
+		}
+
+		for i := int(tbl.SymOffset); i < len(symbols); i++ {
+			name := symbols[i].Name
+			h := GnuHash(name)
+			if tbl.Chains[i-int(tbl.SymOffset)]|1 != h|1 {
+				return fmt.Errorf(
+					"DT_GNU_HASH chain has a wrong hash for dynamic symbol %d '%s'.", i, name)
+			}
+			if !tbl.MayContain(h) {
+				return fmt.Errorf(
+					"Dynamic symbol %d '%s' does not pass the DT_GNU_HASH bloom filter.",
+					i, name)
+			}
+			if !containsIndex(tbl.Lookup(name, symbols), uint32(i)) {
+				return fmt.Errorf(
+					"Dynamic symbol %d '%s' is not reachable through the DT_GNU_HASH table.",
+					i, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsIndex(indeces []uint32, index uint32) bool {
+	for _, i := range indeces {
+		if i == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (elf *ELF) readHashTbls() error {
+	if elf.hashTbls != nil {
+		return nil
+	}
+
+	dyn, err := elf.Dynamic()
+	if err != nil {
+		return err
+	}
+
+	tbls := new(hashTbls)
+
+	offset, exists, err := elf.hashTblOffset(dyn, DynTagHash, SectTypeHashTab)
+	if err != nil {
+		return err
+	}
+	if exists {
+		tbls.hash, err = elf.readHashTbl(offset)
+		if err != nil {
+			return fmt.Errorf("Error reading DT_HASH table.\n%s", err.Error())
+		}
+	}
+
+	offset, exists, err = elf.hashTblOffset(dyn, DynTagGnuHash, SectTypeGnuHash)
+	if err != nil {
+		return err
+	}
+	if exists {
+		symbols, err := elf.DynamicSymbols()
+		if err != nil {
+			return err
+		}
+
+		tbls.gnuHash, err = elf.readGnuHashTbl(offset, uint32(len(symbols)))
+		if err != nil {
+			return fmt.Errorf("Error reading DT_GNU_HASH table.\n%s", err.Error())
+		}
+	}
+
+	elf.hashTbls = tbls
+	return nil
+}
+
+// Returns the file offset of the hash table referred to by the dynamic entry
+// with the tag, or of the section of type sectType if there is no dynamic
+// section.
+func (elf *ELF) hashTblOffset(dyn *Dynamic, tag DynTag, sectType SectType) (uint64, bool, error) {
+	if dyn != nil {
+		var addr uint64
+		if tag == DynTagHash {
+			addr = dyn.Hash
+		} else {
+			addr = dyn.GnuHash
+		}
+		if addr == 0 {
+			return 0, false, nil
+		}
+
+		offset, err := elf.addrToOffset(addr, 16)
+		if err != nil {
+			err = fmt.Errorf("Unable to locate the symbol hash table.\n%s", err.Error())
+			return 0, false, err
+		}
+
+		return offset, true, nil
+	}
+
+	section := elf.sectionOfType(sectType)
+	if section == nil {
+		return 0, false, nil
+	}
+
+	return section.SectHdr().Offset(), true, nil
+}
+
+func (elf *ELF) readWords(offset uint64, count uint64) ([]uint32, error) {
+	data, err := elf.readAt(offset, 4*uint64(count))
+	if err != nil {
+		return nil, err
+	}
+
+	endianess := elf.Endianess()
+	words := make([]uint32, count)
+	for i := range words {
+		words[i] = endianess.Uint32(data[4*i:])
+	}
+
+	return words, nil
+}
+
+func (elf *ELF) readHashTbl(offset uint64) (*HashTbl, error) {
+	hdr, err := elf.readWords(offset, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := elf.readWords(offset+8, uint64(hdr[0])+uint64(hdr[1]))
+	if err != nil {
+		return nil, err
+	}
+
+	tbl := new(HashTbl)
+	tbl.Buckets = words[:hdr[0]]
+	tbl.Chains = words[hdr[0]:]
+	return tbl, nil
+}
+
+// Reads the GNU hash table at offset. The table does not record the length of
+// its chains, which is derived from the number of dynamic symbols.
+func (elf *ELF) readGnuHashTbl(offset uint64, numSymbols uint32) (*GnuHashTbl, error) {
+	hdr, err := elf.readWords(offset, 4)
+	if err != nil {
+		return nil, err
+	}
+	offset += 16
+
+	tbl := new(GnuHashTbl)
+	tbl.SymOffset = hdr[1]
+	tbl.BloomShift = hdr[3]
+
+	bloomSize := hdr[2]
+	if elf.Header().ELFIdent().Class == Class32 {
+		tbl.wordBits = 32
+		words, err := elf.readWords(offset, uint64(bloomSize))
+		if err != nil {
+			return nil, err
+		}
+		tbl.Bloom = make([]uint64, bloomSize)
+		for i, w := range words {
+			tbl.Bloom[i] = uint64(w)
+		}
+		offset += 4 * uint64(bloomSize)
+	} else {
+		tbl.wordBits = 64
+		data, err := elf.readAt(offset, 8*uint64(bloomSize))
+		if err != nil {
+			return nil, err
+		}
+		tbl.Bloom = make([]uint64, bloomSize)
+		for i := range tbl.Bloom {
+			tbl.Bloom[i] = elf.Endianess().Uint64(data[8*i:])
+		}
+		offset += 8 * uint64(bloomSize)
+	}
+
+	tbl.Buckets, err = elf.readWords(offset, uint64(hdr[0]))
+	if err != nil {
+		return nil, err
+	}
+	offset += 4 * uint64(hdr[0])
+
+	if numSymbols < tbl.SymOffset {
+		err = fmt.Errorf(
+			"Symbol offset %d is beyond the %d dynamic symbols.", tbl.SymOffset, numSymbols)
+		return nil, err
+	}
+
+	tbl.Chains, err = elf.readWords(offset, uint64(numSymbols-tbl.SymOffset))
+	if err != nil {
+		return nil, err
+	}
+
+	return tbl, nil
+}
@@ -0,0 +1,169 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestHashFunctions(t *testing.T) {
+	if ElfHash("") != 0 || ElfHash("printf") != 0x077905a6 {
+		t.Errorf("Wrong ELF hash.")
+	}
+	if GnuHash("") != 0x00001505 || GnuHash("printf") != 0x156b2bb8 {
+		t.Errorf("Wrong GNU hash.")
+	}
+}
+
+func TestHashTbls(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	symbols, err := elf.DynamicSymbols()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	hashTbl, err := elf.HashTbl()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	gnuHashTbl, err := elf.GnuHashTbl()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if hashTbl == nil || gnuHashTbl == nil {
+		t.Errorf("Missing hash tables.")
+		return
+	}
+	if len(hashTbl.Buckets) != 3 || len(hashTbl.Chains) != 13 {
+		t.Errorf("Wrong DT_HASH table size.")
+		return
+	}
+	if len(gnuHashTbl.Buckets) != 3 || gnuHashTbl.SymOffset != 7 {
+		t.Errorf("Wrong DT_GNU_HASH table size.")
+		return
+	}
+
+	for i := 1; i < len(symbols); i++ {
+		indeces := hashTbl.Lookup(symbols[i].Name, symbols)
+		if !containsIndex(indeces, uint32(i)) {
+			t.Errorf("Symbol %d not found through the DT_HASH table.", i)
+		}
+
+		indeces = gnuHashTbl.Lookup(symbols[i].Name, symbols)
+		if i >= int(gnuHashTbl.SymOffset) && !containsIndex(indeces, uint32(i)) {
+			t.Errorf("Symbol %d not found through the DT_GNU_HASH table.", i)
+		}
+		if i < int(gnuHashTbl.SymOffset) && len(indeces) != 0 {
+			t.Errorf("Undefined symbol %d found through the DT_GNU_HASH table.", i)
+		}
+	}
+
+	if len(hashTbl.Lookup("golf_missing", symbols)) != 0 ||
+		len(gnuHashTbl.Lookup("golf_missing", symbols)) != 0 {
+		t.Errorf("Found a missing symbol.")
+	}
+
+	err = elf.CheckHashTbls()
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestLookupDynamicSymbol(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	expected := []struct {
+		name    string
+		version string
+		value   uint64
+	}{
+		{"golf_add", "", 0x111d},
+		{"golf_add", "GOLF_2.0", 0x111d},
+		{"golf_add", "GOLF_1.0", 0x1119},
+		{"golf_copy", "", 0x112a},
+		{"golf_counter", "GOLF_2.0", 0x4008},
+	}
+	for _, e := range expected {
+		sym, err := elf.LookupDynamicSymbol(e.name, e.version)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if sym == nil || sym.Value != e.value {
+			t.Errorf("Wrong symbol for '%s@%s'.", e.name, e.version)
+		}
+	}
+
+	missing := []struct {
+		name    string
+		version string
+	}{
+		{"puts", ""},
+		{"golf_missing", ""},
+		{"golf_copy", "GOLF_1.0"},
+	}
+	for _, m := range missing {
+		sym, err := elf.LookupDynamicSymbol(m.name, m.version)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if sym != nil {
+			t.Errorf("Unexpected symbol for '%s@%s'.", m.name, m.version)
+		}
+	}
+}
+
+func TestCheckHashTblsCorrupted(t *testing.T) {
+	data, err := ioutil.ReadFile("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// Corrupt the hash value of the first symbol in the chains of the
+	// '.gnu.hash' section, which is at offset 0x270 and has a single bloom
+	// filter word and 3 buckets.
+	data[0x270+16+8+12] ^= 0x10
+
+	elf, err := NewFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	err = elf.CheckHashTbls()
+	if err == nil {
+		t.Errorf("Corrupted DT_GNU_HASH table passed the consistency check.")
+	}
+}
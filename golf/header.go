@@ -19,7 +19,7 @@ package golf
 import (
 	"encoding/binary"
 	"fmt"
-	"os"
+	"io"
 )
 
 // Values of type ELFClass represent the class (32-bit or 64-bit) of an ELF
@@ -150,15 +150,20 @@ const (
 )
 
 const (
-	MachineSPARC   MachineArch = MachineArch(0x02)
-	MachineX86     MachineArch = MachineArch(0x03)
-	MachineMIPS    MachineArch = MachineArch(0x08)
-	MachinePowerPC MachineArch = MachineArch(0x14)
-	MachineARM     MachineArch = MachineArch(0x28)
-	MachineSuperH  MachineArch = MachineArch(0x2A)
-	MachineIA64    MachineArch = MachineArch(0x32)
-	MachineX86_64  MachineArch = MachineArch(0x3E)
-	MachineAArch64 MachineArch = MachineArch(0xB7)
+	MachineNone      MachineArch = MachineArch(0x00)
+	MachineSPARC     MachineArch = MachineArch(0x02)
+	MachineX86       MachineArch = MachineArch(0x03)
+	MachineMIPS      MachineArch = MachineArch(0x08)
+	MachinePowerPC   MachineArch = MachineArch(0x14)
+	MachinePowerPC64 MachineArch = MachineArch(0x15)
+	MachineS390      MachineArch = MachineArch(0x16)
+	MachineARM       MachineArch = MachineArch(0x28)
+	MachineSuperH    MachineArch = MachineArch(0x2A)
+	MachineSPARCV9   MachineArch = MachineArch(0x2B)
+	MachineIA64      MachineArch = MachineArch(0x32)
+	MachineX86_64    MachineArch = MachineArch(0x3E)
+	MachineAArch64   MachineArch = MachineArch(0xB7)
+	MachineRISCV     MachineArch = MachineArch(0xF3)
 )
 
 type header32 struct {
@@ -339,31 +344,32 @@ func (header *header64) StrTblIndex() uint16 {
 	return header.platformSpecific.StrTblIndex
 }
 
-func readHeader(file *os.File) (ELFHeader, error) {
-	fileName := file.Name()
-	_, err := file.Seek(0, 0)
-	if err != nil {
-		err = fmt.Errorf("Unable to seek while reading '%s'.\n%s", fileName, err.Error())
-		return nil, err
-	}
+func readHeader(r io.ReaderAt, size int64) (ELFHeader, error) {
+	reader := io.NewSectionReader(r, 0, size)
 
 	var ident ELFIdent
-	err = binary.Read(file, binary.LittleEndian, &ident)
+	err := binary.Read(reader, binary.LittleEndian, &ident)
 	if err != nil {
-		err = fmt.Errorf("Error reading ELFIdent from '%s'.\n%s", fileName, err.Error())
-		return nil, err
+		return nil, fmt.Errorf("Error reading ELFIdent.\n%s", err.Error())
+	}
+	if ident.MagicNumber != [4]byte{Mag0, Mag1, Mag2, Mag3} {
+		return nil, fmt.Errorf("Invalid ELF magic number %v.", ident.MagicNumber)
+	}
+	if ident.Class != Class32 && ident.Class != Class64 {
+		return nil, fmt.Errorf("Invalid ELF class %d.", ident.Class)
+	}
+	if _, ok := endianMap[ident.Endianess]; !ok {
+		return nil, fmt.Errorf("Invalid ELF data encoding %d.", ident.Endianess)
 	}
 
 	if ident.Class == Class32 {
 		header := new(header32)
 
 		header.ident = ident
-		err = binary.Read(file, endianMap[ident.Endianess], &header.platformSpecific)
+		err = binary.Read(reader, endianMap[ident.Endianess], &header.platformSpecific)
 		if err != nil {
 			err = fmt.Errorf(
-				"Error reading platform specific part of header from '%s'.\n%s",
-				fileName,
-				err.Error())
+				"Error reading platform specific part of header.\n%s", err.Error())
 			return nil, err
 		}
 
@@ -372,12 +378,10 @@ func readHeader(file *os.File) (ELFHeader, error) {
 		header := new(header64)
 
 		header.ident = ident
-		err = binary.Read(file, endianMap[ident.Endianess], &header.platformSpecific)
+		err = binary.Read(reader, endianMap[ident.Endianess], &header.platformSpecific)
 		if err != nil {
 			err = fmt.Errorf(
-				"Error reading platform specific part of header from '%s'.\n%s",
-				fileName,
-				err.Error())
+				"Error reading platform specific part of header.\n%s", err.Error())
 			return nil, err
 		}
 
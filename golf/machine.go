@@ -0,0 +1,285 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"fmt"
+)
+
+// The flags field of the ELF header is machine specific. The types in this
+// file decode it for the machines which use it. For example, the float ABI of
+// a RISC-V file is:
+//
+//     golf.RISCVFlags(elf.Header().Flags()).FloatABI()
+
+// RISCVFlags are the flags in the ELF header of RISC-V files.
+type RISCVFlags uint32
+
+const (
+	// The file uses the compressed instruction extension.
+	RISCVFlagRVC RISCVFlags = RISCVFlags(0x1)
+
+	// The bits holding the RISCVFloatABI of the file.
+	RISCVFlagFloatABIMask RISCVFlags = RISCVFlags(0x6)
+
+	// The file uses the RV32E base integer instruction set.
+	RISCVFlagRVE RISCVFlags = RISCVFlags(0x8)
+
+	// The file requires the RVTSO memory consistency model.
+	RISCVFlagTSO RISCVFlags = RISCVFlags(0x10)
+)
+
+// RISCVFloatABI values denote the registers used to pass floating point
+// arguments in a RISC-V file.
+type RISCVFloatABI uint32
+
+const (
+	RISCVFloatABISoft   RISCVFloatABI = RISCVFloatABI(0x0)
+	RISCVFloatABISingle RISCVFloatABI = RISCVFloatABI(0x2)
+	RISCVFloatABIDouble RISCVFloatABI = RISCVFloatABI(0x4)
+	RISCVFloatABIQuad   RISCVFloatABI = RISCVFloatABI(0x6)
+)
+
+// Returns the float ABI of the file.
+func (flags RISCVFlags) FloatABI() RISCVFloatABI {
+	return RISCVFloatABI(flags & RISCVFlagFloatABIMask)
+}
+
+// MIPSFlags are the flags in the ELF header of MIPS files.
+type MIPSFlags uint32
+
+const (
+	MIPSFlagNoReorder MIPSFlags = MIPSFlags(0x1)
+	MIPSFlagPIC       MIPSFlags = MIPSFlags(0x2)
+	MIPSFlagCPIC      MIPSFlags = MIPSFlags(0x4)
+	MIPSFlagXGOT      MIPSFlags = MIPSFlags(0x8)
+	MIPSFlagABI2      MIPSFlags = MIPSFlags(0x20)
+	MIPSFlag32BitMode MIPSFlags = MIPSFlags(0x100)
+	MIPSFlagFP64      MIPSFlags = MIPSFlags(0x200)
+	MIPSFlagNaN2008   MIPSFlags = MIPSFlags(0x400)
+	MIPSFlagABIMask   MIPSFlags = MIPSFlags(0x0000f000)
+	MIPSFlagMachMask  MIPSFlags = MIPSFlags(0x00ff0000)
+	MIPSFlagArchMask  MIPSFlags = MIPSFlags(0xf0000000)
+	MIPSFlagABIO32    MIPSFlags = MIPSFlags(0x1000)
+	MIPSFlagABIO64    MIPSFlags = MIPSFlags(0x2000)
+	MIPSFlagABIEABI32 MIPSFlags = MIPSFlags(0x3000)
+	MIPSFlagABIEABI64 MIPSFlags = MIPSFlags(0x4000)
+)
+
+// MIPSArch values denote the MIPS instruction set architecture of a file.
+type MIPSArch uint32
+
+const (
+	MIPSArch1    MIPSArch = MIPSArch(0x00000000)
+	MIPSArch2    MIPSArch = MIPSArch(0x10000000)
+	MIPSArch3    MIPSArch = MIPSArch(0x20000000)
+	MIPSArch4    MIPSArch = MIPSArch(0x30000000)
+	MIPSArch5    MIPSArch = MIPSArch(0x40000000)
+	MIPSArch32   MIPSArch = MIPSArch(0x50000000)
+	MIPSArch64   MIPSArch = MIPSArch(0x60000000)
+	MIPSArch32R2 MIPSArch = MIPSArch(0x70000000)
+	MIPSArch64R2 MIPSArch = MIPSArch(0x80000000)
+	MIPSArch32R6 MIPSArch = MIPSArch(0x90000000)
+	MIPSArch64R6 MIPSArch = MIPSArch(0xa0000000)
+)
+
+// MIPSABI values denote the calling convention used in a MIPS file.
+type MIPSABI uint8
+
+const (
+	MIPSABIUnknown MIPSABI = MIPSABI(0)
+	MIPSABIO32     MIPSABI = MIPSABI(1)
+	MIPSABIN32     MIPSABI = MIPSABI(2)
+	MIPSABIN64     MIPSABI = MIPSABI(3)
+	MIPSABIO64     MIPSABI = MIPSABI(4)
+	MIPSABIEABI32  MIPSABI = MIPSABI(5)
+	MIPSABIEABI64  MIPSABI = MIPSABI(6)
+)
+
+// Returns the instruction set architecture of the file.
+func (flags MIPSFlags) Arch() MIPSArch {
+	return MIPSArch(flags & MIPSFlagArchMask)
+}
+
+// Returns the ABI of a file of the class. The n64 ABI is implied by 64-bit
+// files, and n32 is marked by the MIPSFlagABI2 flag of 32-bit files. The
+// other ABIs are recorded in the bits of MIPSFlagABIMask. 32-bit files with
+// none of these use the o32 ABI.
+func (flags MIPSFlags) ABI(class ELFClass) MIPSABI {
+	if class == Class64 {
+		return MIPSABIN64
+	}
+	if class != Class32 {
+		return MIPSABIUnknown
+	}
+	if flags&MIPSFlagABI2 != 0 {
+		return MIPSABIN32
+	}
+
+	switch flags & MIPSFlagABIMask {
+	case 0, MIPSFlagABIO32:
+		return MIPSABIO32
+	case MIPSFlagABIO64:
+		return MIPSABIO64
+	case MIPSFlagABIEABI32:
+		return MIPSABIEABI32
+	case MIPSFlagABIEABI64:
+		return MIPSABIEABI64
+	default:
+		return MIPSABIUnknown
+	}
+}
+
+// MIPSFPABI values denote the floating point ABI in the MIPS ABI flags.
+type MIPSFPABI uint8
+
+const (
+	MIPSFPABIAny     MIPSFPABI = MIPSFPABI(0)
+	MIPSFPABIDouble  MIPSFPABI = MIPSFPABI(1)
+	MIPSFPABISingle  MIPSFPABI = MIPSFPABI(2)
+	MIPSFPABISoft    MIPSFPABI = MIPSFPABI(3)
+	MIPSFPABIOldFP64 MIPSFPABI = MIPSFPABI(4)
+	MIPSFPABIXX      MIPSFPABI = MIPSFPABI(5)
+	MIPSFPABI64      MIPSFPABI = MIPSFPABI(6)
+	MIPSFPABI64A     MIPSFPABI = MIPSFPABI(7)
+)
+
+// Bits of the Flags1 field of the MIPS ABI flags.
+const (
+	MIPSABIFlag1OddSPReg = uint32(0x1)
+)
+
+// MIPSABIFlags is the decoded contents of the '.MIPS.abiflags' section, which
+// describes the requirements of a MIPS file in more detail than the flags in
+// the ELF header.
+type MIPSABIFlags struct {
+	Version  uint16
+	ISALevel uint8
+	ISARev   uint8
+
+	// The sizes of the general purpose, coprocessor 1 and coprocessor 2
+	// registers in bits. They are 0 if the registers are not used.
+	GPRSize  uint
+	CPR1Size uint
+	CPR2Size uint
+
+	FPABI  MIPSFPABI
+	ISAExt uint32
+	ASEs   uint32
+	Flags1 uint32
+	Flags2 uint32
+}
+
+// The size of the version 0 MIPS ABI flags structure.
+const mipsABIFlagsSize = 24
+
+// Returns the size in bits of registers with the size encoding in MIPS ABI
+// flags.
+func mipsRegSize(encoded uint8) (uint, error) {
+	switch encoded {
+	case 0:
+		return 0, nil
+	case 1:
+		return 32, nil
+	case 2:
+		return 64, nil
+	case 3:
+		return 128, nil
+	default:
+		return 0, fmt.Errorf("Invalid MIPS register size encoding %d.", encoded)
+	}
+}
+
+// Returns the MIPS ABI flags of the ELF file, or nil if it does not have a
+// SHT_MIPS_ABIFLAGS section.
+func (elf *ELF) MIPSABIFlags() (*MIPSABIFlags, error) {
+	if elf.Header().Machine() != MachineMIPS {
+		return nil, nil
+	}
+	section := elf.sectionOfType(SectTypeMIPSABIFlags)
+	if section == nil {
+		return nil, nil
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		err = fmt.Errorf("Error reading MIPS ABI flags.\n%s", err.Error())
+		return nil, err
+	}
+	if len(data) < mipsABIFlagsSize {
+		return nil, fmt.Errorf("MIPS ABI flags section is too small.")
+	}
+
+	endianess := elf.Endianess()
+	flags := new(MIPSABIFlags)
+	flags.Version = endianess.Uint16(data)
+	flags.ISALevel = data[2]
+	flags.ISARev = data[3]
+	for i, size := range []*uint{&flags.GPRSize, &flags.CPR1Size, &flags.CPR2Size} {
+		*size, err = mipsRegSize(data[4+i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	flags.FPABI = MIPSFPABI(data[7])
+	flags.ISAExt = endianess.Uint32(data[8:])
+	flags.ASEs = endianess.Uint32(data[12:])
+	flags.Flags1 = endianess.Uint32(data[16:])
+	flags.Flags2 = endianess.Uint32(data[20:])
+
+	return flags, nil
+}
+
+// ARMFlags are the flags in the ELF header of ARM files.
+type ARMFlags uint32
+
+const (
+	ARMFlagFloatSoft ARMFlags = ARMFlags(0x200)
+	ARMFlagFloatHard ARMFlags = ARMFlags(0x400)
+	ARMFlagBE8       ARMFlags = ARMFlags(0x00800000)
+	ARMFlagEABIMask  ARMFlags = ARMFlags(0xff000000)
+)
+
+// Returns the version of the ARM EABI which the file conforms to, or 0 if
+// it does not conform to an EABI.
+func (flags ARMFlags) EABIVersion() uint8 {
+	return uint8((flags & ARMFlagEABIMask) >> 24)
+}
+
+// PPC64Flags are the flags in the ELF header of 64-bit PowerPC files.
+type PPC64Flags uint32
+
+const (
+	PPC64FlagABIMask PPC64Flags = PPC64Flags(0x3)
+)
+
+// Returns the version of the 64-bit PowerPC ABI used in the file. Version 1
+// uses function descriptors, and version 2, the ABI of little endian systems,
+// does not. It is 0 if the version is unspecified, which is the case for
+// files which predate version 2 and follow version 1.
+func (flags PPC64Flags) ABIVersion() uint8 {
+	return uint8(flags & PPC64FlagABIMask)
+}
+
+// S390Flags are the flags in the ELF header of s390 and s390x files.
+type S390Flags uint32
+
+const (
+	// The file uses the high halves of the 64-bit general purpose
+	// registers in 31-bit mode.
+	S390FlagHighGPRs S390Flags = S390Flags(0x1)
+)
@@ -0,0 +1,192 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"testing"
+)
+
+// Each of the object files has a function 'golf_func' which calls the
+// undefined function 'golf_ext'.
+var machineFiles = []struct {
+	fileName    string
+	machine     MachineArch
+	endianess   ELFEndianess
+	addressSize uint8
+
+	// The relocation of the call to 'golf_ext'.
+	callOffset uint64
+	callType   RelocType
+	callAddend int64
+}{
+	{"linux_arm.o", MachineARM, LittleEndian, 4, 0x4, 28, 0},
+	{"linux_mips.o", MachineMIPS, BigEndian, 4, 0x8, 4, 0},
+	{"linux_mips64el.o", MachineMIPS, LittleEndian, 8, 0x8, 4, 0},
+	{"linux_ppc64.o", MachinePowerPC64, BigEndian, 8, 0xc, 10, 0},
+	{"linux_ppc64le.o", MachinePowerPC64, LittleEndian, 8, 0xc, 10, 0},
+	{"linux_riscv64.o", MachineRISCV, LittleEndian, 8, 0x4, 18, 0},
+	{"linux_s390x.o", MachineS390, BigEndian, 8, 0xc, 20, 2},
+}
+
+func TestMachines(t *testing.T) {
+	for _, file := range machineFiles {
+		elf, err := Read("test_data/" + file.fileName)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		defer elf.Close()
+
+		if elf.Header().Machine() != file.machine ||
+			elf.Header().ELFIdent().Endianess != file.endianess {
+			t.Errorf("Wrong machine or endianess of '%s'.", file.fileName)
+			continue
+		}
+		if elf.AddressSize() != file.addressSize {
+			t.Errorf("Wrong address size %d of '%s'.", elf.AddressSize(), file.fileName)
+			continue
+		}
+
+		text := elf.SectMap()[".text"][0]
+		var call *Relocation
+		tbls, err := elf.RelocTbls()
+		if err != nil {
+			t.Errorf("Error reading relocations of '%s'.\n%s", file.fileName, err.Error())
+			continue
+		}
+		for _, tbl := range tbls {
+			if tbl.Target == text {
+				call = &tbl.Relocs[0]
+			}
+		}
+		if call == nil {
+			t.Errorf("No relocations for the text of '%s'.", file.fileName)
+			continue
+		}
+		testReloc(t, *call, 0, file.callOffset, file.callType, "golf_ext", file.callAddend)
+	}
+}
+
+func TestMachineFlags(t *testing.T) {
+	flags := func(fileName string) uint32 {
+		elf, err := Read("test_data/" + fileName)
+		if err != nil {
+			t.Error(err.Error())
+			return 0
+		}
+		defer elf.Close()
+
+		return elf.Header().Flags()
+	}
+
+	riscv := RISCVFlags(flags("linux_riscv64.o"))
+	if riscv&RISCVFlagRVC == 0 || riscv&RISCVFlagRVE != 0 ||
+		riscv.FloatABI() != RISCVFloatABIDouble {
+		t.Errorf("Wrong RISC-V flags 0x%x.", uint32(riscv))
+	}
+
+	mips := MIPSFlags(flags("linux_mips.o"))
+	if mips.ABI(Class32) != MIPSABIO32 || mips.Arch() != MIPSArch32R2 ||
+		mips&MIPSFlagCPIC == 0 || mips&MIPSFlagPIC != 0 {
+		t.Errorf("Wrong MIPS flags 0x%x.", uint32(mips))
+	}
+	mips64 := MIPSFlags(flags("linux_mips64el.o"))
+	if mips64.ABI(Class64) != MIPSABIN64 || mips64.Arch() != MIPSArch64R2 {
+		t.Errorf("Wrong MIPS64 flags 0x%x.", uint32(mips64))
+	}
+	if MIPSFlags(MIPSFlagABI2).ABI(Class32) != MIPSABIN32 ||
+		MIPSFlags(MIPSFlagABIEABI64).ABI(Class32) != MIPSABIEABI64 {
+		t.Errorf("Wrong MIPS ABI of 32-bit files.")
+	}
+
+	arm := ARMFlags(flags("linux_arm.o"))
+	if arm.EABIVersion() != 5 || arm&ARMFlagBE8 != 0 {
+		t.Errorf("Wrong ARM flags 0x%x.", uint32(arm))
+	}
+
+	if PPC64Flags(flags("linux_ppc64.o")).ABIVersion() != 1 ||
+		PPC64Flags(flags("linux_ppc64le.o")).ABIVersion() != 2 {
+		t.Errorf("Wrong PowerPC64 ABI versions.")
+	}
+}
+
+func TestMIPSABIFlags(t *testing.T) {
+	expected := map[string]MIPSABIFlags{
+		"linux_mips.o": {
+			ISALevel: 32, ISARev: 2, GPRSize: 32, CPR1Size: 32,
+			FPABI: MIPSFPABIDouble, Flags1: MIPSABIFlag1OddSPReg,
+		},
+		"linux_mips64el.o": {
+			ISALevel: 64, ISARev: 2, GPRSize: 64, CPR1Size: 64,
+			FPABI: MIPSFPABIDouble, Flags1: MIPSABIFlag1OddSPReg,
+		},
+		"linux_riscv64.o": {},
+	}
+
+	for fileName, want := range expected {
+		elf, err := Read("test_data/" + fileName)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		defer elf.Close()
+
+		flags, err := elf.MIPSABIFlags()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if elf.Header().Machine() != MachineMIPS {
+			if flags != nil {
+				t.Errorf("Found MIPS ABI flags in '%s'.", fileName)
+			}
+			continue
+		}
+		if flags == nil || *flags != want {
+			t.Errorf("Wrong MIPS ABI flags of '%s': %v", fileName, flags)
+		}
+	}
+}
+
+func TestStripDebugMIPS64(t *testing.T) {
+	elf, err := Read("test_data/linux_mips64el.o")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	err = elf.StripDebug()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The symbols of the debug sections are removed, so the symbol indeces
+	// in the split info fields of the relocations are remapped.
+	stripped, err := writeAndReload(elf)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	tbl, err := stripped.RelocTbl(stripped.SectMap()[".rela.text"][0])
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	testReloc(t, tbl.Relocs[0], 0, 0x8, 4, "golf_ext", 0)
+}
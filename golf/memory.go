@@ -0,0 +1,242 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MemReader is a view of the memory image of an ELF file, as laid out by its
+// loadable segments. It implements io.ReaderAt with the offsets being virtual
+// addresses. The part of a loadable segment beyond its file size, like the
+// '.bss' section, reads as zeros. Addresses which are not in any loadable
+// segment are holes, and reading them is an error.
+//
+// In core files, the part of a loadable segment beyond its file size is
+// memory which was not dumped. Reading it from a MemReader returned by
+// Core.NewMemReader is an error.
+type MemReader struct {
+	elf *ELF
+
+	// The loadable segments sorted by their virtual addresses.
+	segs []SegHdr
+
+	// True if the part of a segment beyond its file size reads as zeros.
+	zeroFill bool
+}
+
+// Returns a MemReader over the loadable segments of the ELF file.
+func (elf *ELF) NewMemReader() *MemReader {
+	r := newMemReader(elf)
+	r.zeroFill = true
+	return r
+}
+
+func newMemReader(elf *ELF) *MemReader {
+	r := new(MemReader)
+	r.elf = elf
+
+	for _, segHdr := range elf.progHdrTbl {
+		if segHdr.Type() == SegTypeLoad && segHdr.MemSize() > 0 {
+			r.segs = append(r.segs, segHdr)
+		}
+	}
+	sort.SliceStable(r.segs, func(i, j int) bool {
+		return r.segs[i].VirtualAddress() < r.segs[j].VirtualAddress()
+	})
+
+	return r
+}
+
+// Reads len(p) bytes starting at the virtual address addr into p. The bytes
+// can span adjacent loadable segments. If a byte in the range is in a hole,
+// the bytes before it are read into p, and their count is returned along with
+// an error.
+func (r *MemReader) ReadAt(p []byte, addr int64) (int, error) {
+	if addr < 0 {
+		return 0, fmt.Errorf("Invalid negative address %d.", addr)
+	}
+
+	n := 0
+	cur := uint64(addr)
+	for n < len(p) {
+		segHdr := r.segmentAt(cur)
+		if segHdr == nil {
+			return n, fmt.Errorf("Address 0x%x is not in a loadable segment.", cur)
+		}
+
+		segOffset := cur - segHdr.VirtualAddress()
+		var count uint64
+		if segOffset < segHdr.FileSize() {
+			count = minUint64(uint64(len(p)-n), segHdr.FileSize()-segOffset)
+			data, err := r.elf.readAt(segHdr.Offset()+segOffset, count)
+			if err != nil {
+				err = fmt.Errorf(
+					"Error reading data at address 0x%x.\n%s", cur, err.Error())
+				return n, err
+			}
+			copy(p[n:], data)
+		} else if !r.zeroFill {
+			return n, fmt.Errorf("Memory at address 0x%x is not in the file.", cur)
+		} else {
+			count = minUint64(uint64(len(p)-n), segHdr.MemSize()-segOffset)
+			for i := uint64(0); i < count; i++ {
+				p[n+int(i)] = 0
+			}
+		}
+
+		n += int(count)
+		cur += count
+	}
+
+	return n, nil
+}
+
+// Returns the size bytes starting at the virtual address addr.
+func (r *MemReader) Read(addr uint64, size uint64) ([]byte, error) {
+	data := make([]byte, size)
+	_, err := r.ReadAt(data, int64(addr))
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Returns the loadable segment containing the virtual address addr, or nil if
+// addr is in a hole.
+func (r *MemReader) segmentAt(addr uint64) SegHdr {
+	i := sort.Search(len(r.segs), func(i int) bool {
+		return r.segs[i].VirtualAddress() > addr
+	})
+
+	// Segments can overlap, so look at all segments starting at or before
+	// addr, latest first.
+	for i--; i >= 0; i-- {
+		segHdr := r.segs[i]
+		if addr-segHdr.VirtualAddress() < segHdr.MemSize() {
+			return segHdr
+		}
+	}
+
+	return nil
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// Returns the file offset of the data at virtual address addr. The address
+// should be in the file part of a loadable segment.
+func (elf *ELF) AddrToOffset(addr uint64) (uint64, error) {
+	return elf.addrToOffset(addr, 1)
+}
+
+// Returns the virtual address at which the data at file offset offset is
+// loaded. The offset should be in the file part of a loadable segment.
+func (elf *ELF) OffsetToAddr(offset uint64) (uint64, error) {
+	for _, segHdr := range elf.progHdrTbl {
+		if segHdr.Type() != SegTypeLoad {
+			continue
+		}
+
+		if offset >= segHdr.Offset() && offset-segHdr.Offset() < segHdr.FileSize() {
+			return segHdr.VirtualAddress() + (offset - segHdr.Offset()), nil
+		}
+	}
+
+	return 0, fmt.Errorf("File offset 0x%x is not in a loadable segment.", offset)
+}
+
+// Returns the allocated section containing the virtual address addr, and the
+// offset of addr in the section. Sections of type SectTypeNoBits, like '.bss',
+// are also considered, except the TLS ones like '.tbss'. They occupy no
+// address space of their own, as their addresses overlap those of the
+// sections following them.
+func (elf *ELF) AddrToSectOffset(addr uint64) (*Section, uint64, error) {
+	for _, section := range elf.sections {
+		hdr := section.SectHdr()
+		if hdr.Flags()&SectFlagAlloc == 0 {
+			continue
+		}
+		if hdr.Type() == SectTypeNoBits && hdr.Flags()&SectFlagTLS != 0 {
+			continue
+		}
+
+		if addr >= hdr.Address() && addr-hdr.Address() < hdr.Size() {
+			return section, addr - hdr.Address(), nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf("Address 0x%x is not in an allocated section.", addr)
+}
+
+// Returns the virtual address of the data at offset in the section. The
+// section should be an allocated section.
+func (elf *ELF) SectOffsetToAddr(section *Section, offset uint64) (uint64, error) {
+	hdr := section.SectHdr()
+	if hdr.Flags()&SectFlagAlloc == 0 {
+		return 0, fmt.Errorf("Section '%s' is not an allocated section.", section.Name())
+	}
+
+	if offset >= hdr.Size() {
+		err := fmt.Errorf(
+			"Offset 0x%x is beyond the end of section '%s'.", offset, section.Name())
+		return 0, err
+	}
+
+	return hdr.Address() + offset, nil
+}
+
+// Returns the section containing the file offset, and the offset into the
+// section. Sections of type SectTypeNoBits, which do not occupy space in the
+// file, are not considered.
+func (elf *ELF) OffsetToSectOffset(offset uint64) (*Section, uint64, error) {
+	for _, section := range elf.sections {
+		hdr := section.SectHdr()
+		if hdr.Type() == SectTypeNoBits || hdr.Type() == SectTypeUnused {
+			continue
+		}
+
+		if offset >= hdr.Offset() && offset-hdr.Offset() < hdr.Size() {
+			return section, offset - hdr.Offset(), nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf("File offset 0x%x is not in a section.", offset)
+}
+
+// Returns the file offset of the data at offset in the section.
+func (elf *ELF) SectOffsetToOffset(section *Section, offset uint64) (uint64, error) {
+	hdr := section.SectHdr()
+	if hdr.Type() == SectTypeNoBits {
+		return 0, fmt.Errorf("Section '%s' does not occupy space in the file.", section.Name())
+	}
+
+	if offset >= hdr.Size() {
+		err := fmt.Errorf(
+			"Offset 0x%x is beyond the end of section '%s'.", offset, section.Name())
+		return 0, err
+	}
+
+	return hdr.Offset() + offset, nil
+}
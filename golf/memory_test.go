@@ -0,0 +1,169 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMemReader(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	r := elf.NewMemReader()
+
+	// The '.text' section.
+	text := elf.Sections()[14]
+	textData, err := text.Data()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	data, err := r.Read(text.SectHdr().Address(), text.SectHdr().Size())
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if !bytes.Equal(data, textData) {
+		t.Errorf("Wrong data read at the address of '.text'.")
+		return
+	}
+
+	// The 4 byte 'golf_counter' at 0x4008, initialized to 1, is followed by the
+	// 4 byte '.bss' section, which is not in the file.
+	data, err = r.Read(0x4008, 8)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if !bytes.Equal(data, []byte{1, 0, 0, 0, 0, 0, 0, 0}) {
+		t.Errorf("Wrong data read at 0x4008: %v", data)
+		return
+	}
+
+	// The first loadable segment ends at 0x678.
+	buf := make([]byte, 16)
+	n, err := r.ReadAt(buf, 0x670)
+	if err == nil || n != 8 {
+		t.Errorf("Expected a partial read and error reading a hole, got %d.", n)
+		return
+	}
+
+	_, err = r.Read(0x4010, 1)
+	if err == nil {
+		t.Errorf("Expected an error reading beyond the last segment.")
+		return
+	}
+}
+
+func TestAddrConversions(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	// '.data' is at 0x4000 and at file offset 0x3000.
+	offset, err := elf.AddrToOffset(0x4004)
+	if err != nil || offset != 0x3004 {
+		t.Errorf("Wrong file offset for 0x4004: 0x%x", offset)
+		return
+	}
+	addr, err := elf.OffsetToAddr(0x3004)
+	if err != nil || addr != 0x4004 {
+		t.Errorf("Wrong address for file offset 0x3004: 0x%x", addr)
+		return
+	}
+
+	section, sectOffset, err := elf.AddrToSectOffset(0x4004)
+	if err != nil || section != elf.Sections()[21] || sectOffset != 4 {
+		t.Errorf("Wrong section offset for 0x4004.")
+		return
+	}
+	addr, err = elf.SectOffsetToAddr(section, 4)
+	if err != nil || addr != 0x4004 {
+		t.Errorf("Wrong address for offset 4 in '.data': 0x%x", addr)
+		return
+	}
+
+	section, sectOffset, err = elf.OffsetToSectOffset(0x3004)
+	if err != nil || section != elf.Sections()[21] || sectOffset != 4 {
+		t.Errorf("Wrong section offset for file offset 0x3004.")
+		return
+	}
+	offset, err = elf.SectOffsetToOffset(section, 4)
+	if err != nil || offset != 0x3004 {
+		t.Errorf("Wrong file offset for offset 4 in '.data': 0x%x", offset)
+		return
+	}
+
+	// '.bss' is allocated but is not in the file.
+	bss := elf.Sections()[22]
+	section, sectOffset, err = elf.AddrToSectOffset(0x400d)
+	if err != nil || section != bss || sectOffset != 1 {
+		t.Errorf("Wrong section offset for 0x400d.")
+		return
+	}
+	_, err = elf.AddrToOffset(0x400d)
+	if err == nil {
+		t.Errorf("Expected an error converting a '.bss' address to a file offset.")
+		return
+	}
+	_, err = elf.SectOffsetToOffset(bss, 0)
+	if err == nil {
+		t.Errorf("Expected an error converting a '.bss' offset to a file offset.")
+		return
+	}
+
+	// The '.symtab' section is not allocated.
+	_, err = elf.SectOffsetToAddr(elf.Sections()[24], 0)
+	if err == nil {
+		t.Errorf("Expected an error converting an offset in '.symtab' to an address.")
+		return
+	}
+	_, err = elf.OffsetToAddr(0x3038)
+	if err == nil {
+		t.Errorf("Expected an error converting an offset in '.symtab' to an address.")
+	}
+}
+
+func TestAddrToSectOffsetTLS(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64_tls.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	// '.tbss' and '.dynamic' are both at 0x1eb0, but the address is in
+	// '.dynamic'.
+	tbss, dynamic := elf.Sections()[10], elf.Sections()[11]
+	if tbss.SectHdr().Address() != 0x1eb0 || dynamic.SectHdr().Address() != 0x1eb0 {
+		t.Errorf("Wrong addresses of '.tbss' and '.dynamic'.")
+		return
+	}
+	section, sectOffset, err := elf.AddrToSectOffset(0x1eb0)
+	if err != nil || section != dynamic || sectOffset != 0 {
+		t.Errorf("Wrong section offset for 0x1eb0.")
+	}
+}
@@ -0,0 +1,50 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package golf
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+type mapping []byte
+
+func (m mapping) Close() error {
+	return syscall.Munmap(m)
+}
+
+func mapFile(file *os.File, size int64) (mapping, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("Cannot map an empty file.")
+	}
+
+	if int64(int(size)) != size {
+		return nil, fmt.Errorf("File of size %d is too large to be mapped.", size)
+	}
+
+	data, err := syscall.Mmap(
+		int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapping(data), nil
+}
@@ -0,0 +1,412 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"fmt"
+)
+
+// The names of the values of the types in this file are those printed by the
+// readelf tool of GNU Binutils, so that tools built on golf can print the
+// same output.
+
+// Values of type SegType represent the type of a segment. The SegType*
+// constants are untyped integers of the type returned by the Type method of
+// SegHdr, which can be converted to SegType to get its name.
+type SegType uint32
+
+// Values of type SegFlags represent the flags of a segment, which are
+// combinations of the SegFlags* constants.
+type SegFlags uint32
+
+var elfTypeStr = map[ELFType]string{
+	TypeNone:        "NONE (None)",
+	TypeRelocatable: "REL (Relocatable file)",
+	TypeExecutable:  "EXEC (Executable file)",
+	TypeShared:      "DYN (Shared object file)",
+	TypeCore:        "CORE (Core file)",
+}
+
+// Returns the name of the ELF file type with its description, like
+// 'EXEC (Executable file)'.
+func (t ELFType) String() string {
+	if s, exists := elfTypeStr[t]; exists {
+		return s
+	}
+
+	switch {
+	case t >= TypeStartProcSpecific:
+		return fmt.Sprintf("Processor Specific: (%x)", uint16(t))
+	case t >= TypeStartOSSpecific && t <= TypeEndOSSpecific:
+		return fmt.Sprintf("OS Specific: (%x)", uint16(t))
+	}
+	return fmt.Sprintf("<unknown>: %x", uint16(t))
+}
+
+var osABIStr = map[OSABI]string{
+	ABISystemV:    "UNIX - System V",
+	ABIHPUX:       "UNIX - HP-UX",
+	ABINetBSD:     "UNIX - NetBSD",
+	ABIGnu:        "UNIX - GNU",
+	ABISolaris:    "UNIX - Solaris",
+	ABIAIX:        "UNIX - AIX",
+	ABIIRIX:       "UNIX - IRIX",
+	ABIFreeBSD:    "UNIX - FreeBSD",
+	ABITru64:      "UNIX - TRU64",
+	ABIModesto:    "Novell - Modesto",
+	ABIOpenBSD:    "UNIX - OpenBSD",
+	ABIArmAEABI:   "ARM EABI",
+	ABIArm:        "ARM",
+	ABIStandalone: "Standalone App",
+}
+
+// Returns the name of the operating system ABI, like 'UNIX - System V'.
+func (abi OSABI) String() string {
+	if s, exists := osABIStr[abi]; exists {
+		return s
+	}
+	return fmt.Sprintf("<unknown: %x>", uint8(abi))
+}
+
+var machineArchStr = map[MachineArch]string{
+	MachineNone:      "None",
+	MachineSPARC:     "Sparc",
+	MachineX86:       "Intel 80386",
+	MachineMIPS:      "MIPS R3000",
+	MachinePowerPC:   "PowerPC",
+	MachinePowerPC64: "PowerPC64",
+	MachineS390:      "IBM S/390",
+	MachineARM:       "ARM",
+	MachineSuperH:    "Renesas / SuperH SH",
+	MachineSPARCV9:   "Sparc v9",
+	MachineIA64:      "Intel IA-64",
+	MachineX86_64:    "Advanced Micro Devices X86-64",
+	MachineAArch64:   "AArch64",
+	MachineRISCV:     "RISC-V",
+}
+
+// Returns the name of the machine architecture, like 'AArch64'.
+func (m MachineArch) String() string {
+	if s, exists := machineArchStr[m]; exists {
+		return s
+	}
+	return fmt.Sprintf("<unknown>: 0x%x", uint16(m))
+}
+
+var sectTypeStr = map[SectType]string{
+	SectTypeUnused:         "NULL",
+	SectTypeProgBits:       "PROGBITS",
+	SectTypeSymTab:         "SYMTAB",
+	SectTypeStrTab:         "STRTAB",
+	SectTypeRelA:           "RELA",
+	SectTypeHashTab:        "HASH",
+	SectTypeDynamic:        "DYNAMIC",
+	SectTypeNotes:          "NOTE",
+	SectTypeNoBits:         "NOBITS",
+	SectTypeRel:            "REL",
+	SectType(10):           "SHLIB",
+	SectTypeDynSym:         "DYNSYM",
+	SectTypeInitArray:      "INIT_ARRAY",
+	SectTypeFinalizeArray:  "FINI_ARRAY",
+	SectTypePreInitArray:   "PREINIT_ARRAY",
+	SectTypeGroup:          "GROUP",
+	SectTypeExtSectIndeces: "SYMTAB SECTION INDICES",
+	SectType(19):           "RELR",
+	SectTypeGnuHash:        "GNU_HASH",
+	SectType(0x6ffffff5):   "GNU_ATTRIBUTES",
+	SectType(0x6ffffff7):   "GNU_LIBLIST",
+	SectType(0x6fff4c03):   "LLVM_ADDRSIG",
+	SectTypeGnuVerDef:      "VERDEF",
+	SectTypeGnuVerNeed:     "VERNEED",
+	SectTypeGnuVerSym:      "VERSYM",
+}
+
+// Returns the name of the section type, like 'PROGBITS'. The types in the
+// processor specific range are named relative to the start of the range, like
+// 'LOPROC+0x2a'. See SectTypeStr for their machine specific names.
+func (t SectType) String() string {
+	if s, exists := sectTypeStr[t]; exists {
+		return s
+	}
+
+	switch {
+	case t >= SectTypeStartOSSpecific && t <= SectTypeEndOSSpecific:
+		return fmt.Sprintf("LOOS+0x%x", uint32(t-SectTypeStartOSSpecific))
+	case t >= SectTypeStartProcSpecific && t <= SectTypeEndProcSpecific:
+		return fmt.Sprintf("LOPROC+0x%x", uint32(t-SectTypeStartProcSpecific))
+	case t >= SectTypeStartAppSpecific:
+		return fmt.Sprintf("LOUSER+0x%x", uint32(t-SectTypeStartAppSpecific))
+	}
+	return fmt.Sprintf("%08x: <unknown>", uint32(t))
+}
+
+// The names of the processor specific section types of machines.
+var machineSectTypeStr = map[MachineArch]map[SectType]string{
+	MachineX86_64: {
+		SectType(0x70000001): "X86_64_UNWIND",
+	},
+	MachineARM: {
+		SectType(0x70000001): "ARM_EXIDX",
+		SectType(0x70000002): "ARM_PREEMPTMAP",
+		SectType(0x70000003): "ARM_ATTRIBUTES",
+	},
+	MachineAArch64: {
+		SectType(0x70000003): "AARCH64_ATTRIBUTES",
+	},
+	MachineMIPS: {
+		SectType(0x70000006): "MIPS_REGINFO",
+		SectType(0x7000000d): "MIPS_OPTIONS",
+		SectType(0x7000001e): "MIPS_DWARF",
+		SectTypeMIPSABIFlags: "MIPS_ABIFLAGS",
+	},
+	MachineRISCV: {
+		SectType(0x70000003): "RISCV_ATTRIBUTES",
+	},
+}
+
+// Returns the name of the section type t for the machine. It is the same as
+// t.String() for types which are not processor specific, or which are not
+// known for the machine.
+func SectTypeStr(machine MachineArch, t SectType) string {
+	if s, exists := machineSectTypeStr[machine][t]; exists {
+		return s
+	}
+	return t.String()
+}
+
+var segTypeStr = map[SegType]string{
+	SegType(SegTypeNull):        "NULL",
+	SegType(SegTypeLoad):        "LOAD",
+	SegType(SegTypeDynamic):     "DYNAMIC",
+	SegType(SegTypeInterp):      "INTERP",
+	SegType(SegTypeNote):        "NOTE",
+	SegType(SegTypeReserved):    "SHLIB",
+	SegType(SegTypeProgHdr):     "PHDR",
+	SegType(SegTypeTLS):         "TLS",
+	SegType(SegTypeGnuEHFrame):  "GNU_EH_FRAME",
+	SegType(SegTypeGnuStack):    "GNU_STACK",
+	SegType(SegTypeGnuRelRO):    "GNU_RELRO",
+	SegType(SegTypeGnuProperty): "GNU_PROPERTY",
+	SegType(0x6474e554):         "GNU_SFRAME",
+}
+
+// Returns the name of the segment type, like 'LOAD'. See SegTypeStr for the
+// names of processor specific types.
+func (t SegType) String() string {
+	if s, exists := segTypeStr[t]; exists {
+		return s
+	}
+
+	switch {
+	case t >= SegType(SegTypeStartOSSpecific) && t <= SegType(SegTypeEndOSSpecific):
+		return fmt.Sprintf("LOOS+0x%x", uint32(t)-SegTypeStartOSSpecific)
+	case t >= SegType(SegTypeStartProcSpecific) && t <= SegType(SegTypeEndProcSpecific):
+		return fmt.Sprintf("LOPROC+0x%x", uint32(t)-SegTypeStartProcSpecific)
+	}
+	return fmt.Sprintf("<unknown>: %x", uint32(t))
+}
+
+// The names of the processor specific segment types of machines.
+var machineSegTypeStr = map[MachineArch]map[SegType]string{
+	MachineARM: {
+		SegType(0x70000001): "EXIDX",
+	},
+	MachineAArch64: {
+		SegType(0x70000002): "AARCH64_MEMTAG_MTE",
+	},
+	MachineMIPS: {
+		SegType(0x70000000): "REGINFO",
+		SegType(0x70000001): "RTPROC",
+		SegType(0x70000002): "OPTIONS",
+		SegType(0x70000003): "ABIFLAGS",
+	},
+	MachineRISCV: {
+		SegType(0x70000003): "RISCV_ATTRIBUTES",
+	},
+}
+
+// Returns the name of the segment type t for the machine. It is the same as
+// t.String() for types which are not processor specific, or which are not
+// known for the machine.
+func SegTypeStr(machine MachineArch, t SegType) string {
+	if s, exists := machineSegTypeStr[machine][t]; exists {
+		return s
+	}
+	return t.String()
+}
+
+// Returns the flags as three characters for the readable, writable and
+// executable flags, with spaces for the flags which are not set, like 'R E'.
+func (flags SegFlags) String() string {
+	s := []byte("   ")
+	if uint32(flags)&SegFlagsReadable != 0 {
+		s[0] = 'R'
+	}
+	if uint32(flags)&SegFlagsWritable != 0 {
+		s[1] = 'W'
+	}
+	if uint32(flags)&SegFlagsExecutable != 0 {
+		s[2] = 'E'
+	}
+	return string(s)
+}
+
+var symBindingStr = map[SymBinding]string{
+	SymBindingLocal:     "LOCAL",
+	SymBindingGlobal:    "GLOBAL",
+	SymBindingWeak:      "WEAK",
+	SymBindingGnuUnique: "UNIQUE",
+}
+
+// Returns the name of the symbol binding, like 'GLOBAL'.
+func (b SymBinding) String() string {
+	if s, exists := symBindingStr[b]; exists {
+		return s
+	}
+
+	switch {
+	case b >= SymBindingStartProcSpecific && b <= SymBindingEndProcSpecific:
+		return fmt.Sprintf("<processor specific>: %d", uint8(b))
+	case b >= SymBindingStartOSSpecific && b <= SymBindingEndOSSpecific:
+		return fmt.Sprintf("<OS specific>: %d", uint8(b))
+	}
+	return fmt.Sprintf("<unknown>: %d", uint8(b))
+}
+
+var symTypeStr = map[SymType]string{
+	SymTypeNone:     "NOTYPE",
+	SymTypeObject:   "OBJECT",
+	SymTypeFunc:     "FUNC",
+	SymTypeSection:  "SECTION",
+	SymTypeFile:     "FILE",
+	SymTypeCommon:   "COMMON",
+	SymTypeTLS:      "TLS",
+	SymTypeGnuIFunc: "IFUNC",
+}
+
+// Returns the name of the symbol type, like 'FUNC'.
+func (t SymType) String() string {
+	if s, exists := symTypeStr[t]; exists {
+		return s
+	}
+
+	switch {
+	case t >= SymTypeStartProcSpecific && t <= SymTypeEndProcSpecific:
+		return fmt.Sprintf("<processor specific>: %d", uint8(t))
+	case t >= SymTypeStartOSSpecific && t <= SymTypeEndOSSpecific:
+		return fmt.Sprintf("<OS specific>: %d", uint8(t))
+	}
+	return fmt.Sprintf("<unknown>: %d", uint8(t))
+}
+
+var symVisibilityStr = map[SymVisibility]string{
+	SymVisibilityDefault:   "DEFAULT",
+	SymVisibilityInternal:  "INTERNAL",
+	SymVisibilityHidden:    "HIDDEN",
+	SymVisibilityProtected: "PROTECTED",
+}
+
+// Returns the name of the symbol visibility, like 'HIDDEN'.
+func (v SymVisibility) String() string {
+	if s, exists := symVisibilityStr[v]; exists {
+		return s
+	}
+	return fmt.Sprintf("<unknown>: %d", uint8(v))
+}
+
+var dynTagStr = map[DynTag]string{
+	DynTagNull:             "NULL",
+	DynTagNeeded:           "NEEDED",
+	DynTagPltRelSize:       "PLTRELSZ",
+	DynTagPltGot:           "PLTGOT",
+	DynTagHash:             "HASH",
+	DynTagStrTab:           "STRTAB",
+	DynTagSymTab:           "SYMTAB",
+	DynTagRelA:             "RELA",
+	DynTagRelASize:         "RELASZ",
+	DynTagRelAEntSize:      "RELAENT",
+	DynTagStrSize:          "STRSZ",
+	DynTagSymEntSize:       "SYMENT",
+	DynTagInit:             "INIT",
+	DynTagFini:             "FINI",
+	DynTagSOName:           "SONAME",
+	DynTagRPath:            "RPATH",
+	DynTagSymbolic:         "SYMBOLIC",
+	DynTagRel:              "REL",
+	DynTagRelSize:          "RELSZ",
+	DynTagRelEntSize:       "RELENT",
+	DynTagPltRel:           "PLTREL",
+	DynTagDebug:            "DEBUG",
+	DynTagTextRel:          "TEXTREL",
+	DynTagJmpRel:           "JMPREL",
+	DynTagBindNow:          "BIND_NOW",
+	DynTagInitArray:        "INIT_ARRAY",
+	DynTagFiniArray:        "FINI_ARRAY",
+	DynTagInitArraySize:    "INIT_ARRAYSZ",
+	DynTagFiniArraySize:    "FINI_ARRAYSZ",
+	DynTagRunPath:          "RUNPATH",
+	DynTagFlags:            "FLAGS",
+	DynTagPreInitArray:     "PREINIT_ARRAY",
+	DynTagPreInitArraySize: "PREINIT_ARRAYSZ",
+	DynTagSymTabShndx:      "SYMTAB_SHNDX",
+	DynTag(35):             "RELRSZ",
+	DynTag(36):             "RELR",
+	DynTag(37):             "RELRENT",
+	DynTagGnuHash:          "GNU_HASH",
+	DynTagVerSym:           "VERSYM",
+	DynTagRelACount:        "RELACOUNT",
+	DynTagRelCount:         "RELCOUNT",
+	DynTagFlags1:           "FLAGS_1",
+	DynTagVerDef:           "VERDEF",
+	DynTagVerDefNum:        "VERDEFNUM",
+	DynTagVerNeed:          "VERNEED",
+	DynTagVerNeedNum:       "VERNEEDNUM",
+	DynTag(0x6ffffdf5):     "GNU_PRELINKED",
+	DynTag(0x6ffffdf6):     "GNU_CONFLICTSZ",
+	DynTag(0x6ffffdf7):     "GNU_LIBLISTSZ",
+	DynTag(0x6ffffdf8):     "CHECKSUM",
+	DynTag(0x6ffffdf9):     "PLTPADSZ",
+	DynTag(0x6ffffdfa):     "MOVEENT",
+	DynTag(0x6ffffdfb):     "MOVESZ",
+	DynTag(0x6ffffdfe):     "SYMINSZ",
+	DynTag(0x6ffffdff):     "SYMINENT",
+	DynTag(0x6ffffef8):     "GNU_CONFLICT",
+	DynTag(0x6ffffef9):     "GNU_LIBLIST",
+	DynTag(0x6ffffefa):     "CONFIG",
+	DynTag(0x6ffffefb):     "DEPAUDIT",
+	DynTag(0x6ffffefc):     "AUDIT",
+	DynTag(0x6ffffefd):     "PLTPAD",
+	DynTag(0x6ffffefe):     "MOVETAB",
+	DynTag(0x6ffffeff):     "SYMINFO",
+	DynTag(0x7ffffffd):     "AUXILIARY",
+	DynTag(0x7fffffff):     "FILTER",
+}
+
+// Returns the name of the dynamic tag, like 'NEEDED'.
+func (tag DynTag) String() string {
+	if s, exists := dynTagStr[tag]; exists {
+		return s
+	}
+
+	switch {
+	case tag >= DynTagStartProcSpecific && tag <= DynTagEndProcSpecific:
+		return fmt.Sprintf("Processor Specific: %x", uint64(tag))
+	case tag >= DynTagStartOSSpecific && tag < DynTagStartProcSpecific:
+		return fmt.Sprintf("Operating System specific: %x", uint64(tag))
+	}
+	return fmt.Sprintf("<unknown>: %x", uint64(tag))
+}
@@ -0,0 +1,93 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	names := []struct {
+		value    fmt.Stringer
+		expected string
+	}{
+		{TypeExecutable, "EXEC (Executable file)"},
+		{TypeShared, "DYN (Shared object file)"},
+		{ELFType(0xfe01), "OS Specific: (fe01)"},
+		{ELFType(0xff02), "Processor Specific: (ff02)"},
+		{ELFType(7), "<unknown>: 7"},
+		{MachineX86_64, "Advanced Micro Devices X86-64"},
+		{MachineAArch64, "AArch64"},
+		{MachineArch(0x1234), "<unknown>: 0x1234"},
+		{SectTypeProgBits, "PROGBITS"},
+		{SectTypeGnuVerSym, "VERSYM"},
+		{SectTypeExtSectIndeces, "SYMTAB SECTION INDICES"},
+		{SectType(0x60000010), "LOOS+0x10"},
+		{SectType(0x70000001), "LOPROC+0x1"},
+		{SectType(0x80000002), "LOUSER+0x2"},
+		{SegType(SegTypeLoad), "LOAD"},
+		{SegType(SegTypeGnuProperty), "GNU_PROPERTY"},
+		{SegType(0x70000003), "LOPROC+0x3"},
+		{SegType(42), "<unknown>: 2a"},
+		{SegFlags(SegFlagsReadable | SegFlagsExecutable), "R E"},
+		{SegFlags(SegFlagsReadable | SegFlagsWritable), "RW "},
+		{SymBindingWeak, "WEAK"},
+		{SymTypeGnuIFunc, "IFUNC"},
+		{SymVisibilityHidden, "HIDDEN"},
+		{DynTagNeeded, "NEEDED"},
+		{DynTagFlags1, "FLAGS_1"},
+		{DynTagVerNeedNum, "VERNEEDNUM"},
+	}
+
+	for _, n := range names {
+		if s := n.value.String(); s != n.expected {
+			t.Errorf("Wrong name '%s' of %T %d, expected '%s'.", s, n.value, n.value, n.expected)
+		}
+	}
+}
+
+func TestMachineSpecificNames(t *testing.T) {
+	if s := SectTypeStr(MachineX86_64, SectType(0x70000001)); s != "X86_64_UNWIND" {
+		t.Errorf("Wrong name of x86_64 section type: %s", s)
+	}
+	if s := SectTypeStr(MachineMIPS, SectTypeMIPSABIFlags); s != "MIPS_ABIFLAGS" {
+		t.Errorf("Wrong name of MIPS section type: %s", s)
+	}
+	if s := SectTypeStr(MachineX86, SectType(0x70000001)); s != "LOPROC+0x1" {
+		t.Errorf("Wrong name of unknown x86 section type: %s", s)
+	}
+	if s := SectTypeStr(MachineARM, SectTypeProgBits); s != "PROGBITS" {
+		t.Errorf("Wrong name of generic section type: %s", s)
+	}
+	if s := SegTypeStr(MachineARM, SegType(0x70000001)); s != "EXIDX" {
+		t.Errorf("Wrong name of ARM segment type: %s", s)
+	}
+
+	elf, err := Read("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	var types []string
+	for _, hdr := range elf.ProgHdrTbl() {
+		types = append(types, SegTypeStr(elf.Header().Machine(), SegType(hdr.Type())))
+	}
+	if len(types) == 0 || types[0] != "PHDR" {
+		t.Errorf("Wrong segment types: %v", types)
+	}
+}
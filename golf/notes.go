@@ -0,0 +1,408 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Values of type NoteType represent the type of a note. The meaning of a note
+// type depends on the name (the owner) of the note.
+type NoteType uint32
+
+// Values of type GnuABIOS represent the operating system in a
+// NT_GNU_ABI_TAG note.
+type GnuABIOS uint32
+
+// Values of type GnuPropertyType represent the type of a property in a
+// NT_GNU_PROPERTY_TYPE_0 note.
+type GnuPropertyType uint32
+
+const (
+	// Name of the owner of GNU notes.
+	NoteNameGnu = "GNU"
+)
+
+// Types of notes owned by "GNU".
+const (
+	NoteTypeGnuABITag        NoteType = NoteType(1)
+	NoteTypeGnuHWCap         NoteType = NoteType(2)
+	NoteTypeGnuBuildID       NoteType = NoteType(3)
+	NoteTypeGnuGoldVersion   NoteType = NoteType(4)
+	NoteTypeGnuPropertyType0 NoteType = NoteType(5)
+)
+
+const (
+	GnuABIOSLinux    GnuABIOS = GnuABIOS(0)
+	GnuABIOSHurd     GnuABIOS = GnuABIOS(1)
+	GnuABIOSSolaris  GnuABIOS = GnuABIOS(2)
+	GnuABIOSFreeBSD  GnuABIOS = GnuABIOS(3)
+	GnuABIOSNetBSD   GnuABIOS = GnuABIOS(4)
+	GnuABIOSSyllable GnuABIOS = GnuABIOS(5)
+)
+
+// Property types in a NT_GNU_PROPERTY_TYPE_0 note. Types in the processor
+// specific range are interpreted as per the machine of the ELF file.
+const (
+	GnuPropertyStackSize          GnuPropertyType = GnuPropertyType(1)
+	GnuPropertyNoCopyOnProtected  GnuPropertyType = GnuPropertyType(2)
+	GnuPropertyStartProcSpecific  GnuPropertyType = GnuPropertyType(0xc0000000)
+	GnuPropertyAArch64Feature1And GnuPropertyType = GnuPropertyType(0xc0000000)
+	GnuPropertyX86Feature1And     GnuPropertyType = GnuPropertyType(0xc0000002)
+	GnuPropertyX86ISA1Needed      GnuPropertyType = GnuPropertyType(0xc0008002)
+	GnuPropertyX86Feature2Needed  GnuPropertyType = GnuPropertyType(0xc0008001)
+	GnuPropertyX86ISA1Used        GnuPropertyType = GnuPropertyType(0xc0010002)
+	GnuPropertyX86Feature2Used    GnuPropertyType = GnuPropertyType(0xc0010001)
+	GnuPropertyEndProcSpecific    GnuPropertyType = GnuPropertyType(0xdfffffff)
+	GnuPropertyStartAppSpecific   GnuPropertyType = GnuPropertyType(0xe0000000)
+	GnuPropertyEndAppSpecific     GnuPropertyType = GnuPropertyType(0xffffffff)
+)
+
+// Bits of the value of the GnuPropertyX86Feature1And property.
+const (
+	GnuPropertyX86Feature1IBT   = uint32(1 << 0)
+	GnuPropertyX86Feature1SHSTK = uint32(1 << 1)
+)
+
+// Bits of the value of the GnuPropertyAArch64Feature1And property.
+const (
+	GnuPropertyAArch64Feature1BTI = uint32(1 << 0)
+	GnuPropertyAArch64Feature1PAC = uint32(1 << 1)
+)
+
+// Note represents a single entry in a note section or segment.
+type Note struct {
+	// The name of the owner of the note, without the terminating NULL byte.
+	Name string
+
+	Type NoteType
+
+	// The raw descriptor of the note.
+	Desc []byte
+}
+
+// NoteIter iterates over the notes in the data of a note section or segment.
+type NoteIter struct {
+	data      []byte
+	offset    uint64
+	align     uint64
+	endianess binary.ByteOrder
+}
+
+// Returns an iterator over the notes in data. The value align is the
+// alignment of the note section or segment from which data was read. Notes
+// in sections or segments with an alignment of 8 have their names and
+// descriptors padded to 8 bytes; all others are padded to 4 bytes.
+func NewNoteIter(data []byte, align uint64, endianess binary.ByteOrder) *NoteIter {
+	it := new(NoteIter)
+	it.data = data
+	it.endianess = endianess
+	if align == 8 {
+		it.align = 8
+	} else {
+		it.align = 4
+	}
+
+	return it
+}
+
+// Returns the next note. A nil note with a nil error is returned when there
+// are no more notes.
+func (it *NoteIter) Next() (*Note, error) {
+	remaining := uint64(len(it.data)) - it.offset
+	if remaining == 0 {
+		return nil, nil
+	}
+	if remaining < 12 {
+		return nil, fmt.Errorf("Truncated note header at offset 0x%x.", it.offset)
+	}
+
+	header := it.data[it.offset:]
+	nameSize := uint64(it.endianess.Uint32(header))
+	descSize := uint64(it.endianess.Uint32(header[4:]))
+	note := new(Note)
+	note.Type = NoteType(it.endianess.Uint32(header[8:]))
+
+	// The name and the descriptor start at aligned offsets.
+	nameOffset := it.offset + 12
+	descOffset := alignUp(nameOffset+nameSize, it.align)
+	end := alignUp(descOffset+descSize, it.align)
+	if descOffset+descSize > uint64(len(it.data)) {
+		return nil, fmt.Errorf("Truncated note at offset 0x%x.", it.offset)
+	}
+
+	name := it.data[nameOffset : nameOffset+nameSize]
+	if i := bytes.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+	note.Name = string(name)
+	note.Desc = it.data[descOffset : descOffset+descSize]
+
+	// The padding of the last descriptor may be missing.
+	if end > uint64(len(it.data)) {
+		end = uint64(len(it.data))
+	}
+	it.offset = end
+
+	return note, nil
+}
+
+// Returns value rounded up to a multiple of align. An alignment of 0 or 1 means
+// no alignment.
+func alignUp(value uint64, align uint64) uint64 {
+	if align <= 1 {
+		return value
+	}
+
+	return (value + align - 1) / align * align
+}
+
+// Returns all the notes in data. See NewNoteIter for the meaning of align.
+func ReadNotes(data []byte, align uint64, endianess binary.ByteOrder) ([]*Note, error) {
+	var notes []*Note
+	it := NewNoteIter(data, align, endianess)
+	for {
+		note, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		if note == nil {
+			return notes, nil
+		}
+
+		notes = append(notes, note)
+	}
+}
+
+// Returns the notes in the ELF file. Notes are read from the PT_NOTE
+// segments if the file has any. Otherwise, they are read from the sections of
+// type SectTypeNotes.
+func (elf *ELF) Notes() ([]*Note, error) {
+	var notes []*Note
+	foundSeg := false
+	for _, segHdr := range elf.progHdrTbl {
+		if segHdr.Type() != SegTypeNote {
+			continue
+		}
+
+		foundSeg = true
+		data, err := elf.SegData(segHdr)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading note segment.\n%s", err.Error())
+		}
+
+		segNotes, err := ReadNotes(data, segHdr.Alignment(), elf.Endianess())
+		if err != nil {
+			return nil, fmt.Errorf("Error reading notes from segment.\n%s", err.Error())
+		}
+
+		notes = append(notes, segNotes...)
+	}
+
+	if foundSeg {
+		return notes, nil
+	}
+
+	for _, section := range elf.sections {
+		hdr := section.SectHdr()
+		if hdr.Type() != SectTypeNotes {
+			continue
+		}
+
+		data, err := section.Data()
+		if err != nil {
+			err = fmt.Errorf(
+				"Error reading note section '%s'.\n%s", section.Name(), err.Error())
+			return nil, err
+		}
+
+		sectNotes, err := ReadNotes(data, hdr.Alignment(), elf.Endianess())
+		if err != nil {
+			err = fmt.Errorf(
+				"Error reading notes from section '%s'.\n%s", section.Name(), err.Error())
+			return nil, err
+		}
+
+		notes = append(notes, sectNotes...)
+	}
+
+	return notes, nil
+}
+
+// Returns the first GNU note of the given type. Returns nil if there is no
+// such note.
+func (elf *ELF) gnuNote(noteType NoteType) (*Note, error) {
+	notes, err := elf.Notes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range notes {
+		if note.Name == NoteNameGnu && note.Type == noteType {
+			return note, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Returns the GNU build-id of the ELF file. Returns nil if the file does not
+// have a NT_GNU_BUILD_ID note.
+func (elf *ELF) BuildID() ([]byte, error) {
+	note, err := elf.gnuNote(NoteTypeGnuBuildID)
+	if note == nil || err != nil {
+		return nil, err
+	}
+
+	return note.Desc, nil
+}
+
+// GnuABITag is the decoded descriptor of a NT_GNU_ABI_TAG note. It specifies
+// the earliest version of the OS kernel supported by the ELF file.
+type GnuABITag struct {
+	OS    GnuABIOS
+	Major uint32
+	Minor uint32
+	Patch uint32
+}
+
+// Decodes the descriptor of a NT_GNU_ABI_TAG note.
+func DecodeGnuABITag(desc []byte, endianess binary.ByteOrder) (*GnuABITag, error) {
+	if len(desc) < 16 {
+		return nil, fmt.Errorf("NT_GNU_ABI_TAG descriptor is too short.")
+	}
+
+	tag := new(GnuABITag)
+	tag.OS = GnuABIOS(endianess.Uint32(desc))
+	tag.Major = endianess.Uint32(desc[4:])
+	tag.Minor = endianess.Uint32(desc[8:])
+	tag.Patch = endianess.Uint32(desc[12:])
+	return tag, nil
+}
+
+// Returns the decoded NT_GNU_ABI_TAG note of the ELF file. Returns nil if the
+// file does not have the note.
+func (elf *ELF) GnuABITag() (*GnuABITag, error) {
+	note, err := elf.gnuNote(NoteTypeGnuABITag)
+	if note == nil || err != nil {
+		return nil, err
+	}
+
+	return DecodeGnuABITag(note.Desc, elf.Endianess())
+}
+
+// GnuProperty is a single property in a NT_GNU_PROPERTY_TYPE_0 note.
+type GnuProperty struct {
+	Type GnuPropertyType
+
+	// The raw data of the property.
+	Data []byte
+}
+
+// Decodes the properties in the descriptor of a NT_GNU_PROPERTY_TYPE_0 note.
+// The property data is padded to 8 bytes in ELF64 files and to 4 bytes in
+// ELF32 files.
+func DecodeGnuProperties(
+	desc []byte, class ELFClass, endianess binary.ByteOrder) ([]GnuProperty, error) {
+	align := uint64(4)
+	if class == Class64 {
+		align = 8
+	}
+
+	var props []GnuProperty
+	offset := uint64(0)
+	for offset < uint64(len(desc)) {
+		if offset+8 > uint64(len(desc)) {
+			return nil, fmt.Errorf("Truncated GNU property at offset 0x%x.", offset)
+		}
+
+		var prop GnuProperty
+		prop.Type = GnuPropertyType(endianess.Uint32(desc[offset:]))
+		size := uint64(endianess.Uint32(desc[offset+4:]))
+		if offset+8+size > uint64(len(desc)) {
+			return nil, fmt.Errorf("Truncated GNU property at offset 0x%x.", offset)
+		}
+		prop.Data = desc[offset+8 : offset+8+size]
+		props = append(props, prop)
+
+		offset += 8 + alignUp(size, align)
+	}
+
+	return props, nil
+}
+
+// Returns the properties in the NT_GNU_PROPERTY_TYPE_0 note of the ELF file.
+// Returns nil if the file does not have the note.
+func (elf *ELF) GnuProperties() ([]GnuProperty, error) {
+	note, err := elf.gnuNote(NoteTypeGnuPropertyType0)
+	if note == nil || err != nil {
+		return nil, err
+	}
+
+	return DecodeGnuProperties(note.Desc, elf.Header().ELFIdent().Class, elf.Endianess())
+}
+
+// GnuFeatures captures the control-flow protection features which are marked
+// as enabled by the GNU properties of an ELF file.
+type GnuFeatures struct {
+	// x86 indirect branch tracking.
+	X86IBT bool
+
+	// x86 shadow stack.
+	X86SHSTK bool
+
+	// AArch64 branch target identification.
+	AArch64BTI bool
+
+	// AArch64 pointer authentication.
+	AArch64PAC bool
+}
+
+// Returns the features marked by the X86_FEATURE_1_AND or the
+// AARCH64_FEATURE_1_AND property, depending on the machine of the ELF file.
+func (elf *ELF) GnuFeatures() (*GnuFeatures, error) {
+	props, err := elf.GnuProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	features := new(GnuFeatures)
+	machine := elf.Header().Machine()
+	for _, prop := range props {
+		if len(prop.Data) < 4 {
+			continue
+		}
+		value := elf.Endianess().Uint32(prop.Data)
+
+		switch machine {
+		case MachineX86, MachineX86_64:
+			if prop.Type == GnuPropertyX86Feature1And {
+				features.X86IBT = value&GnuPropertyX86Feature1IBT != 0
+				features.X86SHSTK = value&GnuPropertyX86Feature1SHSTK != 0
+			}
+		case MachineAArch64:
+			if prop.Type == GnuPropertyAArch64Feature1And {
+				features.AArch64BTI = value&GnuPropertyAArch64Feature1BTI != 0
+				features.AArch64PAC = value&GnuPropertyAArch64Feature1PAC != 0
+			}
+		}
+	}
+
+	return features, nil
+}
@@ -0,0 +1,160 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+func TestNotes(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	notes, err := elf.Notes()
+	if err != nil {
+		t.Errorf("Error reading notes.\n%s", err.Error())
+		return
+	}
+	if len(notes) != 2 {
+		t.Errorf("Wrong number of notes %d.", len(notes))
+		return
+	}
+	if notes[0].Name != NoteNameGnu || notes[0].Type != NoteTypeGnuABITag {
+		t.Errorf("Wrong first note.")
+	}
+	if notes[1].Name != NoteNameGnu || notes[1].Type != NoteTypeGnuBuildID {
+		t.Errorf("Wrong second note.")
+	}
+
+	buildID, err := elf.BuildID()
+	if err != nil {
+		t.Errorf("Error reading build-id.\n%s", err.Error())
+		return
+	}
+	if hex.EncodeToString(buildID) != "ae3d9f1b6e44f5719b5acfd55b7eaec793b2a2e9" {
+		t.Errorf("Wrong build-id %x.", buildID)
+	}
+
+	abiTag, err := elf.GnuABITag()
+	if err != nil {
+		t.Errorf("Error reading ABI tag.\n%s", err.Error())
+		return
+	}
+	if *abiTag != (GnuABITag{GnuABIOSLinux, 2, 6, 24}) {
+		t.Errorf("Wrong ABI tag %v.", *abiTag)
+	}
+
+	props, err := elf.GnuProperties()
+	if err != nil {
+		t.Errorf("Error reading GNU properties.\n%s", err.Error())
+		return
+	}
+	if props != nil {
+		t.Errorf("Unexpected GNU properties.")
+	}
+}
+
+func TestGnuProperties(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64_cet.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	buildID, err := elf.BuildID()
+	if err != nil {
+		t.Errorf("Error reading build-id.\n%s", err.Error())
+		return
+	}
+	if hex.EncodeToString(buildID) != "c32644ea6ef90207ec0b5d8617321569" {
+		t.Errorf("Wrong build-id %x.", buildID)
+	}
+
+	props, err := elf.GnuProperties()
+	if err != nil {
+		t.Errorf("Error reading GNU properties.\n%s", err.Error())
+		return
+	}
+	if len(props) != 1 || props[0].Type != GnuPropertyX86Feature1And {
+		t.Errorf("Wrong GNU properties.")
+		return
+	}
+
+	features, err := elf.GnuFeatures()
+	if err != nil {
+		t.Errorf("Error reading GNU features.\n%s", err.Error())
+		return
+	}
+	if *features != (GnuFeatures{X86IBT: true, X86SHSTK: true}) {
+		t.Errorf("Wrong GNU features %v.", *features)
+	}
+}
+
+func TestNoteIterBigEndian(t *testing.T) {
+	// A NT_GNU_PROPERTY_TYPE_0 note with the AARCH64_FEATURE_1_AND property
+	// marking BTI and PAC, followed by a note of an unknown type, in an 8
+	// byte aligned big endian note segment.
+	data := []byte{
+		0, 0, 0, 4, 0, 0, 0, 16, 0, 0, 0, 5, 'G', 'N', 'U', 0,
+		0xc0, 0, 0, 0, 0, 0, 0, 4, 0, 0, 0, 3, 0, 0, 0, 0,
+		0, 0, 0, 5, 0, 0, 0, 3, 0, 0, 0, 0x77, 'X', 'Y', 'Z', 'W',
+		0, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3,
+	}
+
+	notes, err := ReadNotes(data, 8, binary.BigEndian)
+	if err != nil {
+		t.Errorf("Error reading notes.\n%s", err.Error())
+		return
+	}
+	if len(notes) != 2 {
+		t.Errorf("Wrong number of notes %d.", len(notes))
+		return
+	}
+
+	props, err := DecodeGnuProperties(notes[0].Desc, Class64, binary.BigEndian)
+	if err != nil {
+		t.Errorf("Error decoding GNU properties.\n%s", err.Error())
+		return
+	}
+	if len(props) != 1 || props[0].Type != GnuPropertyAArch64Feature1And {
+		t.Errorf("Wrong GNU properties.")
+		return
+	}
+	value := binary.BigEndian.Uint32(props[0].Data)
+	if value != GnuPropertyAArch64Feature1BTI|GnuPropertyAArch64Feature1PAC {
+		t.Errorf("Wrong AArch64 feature bits 0x%x.", value)
+	}
+
+	if notes[1].Name != "XYZW" || notes[1].Type != NoteType(0x77) {
+		t.Errorf("Wrong unknown note.")
+	}
+	if len(notes[1].Desc) != 3 || notes[1].Desc[2] != 3 {
+		t.Errorf("Wrong descriptor of unknown note.")
+	}
+
+	_, err = ReadNotes(data[:20], 8, binary.BigEndian)
+	if err == nil {
+		t.Errorf("Expected an error reading truncated notes.")
+	}
+}
@@ -0,0 +1,812 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// The alignment of the loadable segment added to hold relocated data is that
+// of the first loadable segment, but not more than this value. It is the
+// largest page size of the common architectures.
+const maxPatchSegAlign = 0x10000
+
+// Returns the path of the program interpreter named by the PT_INTERP segment,
+// or an empty string if the ELF file does not have a PT_INTERP segment.
+func (elf *ELF) Interpreter() (string, error) {
+	segHdr, _ := elf.segmentOfType(SegTypeInterp)
+	if segHdr == nil {
+		return "", nil
+	}
+
+	var data []byte
+	var err error
+	if section := elf.interpSection(segHdr); section != nil {
+		data, err = section.Data()
+	} else {
+		data, err = elf.SegData(segHdr)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Error reading the program interpreter.\n%s", err.Error())
+	}
+
+	if end := bytes.IndexByte(data, 0); end >= 0 {
+		data = data[:end]
+	}
+
+	return string(data), nil
+}
+
+// Sets the path of the program interpreter named by the PT_INTERP segment,
+// like 'patchelf --set-interpreter'. If the path does not fit in the
+// '.interp' section, the section is moved to a new loadable segment at the
+// end of the file.
+func (elf *ELF) SetInterpreter(path string) error {
+	segHdr, segIndex := elf.segmentOfType(SegTypeInterp)
+	if segHdr == nil {
+		return fmt.Errorf("The ELF file does not have a program interpreter.")
+	}
+
+	section := elf.interpSection(segHdr)
+	if section == nil {
+		return fmt.Errorf("The section holding the program interpreter is not found.")
+	}
+
+	data := append([]byte(path), 0)
+	if uint64(len(data)) <= section.header.Size() {
+		padded := make([]byte, section.header.Size())
+		copy(padded, data)
+		section.SetData(padded)
+		return nil
+	}
+
+	err := elf.relocateSection(section, data)
+	if err != nil {
+		return err
+	}
+
+	return elf.moveSegment(elf.sectionIndex(section), segIndex)
+}
+
+// Sets the SONAME of the shared library, like 'patchelf --set-soname'. A
+// DT_SONAME entry is added if not present.
+func (elf *ELF) SetSOName(soName string) error {
+	return elf.editDynamic(func(ed *dynEditor) error {
+		return ed.setString(DynTagSOName, soName)
+	})
+}
+
+// Sets the DT_RPATH of the ELF file. A DT_RPATH entry is added if not present,
+// and removed if rpath is empty. Note that the dynamic loader ignores
+// DT_RPATH if DT_RUNPATH is present.
+func (elf *ELF) SetRPath(rpath string) error {
+	return elf.editDynamic(func(ed *dynEditor) error {
+		return ed.setString(DynTagRPath, rpath)
+	})
+}
+
+// Sets the DT_RUNPATH of the ELF file, like 'patchelf --set-rpath'. A
+// DT_RUNPATH entry is added if not present, and removed if runPath is empty.
+func (elf *ELF) SetRunPath(runPath string) error {
+	return elf.editDynamic(func(ed *dynEditor) error {
+		return ed.setString(DynTagRunPath, runPath)
+	})
+}
+
+// Adds a DT_NEEDED entry for the library named name, like
+// 'patchelf --add-needed'. The entry is added before the existing DT_NEEDED
+// entries, so that the library is searched first. Nothing is done if the
+// library is already needed.
+func (elf *ELF) AddNeeded(name string) error {
+	return elf.editDynamic(func(ed *dynEditor) error {
+		if ed.find(DynTagNeeded, name) >= 0 {
+			return nil
+		}
+
+		offset, err := ed.stringOffset(name, -1)
+		if err != nil {
+			return err
+		}
+
+		ed.entries = append([]DynEntry{{DynTagNeeded, offset}}, ed.entries...)
+		return nil
+	})
+}
+
+// Removes the DT_NEEDED entry for the library named name, like
+// 'patchelf --remove-needed'.
+func (elf *ELF) RemoveNeeded(name string) error {
+	return elf.editDynamic(func(ed *dynEditor) error {
+		i := ed.find(DynTagNeeded, name)
+		if i < 0 {
+			return fmt.Errorf("Library '%s' is not needed.", name)
+		}
+
+		ed.entries = append(ed.entries[:i:i], ed.entries[i+1:]...)
+		return nil
+	})
+}
+
+// Replaces the DT_NEEDED entry for the library named oldName with an entry
+// for the library named newName, like 'patchelf --replace-needed'. The
+// version requirements on the library are updated as well.
+func (elf *ELF) ReplaceNeeded(oldName, newName string) error {
+	return elf.editDynamic(func(ed *dynEditor) error {
+		i := ed.find(DynTagNeeded, oldName)
+		if i < 0 {
+			return fmt.Errorf("Library '%s' is not needed.", oldName)
+		}
+
+		offset, err := ed.stringOffset(newName, i)
+		if err != nil {
+			return err
+		}
+		ed.entries[i].Value = offset
+
+		return ed.replaceVerNeedFile(oldName, offset)
+	})
+}
+
+// dynEditor holds the contents of the dynamic section and the dynamic string
+// table while they are being edited.
+type dynEditor struct {
+	elf *ELF
+
+	dynSect *Section
+	strSect *Section
+
+	entries []DynEntry
+	strData []byte
+
+	// Offsets in the string table which are referred to by the dynamic
+	// symbols and the symbol versioning sections.
+	strRefs []uint64
+}
+
+// Edits the dynamic section and the dynamic string table with edit. The
+// edited tables are written back in place if they fit, and moved to a new
+// loadable segment otherwise.
+func (elf *ELF) editDynamic(edit func(ed *dynEditor) error) error {
+	ed := &dynEditor{elf: elf}
+	ed.dynSect = elf.sectionOfType(SectTypeDynamic)
+	if ed.dynSect == nil {
+		return fmt.Errorf("The ELF file does not have a dynamic section.")
+	}
+
+	link := ed.dynSect.header.Link()
+	if link == 0 || link >= uint32(len(elf.sections)) {
+		return fmt.Errorf("Invalid string table index %d of the dynamic section.", link)
+	}
+	ed.strSect = elf.sections[link]
+
+	data, err := ed.dynSect.Data()
+	if err != nil {
+		return fmt.Errorf("Error reading the dynamic section.\n%s", err.Error())
+	}
+	ed.entries, err = readDynEntries(data, elf.header.ELFIdent())
+	if err != nil {
+		return err
+	}
+
+	strData, err := ed.strSect.Data()
+	if err != nil {
+		return fmt.Errorf("Error reading the dynamic string table.\n%s", err.Error())
+	}
+	ed.strData = append([]byte(nil), strData...)
+
+	ed.strRefs, err = elf.dynStrRefs(link)
+	if err != nil {
+		return err
+	}
+
+	err = edit(ed)
+	if err != nil {
+		return err
+	}
+
+	return ed.commit()
+}
+
+// Returns the index of the entry with the tag whose string is str, or -1 if
+// there is no such entry.
+func (ed *dynEditor) find(tag DynTag, str string) int {
+	for i, entry := range ed.entries {
+		if entry.Tag != tag || entry.Value >= uint64(len(ed.strData)) {
+			continue
+		}
+
+		s, err := NewRawStrTbl(ed.strData).ReadStr(uint32(entry.Value))
+		if err == nil && s == str {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Sets the string of the entry with the tag. The entry is added before the
+// terminating DT_NULL entry if not present, and removed if str is empty.
+func (ed *dynEditor) setString(tag DynTag, str string) error {
+	i := -1
+	for j, entry := range ed.entries {
+		if entry.Tag == tag {
+			i = j
+			break
+		}
+	}
+
+	if str == "" {
+		if i >= 0 {
+			ed.entries = append(ed.entries[:i:i], ed.entries[i+1:]...)
+		}
+		return nil
+	}
+
+	offset, err := ed.stringOffset(str, i)
+	if err != nil {
+		return err
+	}
+
+	if i < 0 {
+		ed.entries = append(ed.entries, DynEntry{tag, offset})
+	} else {
+		ed.entries[i].Value = offset
+	}
+
+	return nil
+}
+
+// Returns the offset of str in the string table. If the entry at index
+// replacing is the only user of its string, and str fits in its place, the
+// string is overwritten. Otherwise, an existing copy of str is used, or str
+// is appended to the string table.
+func (ed *dynEditor) stringOffset(str string, replacing int) (uint64, error) {
+	if bytes.IndexByte([]byte(str), 0) >= 0 {
+		return 0, fmt.Errorf("Dynamic string '%s' has a NULL character.", str)
+	}
+
+	if replacing >= 0 {
+		offset := ed.entries[replacing].Value
+		if offset < uint64(len(ed.strData)) {
+			end := bytes.IndexByte(ed.strData[offset:], 0)
+			if end >= len(str) && ed.ownsString(replacing, offset, uint64(end)) {
+				for i := uint64(0); i < uint64(end); i++ {
+					ed.strData[offset+i] = 0
+				}
+				copy(ed.strData[offset:], str)
+				return offset, nil
+			}
+		}
+	}
+
+	// An existing copy can be the suffix of a longer string.
+	if i := bytes.Index(ed.strData, append([]byte(str), 0)); i >= 0 {
+		return uint64(i), nil
+	}
+
+	offset := uint64(len(ed.strData))
+	ed.strData = append(ed.strData, str...)
+	ed.strData = append(ed.strData, 0)
+	return offset, nil
+}
+
+// Returns true if the size bytes at offset in the string table are not
+// referred to by any entry other than the one at index owner.
+func (ed *dynEditor) ownsString(owner int, offset, size uint64) bool {
+	inString := func(ref uint64) bool {
+		return ref >= offset && ref <= offset+size
+	}
+
+	for i, entry := range ed.entries {
+		if i != owner && dynTagIsString(entry.Tag) && inString(entry.Value) {
+			return false
+		}
+	}
+	for _, ref := range ed.strRefs {
+		if inString(ref) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Makes the version requirements on the library named oldName refer to the
+// string at offset in the string table.
+func (ed *dynEditor) replaceVerNeedFile(oldName string, offset uint64) error {
+	section := ed.elf.sectionOfType(SectTypeGnuVerNeed)
+	if section == nil {
+		return nil
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		return err
+	}
+	data = append([]byte(nil), data...)
+
+	endianess := ed.elf.Endianess()
+	replaced := false
+	err = walkVerNeed(data, versionEntryCount(section, verNeedSize), endianess,
+		func(entry []byte, aux [][]byte) {
+			name, err := NewRawStrTbl(ed.strData).ReadStr(endianess.Uint32(entry[4:]))
+			if err == nil && name == oldName {
+				endianess.PutUint32(entry[4:], uint32(offset))
+				replaced = true
+			}
+		})
+	if err != nil {
+		return err
+	}
+
+	if replaced {
+		section.SetData(data)
+	}
+	return nil
+}
+
+// Writes the edited string table and dynamic section back to the ELF file.
+func (ed *dynEditor) commit() error {
+	elf := ed.elf
+	strSize := ed.strSect.header.Size()
+	if uint64(len(ed.strData)) > strSize {
+		err := elf.relocateSection(ed.strSect, ed.strData)
+		if err != nil {
+			return err
+		}
+
+		for i := range ed.entries {
+			switch ed.entries[i].Tag {
+			case DynTagStrTab:
+				ed.entries[i].Value = ed.strSect.header.Address()
+			case DynTagStrSize:
+				ed.entries[i].Value = uint64(len(ed.strData))
+			}
+		}
+	} else if !bytes.Equal(ed.strData, ed.origStrData()) {
+		ed.strSect.SetData(ed.strData)
+	}
+
+	class := elf.header.ELFIdent().Class
+	entrySize := uint64(16)
+	if class == Class32 {
+		entrySize = 8
+	}
+
+	capacity := ed.dynSect.header.Size() / entrySize
+	count := uint64(len(ed.entries)) + 1
+	if count <= capacity {
+		count = capacity
+	}
+	data, err := encodeDynEntries(ed.entries, count, class, elf.Endianess())
+	if err != nil {
+		return err
+	}
+
+	if count <= capacity {
+		ed.dynSect.SetData(data)
+		return nil
+	}
+
+	err = elf.relocateSection(ed.dynSect, data)
+	if err != nil {
+		return err
+	}
+
+	_, segIndex := elf.segmentOfType(SegTypeDynamic)
+	if segIndex < 0 {
+		return nil
+	}
+
+	return elf.moveSegment(elf.sectionIndex(ed.dynSect), segIndex)
+}
+
+// Returns the data of the string table before editing.
+func (ed *dynEditor) origStrData() []byte {
+	data, err := ed.strSect.Data()
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
+
+// Returns true if the value of dynamic entries with the tag is an offset in
+// the dynamic string table.
+func dynTagIsString(tag DynTag) bool {
+	switch tag {
+	case DynTagNeeded, DynTagSOName, DynTagRPath, DynTagRunPath:
+		return true
+	default:
+		return false
+	}
+}
+
+// Returns the offsets in the string table at index strTblIndex which are
+// referred to by the names of the dynamic symbols, and by the symbol
+// versioning sections.
+func (elf *ELF) dynStrRefs(strTblIndex uint32) ([]uint64, error) {
+	endianess := elf.Endianess()
+	var refs []uint64
+	for _, section := range elf.sections {
+		hdr := section.header
+		if hdr.Link() != strTblIndex {
+			continue
+		}
+
+		var data []byte
+		switch hdr.Type() {
+		case SectTypeDynSym, SectTypeGnuVerDef, SectTypeGnuVerNeed:
+			var err error
+			data, err = section.Data()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+
+		switch hdr.Type() {
+		case SectTypeDynSym:
+			if hdr.EntrySize() < 4 {
+				return nil, fmt.Errorf("Invalid entry size of section '%s'.", section.Name())
+			}
+			for offset := uint64(0); offset+hdr.EntrySize() <= uint64(len(data)); offset += hdr.EntrySize() {
+				refs = append(refs, uint64(endianess.Uint32(data[offset:])))
+			}
+		case SectTypeGnuVerDef:
+			err := walkVerDef(data, versionEntryCount(section, verDefSize), endianess,
+				func(entry []byte, aux [][]byte) {
+					for _, a := range aux {
+						refs = append(refs, uint64(endianess.Uint32(a)))
+					}
+				})
+			if err != nil {
+				return nil, err
+			}
+		case SectTypeGnuVerNeed:
+			err := walkVerNeed(data, versionEntryCount(section, verNeedSize), endianess,
+				func(entry []byte, aux [][]byte) {
+					refs = append(refs, uint64(endianess.Uint32(entry[4:])))
+					for _, a := range aux {
+						refs = append(refs, uint64(endianess.Uint32(a[8:])))
+					}
+				})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+// Calls visit with the raw bytes of each version definition in data, and of
+// its auxiliary entries.
+func walkVerDef(data []byte, count uint64, endianess binary.ByteOrder,
+	visit func(entry []byte, aux [][]byte)) error {
+	return walkVersionEntries(data, count, verDefSize, verDefAuxSize, endianess,
+		func(entry []byte) (uint64, uint64, uint64) {
+			return uint64(endianess.Uint16(entry[6:])), uint64(endianess.Uint32(entry[12:])),
+				uint64(endianess.Uint32(entry[16:]))
+		},
+		func(aux []byte) uint64 {
+			return uint64(endianess.Uint32(aux[4:]))
+		}, visit)
+}
+
+// Calls visit with the raw bytes of each version requirement in data, and of
+// its auxiliary entries.
+func walkVerNeed(data []byte, count uint64, endianess binary.ByteOrder,
+	visit func(entry []byte, aux [][]byte)) error {
+	return walkVersionEntries(data, count, verNeedSize, verNeedAuxSize, endianess,
+		func(entry []byte) (uint64, uint64, uint64) {
+			return uint64(endianess.Uint16(entry[2:])), uint64(endianess.Uint32(entry[8:])),
+				uint64(endianess.Uint32(entry[12:]))
+		},
+		func(aux []byte) uint64 {
+			return uint64(endianess.Uint32(aux[12:]))
+		}, visit)
+}
+
+// Walks the chain of count version entries of size entrySize in data. The
+// function fields returns the number of auxiliary entries of an entry, the
+// offset of the first one relative to the entry, and the offset of the next
+// entry relative to the entry. The function auxNext returns the offset of the
+// next auxiliary entry relative to an auxiliary entry.
+func walkVersionEntries(
+	data []byte, count, entrySize, auxSize uint64, endianess binary.ByteOrder,
+	fields func(entry []byte) (uint64, uint64, uint64), auxNext func(aux []byte) uint64,
+	visit func(entry []byte, aux [][]byte)) error {
+	offset := uint64(0)
+	for i := uint64(0); i < count; i++ {
+		if offset+entrySize > uint64(len(data)) {
+			return fmt.Errorf("Version entry %d is out of bounds.", i)
+		}
+
+		entry := data[offset : offset+entrySize]
+		auxCount, auxOffset, next := fields(entry)
+		auxOffset += offset
+
+		var aux [][]byte
+		for j := uint64(0); j < auxCount; j++ {
+			if auxOffset+auxSize > uint64(len(data)) {
+				return fmt.Errorf("Auxiliary entry %d of version entry %d is out of bounds.", j, i)
+			}
+
+			aux = append(aux, data[auxOffset:auxOffset+auxSize])
+			auxOffset += auxNext(data[auxOffset:])
+		}
+		visit(entry, aux)
+
+		if next == 0 {
+			break
+		}
+		offset += next
+	}
+
+	return nil
+}
+
+// Returns the raw data of the dynamic entries, terminated by DT_NULL entries
+// so that there are count entries in all.
+func encodeDynEntries(
+	entries []DynEntry, count uint64, class ELFClass, endianess binary.ByteOrder) ([]byte, error) {
+	entrySize := uint64(16)
+	if class == Class32 {
+		entrySize = 8
+	}
+
+	data := make([]byte, count*entrySize)
+	for i, entry := range entries {
+		offset := uint64(i) * entrySize
+		if class == Class32 {
+			if !fitsUint32(uint64(entry.Tag), entry.Value) {
+				return nil, fmt.Errorf("Dynamic entry with tag 0x%x does not fit.", entry.Tag)
+			}
+			endianess.PutUint32(data[offset:], uint32(entry.Tag))
+			endianess.PutUint32(data[offset+4:], uint32(entry.Value))
+		} else {
+			endianess.PutUint64(data[offset:], uint64(entry.Tag))
+			endianess.PutUint64(data[offset+8:], entry.Value)
+		}
+	}
+
+	return data, nil
+}
+
+// Returns the first segment of the type and its index in the program header
+// table, or nil and -1 if there is no such segment.
+func (elf *ELF) segmentOfType(segType uint32) (SegHdr, int) {
+	for i, segHdr := range elf.progHdrTbl {
+		if segHdr.Type() == segType {
+			return segHdr, i
+		}
+	}
+
+	return nil, -1
+}
+
+// Returns the section holding the program interpreter named by the segment.
+func (elf *ELF) interpSection(segHdr SegHdr) *Section {
+	for _, section := range elf.sections {
+		hdr := section.header
+		if hdr.Type() == SectTypeProgBits && hdr.Flags()&SectFlagAlloc != 0 &&
+			hdr.Address() == segHdr.VirtualAddress() {
+			return section
+		}
+	}
+
+	return nil
+}
+
+// Moves the section to the end of the loadable segment holding relocated data,
+// and sets its data. The segment is added if not present. A section which is
+// already at the end of the segment is grown in place.
+func (elf *ELF) relocateSection(section *Section, data []byte) error {
+	segIndex, err := elf.patchSegment()
+	if err != nil {
+		return err
+	}
+
+	class := elf.header.ELFIdent().Class
+	seg := SegHdrFieldsOf(elf.progHdrTbl[segIndex])
+	offset := alignUp(seg.Offset+seg.FileSize, section.header.Alignment())
+	if hdr := section.header; hdr.Offset() > seg.Offset &&
+		hdr.Offset()+hdr.Size() == seg.Offset+seg.FileSize {
+		offset = hdr.Offset()
+	}
+
+	fields := SectHdrFieldsOf(section.header)
+	fields.Offset = offset
+	fields.Address = seg.VirtualAddress + (offset - seg.Offset)
+	fields.Size = uint64(len(data))
+	hdr, err := NewSectHdr(class, fields)
+	if err != nil {
+		return err
+	}
+
+	seg.FileSize = offset + uint64(len(data)) - seg.Offset
+	seg.MemSize = seg.FileSize
+	if fields.Flags&SectFlagWrite != 0 {
+		seg.Flags |= SegFlagsWritable
+	}
+	if fields.Flags&SectFlagExecInstr != 0 {
+		seg.Flags |= SegFlagsExecutable
+	}
+	segHdr, err := NewSegHdr(class, seg)
+	if err != nil {
+		return err
+	}
+
+	progHdrTbl := append([]SegHdr(nil), elf.progHdrTbl...)
+	progHdrTbl[segIndex] = segHdr
+	elf.SetProgHdrTbl(progHdrTbl)
+
+	section.SetSectHdr(hdr)
+	section.SetData(data)
+	return nil
+}
+
+// Makes the segment at index segIndex in the program header table describe
+// the section at index sectIndex.
+func (elf *ELF) moveSegment(sectIndex, segIndex int) error {
+	hdr := elf.sections[sectIndex].header
+	fields := SegHdrFieldsOf(elf.progHdrTbl[segIndex])
+	fields.Offset = hdr.Offset()
+	fields.VirtualAddress = hdr.Address()
+	fields.PhysicalAddress = hdr.Address()
+	fields.FileSize = hdr.Size()
+	fields.MemSize = hdr.Size()
+
+	segHdr, err := NewSegHdr(elf.header.ELFIdent().Class, fields)
+	if err != nil {
+		return err
+	}
+
+	progHdrTbl := append([]SegHdr(nil), elf.progHdrTbl...)
+	progHdrTbl[segIndex] = segHdr
+	elf.SetProgHdrTbl(progHdrTbl)
+	return nil
+}
+
+// Returns the index in the program header table of the loadable segment which
+// holds data moved out of its original place, adding it if not present.
+//
+// The segment is added after the end of the file and of the address space
+// of the ELF file, and it starts with the program header table, which grows
+// by the entry of the segment. A PT_PHDR segment is added if not present. The
+// difference between the virtual address and the offset of the segment is
+// the same as that of the first loadable segment, so that the address of the
+// program header table can be computed from its offset as older kernels do.
+func (elf *ELF) patchSegment() (int, error) {
+	if elf.patchSegOffset != 0 {
+		for i, segHdr := range elf.progHdrTbl {
+			if segHdr.Type() == SegTypeLoad && segHdr.Offset() == elf.patchSegOffset {
+				return i, nil
+			}
+		}
+	}
+
+	var firstLoad SegHdr
+	lastLoad := -1
+	maxAddr := uint64(0)
+	for i, segHdr := range elf.progHdrTbl {
+		if segHdr.Type() != SegTypeLoad {
+			continue
+		}
+
+		if firstLoad == nil {
+			firstLoad = segHdr
+		}
+		lastLoad = i
+		if end := segHdr.VirtualAddress() + segHdr.MemSize(); end > maxAddr {
+			maxAddr = end
+		}
+	}
+	if firstLoad == nil {
+		return -1, fmt.Errorf("The ELF file does not have a loadable segment.")
+	}
+	if firstLoad.VirtualAddress() < firstLoad.Offset() {
+		return -1, fmt.Errorf("The first loadable segment starts below its file offset.")
+	}
+
+	align := minUint64(firstLoad.Alignment(), maxPatchSegAlign)
+	if align == 0 {
+		align = 1
+	}
+	delta := firstLoad.VirtualAddress() - firstLoad.Offset()
+
+	fileEnd := uint64(elf.size)
+	for _, section := range elf.sections {
+		hdr := section.header
+		if hdr.Type() != SectTypeNoBits && hdr.Offset()+hdr.Size() > fileEnd {
+			fileEnd = hdr.Offset() + hdr.Size()
+		}
+	}
+	for _, segHdr := range elf.progHdrTbl {
+		if end := segHdr.Offset() + segHdr.FileSize(); end > fileEnd {
+			fileEnd = end
+		}
+	}
+	offset := alignUp(fileEnd, align)
+	if addr := alignUp(maxAddr, align); addr-delta > offset {
+		offset = addr - delta
+	}
+
+	class := elf.header.ELFIdent().Class
+	segHdrSize, tblAlign := uint64(segHdr64Size), uint64(8)
+	if class == Class32 {
+		segHdrSize, tblAlign = segHdr32Size, 4
+	}
+
+	// The program header table with the new segment after the last loadable
+	// segment, and the PT_PHDR segment first if it is added.
+	phdr := SegHdrFields{
+		Type:      SegTypeProgHdr,
+		Flags:     SegFlagsReadable,
+		Alignment: tblAlign,
+	}
+	phdrIndex, segIndex := -1, -1
+	var progHdrTbl []SegHdr
+	if segHdr, _ := elf.segmentOfType(SegTypeProgHdr); segHdr == nil {
+		progHdrTbl = append(progHdrTbl, nil)
+		phdrIndex = 0
+	}
+	for i, segHdr := range elf.progHdrTbl {
+		if segHdr.Type() == SegTypeProgHdr && phdrIndex < 0 {
+			phdr = SegHdrFieldsOf(segHdr)
+			phdrIndex = len(progHdrTbl)
+		}
+		progHdrTbl = append(progHdrTbl, segHdr)
+		if i == lastLoad {
+			segIndex = len(progHdrTbl)
+			progHdrTbl = append(progHdrTbl, nil)
+		}
+	}
+
+	size := uint64(len(progHdrTbl)) * segHdrSize
+	addr := delta + offset
+	phdr.Offset, phdr.VirtualAddress, phdr.PhysicalAddress = offset, addr, addr
+	phdr.FileSize, phdr.MemSize = size, size
+	load := SegHdrFields{
+		Type:            SegTypeLoad,
+		Offset:          offset,
+		VirtualAddress:  addr,
+		PhysicalAddress: addr,
+		FileSize:        size,
+		MemSize:         size,
+		Flags:           SegFlagsReadable,
+		Alignment:       align,
+	}
+
+	var err error
+	progHdrTbl[phdrIndex], err = NewSegHdr(class, phdr)
+	if err != nil {
+		return -1, err
+	}
+	progHdrTbl[segIndex], err = NewSegHdr(class, load)
+	if err != nil {
+		return -1, err
+	}
+
+	elf.SetProgHdrTbl(progHdrTbl)
+	elf.patchSegOffset = offset
+	return segIndex, nil
+}
@@ -0,0 +1,285 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestInterpreter(t *testing.T) {
+	expected := map[string]string{
+		"test_data/linux_x86_64.exe": "/lib64/ld-linux-x86-64.so.2",
+		"test_data/linux_x86_64.so":  "",
+	}
+	for fileName, interp := range expected {
+		elf, err := Read(fileName)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		defer elf.Close()
+
+		actual, err := elf.Interpreter()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if actual != interp {
+			t.Errorf("Wrong interpreter of '%s': %s", fileName, actual)
+		}
+	}
+}
+
+func TestPatchInPlace(t *testing.T) {
+	const fileName = "test_data/linux_x86_64.so"
+	elf, err := Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	// The new DT_RUNPATH fits in place of the old one, the new DT_RPATH is a
+	// suffix of the new DT_RUNPATH, and the dynamic section has room for an
+	// entry.
+	err = elf.SetRunPath("/opt/golf")
+	if err == nil {
+		err = elf.SetRPath("/golf")
+	}
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	patched, err := writeAndReload(elf)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if patched.Size() != elf.Size() {
+		t.Errorf("Size of the file changed: %d", patched.Size())
+		return
+	}
+	if len(patched.ProgHdrTbl()) != len(elf.ProgHdrTbl()) {
+		t.Errorf("Segments were added to the file.")
+		return
+	}
+
+	dyn, err := patched.Dynamic()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if dyn.RunPath != "/opt/golf" || dyn.RPath != "/golf" {
+		t.Errorf("Wrong dynamic properties: %s, %s", dyn.RunPath, dyn.RPath)
+		return
+	}
+
+	// The symbols and versions share the string table.
+	syms, err := patched.DynamicSymbols()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(syms) == 0 || syms[len(syms)-1].Name != "golf_copy" {
+		t.Errorf("Wrong dynamic symbols in the patched file.")
+		return
+	}
+
+	// The SONAME is shared with the base version definition, so the new
+	// SONAME is added to the string table.
+	err = elf.SetRPath("")
+	if err == nil {
+		err = elf.SetSOName("libgolf.so.2")
+	}
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	patched, err = writeAndReload(elf)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	dyn, err = patched.Dynamic()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if dyn.Has(DynTagRPath) || dyn.SOName != "libgolf.so.2" {
+		t.Errorf("Wrong dynamic properties: %s, %s", dyn.RPath, dyn.SOName)
+		return
+	}
+
+	defs, err := patched.VersionDefinitions()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(defs) == 0 || defs[0].Name != "libgolf.so.1" {
+		t.Errorf("Wrong version definitions: %v", defs)
+	}
+}
+
+func TestPatchRelocated(t *testing.T) {
+	const fileName = "test_data/linux_x86_64.exe"
+	elf, err := Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	origInterp, err := elf.Interpreter()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	dir, err := ioutil.TempDir("", "golf")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// A path to the interpreter which does not fit in the '.interp' section.
+	interpDir := filepath.Join(dir, "a_directory_with_a_long_name_for_the_interpreter")
+	err = os.Mkdir(interpDir, 0755)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	interp := filepath.Join(interpDir, "ld.so")
+	err = os.Symlink(origInterp, interp)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The added libraries do not fit in the string table, and the entries do
+	// not fit in the dynamic section.
+	const runPath = "$ORIGIN/../lib/a_directory_with_a_long_name:/opt/golf/lib"
+	needed := []string{"libm.so.6", "libdl.so.2", "librt.so.1", "libutil.so.1", "libpthread.so.0"}
+	err = elf.SetInterpreter(interp)
+	if err == nil {
+		err = elf.SetRunPath(runPath)
+	}
+	for _, name := range needed {
+		if err == nil {
+			err = elf.AddNeeded(name)
+		}
+	}
+	if err == nil {
+		err = elf.ReplaceNeeded("libm.so.6", "libc.so.6")
+	}
+	if err == nil {
+		err = elf.RemoveNeeded("libc.so.6")
+	}
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	patchedFileName := filepath.Join(dir, "patched.exe")
+	err = elf.WriteFile(patchedFileName, 0755)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	patched, err := Read(patchedFileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer patched.Close()
+
+	actualInterp, err := patched.Interpreter()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if actualInterp != interp {
+		t.Errorf("Wrong interpreter: %s", actualInterp)
+		return
+	}
+
+	dyn, err := patched.Dynamic()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	expectedNeeded := []string{
+		"libpthread.so.0", "libutil.so.1", "librt.so.1", "libdl.so.2", "libc.so.6"}
+	if !reflect.DeepEqual(dyn.Needed, expectedNeeded) {
+		t.Errorf("Wrong needed libraries: %v", dyn.Needed)
+		return
+	}
+	if dyn.RunPath != runPath {
+		t.Errorf("Wrong run path: %s", dyn.RunPath)
+		return
+	}
+
+	reqs, err := patched.VersionRequirements()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(reqs) != 1 || reqs[0].File != "libc.so.6" {
+		t.Errorf("Wrong version requirements: %v", reqs)
+		return
+	}
+
+	// A loadable segment is added after the other loadable segments. It holds
+	// the program header table, as described by the PT_PHDR segment.
+	progHdrTbl := patched.ProgHdrTbl()
+	if len(progHdrTbl) != len(elf.ProgHdrTbl()) || len(progHdrTbl) != 10 {
+		t.Errorf("Wrong number of segments: %d", len(progHdrTbl))
+		return
+	}
+	phdr, load := progHdrTbl[0], progHdrTbl[4]
+	if phdr.Type() != SegTypeProgHdr || load.Type() != SegTypeLoad ||
+		phdr.Offset() != load.Offset() || phdr.VirtualAddress() != load.VirtualAddress() ||
+		patched.Header().ProgHdrTblOffset() != phdr.Offset() {
+		t.Errorf("Wrong program header table segments.")
+		return
+	}
+	first := progHdrTbl[2]
+	if load.VirtualAddress()-load.Offset() != first.VirtualAddress()-first.Offset() {
+		t.Errorf("Wrong virtual address of the added segment: 0x%x", load.VirtualAddress())
+		return
+	}
+
+	_, err = os.Stat(origInterp)
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" || err != nil {
+		return
+	}
+	err = exec.Command(patchedFileName).Run()
+	if err != nil {
+		t.Errorf("Error running the patched file.\n%s", err.Error())
+	}
+}
@@ -0,0 +1,400 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Values of type RelocType represent the machine specific type of a
+// relocation. The constants for the different machines are named with the
+// prefixes Reloc386_, RelocX86_64_, RelocARM_, RelocAArch64_, RelocRISCV_,
+// RelocPPC64_, RelocMIPS_ and RelocS390_.
+type RelocType uint32
+
+// Returns the name of the relocation type t for the machine, as found in the
+// ELF specification of the machine. An empty string is returned if the type
+// or machine is unknown. The types of 64-bit MIPS relocations combine up to
+// three types, which are known only if the second and third are zero.
+func RelocTypeStr(machine MachineArch, t RelocType) string {
+	switch machine {
+	case MachineX86:
+		return Reloc386Str[t]
+	case MachineX86_64:
+		return RelocX86_64Str[t]
+	case MachineARM:
+		return RelocARMStr[t]
+	case MachineAArch64:
+		return RelocAArch64Str[t]
+	case MachineRISCV:
+		return RelocRISCVStr[t]
+	case MachinePowerPC64:
+		return RelocPPC64Str[t]
+	case MachineMIPS:
+		return RelocMIPSStr[t]
+	case MachineS390:
+		return RelocS390Str[t]
+	default:
+		return ""
+	}
+}
+
+// Relocation is an entry in a relocation table.
+type Relocation struct {
+	// The location at which the relocation should be applied. For relocatable
+	// files, it is an offset into the target section. For executables and
+	// shared libraries, it is a virtual address.
+	Offset uint64
+
+	// The machine specific type of the relocation.
+	Type RelocType
+
+	// The index of the symbol, in the linked symbol table, with respect to
+	// which the relocation should be made.
+	SymIndex uint32
+
+	// The symbol at SymIndex. It is nil if SymIndex is zero.
+	Sym *ResolvedSymbol
+
+	// The addend of the relocation. It is always zero for relocations read
+	// from SHT_REL tables, whose addends are stored at the relocated location.
+	Addend int64
+
+	// True if the relocation was read from a SHT_RELA table.
+	HasAddend bool
+}
+
+// RelocTbl is the decoded contents of a SHT_REL or SHT_RELA section.
+type RelocTbl struct {
+	// The relocation section.
+	Section *Section
+
+	// The section to which the relocations apply, as specified by the info
+	// field of the relocation section header. It is nil for tables which do
+	// not apply to a single section, like '.rela.dyn'.
+	Target *Section
+
+	Relocs []Relocation
+}
+
+// Returns the relocation tables of all SHT_REL and SHT_RELA sections in the
+// order of their headers in the section header table.
+func (elf *ELF) RelocTbls() ([]*RelocTbl, error) {
+	var tbls []*RelocTbl
+	for _, section := range elf.sections {
+		sectType := section.SectHdr().Type()
+		if sectType != SectTypeRel && sectType != SectTypeRelA {
+			continue
+		}
+
+		tbl, err := elf.RelocTbl(section)
+		if err != nil {
+			return nil, err
+		}
+
+		tbls = append(tbls, tbl)
+	}
+
+	return tbls, nil
+}
+
+// Returns the relocation table in the SHT_REL or SHT_RELA section. Symbols of
+// the relocations are resolved using the symbol table linked to the section.
+func (elf *ELF) RelocTbl(section *Section) (*RelocTbl, error) {
+	hdr := section.SectHdr()
+	if hdr.Type() != SectTypeRel && hdr.Type() != SectTypeRelA {
+		return nil, fmt.Errorf("Section '%s' is not a relocation section.", section.Name())
+	}
+
+	tbl := new(RelocTbl)
+	tbl.Section = section
+
+	info := hdr.Info()
+	if info != 0 && info < uint32(len(elf.sections)) {
+		tbl.Target = elf.sections[info]
+	}
+
+	var symbols []ResolvedSymbol
+	link := hdr.Link()
+	if link != 0 && link < uint32(len(elf.sections)) {
+		var err error
+		switch elf.sections[link].SectHdr().Type() {
+		case SectTypeSymTab:
+			symbols, err = elf.Symbols()
+		case SectTypeDynSym:
+			symbols, err = elf.DynamicSymbols()
+		}
+		if err != nil {
+			err = fmt.Errorf(
+				"Error reading symbols for relocation section '%s'.\n%s",
+				section.Name(), err.Error())
+			return nil, err
+		}
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		err = fmt.Errorf(
+			"Error reading relocation section '%s'.\n%s", section.Name(), err.Error())
+		return nil, err
+	}
+
+	tbl.Relocs, err = elf.readRelocs(
+		data, hdr.EntrySize(), hdr.Type() == SectTypeRelA, symbols)
+	if err != nil {
+		err = fmt.Errorf(
+			"Error decoding relocation section '%s'.\n%s", section.Name(), err.Error())
+		return nil, err
+	}
+
+	return tbl, nil
+}
+
+// Returns the dynamic relocations, which are the relocations in the tables
+// referred to by the DT_RELA, DT_REL and DT_JMPREL entries of the dynamic
+// section, in that order. Symbols of the relocations are resolved using the
+// dynamic symbol table. If the ELF file does not have a dynamic section, then
+// an empty slice is returned.
+func (elf *ELF) DynamicRelocs() ([]Relocation, error) {
+	dyn, err := elf.Dynamic()
+	if err != nil {
+		return nil, err
+	}
+	if dyn == nil {
+		return nil, nil
+	}
+
+	symbols, err := elf.DynamicSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	jmpRel := dyn.JmpRel
+	if dyn.PltRelType == DynTagRelA {
+		jmpRel.EntrySize = dyn.RelA.EntrySize
+	} else {
+		jmpRel.EntrySize = dyn.Rel.EntrySize
+	}
+
+	tbls := []struct {
+		name   string
+		tbl    DynRelTbl
+		isRelA bool
+	}{
+		{"DT_RELA", dyn.RelA, true},
+		{"DT_REL", dyn.Rel, false},
+		{"DT_JMPREL", jmpRel, dyn.PltRelType == DynTagRelA},
+	}
+
+	var relocs []Relocation
+	for _, t := range tbls {
+		if t.tbl.Addr == 0 || t.tbl.Size == 0 {
+			continue
+		}
+
+		offset, err := elf.addrToOffset(t.tbl.Addr, t.tbl.Size)
+		if err != nil {
+			err = fmt.Errorf("Unable to locate %s table.\n%s", t.name, err.Error())
+			return nil, err
+		}
+
+		data, err := elf.readAt(offset, t.tbl.Size)
+		if err != nil {
+			err = fmt.Errorf("Error reading %s table.\n%s", t.name, err.Error())
+			return nil, err
+		}
+
+		// Files with only PLT relocations need not have the DT_RELAENT or
+		// DT_RELENT entry, in which case the entries have the standard size.
+		entrySize := t.tbl.EntrySize
+		if entrySize == 0 {
+			entrySize = relocEntrySize(elf.Header().ELFIdent().Class, t.isRelA)
+		}
+		tblRelocs, err := elf.readRelocs(data, entrySize, t.isRelA, symbols)
+		if err != nil {
+			err = fmt.Errorf("Error decoding %s table.\n%s", t.name, err.Error())
+			return nil, err
+		}
+
+		relocs = append(relocs, tblRelocs...)
+	}
+
+	return relocs, nil
+}
+
+// Returns the size of the Elf32_Rel, Elf32_Rela, Elf64_Rel or Elf64_Rela
+// structure.
+func relocEntrySize(class ELFClass, isRelA bool) uint64 {
+	size := uint64(16)
+	if class == Class32 {
+		size = 8
+	}
+	if isRelA {
+		size += size / 2
+	}
+
+	return size
+}
+
+func (elf *ELF) readRelocs(
+	data []byte, entrySize uint64, isRelA bool, symbols []ResolvedSymbol) ([]Relocation, error) {
+	class := elf.Header().ELFIdent().Class
+	endianess := elf.Endianess()
+
+	if entrySize < relocEntrySize(class, isRelA) {
+		return nil, fmt.Errorf("Invalid relocation entry size %d.", entrySize)
+	}
+
+	count := uint64(len(data)) / entrySize
+	relocs := make([]Relocation, count)
+	for i := uint64(0); i < count; i++ {
+		entry := data[i*entrySize:]
+		reloc := &relocs[i]
+		reloc.HasAddend = isRelA
+
+		if class == Class32 {
+			reloc.Offset = uint64(endianess.Uint32(entry))
+			info := endianess.Uint32(entry[4:])
+			reloc.SymIndex = info >> 8
+			reloc.Type = RelocType(info & 0xff)
+			if isRelA {
+				reloc.Addend = int64(int32(endianess.Uint32(entry[8:])))
+			}
+		} else {
+			reloc.Offset = endianess.Uint64(entry)
+			reloc.SymIndex, reloc.Type = elf.relocInfo64(entry[8:])
+			if isRelA {
+				reloc.Addend = int64(endianess.Uint64(entry[16:]))
+			}
+		}
+
+		if reloc.SymIndex == 0 {
+			continue
+		}
+		if reloc.SymIndex >= uint32(len(symbols)) {
+			err := fmt.Errorf(
+				"Invalid symbol index %d in relocation %d.", reloc.SymIndex, i)
+			return nil, err
+		}
+		reloc.Sym = &symbols[reloc.SymIndex]
+	}
+
+	return relocs, nil
+}
+
+// Returns the symbol index and the type in the info field of a 64-bit
+// relocation entry. MIPS64 splits the field into a 32-bit symbol index
+// followed by the bytes r_ssym, r_type3, r_type2 and r_type, in that order
+// for either endianess. The three types are combined into the returned type,
+// r_type in the lowest byte.
+func (elf *ELF) relocInfo64(info []byte) (uint32, RelocType) {
+	endianess := elf.Endianess()
+	if elf.Header().Machine() == MachineMIPS {
+		t := uint32(info[7]) | uint32(info[6])<<8 | uint32(info[5])<<16
+		return endianess.Uint32(info), RelocType(t)
+	}
+
+	value := endianess.Uint64(info)
+	return uint32(value >> 32), RelocType(value & 0xffffffff)
+}
+
+// Sets the symbol index in the info field of a 64-bit relocation entry,
+// keeping its type.
+func (elf *ELF) setRelocSymIndex64(info []byte, symIndex uint32) {
+	endianess := elf.Endianess()
+	if elf.Header().Machine() == MachineMIPS {
+		endianess.PutUint32(info, symIndex)
+		return
+	}
+
+	value := endianess.Uint64(info)
+	endianess.PutUint64(info, uint64(symIndex)<<32|value&0xffffffff)
+}
+
+// RelrTbl is the decoded contents of a SHT_RELR section, which holds the
+// relative relocations of a dynamic object in a compact form.
+type RelrTbl struct {
+	// The relocation section.
+	Section *Section
+
+	// The virtual addresses of the locations to which the relative
+	// relocations apply, in the order in which they are encoded.
+	Offsets []uint64
+}
+
+// Returns the relative relocation table in the SHT_RELR section.
+func (elf *ELF) RelrTbl(section *Section) (*RelrTbl, error) {
+	hdr := section.SectHdr()
+	if hdr.Type() != SectTypeRelr {
+		return nil, fmt.Errorf("Section '%s' is not a SHT_RELR section.", section.Name())
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		err = fmt.Errorf(
+			"Error reading relocation section '%s'.\n%s", section.Name(), err.Error())
+		return nil, err
+	}
+
+	wordSize := uint64(8)
+	if elf.Header().ELFIdent().Class == Class32 {
+		wordSize = 4
+	}
+	entrySize := hdr.EntrySize()
+	if entrySize == 0 {
+		entrySize = wordSize
+	}
+	if entrySize != wordSize {
+		return nil, fmt.Errorf(
+			"Invalid entry size %d of relocation section '%s'.", entrySize, section.Name())
+	}
+
+	return &RelrTbl{section, decodeRelr(data, wordSize, elf.Endianess())}, nil
+}
+
+// Returns the offsets encoded in the entries of a SHT_RELR section. An entry
+// with its lowest bit clear is the offset of a relocation. Each following
+// entry with its lowest bit set is a bitmap of the relocations at the next
+// 63 (or 31) words, from its second lowest bit up.
+func decodeRelr(data []byte, wordSize uint64, endianess binary.ByteOrder) []uint64 {
+	var offsets []uint64
+	var next uint64
+	bits := wordSize*8 - 1
+	for i := uint64(0); i+wordSize <= uint64(len(data)); i += wordSize {
+		var entry uint64
+		if wordSize == 4 {
+			entry = uint64(endianess.Uint32(data[i:]))
+		} else {
+			entry = endianess.Uint64(data[i:])
+		}
+
+		if entry&1 == 0 {
+			offsets = append(offsets, entry)
+			next = entry + wordSize
+			continue
+		}
+		for bit := uint64(0); bit < bits; bit++ {
+			if entry&(2<<bit) != 0 {
+				offsets = append(offsets, next+bit*wordSize)
+			}
+		}
+		next += bits * wordSize
+	}
+
+	return offsets
+}
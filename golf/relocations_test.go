@@ -0,0 +1,197 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"testing"
+)
+
+func testReloc(
+	t *testing.T, reloc Relocation, index int,
+	offset uint64, relocType RelocType, symName string, addend int64) {
+	if reloc.Offset != offset {
+		t.Errorf("Wrong offset 0x%x of relocation %d.", reloc.Offset, index)
+	}
+	if reloc.Type != relocType {
+		t.Errorf("Wrong type %d of relocation %d.", reloc.Type, index)
+	}
+	if symName == "" {
+		if reloc.Sym != nil {
+			t.Errorf("Unexpected symbol for relocation %d.", index)
+		}
+	} else if reloc.Sym == nil || reloc.Sym.Name != symName {
+		t.Errorf("Wrong symbol for relocation %d.", index)
+	}
+	if reloc.Addend != addend {
+		t.Errorf("Wrong addend 0x%x of relocation %d.", reloc.Addend, index)
+	}
+}
+
+func TestRelocTbls32(t *testing.T) {
+	elf, err := Read("test_data/linux_x86.o")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	tbls, err := elf.RelocTbls()
+	if err != nil {
+		t.Errorf("Error reading relocation tables.\n%s", err.Error())
+		return
+	}
+	if len(tbls) != 1 {
+		t.Errorf("Wrong number of relocation tables %d.", len(tbls))
+		return
+	}
+
+	tbl := tbls[0]
+	if tbl.Section != elf.Sections()[3] || tbl.Target != elf.Sections()[2] {
+		t.Errorf("Wrong relocation section or target section.")
+		return
+	}
+	if len(tbl.Relocs) != 4 {
+		t.Errorf("Wrong number of relocations %d.", len(tbl.Relocs))
+		return
+	}
+
+	testReloc(t, tbl.Relocs[0], 0, 0x11, Reloc386_Pc32, "__x86.get_pc_thunk.bx", 0)
+	testReloc(t, tbl.Relocs[1], 1, 0x17, Reloc386_Gotpc, "_GLOBAL_OFFSET_TABLE_", 0)
+	testReloc(t, tbl.Relocs[2], 2, 0x21, Reloc386_Plt32, "external_func", 0)
+	testReloc(t, tbl.Relocs[3], 3, 0x29, Reloc386_Gotoff, "counter", 0)
+	if tbl.Relocs[0].HasAddend {
+		t.Errorf("SHT_REL relocations should not have addends.")
+	}
+
+	name := RelocTypeStr(elf.Header().Machine(), tbl.Relocs[2].Type)
+	if name != "R_386_PLT32" {
+		t.Errorf("Wrong relocation type name '%s'.", name)
+	}
+}
+
+func TestRelocTbls64(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	tbls, err := elf.RelocTbls()
+	if err != nil {
+		t.Errorf("Error reading relocation tables.\n%s", err.Error())
+		return
+	}
+	if len(tbls) != 2 {
+		t.Errorf("Wrong number of relocation tables %d.", len(tbls))
+		return
+	}
+
+	if tbls[0].Target != nil || len(tbls[0].Relocs) != 1 {
+		t.Errorf("Wrong relocation table '.rela.dyn'.")
+		return
+	}
+	testReloc(t, tbls[0].Relocs[0], 0, 0x600ff8, RelocX86_64_GlobDat, "__gmon_start__", 0)
+
+	if tbls[1].Target != elf.Sections()[12] {
+		t.Errorf("Wrong target section of '.rela.plt'.")
+	}
+	if len(tbls[1].Relocs) != 2 {
+		t.Errorf("Wrong number of relocations in '.rela.plt'.")
+		return
+	}
+	testReloc(
+		t, tbls[1].Relocs[0], 0, 0x601018, RelocX86_64_JumpSlot, "__libc_start_main", 0)
+	testReloc(
+		t, tbls[1].Relocs[1], 1, 0x601020, RelocX86_64_JumpSlot, "__gmon_start__", 0)
+}
+
+func TestDynamicRelocs(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	relocs, err := elf.DynamicRelocs()
+	if err != nil {
+		t.Errorf("Error reading dynamic relocations.\n%s", err.Error())
+		return
+	}
+	if len(relocs) != 11 {
+		t.Errorf("Wrong number of dynamic relocations %d.", len(relocs))
+		return
+	}
+
+	testReloc(t, relocs[0], 0, 0x3d68, RelocX86_64_Relative, "", 0x1110)
+	testReloc(t, relocs[5], 5, 0x3fe0, RelocX86_64_GlobDat, "golf_counter", 0)
+	testReloc(t, relocs[10], 10, 0x3fd0, RelocX86_64_JumpSlot, "memcpy", 0)
+
+	name := RelocTypeStr(MachineX86_64, relocs[10].Type)
+	if name != "R_X86_64_JUMP_SLOT" {
+		t.Errorf("Wrong relocation type name '%s'.", name)
+	}
+}
+
+func TestRelocTypeStrMachines(t *testing.T) {
+	tests := []struct {
+		machine MachineArch
+		t       RelocType
+		name    string
+	}{
+		{MachineRISCV, RelocRISCV_JumpSlot, "R_RISCV_JUMP_SLOT"},
+		{MachinePowerPC64, RelocPPC64_Relative, "R_PPC64_RELATIVE"},
+		{MachineMIPS, RelocMIPS_Rel32, "R_MIPS_REL32"},
+		{MachineS390, RelocS390_JmpSlot, "R_390_JMP_SLOT"},
+		{MachineS390, RelocType(0x1000), ""},
+	}
+	for _, test := range tests {
+		name := RelocTypeStr(test.machine, test.t)
+		if name != test.name {
+			t.Errorf("Wrong relocation type name '%s', expected '%s'.", name, test.name)
+		}
+	}
+}
+
+func TestRelrTbl(t *testing.T) {
+	elf, err := Read("test_data/relr_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	tbl, err := elf.RelrTbl(elf.SectMap()[".relr.dyn"][0])
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// An address, followed by a bitmap with all 63 bits set and a bitmap
+	// with gaps.
+	if len(tbl.Offsets) != 71 || tbl.Offsets[0] != 0x2000 || tbl.Offsets[63] != 0x21f8 ||
+		tbl.Offsets[64] != 0x2200 || tbl.Offsets[65] != 0x2210 || tbl.Offsets[70] != 0x2248 {
+		t.Errorf("Wrong relative relocation offsets: %x", tbl.Offsets)
+		return
+	}
+
+	_, err = elf.RelrTbl(elf.SectMap()[".dynsym"][0])
+	if err == nil {
+		t.Errorf("Expected an error decoding '.dynsym' as a SHT_RELR section.")
+	}
+}
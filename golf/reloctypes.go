@@ -0,0 +1,1521 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+// Relocation types for x86 (Intel 80386).
+const (
+	Reloc386_None        RelocType = RelocType(0)
+	Reloc386_32          RelocType = RelocType(1)
+	Reloc386_Pc32        RelocType = RelocType(2)
+	Reloc386_Got32       RelocType = RelocType(3)
+	Reloc386_Plt32       RelocType = RelocType(4)
+	Reloc386_Copy        RelocType = RelocType(5)
+	Reloc386_GlobDat     RelocType = RelocType(6)
+	Reloc386_JmpSlot     RelocType = RelocType(7)
+	Reloc386_Relative    RelocType = RelocType(8)
+	Reloc386_Gotoff      RelocType = RelocType(9)
+	Reloc386_Gotpc       RelocType = RelocType(10)
+	Reloc386_32plt       RelocType = RelocType(11)
+	Reloc386_TlsTpoff    RelocType = RelocType(14)
+	Reloc386_TlsIe       RelocType = RelocType(15)
+	Reloc386_TlsGotie    RelocType = RelocType(16)
+	Reloc386_TlsLe       RelocType = RelocType(17)
+	Reloc386_TlsGd       RelocType = RelocType(18)
+	Reloc386_TlsLdm      RelocType = RelocType(19)
+	Reloc386_16          RelocType = RelocType(20)
+	Reloc386_Pc16        RelocType = RelocType(21)
+	Reloc386_8           RelocType = RelocType(22)
+	Reloc386_Pc8         RelocType = RelocType(23)
+	Reloc386_TlsGd32     RelocType = RelocType(24)
+	Reloc386_TlsGdPush   RelocType = RelocType(25)
+	Reloc386_TlsGdCall   RelocType = RelocType(26)
+	Reloc386_TlsGdPop    RelocType = RelocType(27)
+	Reloc386_TlsLdm32    RelocType = RelocType(28)
+	Reloc386_TlsLdmPush  RelocType = RelocType(29)
+	Reloc386_TlsLdmCall  RelocType = RelocType(30)
+	Reloc386_TlsLdmPop   RelocType = RelocType(31)
+	Reloc386_TlsLdo32    RelocType = RelocType(32)
+	Reloc386_TlsIe32     RelocType = RelocType(33)
+	Reloc386_TlsLe32     RelocType = RelocType(34)
+	Reloc386_TlsDtpmod32 RelocType = RelocType(35)
+	Reloc386_TlsDtpoff32 RelocType = RelocType(36)
+	Reloc386_TlsTpoff32  RelocType = RelocType(37)
+	Reloc386_Size32      RelocType = RelocType(38)
+	Reloc386_TlsGotdesc  RelocType = RelocType(39)
+	Reloc386_TlsDescCall RelocType = RelocType(40)
+	Reloc386_TlsDesc     RelocType = RelocType(41)
+	Reloc386_Irelative   RelocType = RelocType(42)
+	Reloc386_Got32x      RelocType = RelocType(43)
+)
+
+var Reloc386Str = map[RelocType]string{
+	Reloc386_None:        "R_386_NONE",
+	Reloc386_32:          "R_386_32",
+	Reloc386_Pc32:        "R_386_PC32",
+	Reloc386_Got32:       "R_386_GOT32",
+	Reloc386_Plt32:       "R_386_PLT32",
+	Reloc386_Copy:        "R_386_COPY",
+	Reloc386_GlobDat:     "R_386_GLOB_DAT",
+	Reloc386_JmpSlot:     "R_386_JMP_SLOT",
+	Reloc386_Relative:    "R_386_RELATIVE",
+	Reloc386_Gotoff:      "R_386_GOTOFF",
+	Reloc386_Gotpc:       "R_386_GOTPC",
+	Reloc386_32plt:       "R_386_32PLT",
+	Reloc386_TlsTpoff:    "R_386_TLS_TPOFF",
+	Reloc386_TlsIe:       "R_386_TLS_IE",
+	Reloc386_TlsGotie:    "R_386_TLS_GOTIE",
+	Reloc386_TlsLe:       "R_386_TLS_LE",
+	Reloc386_TlsGd:       "R_386_TLS_GD",
+	Reloc386_TlsLdm:      "R_386_TLS_LDM",
+	Reloc386_16:          "R_386_16",
+	Reloc386_Pc16:        "R_386_PC16",
+	Reloc386_8:           "R_386_8",
+	Reloc386_Pc8:         "R_386_PC8",
+	Reloc386_TlsGd32:     "R_386_TLS_GD_32",
+	Reloc386_TlsGdPush:   "R_386_TLS_GD_PUSH",
+	Reloc386_TlsGdCall:   "R_386_TLS_GD_CALL",
+	Reloc386_TlsGdPop:    "R_386_TLS_GD_POP",
+	Reloc386_TlsLdm32:    "R_386_TLS_LDM_32",
+	Reloc386_TlsLdmPush:  "R_386_TLS_LDM_PUSH",
+	Reloc386_TlsLdmCall:  "R_386_TLS_LDM_CALL",
+	Reloc386_TlsLdmPop:   "R_386_TLS_LDM_POP",
+	Reloc386_TlsLdo32:    "R_386_TLS_LDO_32",
+	Reloc386_TlsIe32:     "R_386_TLS_IE_32",
+	Reloc386_TlsLe32:     "R_386_TLS_LE_32",
+	Reloc386_TlsDtpmod32: "R_386_TLS_DTPMOD32",
+	Reloc386_TlsDtpoff32: "R_386_TLS_DTPOFF32",
+	Reloc386_TlsTpoff32:  "R_386_TLS_TPOFF32",
+	Reloc386_Size32:      "R_386_SIZE32",
+	Reloc386_TlsGotdesc:  "R_386_TLS_GOTDESC",
+	Reloc386_TlsDescCall: "R_386_TLS_DESC_CALL",
+	Reloc386_TlsDesc:     "R_386_TLS_DESC",
+	Reloc386_Irelative:   "R_386_IRELATIVE",
+	Reloc386_Got32x:      "R_386_GOT32X",
+}
+
+// Relocation types for x86-64.
+const (
+	RelocX86_64_None           RelocType = RelocType(0)
+	RelocX86_64_64             RelocType = RelocType(1)
+	RelocX86_64_Pc32           RelocType = RelocType(2)
+	RelocX86_64_Got32          RelocType = RelocType(3)
+	RelocX86_64_Plt32          RelocType = RelocType(4)
+	RelocX86_64_Copy           RelocType = RelocType(5)
+	RelocX86_64_GlobDat        RelocType = RelocType(6)
+	RelocX86_64_JumpSlot       RelocType = RelocType(7)
+	RelocX86_64_Relative       RelocType = RelocType(8)
+	RelocX86_64_Gotpcrel       RelocType = RelocType(9)
+	RelocX86_64_32             RelocType = RelocType(10)
+	RelocX86_64_32s            RelocType = RelocType(11)
+	RelocX86_64_16             RelocType = RelocType(12)
+	RelocX86_64_Pc16           RelocType = RelocType(13)
+	RelocX86_64_8              RelocType = RelocType(14)
+	RelocX86_64_Pc8            RelocType = RelocType(15)
+	RelocX86_64_Dtpmod64       RelocType = RelocType(16)
+	RelocX86_64_Dtpoff64       RelocType = RelocType(17)
+	RelocX86_64_Tpoff64        RelocType = RelocType(18)
+	RelocX86_64_Tlsgd          RelocType = RelocType(19)
+	RelocX86_64_Tlsld          RelocType = RelocType(20)
+	RelocX86_64_Dtpoff32       RelocType = RelocType(21)
+	RelocX86_64_Gottpoff       RelocType = RelocType(22)
+	RelocX86_64_Tpoff32        RelocType = RelocType(23)
+	RelocX86_64_Pc64           RelocType = RelocType(24)
+	RelocX86_64_Gotoff64       RelocType = RelocType(25)
+	RelocX86_64_Gotpc32        RelocType = RelocType(26)
+	RelocX86_64_Got64          RelocType = RelocType(27)
+	RelocX86_64_Gotpcrel64     RelocType = RelocType(28)
+	RelocX86_64_Gotpc64        RelocType = RelocType(29)
+	RelocX86_64_Gotplt64       RelocType = RelocType(30)
+	RelocX86_64_Pltoff64       RelocType = RelocType(31)
+	RelocX86_64_Size32         RelocType = RelocType(32)
+	RelocX86_64_Size64         RelocType = RelocType(33)
+	RelocX86_64_Gotpc32Tlsdesc RelocType = RelocType(34)
+	RelocX86_64_TlsdescCall    RelocType = RelocType(35)
+	RelocX86_64_Tlsdesc        RelocType = RelocType(36)
+	RelocX86_64_Irelative      RelocType = RelocType(37)
+	RelocX86_64_Relative64     RelocType = RelocType(38)
+	RelocX86_64_Gotpcrelx      RelocType = RelocType(41)
+	RelocX86_64_RexGotpcrelx   RelocType = RelocType(42)
+)
+
+var RelocX86_64Str = map[RelocType]string{
+	RelocX86_64_None:           "R_X86_64_NONE",
+	RelocX86_64_64:             "R_X86_64_64",
+	RelocX86_64_Pc32:           "R_X86_64_PC32",
+	RelocX86_64_Got32:          "R_X86_64_GOT32",
+	RelocX86_64_Plt32:          "R_X86_64_PLT32",
+	RelocX86_64_Copy:           "R_X86_64_COPY",
+	RelocX86_64_GlobDat:        "R_X86_64_GLOB_DAT",
+	RelocX86_64_JumpSlot:       "R_X86_64_JUMP_SLOT",
+	RelocX86_64_Relative:       "R_X86_64_RELATIVE",
+	RelocX86_64_Gotpcrel:       "R_X86_64_GOTPCREL",
+	RelocX86_64_32:             "R_X86_64_32",
+	RelocX86_64_32s:            "R_X86_64_32S",
+	RelocX86_64_16:             "R_X86_64_16",
+	RelocX86_64_Pc16:           "R_X86_64_PC16",
+	RelocX86_64_8:              "R_X86_64_8",
+	RelocX86_64_Pc8:            "R_X86_64_PC8",
+	RelocX86_64_Dtpmod64:       "R_X86_64_DTPMOD64",
+	RelocX86_64_Dtpoff64:       "R_X86_64_DTPOFF64",
+	RelocX86_64_Tpoff64:        "R_X86_64_TPOFF64",
+	RelocX86_64_Tlsgd:          "R_X86_64_TLSGD",
+	RelocX86_64_Tlsld:          "R_X86_64_TLSLD",
+	RelocX86_64_Dtpoff32:       "R_X86_64_DTPOFF32",
+	RelocX86_64_Gottpoff:       "R_X86_64_GOTTPOFF",
+	RelocX86_64_Tpoff32:        "R_X86_64_TPOFF32",
+	RelocX86_64_Pc64:           "R_X86_64_PC64",
+	RelocX86_64_Gotoff64:       "R_X86_64_GOTOFF64",
+	RelocX86_64_Gotpc32:        "R_X86_64_GOTPC32",
+	RelocX86_64_Got64:          "R_X86_64_GOT64",
+	RelocX86_64_Gotpcrel64:     "R_X86_64_GOTPCREL64",
+	RelocX86_64_Gotpc64:        "R_X86_64_GOTPC64",
+	RelocX86_64_Gotplt64:       "R_X86_64_GOTPLT64",
+	RelocX86_64_Pltoff64:       "R_X86_64_PLTOFF64",
+	RelocX86_64_Size32:         "R_X86_64_SIZE32",
+	RelocX86_64_Size64:         "R_X86_64_SIZE64",
+	RelocX86_64_Gotpc32Tlsdesc: "R_X86_64_GOTPC32_TLSDESC",
+	RelocX86_64_TlsdescCall:    "R_X86_64_TLSDESC_CALL",
+	RelocX86_64_Tlsdesc:        "R_X86_64_TLSDESC",
+	RelocX86_64_Irelative:      "R_X86_64_IRELATIVE",
+	RelocX86_64_Relative64:     "R_X86_64_RELATIVE64",
+	RelocX86_64_Gotpcrelx:      "R_X86_64_GOTPCRELX",
+	RelocX86_64_RexGotpcrelx:   "R_X86_64_REX_GOTPCRELX",
+}
+
+// Relocation types for ARM.
+const (
+	RelocARM_None            RelocType = RelocType(0)
+	RelocARM_Pc24            RelocType = RelocType(1)
+	RelocARM_Abs32           RelocType = RelocType(2)
+	RelocARM_Rel32           RelocType = RelocType(3)
+	RelocARM_Pc13            RelocType = RelocType(4)
+	RelocARM_Abs16           RelocType = RelocType(5)
+	RelocARM_Abs12           RelocType = RelocType(6)
+	RelocARM_ThmAbs5         RelocType = RelocType(7)
+	RelocARM_Abs8            RelocType = RelocType(8)
+	RelocARM_Sbrel32         RelocType = RelocType(9)
+	RelocARM_ThmPc22         RelocType = RelocType(10)
+	RelocARM_ThmPc8          RelocType = RelocType(11)
+	RelocARM_AmpVcall9       RelocType = RelocType(12)
+	RelocARM_Swi24           RelocType = RelocType(13)
+	RelocARM_TlsDesc         RelocType = RelocType(13)
+	RelocARM_ThmSwi8         RelocType = RelocType(14)
+	RelocARM_Xpc25           RelocType = RelocType(15)
+	RelocARM_ThmXpc22        RelocType = RelocType(16)
+	RelocARM_TlsDtpmod32     RelocType = RelocType(17)
+	RelocARM_TlsDtpoff32     RelocType = RelocType(18)
+	RelocARM_TlsTpoff32      RelocType = RelocType(19)
+	RelocARM_Copy            RelocType = RelocType(20)
+	RelocARM_GlobDat         RelocType = RelocType(21)
+	RelocARM_JumpSlot        RelocType = RelocType(22)
+	RelocARM_Relative        RelocType = RelocType(23)
+	RelocARM_Gotoff          RelocType = RelocType(24)
+	RelocARM_Gotpc           RelocType = RelocType(25)
+	RelocARM_Got32           RelocType = RelocType(26)
+	RelocARM_Plt32           RelocType = RelocType(27)
+	RelocARM_Call            RelocType = RelocType(28)
+	RelocARM_Jump24          RelocType = RelocType(29)
+	RelocARM_ThmJump24       RelocType = RelocType(30)
+	RelocARM_BaseAbs         RelocType = RelocType(31)
+	RelocARM_AluPcrel70      RelocType = RelocType(32)
+	RelocARM_AluPcrel158     RelocType = RelocType(33)
+	RelocARM_AluPcrel2315    RelocType = RelocType(34)
+	RelocARM_LdrSbrel110     RelocType = RelocType(35)
+	RelocARM_AluSbrel1912    RelocType = RelocType(36)
+	RelocARM_AluSbrel2720    RelocType = RelocType(37)
+	RelocARM_Target1         RelocType = RelocType(38)
+	RelocARM_Sbrel31         RelocType = RelocType(39)
+	RelocARM_V4bx            RelocType = RelocType(40)
+	RelocARM_Target2         RelocType = RelocType(41)
+	RelocARM_Prel31          RelocType = RelocType(42)
+	RelocARM_MovwAbsNc       RelocType = RelocType(43)
+	RelocARM_MovtAbs         RelocType = RelocType(44)
+	RelocARM_MovwPrelNc      RelocType = RelocType(45)
+	RelocARM_MovtPrel        RelocType = RelocType(46)
+	RelocARM_ThmMovwAbsNc    RelocType = RelocType(47)
+	RelocARM_ThmMovtAbs      RelocType = RelocType(48)
+	RelocARM_ThmMovwPrelNc   RelocType = RelocType(49)
+	RelocARM_ThmMovtPrel     RelocType = RelocType(50)
+	RelocARM_ThmJump19       RelocType = RelocType(51)
+	RelocARM_ThmJump6        RelocType = RelocType(52)
+	RelocARM_ThmAluPrel110   RelocType = RelocType(53)
+	RelocARM_ThmPc12         RelocType = RelocType(54)
+	RelocARM_Abs32Noi        RelocType = RelocType(55)
+	RelocARM_Rel32Noi        RelocType = RelocType(56)
+	RelocARM_AluPcG0Nc       RelocType = RelocType(57)
+	RelocARM_AluPcG0         RelocType = RelocType(58)
+	RelocARM_AluPcG1Nc       RelocType = RelocType(59)
+	RelocARM_AluPcG1         RelocType = RelocType(60)
+	RelocARM_AluPcG2         RelocType = RelocType(61)
+	RelocARM_LdrPcG1         RelocType = RelocType(62)
+	RelocARM_LdrPcG2         RelocType = RelocType(63)
+	RelocARM_LdrsPcG0        RelocType = RelocType(64)
+	RelocARM_LdrsPcG1        RelocType = RelocType(65)
+	RelocARM_LdrsPcG2        RelocType = RelocType(66)
+	RelocARM_LdcPcG0         RelocType = RelocType(67)
+	RelocARM_LdcPcG1         RelocType = RelocType(68)
+	RelocARM_LdcPcG2         RelocType = RelocType(69)
+	RelocARM_AluSbG0Nc       RelocType = RelocType(70)
+	RelocARM_AluSbG0         RelocType = RelocType(71)
+	RelocARM_AluSbG1Nc       RelocType = RelocType(72)
+	RelocARM_AluSbG1         RelocType = RelocType(73)
+	RelocARM_AluSbG2         RelocType = RelocType(74)
+	RelocARM_LdrSbG0         RelocType = RelocType(75)
+	RelocARM_LdrSbG1         RelocType = RelocType(76)
+	RelocARM_LdrSbG2         RelocType = RelocType(77)
+	RelocARM_LdrsSbG0        RelocType = RelocType(78)
+	RelocARM_LdrsSbG1        RelocType = RelocType(79)
+	RelocARM_LdrsSbG2        RelocType = RelocType(80)
+	RelocARM_LdcSbG0         RelocType = RelocType(81)
+	RelocARM_LdcSbG1         RelocType = RelocType(82)
+	RelocARM_LdcSbG2         RelocType = RelocType(83)
+	RelocARM_MovwBrelNc      RelocType = RelocType(84)
+	RelocARM_MovtBrel        RelocType = RelocType(85)
+	RelocARM_MovwBrel        RelocType = RelocType(86)
+	RelocARM_ThmMovwBrelNc   RelocType = RelocType(87)
+	RelocARM_ThmMovtBrel     RelocType = RelocType(88)
+	RelocARM_ThmMovwBrel     RelocType = RelocType(89)
+	RelocARM_TlsGotdesc      RelocType = RelocType(90)
+	RelocARM_TlsCall         RelocType = RelocType(91)
+	RelocARM_TlsDescseq      RelocType = RelocType(92)
+	RelocARM_ThmTlsCall      RelocType = RelocType(93)
+	RelocARM_Plt32Abs        RelocType = RelocType(94)
+	RelocARM_GotAbs          RelocType = RelocType(95)
+	RelocARM_GotPrel         RelocType = RelocType(96)
+	RelocARM_GotBrel12       RelocType = RelocType(97)
+	RelocARM_Gotoff12        RelocType = RelocType(98)
+	RelocARM_Gotrelax        RelocType = RelocType(99)
+	RelocARM_GnuVtentry      RelocType = RelocType(100)
+	RelocARM_GnuVtinherit    RelocType = RelocType(101)
+	RelocARM_ThmPc11         RelocType = RelocType(102)
+	RelocARM_ThmPc9          RelocType = RelocType(103)
+	RelocARM_TlsGd32         RelocType = RelocType(104)
+	RelocARM_TlsLdm32        RelocType = RelocType(105)
+	RelocARM_TlsLdo32        RelocType = RelocType(106)
+	RelocARM_TlsIe32         RelocType = RelocType(107)
+	RelocARM_TlsLe32         RelocType = RelocType(108)
+	RelocARM_TlsLdo12        RelocType = RelocType(109)
+	RelocARM_TlsLe12         RelocType = RelocType(110)
+	RelocARM_TlsIe12gp       RelocType = RelocType(111)
+	RelocARM_MeToo           RelocType = RelocType(128)
+	RelocARM_ThmTlsDescseq   RelocType = RelocType(129)
+	RelocARM_ThmTlsDescseq16 RelocType = RelocType(129)
+	RelocARM_ThmTlsDescseq32 RelocType = RelocType(130)
+	RelocARM_ThmGotBrel12    RelocType = RelocType(131)
+	RelocARM_Irelative       RelocType = RelocType(160)
+	RelocARM_Rxpc25          RelocType = RelocType(249)
+	RelocARM_Rsbrel32        RelocType = RelocType(250)
+	RelocARM_ThmRpc22        RelocType = RelocType(251)
+	RelocARM_Rrel32          RelocType = RelocType(252)
+	RelocARM_Rabs22          RelocType = RelocType(253)
+	RelocARM_Rpc24           RelocType = RelocType(254)
+	RelocARM_Rbase           RelocType = RelocType(255)
+)
+
+var RelocARMStr = map[RelocType]string{
+	RelocARM_None:            "R_ARM_NONE",
+	RelocARM_Pc24:            "R_ARM_PC24",
+	RelocARM_Abs32:           "R_ARM_ABS32",
+	RelocARM_Rel32:           "R_ARM_REL32",
+	RelocARM_Pc13:            "R_ARM_PC13",
+	RelocARM_Abs16:           "R_ARM_ABS16",
+	RelocARM_Abs12:           "R_ARM_ABS12",
+	RelocARM_ThmAbs5:         "R_ARM_THM_ABS5",
+	RelocARM_Abs8:            "R_ARM_ABS8",
+	RelocARM_Sbrel32:         "R_ARM_SBREL32",
+	RelocARM_ThmPc22:         "R_ARM_THM_PC22",
+	RelocARM_ThmPc8:          "R_ARM_THM_PC8",
+	RelocARM_AmpVcall9:       "R_ARM_AMP_VCALL9",
+	RelocARM_TlsDesc:         "R_ARM_TLS_DESC",
+	RelocARM_ThmSwi8:         "R_ARM_THM_SWI8",
+	RelocARM_Xpc25:           "R_ARM_XPC25",
+	RelocARM_ThmXpc22:        "R_ARM_THM_XPC22",
+	RelocARM_TlsDtpmod32:     "R_ARM_TLS_DTPMOD32",
+	RelocARM_TlsDtpoff32:     "R_ARM_TLS_DTPOFF32",
+	RelocARM_TlsTpoff32:      "R_ARM_TLS_TPOFF32",
+	RelocARM_Copy:            "R_ARM_COPY",
+	RelocARM_GlobDat:         "R_ARM_GLOB_DAT",
+	RelocARM_JumpSlot:        "R_ARM_JUMP_SLOT",
+	RelocARM_Relative:        "R_ARM_RELATIVE",
+	RelocARM_Gotoff:          "R_ARM_GOTOFF",
+	RelocARM_Gotpc:           "R_ARM_GOTPC",
+	RelocARM_Got32:           "R_ARM_GOT32",
+	RelocARM_Plt32:           "R_ARM_PLT32",
+	RelocARM_Call:            "R_ARM_CALL",
+	RelocARM_Jump24:          "R_ARM_JUMP24",
+	RelocARM_ThmJump24:       "R_ARM_THM_JUMP24",
+	RelocARM_BaseAbs:         "R_ARM_BASE_ABS",
+	RelocARM_AluPcrel70:      "R_ARM_ALU_PCREL_7_0",
+	RelocARM_AluPcrel158:     "R_ARM_ALU_PCREL_15_8",
+	RelocARM_AluPcrel2315:    "R_ARM_ALU_PCREL_23_15",
+	RelocARM_LdrSbrel110:     "R_ARM_LDR_SBREL_11_0",
+	RelocARM_AluSbrel1912:    "R_ARM_ALU_SBREL_19_12",
+	RelocARM_AluSbrel2720:    "R_ARM_ALU_SBREL_27_20",
+	RelocARM_Target1:         "R_ARM_TARGET1",
+	RelocARM_Sbrel31:         "R_ARM_SBREL31",
+	RelocARM_V4bx:            "R_ARM_V4BX",
+	RelocARM_Target2:         "R_ARM_TARGET2",
+	RelocARM_Prel31:          "R_ARM_PREL31",
+	RelocARM_MovwAbsNc:       "R_ARM_MOVW_ABS_NC",
+	RelocARM_MovtAbs:         "R_ARM_MOVT_ABS",
+	RelocARM_MovwPrelNc:      "R_ARM_MOVW_PREL_NC",
+	RelocARM_MovtPrel:        "R_ARM_MOVT_PREL",
+	RelocARM_ThmMovwAbsNc:    "R_ARM_THM_MOVW_ABS_NC",
+	RelocARM_ThmMovtAbs:      "R_ARM_THM_MOVT_ABS",
+	RelocARM_ThmMovwPrelNc:   "R_ARM_THM_MOVW_PREL_NC",
+	RelocARM_ThmMovtPrel:     "R_ARM_THM_MOVT_PREL",
+	RelocARM_ThmJump19:       "R_ARM_THM_JUMP19",
+	RelocARM_ThmJump6:        "R_ARM_THM_JUMP6",
+	RelocARM_ThmAluPrel110:   "R_ARM_THM_ALU_PREL_11_0",
+	RelocARM_ThmPc12:         "R_ARM_THM_PC12",
+	RelocARM_Abs32Noi:        "R_ARM_ABS32_NOI",
+	RelocARM_Rel32Noi:        "R_ARM_REL32_NOI",
+	RelocARM_AluPcG0Nc:       "R_ARM_ALU_PC_G0_NC",
+	RelocARM_AluPcG0:         "R_ARM_ALU_PC_G0",
+	RelocARM_AluPcG1Nc:       "R_ARM_ALU_PC_G1_NC",
+	RelocARM_AluPcG1:         "R_ARM_ALU_PC_G1",
+	RelocARM_AluPcG2:         "R_ARM_ALU_PC_G2",
+	RelocARM_LdrPcG1:         "R_ARM_LDR_PC_G1",
+	RelocARM_LdrPcG2:         "R_ARM_LDR_PC_G2",
+	RelocARM_LdrsPcG0:        "R_ARM_LDRS_PC_G0",
+	RelocARM_LdrsPcG1:        "R_ARM_LDRS_PC_G1",
+	RelocARM_LdrsPcG2:        "R_ARM_LDRS_PC_G2",
+	RelocARM_LdcPcG0:         "R_ARM_LDC_PC_G0",
+	RelocARM_LdcPcG1:         "R_ARM_LDC_PC_G1",
+	RelocARM_LdcPcG2:         "R_ARM_LDC_PC_G2",
+	RelocARM_AluSbG0Nc:       "R_ARM_ALU_SB_G0_NC",
+	RelocARM_AluSbG0:         "R_ARM_ALU_SB_G0",
+	RelocARM_AluSbG1Nc:       "R_ARM_ALU_SB_G1_NC",
+	RelocARM_AluSbG1:         "R_ARM_ALU_SB_G1",
+	RelocARM_AluSbG2:         "R_ARM_ALU_SB_G2",
+	RelocARM_LdrSbG0:         "R_ARM_LDR_SB_G0",
+	RelocARM_LdrSbG1:         "R_ARM_LDR_SB_G1",
+	RelocARM_LdrSbG2:         "R_ARM_LDR_SB_G2",
+	RelocARM_LdrsSbG0:        "R_ARM_LDRS_SB_G0",
+	RelocARM_LdrsSbG1:        "R_ARM_LDRS_SB_G1",
+	RelocARM_LdrsSbG2:        "R_ARM_LDRS_SB_G2",
+	RelocARM_LdcSbG0:         "R_ARM_LDC_SB_G0",
+	RelocARM_LdcSbG1:         "R_ARM_LDC_SB_G1",
+	RelocARM_LdcSbG2:         "R_ARM_LDC_SB_G2",
+	RelocARM_MovwBrelNc:      "R_ARM_MOVW_BREL_NC",
+	RelocARM_MovtBrel:        "R_ARM_MOVT_BREL",
+	RelocARM_MovwBrel:        "R_ARM_MOVW_BREL",
+	RelocARM_ThmMovwBrelNc:   "R_ARM_THM_MOVW_BREL_NC",
+	RelocARM_ThmMovtBrel:     "R_ARM_THM_MOVT_BREL",
+	RelocARM_ThmMovwBrel:     "R_ARM_THM_MOVW_BREL",
+	RelocARM_TlsGotdesc:      "R_ARM_TLS_GOTDESC",
+	RelocARM_TlsCall:         "R_ARM_TLS_CALL",
+	RelocARM_TlsDescseq:      "R_ARM_TLS_DESCSEQ",
+	RelocARM_ThmTlsCall:      "R_ARM_THM_TLS_CALL",
+	RelocARM_Plt32Abs:        "R_ARM_PLT32_ABS",
+	RelocARM_GotAbs:          "R_ARM_GOT_ABS",
+	RelocARM_GotPrel:         "R_ARM_GOT_PREL",
+	RelocARM_GotBrel12:       "R_ARM_GOT_BREL12",
+	RelocARM_Gotoff12:        "R_ARM_GOTOFF12",
+	RelocARM_Gotrelax:        "R_ARM_GOTRELAX",
+	RelocARM_GnuVtentry:      "R_ARM_GNU_VTENTRY",
+	RelocARM_GnuVtinherit:    "R_ARM_GNU_VTINHERIT",
+	RelocARM_ThmPc11:         "R_ARM_THM_PC11",
+	RelocARM_ThmPc9:          "R_ARM_THM_PC9",
+	RelocARM_TlsGd32:         "R_ARM_TLS_GD32",
+	RelocARM_TlsLdm32:        "R_ARM_TLS_LDM32",
+	RelocARM_TlsLdo32:        "R_ARM_TLS_LDO32",
+	RelocARM_TlsIe32:         "R_ARM_TLS_IE32",
+	RelocARM_TlsLe32:         "R_ARM_TLS_LE32",
+	RelocARM_TlsLdo12:        "R_ARM_TLS_LDO12",
+	RelocARM_TlsLe12:         "R_ARM_TLS_LE12",
+	RelocARM_TlsIe12gp:       "R_ARM_TLS_IE12GP",
+	RelocARM_MeToo:           "R_ARM_ME_TOO",
+	RelocARM_ThmTlsDescseq16: "R_ARM_THM_TLS_DESCSEQ16",
+	RelocARM_ThmTlsDescseq32: "R_ARM_THM_TLS_DESCSEQ32",
+	RelocARM_ThmGotBrel12:    "R_ARM_THM_GOT_BREL12",
+	RelocARM_Irelative:       "R_ARM_IRELATIVE",
+	RelocARM_Rxpc25:          "R_ARM_RXPC25",
+	RelocARM_Rsbrel32:        "R_ARM_RSBREL32",
+	RelocARM_ThmRpc22:        "R_ARM_THM_RPC22",
+	RelocARM_Rrel32:          "R_ARM_RREL32",
+	RelocARM_Rabs22:          "R_ARM_RABS22",
+	RelocARM_Rpc24:           "R_ARM_RPC24",
+	RelocARM_Rbase:           "R_ARM_RBASE",
+}
+
+// Relocation types for AArch64.
+const (
+	RelocAArch64_None                     RelocType = RelocType(0)
+	RelocAArch64_Abs64                    RelocType = RelocType(257)
+	RelocAArch64_Abs32                    RelocType = RelocType(258)
+	RelocAArch64_Abs16                    RelocType = RelocType(259)
+	RelocAArch64_Prel64                   RelocType = RelocType(260)
+	RelocAArch64_Prel32                   RelocType = RelocType(261)
+	RelocAArch64_Prel16                   RelocType = RelocType(262)
+	RelocAArch64_MovwUabsG0               RelocType = RelocType(263)
+	RelocAArch64_MovwUabsG0Nc             RelocType = RelocType(264)
+	RelocAArch64_MovwUabsG1               RelocType = RelocType(265)
+	RelocAArch64_MovwUabsG1Nc             RelocType = RelocType(266)
+	RelocAArch64_MovwUabsG2               RelocType = RelocType(267)
+	RelocAArch64_MovwUabsG2Nc             RelocType = RelocType(268)
+	RelocAArch64_MovwUabsG3               RelocType = RelocType(269)
+	RelocAArch64_MovwSabsG0               RelocType = RelocType(270)
+	RelocAArch64_MovwSabsG1               RelocType = RelocType(271)
+	RelocAArch64_MovwSabsG2               RelocType = RelocType(272)
+	RelocAArch64_LdPrelLo19               RelocType = RelocType(273)
+	RelocAArch64_AdrPrelLo21              RelocType = RelocType(274)
+	RelocAArch64_AdrPrelPgHi21            RelocType = RelocType(275)
+	RelocAArch64_AdrPrelPgHi21Nc          RelocType = RelocType(276)
+	RelocAArch64_AddAbsLo12Nc             RelocType = RelocType(277)
+	RelocAArch64_Ldst8AbsLo12Nc           RelocType = RelocType(278)
+	RelocAArch64_Tstbr14                  RelocType = RelocType(279)
+	RelocAArch64_Condbr19                 RelocType = RelocType(280)
+	RelocAArch64_Jump26                   RelocType = RelocType(282)
+	RelocAArch64_Call26                   RelocType = RelocType(283)
+	RelocAArch64_Ldst16AbsLo12Nc          RelocType = RelocType(284)
+	RelocAArch64_Ldst32AbsLo12Nc          RelocType = RelocType(285)
+	RelocAArch64_Ldst64AbsLo12Nc          RelocType = RelocType(286)
+	RelocAArch64_MovwPrelG0               RelocType = RelocType(287)
+	RelocAArch64_MovwPrelG0Nc             RelocType = RelocType(288)
+	RelocAArch64_MovwPrelG1               RelocType = RelocType(289)
+	RelocAArch64_MovwPrelG1Nc             RelocType = RelocType(290)
+	RelocAArch64_MovwPrelG2               RelocType = RelocType(291)
+	RelocAArch64_MovwPrelG2Nc             RelocType = RelocType(292)
+	RelocAArch64_MovwPrelG3               RelocType = RelocType(293)
+	RelocAArch64_Ldst128AbsLo12Nc         RelocType = RelocType(299)
+	RelocAArch64_MovwGotoffG0             RelocType = RelocType(300)
+	RelocAArch64_MovwGotoffG0Nc           RelocType = RelocType(301)
+	RelocAArch64_MovwGotoffG1             RelocType = RelocType(302)
+	RelocAArch64_MovwGotoffG1Nc           RelocType = RelocType(303)
+	RelocAArch64_MovwGotoffG2             RelocType = RelocType(304)
+	RelocAArch64_MovwGotoffG2Nc           RelocType = RelocType(305)
+	RelocAArch64_MovwGotoffG3             RelocType = RelocType(306)
+	RelocAArch64_Gotrel64                 RelocType = RelocType(307)
+	RelocAArch64_Gotrel32                 RelocType = RelocType(308)
+	RelocAArch64_GotLdPrel19              RelocType = RelocType(309)
+	RelocAArch64_Ld64GotoffLo15           RelocType = RelocType(310)
+	RelocAArch64_AdrGotPage               RelocType = RelocType(311)
+	RelocAArch64_Ld64GotLo12Nc            RelocType = RelocType(312)
+	RelocAArch64_Ld64GotpageLo15          RelocType = RelocType(313)
+	RelocAArch64_TlsgdAdrPrel21           RelocType = RelocType(512)
+	RelocAArch64_TlsgdAdrPage21           RelocType = RelocType(513)
+	RelocAArch64_TlsgdAddLo12Nc           RelocType = RelocType(514)
+	RelocAArch64_TlsgdMovwG1              RelocType = RelocType(515)
+	RelocAArch64_TlsgdMovwG0Nc            RelocType = RelocType(516)
+	RelocAArch64_TlsldAdrPrel21           RelocType = RelocType(517)
+	RelocAArch64_TlsldAdrPage21           RelocType = RelocType(518)
+	RelocAArch64_TlsldAddLo12Nc           RelocType = RelocType(519)
+	RelocAArch64_TlsldMovwG1              RelocType = RelocType(520)
+	RelocAArch64_TlsldMovwG0Nc            RelocType = RelocType(521)
+	RelocAArch64_TlsldLdPrel19            RelocType = RelocType(522)
+	RelocAArch64_TlsldMovwDtprelG2        RelocType = RelocType(523)
+	RelocAArch64_TlsldMovwDtprelG1        RelocType = RelocType(524)
+	RelocAArch64_TlsldMovwDtprelG1Nc      RelocType = RelocType(525)
+	RelocAArch64_TlsldMovwDtprelG0        RelocType = RelocType(526)
+	RelocAArch64_TlsldMovwDtprelG0Nc      RelocType = RelocType(527)
+	RelocAArch64_TlsldAddDtprelHi12       RelocType = RelocType(528)
+	RelocAArch64_TlsldAddDtprelLo12       RelocType = RelocType(529)
+	RelocAArch64_TlsldAddDtprelLo12Nc     RelocType = RelocType(530)
+	RelocAArch64_TlsldLdst8DtprelLo12     RelocType = RelocType(531)
+	RelocAArch64_TlsldLdst8DtprelLo12Nc   RelocType = RelocType(532)
+	RelocAArch64_TlsldLdst16DtprelLo12    RelocType = RelocType(533)
+	RelocAArch64_TlsldLdst16DtprelLo12Nc  RelocType = RelocType(534)
+	RelocAArch64_TlsldLdst32DtprelLo12    RelocType = RelocType(535)
+	RelocAArch64_TlsldLdst32DtprelLo12Nc  RelocType = RelocType(536)
+	RelocAArch64_TlsldLdst64DtprelLo12    RelocType = RelocType(537)
+	RelocAArch64_TlsldLdst64DtprelLo12Nc  RelocType = RelocType(538)
+	RelocAArch64_TlsieMovwGottprelG1      RelocType = RelocType(539)
+	RelocAArch64_TlsieMovwGottprelG0Nc    RelocType = RelocType(540)
+	RelocAArch64_TlsieAdrGottprelPage21   RelocType = RelocType(541)
+	RelocAArch64_TlsieLd64GottprelLo12Nc  RelocType = RelocType(542)
+	RelocAArch64_TlsieLdGottprelPrel19    RelocType = RelocType(543)
+	RelocAArch64_TlsleMovwTprelG2         RelocType = RelocType(544)
+	RelocAArch64_TlsleMovwTprelG1         RelocType = RelocType(545)
+	RelocAArch64_TlsleMovwTprelG1Nc       RelocType = RelocType(546)
+	RelocAArch64_TlsleMovwTprelG0         RelocType = RelocType(547)
+	RelocAArch64_TlsleMovwTprelG0Nc       RelocType = RelocType(548)
+	RelocAArch64_TlsleAddTprelHi12        RelocType = RelocType(549)
+	RelocAArch64_TlsleAddTprelLo12        RelocType = RelocType(550)
+	RelocAArch64_TlsleAddTprelLo12Nc      RelocType = RelocType(551)
+	RelocAArch64_TlsleLdst8TprelLo12      RelocType = RelocType(552)
+	RelocAArch64_TlsleLdst8TprelLo12Nc    RelocType = RelocType(553)
+	RelocAArch64_TlsleLdst16TprelLo12     RelocType = RelocType(554)
+	RelocAArch64_TlsleLdst16TprelLo12Nc   RelocType = RelocType(555)
+	RelocAArch64_TlsleLdst32TprelLo12     RelocType = RelocType(556)
+	RelocAArch64_TlsleLdst32TprelLo12Nc   RelocType = RelocType(557)
+	RelocAArch64_TlsleLdst64TprelLo12     RelocType = RelocType(558)
+	RelocAArch64_TlsleLdst64TprelLo12Nc   RelocType = RelocType(559)
+	RelocAArch64_TlsdescLdPrel19          RelocType = RelocType(560)
+	RelocAArch64_TlsdescAdrPrel21         RelocType = RelocType(561)
+	RelocAArch64_TlsdescAdrPage21         RelocType = RelocType(562)
+	RelocAArch64_TlsdescLd64Lo12          RelocType = RelocType(563)
+	RelocAArch64_TlsdescAddLo12           RelocType = RelocType(564)
+	RelocAArch64_TlsdescOffG1             RelocType = RelocType(565)
+	RelocAArch64_TlsdescOffG0Nc           RelocType = RelocType(566)
+	RelocAArch64_TlsdescLdr               RelocType = RelocType(567)
+	RelocAArch64_TlsdescAdd               RelocType = RelocType(568)
+	RelocAArch64_TlsdescCall              RelocType = RelocType(569)
+	RelocAArch64_TlsleLdst128TprelLo12    RelocType = RelocType(570)
+	RelocAArch64_TlsleLdst128TprelLo12Nc  RelocType = RelocType(571)
+	RelocAArch64_TlsldLdst128DtprelLo12   RelocType = RelocType(572)
+	RelocAArch64_TlsldLdst128DtprelLo12Nc RelocType = RelocType(573)
+	RelocAArch64_Copy                     RelocType = RelocType(1024)
+	RelocAArch64_GlobDat                  RelocType = RelocType(1025)
+	RelocAArch64_JumpSlot                 RelocType = RelocType(1026)
+	RelocAArch64_Relative                 RelocType = RelocType(1027)
+	RelocAArch64_TlsDtpmod                RelocType = RelocType(1028)
+	RelocAArch64_TlsDtprel                RelocType = RelocType(1029)
+	RelocAArch64_TlsTprel                 RelocType = RelocType(1030)
+	RelocAArch64_Tlsdesc                  RelocType = RelocType(1031)
+	RelocAArch64_Irelative                RelocType = RelocType(1032)
+)
+
+var RelocAArch64Str = map[RelocType]string{
+	RelocAArch64_None:                     "R_AARCH64_NONE",
+	RelocAArch64_Abs64:                    "R_AARCH64_ABS64",
+	RelocAArch64_Abs32:                    "R_AARCH64_ABS32",
+	RelocAArch64_Abs16:                    "R_AARCH64_ABS16",
+	RelocAArch64_Prel64:                   "R_AARCH64_PREL64",
+	RelocAArch64_Prel32:                   "R_AARCH64_PREL32",
+	RelocAArch64_Prel16:                   "R_AARCH64_PREL16",
+	RelocAArch64_MovwUabsG0:               "R_AARCH64_MOVW_UABS_G0",
+	RelocAArch64_MovwUabsG0Nc:             "R_AARCH64_MOVW_UABS_G0_NC",
+	RelocAArch64_MovwUabsG1:               "R_AARCH64_MOVW_UABS_G1",
+	RelocAArch64_MovwUabsG1Nc:             "R_AARCH64_MOVW_UABS_G1_NC",
+	RelocAArch64_MovwUabsG2:               "R_AARCH64_MOVW_UABS_G2",
+	RelocAArch64_MovwUabsG2Nc:             "R_AARCH64_MOVW_UABS_G2_NC",
+	RelocAArch64_MovwUabsG3:               "R_AARCH64_MOVW_UABS_G3",
+	RelocAArch64_MovwSabsG0:               "R_AARCH64_MOVW_SABS_G0",
+	RelocAArch64_MovwSabsG1:               "R_AARCH64_MOVW_SABS_G1",
+	RelocAArch64_MovwSabsG2:               "R_AARCH64_MOVW_SABS_G2",
+	RelocAArch64_LdPrelLo19:               "R_AARCH64_LD_PREL_LO19",
+	RelocAArch64_AdrPrelLo21:              "R_AARCH64_ADR_PREL_LO21",
+	RelocAArch64_AdrPrelPgHi21:            "R_AARCH64_ADR_PREL_PG_HI21",
+	RelocAArch64_AdrPrelPgHi21Nc:          "R_AARCH64_ADR_PREL_PG_HI21_NC",
+	RelocAArch64_AddAbsLo12Nc:             "R_AARCH64_ADD_ABS_LO12_NC",
+	RelocAArch64_Ldst8AbsLo12Nc:           "R_AARCH64_LDST8_ABS_LO12_NC",
+	RelocAArch64_Tstbr14:                  "R_AARCH64_TSTBR14",
+	RelocAArch64_Condbr19:                 "R_AARCH64_CONDBR19",
+	RelocAArch64_Jump26:                   "R_AARCH64_JUMP26",
+	RelocAArch64_Call26:                   "R_AARCH64_CALL26",
+	RelocAArch64_Ldst16AbsLo12Nc:          "R_AARCH64_LDST16_ABS_LO12_NC",
+	RelocAArch64_Ldst32AbsLo12Nc:          "R_AARCH64_LDST32_ABS_LO12_NC",
+	RelocAArch64_Ldst64AbsLo12Nc:          "R_AARCH64_LDST64_ABS_LO12_NC",
+	RelocAArch64_MovwPrelG0:               "R_AARCH64_MOVW_PREL_G0",
+	RelocAArch64_MovwPrelG0Nc:             "R_AARCH64_MOVW_PREL_G0_NC",
+	RelocAArch64_MovwPrelG1:               "R_AARCH64_MOVW_PREL_G1",
+	RelocAArch64_MovwPrelG1Nc:             "R_AARCH64_MOVW_PREL_G1_NC",
+	RelocAArch64_MovwPrelG2:               "R_AARCH64_MOVW_PREL_G2",
+	RelocAArch64_MovwPrelG2Nc:             "R_AARCH64_MOVW_PREL_G2_NC",
+	RelocAArch64_MovwPrelG3:               "R_AARCH64_MOVW_PREL_G3",
+	RelocAArch64_Ldst128AbsLo12Nc:         "R_AARCH64_LDST128_ABS_LO12_NC",
+	RelocAArch64_MovwGotoffG0:             "R_AARCH64_MOVW_GOTOFF_G0",
+	RelocAArch64_MovwGotoffG0Nc:           "R_AARCH64_MOVW_GOTOFF_G0_NC",
+	RelocAArch64_MovwGotoffG1:             "R_AARCH64_MOVW_GOTOFF_G1",
+	RelocAArch64_MovwGotoffG1Nc:           "R_AARCH64_MOVW_GOTOFF_G1_NC",
+	RelocAArch64_MovwGotoffG2:             "R_AARCH64_MOVW_GOTOFF_G2",
+	RelocAArch64_MovwGotoffG2Nc:           "R_AARCH64_MOVW_GOTOFF_G2_NC",
+	RelocAArch64_MovwGotoffG3:             "R_AARCH64_MOVW_GOTOFF_G3",
+	RelocAArch64_Gotrel64:                 "R_AARCH64_GOTREL64",
+	RelocAArch64_Gotrel32:                 "R_AARCH64_GOTREL32",
+	RelocAArch64_GotLdPrel19:              "R_AARCH64_GOT_LD_PREL19",
+	RelocAArch64_Ld64GotoffLo15:           "R_AARCH64_LD64_GOTOFF_LO15",
+	RelocAArch64_AdrGotPage:               "R_AARCH64_ADR_GOT_PAGE",
+	RelocAArch64_Ld64GotLo12Nc:            "R_AARCH64_LD64_GOT_LO12_NC",
+	RelocAArch64_Ld64GotpageLo15:          "R_AARCH64_LD64_GOTPAGE_LO15",
+	RelocAArch64_TlsgdAdrPrel21:           "R_AARCH64_TLSGD_ADR_PREL21",
+	RelocAArch64_TlsgdAdrPage21:           "R_AARCH64_TLSGD_ADR_PAGE21",
+	RelocAArch64_TlsgdAddLo12Nc:           "R_AARCH64_TLSGD_ADD_LO12_NC",
+	RelocAArch64_TlsgdMovwG1:              "R_AARCH64_TLSGD_MOVW_G1",
+	RelocAArch64_TlsgdMovwG0Nc:            "R_AARCH64_TLSGD_MOVW_G0_NC",
+	RelocAArch64_TlsldAdrPrel21:           "R_AARCH64_TLSLD_ADR_PREL21",
+	RelocAArch64_TlsldAdrPage21:           "R_AARCH64_TLSLD_ADR_PAGE21",
+	RelocAArch64_TlsldAddLo12Nc:           "R_AARCH64_TLSLD_ADD_LO12_NC",
+	RelocAArch64_TlsldMovwG1:              "R_AARCH64_TLSLD_MOVW_G1",
+	RelocAArch64_TlsldMovwG0Nc:            "R_AARCH64_TLSLD_MOVW_G0_NC",
+	RelocAArch64_TlsldLdPrel19:            "R_AARCH64_TLSLD_LD_PREL19",
+	RelocAArch64_TlsldMovwDtprelG2:        "R_AARCH64_TLSLD_MOVW_DTPREL_G2",
+	RelocAArch64_TlsldMovwDtprelG1:        "R_AARCH64_TLSLD_MOVW_DTPREL_G1",
+	RelocAArch64_TlsldMovwDtprelG1Nc:      "R_AARCH64_TLSLD_MOVW_DTPREL_G1_NC",
+	RelocAArch64_TlsldMovwDtprelG0:        "R_AARCH64_TLSLD_MOVW_DTPREL_G0",
+	RelocAArch64_TlsldMovwDtprelG0Nc:      "R_AARCH64_TLSLD_MOVW_DTPREL_G0_NC",
+	RelocAArch64_TlsldAddDtprelHi12:       "R_AARCH64_TLSLD_ADD_DTPREL_HI12",
+	RelocAArch64_TlsldAddDtprelLo12:       "R_AARCH64_TLSLD_ADD_DTPREL_LO12",
+	RelocAArch64_TlsldAddDtprelLo12Nc:     "R_AARCH64_TLSLD_ADD_DTPREL_LO12_NC",
+	RelocAArch64_TlsldLdst8DtprelLo12:     "R_AARCH64_TLSLD_LDST8_DTPREL_LO12",
+	RelocAArch64_TlsldLdst8DtprelLo12Nc:   "R_AARCH64_TLSLD_LDST8_DTPREL_LO12_NC",
+	RelocAArch64_TlsldLdst16DtprelLo12:    "R_AARCH64_TLSLD_LDST16_DTPREL_LO12",
+	RelocAArch64_TlsldLdst16DtprelLo12Nc:  "R_AARCH64_TLSLD_LDST16_DTPREL_LO12_NC",
+	RelocAArch64_TlsldLdst32DtprelLo12:    "R_AARCH64_TLSLD_LDST32_DTPREL_LO12",
+	RelocAArch64_TlsldLdst32DtprelLo12Nc:  "R_AARCH64_TLSLD_LDST32_DTPREL_LO12_NC",
+	RelocAArch64_TlsldLdst64DtprelLo12:    "R_AARCH64_TLSLD_LDST64_DTPREL_LO12",
+	RelocAArch64_TlsldLdst64DtprelLo12Nc:  "R_AARCH64_TLSLD_LDST64_DTPREL_LO12_NC",
+	RelocAArch64_TlsieMovwGottprelG1:      "R_AARCH64_TLSIE_MOVW_GOTTPREL_G1",
+	RelocAArch64_TlsieMovwGottprelG0Nc:    "R_AARCH64_TLSIE_MOVW_GOTTPREL_G0_NC",
+	RelocAArch64_TlsieAdrGottprelPage21:   "R_AARCH64_TLSIE_ADR_GOTTPREL_PAGE21",
+	RelocAArch64_TlsieLd64GottprelLo12Nc:  "R_AARCH64_TLSIE_LD64_GOTTPREL_LO12_NC",
+	RelocAArch64_TlsieLdGottprelPrel19:    "R_AARCH64_TLSIE_LD_GOTTPREL_PREL19",
+	RelocAArch64_TlsleMovwTprelG2:         "R_AARCH64_TLSLE_MOVW_TPREL_G2",
+	RelocAArch64_TlsleMovwTprelG1:         "R_AARCH64_TLSLE_MOVW_TPREL_G1",
+	RelocAArch64_TlsleMovwTprelG1Nc:       "R_AARCH64_TLSLE_MOVW_TPREL_G1_NC",
+	RelocAArch64_TlsleMovwTprelG0:         "R_AARCH64_TLSLE_MOVW_TPREL_G0",
+	RelocAArch64_TlsleMovwTprelG0Nc:       "R_AARCH64_TLSLE_MOVW_TPREL_G0_NC",
+	RelocAArch64_TlsleAddTprelHi12:        "R_AARCH64_TLSLE_ADD_TPREL_HI12",
+	RelocAArch64_TlsleAddTprelLo12:        "R_AARCH64_TLSLE_ADD_TPREL_LO12",
+	RelocAArch64_TlsleAddTprelLo12Nc:      "R_AARCH64_TLSLE_ADD_TPREL_LO12_NC",
+	RelocAArch64_TlsleLdst8TprelLo12:      "R_AARCH64_TLSLE_LDST8_TPREL_LO12",
+	RelocAArch64_TlsleLdst8TprelLo12Nc:    "R_AARCH64_TLSLE_LDST8_TPREL_LO12_NC",
+	RelocAArch64_TlsleLdst16TprelLo12:     "R_AARCH64_TLSLE_LDST16_TPREL_LO12",
+	RelocAArch64_TlsleLdst16TprelLo12Nc:   "R_AARCH64_TLSLE_LDST16_TPREL_LO12_NC",
+	RelocAArch64_TlsleLdst32TprelLo12:     "R_AARCH64_TLSLE_LDST32_TPREL_LO12",
+	RelocAArch64_TlsleLdst32TprelLo12Nc:   "R_AARCH64_TLSLE_LDST32_TPREL_LO12_NC",
+	RelocAArch64_TlsleLdst64TprelLo12:     "R_AARCH64_TLSLE_LDST64_TPREL_LO12",
+	RelocAArch64_TlsleLdst64TprelLo12Nc:   "R_AARCH64_TLSLE_LDST64_TPREL_LO12_NC",
+	RelocAArch64_TlsdescLdPrel19:          "R_AARCH64_TLSDESC_LD_PREL19",
+	RelocAArch64_TlsdescAdrPrel21:         "R_AARCH64_TLSDESC_ADR_PREL21",
+	RelocAArch64_TlsdescAdrPage21:         "R_AARCH64_TLSDESC_ADR_PAGE21",
+	RelocAArch64_TlsdescLd64Lo12:          "R_AARCH64_TLSDESC_LD64_LO12",
+	RelocAArch64_TlsdescAddLo12:           "R_AARCH64_TLSDESC_ADD_LO12",
+	RelocAArch64_TlsdescOffG1:             "R_AARCH64_TLSDESC_OFF_G1",
+	RelocAArch64_TlsdescOffG0Nc:           "R_AARCH64_TLSDESC_OFF_G0_NC",
+	RelocAArch64_TlsdescLdr:               "R_AARCH64_TLSDESC_LDR",
+	RelocAArch64_TlsdescAdd:               "R_AARCH64_TLSDESC_ADD",
+	RelocAArch64_TlsdescCall:              "R_AARCH64_TLSDESC_CALL",
+	RelocAArch64_TlsleLdst128TprelLo12:    "R_AARCH64_TLSLE_LDST128_TPREL_LO12",
+	RelocAArch64_TlsleLdst128TprelLo12Nc:  "R_AARCH64_TLSLE_LDST128_TPREL_LO12_NC",
+	RelocAArch64_TlsldLdst128DtprelLo12:   "R_AARCH64_TLSLD_LDST128_DTPREL_LO12",
+	RelocAArch64_TlsldLdst128DtprelLo12Nc: "R_AARCH64_TLSLD_LDST128_DTPREL_LO12_NC",
+	RelocAArch64_Copy:                     "R_AARCH64_COPY",
+	RelocAArch64_GlobDat:                  "R_AARCH64_GLOB_DAT",
+	RelocAArch64_JumpSlot:                 "R_AARCH64_JUMP_SLOT",
+	RelocAArch64_Relative:                 "R_AARCH64_RELATIVE",
+	RelocAArch64_TlsDtpmod:                "R_AARCH64_TLS_DTPMOD",
+	RelocAArch64_TlsDtprel:                "R_AARCH64_TLS_DTPREL",
+	RelocAArch64_TlsTprel:                 "R_AARCH64_TLS_TPREL",
+	RelocAArch64_Tlsdesc:                  "R_AARCH64_TLSDESC",
+	RelocAArch64_Irelative:                "R_AARCH64_IRELATIVE",
+}
+
+// Relocation types for RISC-V.
+const (
+	RelocRISCV_None        RelocType = RelocType(0)
+	RelocRISCV_32          RelocType = RelocType(1)
+	RelocRISCV_64          RelocType = RelocType(2)
+	RelocRISCV_Relative    RelocType = RelocType(3)
+	RelocRISCV_Copy        RelocType = RelocType(4)
+	RelocRISCV_JumpSlot    RelocType = RelocType(5)
+	RelocRISCV_TlsDtpmod32 RelocType = RelocType(6)
+	RelocRISCV_TlsDtpmod64 RelocType = RelocType(7)
+	RelocRISCV_TlsDtprel32 RelocType = RelocType(8)
+	RelocRISCV_TlsDtprel64 RelocType = RelocType(9)
+	RelocRISCV_TlsTprel32  RelocType = RelocType(10)
+	RelocRISCV_TlsTprel64  RelocType = RelocType(11)
+	RelocRISCV_Branch      RelocType = RelocType(16)
+	RelocRISCV_Jal         RelocType = RelocType(17)
+	RelocRISCV_Call        RelocType = RelocType(18)
+	RelocRISCV_CallPlt     RelocType = RelocType(19)
+	RelocRISCV_GotHi20     RelocType = RelocType(20)
+	RelocRISCV_TlsGotHi20  RelocType = RelocType(21)
+	RelocRISCV_TlsGdHi20   RelocType = RelocType(22)
+	RelocRISCV_PcrelHi20   RelocType = RelocType(23)
+	RelocRISCV_PcrelLo12I  RelocType = RelocType(24)
+	RelocRISCV_PcrelLo12S  RelocType = RelocType(25)
+	RelocRISCV_Hi20        RelocType = RelocType(26)
+	RelocRISCV_Lo12I       RelocType = RelocType(27)
+	RelocRISCV_Lo12S       RelocType = RelocType(28)
+	RelocRISCV_TprelHi20   RelocType = RelocType(29)
+	RelocRISCV_TprelLo12I  RelocType = RelocType(30)
+	RelocRISCV_TprelLo12S  RelocType = RelocType(31)
+	RelocRISCV_TprelAdd    RelocType = RelocType(32)
+	RelocRISCV_Add8        RelocType = RelocType(33)
+	RelocRISCV_Add16       RelocType = RelocType(34)
+	RelocRISCV_Add32       RelocType = RelocType(35)
+	RelocRISCV_Add64       RelocType = RelocType(36)
+	RelocRISCV_Sub8        RelocType = RelocType(37)
+	RelocRISCV_Sub16       RelocType = RelocType(38)
+	RelocRISCV_Sub32       RelocType = RelocType(39)
+	RelocRISCV_Sub64       RelocType = RelocType(40)
+	RelocRISCV_Align       RelocType = RelocType(43)
+	RelocRISCV_RvcBranch   RelocType = RelocType(44)
+	RelocRISCV_RvcJump     RelocType = RelocType(45)
+	RelocRISCV_RvcLui      RelocType = RelocType(46)
+	RelocRISCV_GprelI      RelocType = RelocType(47)
+	RelocRISCV_GprelS      RelocType = RelocType(48)
+	RelocRISCV_TprelI      RelocType = RelocType(49)
+	RelocRISCV_TprelS      RelocType = RelocType(50)
+	RelocRISCV_Relax       RelocType = RelocType(51)
+	RelocRISCV_Sub6        RelocType = RelocType(52)
+	RelocRISCV_Set6        RelocType = RelocType(53)
+	RelocRISCV_Set8        RelocType = RelocType(54)
+	RelocRISCV_Set16       RelocType = RelocType(55)
+	RelocRISCV_Set32       RelocType = RelocType(56)
+	RelocRISCV_32Pcrel     RelocType = RelocType(57)
+	RelocRISCV_Irelative   RelocType = RelocType(58)
+)
+
+var RelocRISCVStr = map[RelocType]string{
+	RelocRISCV_None:        "R_RISCV_NONE",
+	RelocRISCV_32:          "R_RISCV_32",
+	RelocRISCV_64:          "R_RISCV_64",
+	RelocRISCV_Relative:    "R_RISCV_RELATIVE",
+	RelocRISCV_Copy:        "R_RISCV_COPY",
+	RelocRISCV_JumpSlot:    "R_RISCV_JUMP_SLOT",
+	RelocRISCV_TlsDtpmod32: "R_RISCV_TLS_DTPMOD32",
+	RelocRISCV_TlsDtpmod64: "R_RISCV_TLS_DTPMOD64",
+	RelocRISCV_TlsDtprel32: "R_RISCV_TLS_DTPREL32",
+	RelocRISCV_TlsDtprel64: "R_RISCV_TLS_DTPREL64",
+	RelocRISCV_TlsTprel32:  "R_RISCV_TLS_TPREL32",
+	RelocRISCV_TlsTprel64:  "R_RISCV_TLS_TPREL64",
+	RelocRISCV_Branch:      "R_RISCV_BRANCH",
+	RelocRISCV_Jal:         "R_RISCV_JAL",
+	RelocRISCV_Call:        "R_RISCV_CALL",
+	RelocRISCV_CallPlt:     "R_RISCV_CALL_PLT",
+	RelocRISCV_GotHi20:     "R_RISCV_GOT_HI20",
+	RelocRISCV_TlsGotHi20:  "R_RISCV_TLS_GOT_HI20",
+	RelocRISCV_TlsGdHi20:   "R_RISCV_TLS_GD_HI20",
+	RelocRISCV_PcrelHi20:   "R_RISCV_PCREL_HI20",
+	RelocRISCV_PcrelLo12I:  "R_RISCV_PCREL_LO12_I",
+	RelocRISCV_PcrelLo12S:  "R_RISCV_PCREL_LO12_S",
+	RelocRISCV_Hi20:        "R_RISCV_HI20",
+	RelocRISCV_Lo12I:       "R_RISCV_LO12_I",
+	RelocRISCV_Lo12S:       "R_RISCV_LO12_S",
+	RelocRISCV_TprelHi20:   "R_RISCV_TPREL_HI20",
+	RelocRISCV_TprelLo12I:  "R_RISCV_TPREL_LO12_I",
+	RelocRISCV_TprelLo12S:  "R_RISCV_TPREL_LO12_S",
+	RelocRISCV_TprelAdd:    "R_RISCV_TPREL_ADD",
+	RelocRISCV_Add8:        "R_RISCV_ADD8",
+	RelocRISCV_Add16:       "R_RISCV_ADD16",
+	RelocRISCV_Add32:       "R_RISCV_ADD32",
+	RelocRISCV_Add64:       "R_RISCV_ADD64",
+	RelocRISCV_Sub8:        "R_RISCV_SUB8",
+	RelocRISCV_Sub16:       "R_RISCV_SUB16",
+	RelocRISCV_Sub32:       "R_RISCV_SUB32",
+	RelocRISCV_Sub64:       "R_RISCV_SUB64",
+	RelocRISCV_Align:       "R_RISCV_ALIGN",
+	RelocRISCV_RvcBranch:   "R_RISCV_RVC_BRANCH",
+	RelocRISCV_RvcJump:     "R_RISCV_RVC_JUMP",
+	RelocRISCV_RvcLui:      "R_RISCV_RVC_LUI",
+	RelocRISCV_GprelI:      "R_RISCV_GPREL_I",
+	RelocRISCV_GprelS:      "R_RISCV_GPREL_S",
+	RelocRISCV_TprelI:      "R_RISCV_TPREL_I",
+	RelocRISCV_TprelS:      "R_RISCV_TPREL_S",
+	RelocRISCV_Relax:       "R_RISCV_RELAX",
+	RelocRISCV_Sub6:        "R_RISCV_SUB6",
+	RelocRISCV_Set6:        "R_RISCV_SET6",
+	RelocRISCV_Set8:        "R_RISCV_SET8",
+	RelocRISCV_Set16:       "R_RISCV_SET16",
+	RelocRISCV_Set32:       "R_RISCV_SET32",
+	RelocRISCV_32Pcrel:     "R_RISCV_32_PCREL",
+	RelocRISCV_Irelative:   "R_RISCV_IRELATIVE",
+}
+
+// Relocation types for 64-bit PowerPC.
+const (
+	RelocPPC64_None             RelocType = RelocType(0)
+	RelocPPC64_Addr32           RelocType = RelocType(1)
+	RelocPPC64_Addr24           RelocType = RelocType(2)
+	RelocPPC64_Addr16           RelocType = RelocType(3)
+	RelocPPC64_Addr16Lo         RelocType = RelocType(4)
+	RelocPPC64_Addr16Hi         RelocType = RelocType(5)
+	RelocPPC64_Addr16Ha         RelocType = RelocType(6)
+	RelocPPC64_Addr14           RelocType = RelocType(7)
+	RelocPPC64_Addr14Brtaken    RelocType = RelocType(8)
+	RelocPPC64_Addr14Brntaken   RelocType = RelocType(9)
+	RelocPPC64_Rel24            RelocType = RelocType(10)
+	RelocPPC64_Rel14            RelocType = RelocType(11)
+	RelocPPC64_Rel14Brtaken     RelocType = RelocType(12)
+	RelocPPC64_Rel14Brntaken    RelocType = RelocType(13)
+	RelocPPC64_Got16            RelocType = RelocType(14)
+	RelocPPC64_Got16Lo          RelocType = RelocType(15)
+	RelocPPC64_Got16Hi          RelocType = RelocType(16)
+	RelocPPC64_Got16Ha          RelocType = RelocType(17)
+	RelocPPC64_Copy             RelocType = RelocType(19)
+	RelocPPC64_GlobDat          RelocType = RelocType(20)
+	RelocPPC64_JmpSlot          RelocType = RelocType(21)
+	RelocPPC64_Relative         RelocType = RelocType(22)
+	RelocPPC64_Uaddr32          RelocType = RelocType(24)
+	RelocPPC64_Uaddr16          RelocType = RelocType(25)
+	RelocPPC64_Rel32            RelocType = RelocType(26)
+	RelocPPC64_Plt32            RelocType = RelocType(27)
+	RelocPPC64_Pltrel32         RelocType = RelocType(28)
+	RelocPPC64_Plt16Lo          RelocType = RelocType(29)
+	RelocPPC64_Plt16Hi          RelocType = RelocType(30)
+	RelocPPC64_Plt16Ha          RelocType = RelocType(31)
+	RelocPPC64_Sectoff          RelocType = RelocType(33)
+	RelocPPC64_SectoffLo        RelocType = RelocType(34)
+	RelocPPC64_SectoffHi        RelocType = RelocType(35)
+	RelocPPC64_SectoffHa        RelocType = RelocType(36)
+	RelocPPC64_Rel30            RelocType = RelocType(37)
+	RelocPPC64_Addr64           RelocType = RelocType(38)
+	RelocPPC64_Addr16Higher     RelocType = RelocType(39)
+	RelocPPC64_Addr16Highera    RelocType = RelocType(40)
+	RelocPPC64_Addr16Highest    RelocType = RelocType(41)
+	RelocPPC64_Addr16Highesta   RelocType = RelocType(42)
+	RelocPPC64_Uaddr64          RelocType = RelocType(43)
+	RelocPPC64_Rel64            RelocType = RelocType(44)
+	RelocPPC64_Plt64            RelocType = RelocType(45)
+	RelocPPC64_Pltrel64         RelocType = RelocType(46)
+	RelocPPC64_Toc16            RelocType = RelocType(47)
+	RelocPPC64_Toc16Lo          RelocType = RelocType(48)
+	RelocPPC64_Toc16Hi          RelocType = RelocType(49)
+	RelocPPC64_Toc16Ha          RelocType = RelocType(50)
+	RelocPPC64_Toc              RelocType = RelocType(51)
+	RelocPPC64_Pltgot16         RelocType = RelocType(52)
+	RelocPPC64_Pltgot16Lo       RelocType = RelocType(53)
+	RelocPPC64_Pltgot16Hi       RelocType = RelocType(54)
+	RelocPPC64_Pltgot16Ha       RelocType = RelocType(55)
+	RelocPPC64_Addr16Ds         RelocType = RelocType(56)
+	RelocPPC64_Addr16LoDs       RelocType = RelocType(57)
+	RelocPPC64_Got16Ds          RelocType = RelocType(58)
+	RelocPPC64_Got16LoDs        RelocType = RelocType(59)
+	RelocPPC64_Plt16LoDs        RelocType = RelocType(60)
+	RelocPPC64_SectoffDs        RelocType = RelocType(61)
+	RelocPPC64_SectoffLoDs      RelocType = RelocType(62)
+	RelocPPC64_Toc16Ds          RelocType = RelocType(63)
+	RelocPPC64_Toc16LoDs        RelocType = RelocType(64)
+	RelocPPC64_Pltgot16Ds       RelocType = RelocType(65)
+	RelocPPC64_Pltgot16LoDs     RelocType = RelocType(66)
+	RelocPPC64_Tls              RelocType = RelocType(67)
+	RelocPPC64_Dtpmod64         RelocType = RelocType(68)
+	RelocPPC64_Tprel16          RelocType = RelocType(69)
+	RelocPPC64_Tprel16Lo        RelocType = RelocType(70)
+	RelocPPC64_Tprel16Hi        RelocType = RelocType(71)
+	RelocPPC64_Tprel16Ha        RelocType = RelocType(72)
+	RelocPPC64_Tprel64          RelocType = RelocType(73)
+	RelocPPC64_Dtprel16         RelocType = RelocType(74)
+	RelocPPC64_Dtprel16Lo       RelocType = RelocType(75)
+	RelocPPC64_Dtprel16Hi       RelocType = RelocType(76)
+	RelocPPC64_Dtprel16Ha       RelocType = RelocType(77)
+	RelocPPC64_Dtprel64         RelocType = RelocType(78)
+	RelocPPC64_GotTlsgd16       RelocType = RelocType(79)
+	RelocPPC64_GotTlsgd16Lo     RelocType = RelocType(80)
+	RelocPPC64_GotTlsgd16Hi     RelocType = RelocType(81)
+	RelocPPC64_GotTlsgd16Ha     RelocType = RelocType(82)
+	RelocPPC64_GotTlsld16       RelocType = RelocType(83)
+	RelocPPC64_GotTlsld16Lo     RelocType = RelocType(84)
+	RelocPPC64_GotTlsld16Hi     RelocType = RelocType(85)
+	RelocPPC64_GotTlsld16Ha     RelocType = RelocType(86)
+	RelocPPC64_GotTprel16Ds     RelocType = RelocType(87)
+	RelocPPC64_GotTprel16LoDs   RelocType = RelocType(88)
+	RelocPPC64_GotTprel16Hi     RelocType = RelocType(89)
+	RelocPPC64_GotTprel16Ha     RelocType = RelocType(90)
+	RelocPPC64_GotDtprel16Ds    RelocType = RelocType(91)
+	RelocPPC64_GotDtprel16LoDs  RelocType = RelocType(92)
+	RelocPPC64_GotDtprel16Hi    RelocType = RelocType(93)
+	RelocPPC64_GotDtprel16Ha    RelocType = RelocType(94)
+	RelocPPC64_Tprel16Ds        RelocType = RelocType(95)
+	RelocPPC64_Tprel16LoDs      RelocType = RelocType(96)
+	RelocPPC64_Tprel16Higher    RelocType = RelocType(97)
+	RelocPPC64_Tprel16Highera   RelocType = RelocType(98)
+	RelocPPC64_Tprel16Highest   RelocType = RelocType(99)
+	RelocPPC64_Tprel16Highesta  RelocType = RelocType(100)
+	RelocPPC64_Dtprel16Ds       RelocType = RelocType(101)
+	RelocPPC64_Dtprel16LoDs     RelocType = RelocType(102)
+	RelocPPC64_Dtprel16Higher   RelocType = RelocType(103)
+	RelocPPC64_Dtprel16Highera  RelocType = RelocType(104)
+	RelocPPC64_Dtprel16Highest  RelocType = RelocType(105)
+	RelocPPC64_Dtprel16Highesta RelocType = RelocType(106)
+	RelocPPC64_Tlsgd            RelocType = RelocType(107)
+	RelocPPC64_Tlsld            RelocType = RelocType(108)
+	RelocPPC64_Tocsave          RelocType = RelocType(109)
+	RelocPPC64_Addr16High       RelocType = RelocType(110)
+	RelocPPC64_Addr16Higha      RelocType = RelocType(111)
+	RelocPPC64_Tprel16High      RelocType = RelocType(112)
+	RelocPPC64_Tprel16Higha     RelocType = RelocType(113)
+	RelocPPC64_Dtprel16High     RelocType = RelocType(114)
+	RelocPPC64_Dtprel16Higha    RelocType = RelocType(115)
+	RelocPPC64_Rel24Notoc       RelocType = RelocType(116)
+	RelocPPC64_Addr64Local      RelocType = RelocType(117)
+	RelocPPC64_Entry            RelocType = RelocType(118)
+	RelocPPC64_Pltseq           RelocType = RelocType(119)
+	RelocPPC64_Pltcall          RelocType = RelocType(120)
+	RelocPPC64_PltseqNotoc      RelocType = RelocType(121)
+	RelocPPC64_PltcallNotoc     RelocType = RelocType(122)
+	RelocPPC64_PcrelOpt         RelocType = RelocType(123)
+	RelocPPC64_Rel24P9notoc     RelocType = RelocType(124)
+	RelocPPC64_D34              RelocType = RelocType(128)
+	RelocPPC64_D34Lo            RelocType = RelocType(129)
+	RelocPPC64_D34Hi30          RelocType = RelocType(130)
+	RelocPPC64_D34Ha30          RelocType = RelocType(131)
+	RelocPPC64_Pcrel34          RelocType = RelocType(132)
+	RelocPPC64_GotPcrel34       RelocType = RelocType(133)
+	RelocPPC64_PltPcrel34       RelocType = RelocType(134)
+	RelocPPC64_PltPcrel34Notoc  RelocType = RelocType(135)
+	RelocPPC64_Addr16Higher34   RelocType = RelocType(136)
+	RelocPPC64_Addr16Highera34  RelocType = RelocType(137)
+	RelocPPC64_Addr16Highest34  RelocType = RelocType(138)
+	RelocPPC64_Addr16Highesta34 RelocType = RelocType(139)
+	RelocPPC64_Rel16Higher34    RelocType = RelocType(140)
+	RelocPPC64_Rel16Highera34   RelocType = RelocType(141)
+	RelocPPC64_Rel16Highest34   RelocType = RelocType(142)
+	RelocPPC64_Rel16Highesta34  RelocType = RelocType(143)
+	RelocPPC64_D28              RelocType = RelocType(144)
+	RelocPPC64_Pcrel28          RelocType = RelocType(145)
+	RelocPPC64_Tprel34          RelocType = RelocType(146)
+	RelocPPC64_Dtprel34         RelocType = RelocType(147)
+	RelocPPC64_GotTlsgdPcrel34  RelocType = RelocType(148)
+	RelocPPC64_GotTlsldPcrel34  RelocType = RelocType(149)
+	RelocPPC64_GotTprelPcrel34  RelocType = RelocType(150)
+	RelocPPC64_GotDtprelPcrel34 RelocType = RelocType(151)
+	RelocPPC64_Rel16High        RelocType = RelocType(240)
+	RelocPPC64_Rel16Higha       RelocType = RelocType(241)
+	RelocPPC64_Rel16Higher      RelocType = RelocType(242)
+	RelocPPC64_Rel16Highera     RelocType = RelocType(243)
+	RelocPPC64_Rel16Highest     RelocType = RelocType(244)
+	RelocPPC64_Rel16Highesta    RelocType = RelocType(245)
+	RelocPPC64_Rel16dxHa        RelocType = RelocType(246)
+	RelocPPC64_JmpIrel          RelocType = RelocType(247)
+	RelocPPC64_Irelative        RelocType = RelocType(248)
+	RelocPPC64_Rel16            RelocType = RelocType(249)
+	RelocPPC64_Rel16Lo          RelocType = RelocType(250)
+	RelocPPC64_Rel16Hi          RelocType = RelocType(251)
+	RelocPPC64_Rel16Ha          RelocType = RelocType(252)
+	RelocPPC64_GnuVtinherit     RelocType = RelocType(253)
+	RelocPPC64_GnuVtentry       RelocType = RelocType(254)
+)
+
+var RelocPPC64Str = map[RelocType]string{
+	RelocPPC64_None:             "R_PPC64_NONE",
+	RelocPPC64_Addr32:           "R_PPC64_ADDR32",
+	RelocPPC64_Addr24:           "R_PPC64_ADDR24",
+	RelocPPC64_Addr16:           "R_PPC64_ADDR16",
+	RelocPPC64_Addr16Lo:         "R_PPC64_ADDR16_LO",
+	RelocPPC64_Addr16Hi:         "R_PPC64_ADDR16_HI",
+	RelocPPC64_Addr16Ha:         "R_PPC64_ADDR16_HA",
+	RelocPPC64_Addr14:           "R_PPC64_ADDR14",
+	RelocPPC64_Addr14Brtaken:    "R_PPC64_ADDR14_BRTAKEN",
+	RelocPPC64_Addr14Brntaken:   "R_PPC64_ADDR14_BRNTAKEN",
+	RelocPPC64_Rel24:            "R_PPC64_REL24",
+	RelocPPC64_Rel14:            "R_PPC64_REL14",
+	RelocPPC64_Rel14Brtaken:     "R_PPC64_REL14_BRTAKEN",
+	RelocPPC64_Rel14Brntaken:    "R_PPC64_REL14_BRNTAKEN",
+	RelocPPC64_Got16:            "R_PPC64_GOT16",
+	RelocPPC64_Got16Lo:          "R_PPC64_GOT16_LO",
+	RelocPPC64_Got16Hi:          "R_PPC64_GOT16_HI",
+	RelocPPC64_Got16Ha:          "R_PPC64_GOT16_HA",
+	RelocPPC64_Copy:             "R_PPC64_COPY",
+	RelocPPC64_GlobDat:          "R_PPC64_GLOB_DAT",
+	RelocPPC64_JmpSlot:          "R_PPC64_JMP_SLOT",
+	RelocPPC64_Relative:         "R_PPC64_RELATIVE",
+	RelocPPC64_Uaddr32:          "R_PPC64_UADDR32",
+	RelocPPC64_Uaddr16:          "R_PPC64_UADDR16",
+	RelocPPC64_Rel32:            "R_PPC64_REL32",
+	RelocPPC64_Plt32:            "R_PPC64_PLT32",
+	RelocPPC64_Pltrel32:         "R_PPC64_PLTREL32",
+	RelocPPC64_Plt16Lo:          "R_PPC64_PLT16_LO",
+	RelocPPC64_Plt16Hi:          "R_PPC64_PLT16_HI",
+	RelocPPC64_Plt16Ha:          "R_PPC64_PLT16_HA",
+	RelocPPC64_Sectoff:          "R_PPC64_SECTOFF",
+	RelocPPC64_SectoffLo:        "R_PPC64_SECTOFF_LO",
+	RelocPPC64_SectoffHi:        "R_PPC64_SECTOFF_HI",
+	RelocPPC64_SectoffHa:        "R_PPC64_SECTOFF_HA",
+	RelocPPC64_Rel30:            "R_PPC64_REL30",
+	RelocPPC64_Addr64:           "R_PPC64_ADDR64",
+	RelocPPC64_Addr16Higher:     "R_PPC64_ADDR16_HIGHER",
+	RelocPPC64_Addr16Highera:    "R_PPC64_ADDR16_HIGHERA",
+	RelocPPC64_Addr16Highest:    "R_PPC64_ADDR16_HIGHEST",
+	RelocPPC64_Addr16Highesta:   "R_PPC64_ADDR16_HIGHESTA",
+	RelocPPC64_Uaddr64:          "R_PPC64_UADDR64",
+	RelocPPC64_Rel64:            "R_PPC64_REL64",
+	RelocPPC64_Plt64:            "R_PPC64_PLT64",
+	RelocPPC64_Pltrel64:         "R_PPC64_PLTREL64",
+	RelocPPC64_Toc16:            "R_PPC64_TOC16",
+	RelocPPC64_Toc16Lo:          "R_PPC64_TOC16_LO",
+	RelocPPC64_Toc16Hi:          "R_PPC64_TOC16_HI",
+	RelocPPC64_Toc16Ha:          "R_PPC64_TOC16_HA",
+	RelocPPC64_Toc:              "R_PPC64_TOC",
+	RelocPPC64_Pltgot16:         "R_PPC64_PLTGOT16",
+	RelocPPC64_Pltgot16Lo:       "R_PPC64_PLTGOT16_LO",
+	RelocPPC64_Pltgot16Hi:       "R_PPC64_PLTGOT16_HI",
+	RelocPPC64_Pltgot16Ha:       "R_PPC64_PLTGOT16_HA",
+	RelocPPC64_Addr16Ds:         "R_PPC64_ADDR16_DS",
+	RelocPPC64_Addr16LoDs:       "R_PPC64_ADDR16_LO_DS",
+	RelocPPC64_Got16Ds:          "R_PPC64_GOT16_DS",
+	RelocPPC64_Got16LoDs:        "R_PPC64_GOT16_LO_DS",
+	RelocPPC64_Plt16LoDs:        "R_PPC64_PLT16_LO_DS",
+	RelocPPC64_SectoffDs:        "R_PPC64_SECTOFF_DS",
+	RelocPPC64_SectoffLoDs:      "R_PPC64_SECTOFF_LO_DS",
+	RelocPPC64_Toc16Ds:          "R_PPC64_TOC16_DS",
+	RelocPPC64_Toc16LoDs:        "R_PPC64_TOC16_LO_DS",
+	RelocPPC64_Pltgot16Ds:       "R_PPC64_PLTGOT16_DS",
+	RelocPPC64_Pltgot16LoDs:     "R_PPC64_PLTGOT16_LO_DS",
+	RelocPPC64_Tls:              "R_PPC64_TLS",
+	RelocPPC64_Dtpmod64:         "R_PPC64_DTPMOD64",
+	RelocPPC64_Tprel16:          "R_PPC64_TPREL16",
+	RelocPPC64_Tprel16Lo:        "R_PPC64_TPREL16_LO",
+	RelocPPC64_Tprel16Hi:        "R_PPC64_TPREL16_HI",
+	RelocPPC64_Tprel16Ha:        "R_PPC64_TPREL16_HA",
+	RelocPPC64_Tprel64:          "R_PPC64_TPREL64",
+	RelocPPC64_Dtprel16:         "R_PPC64_DTPREL16",
+	RelocPPC64_Dtprel16Lo:       "R_PPC64_DTPREL16_LO",
+	RelocPPC64_Dtprel16Hi:       "R_PPC64_DTPREL16_HI",
+	RelocPPC64_Dtprel16Ha:       "R_PPC64_DTPREL16_HA",
+	RelocPPC64_Dtprel64:         "R_PPC64_DTPREL64",
+	RelocPPC64_GotTlsgd16:       "R_PPC64_GOT_TLSGD16",
+	RelocPPC64_GotTlsgd16Lo:     "R_PPC64_GOT_TLSGD16_LO",
+	RelocPPC64_GotTlsgd16Hi:     "R_PPC64_GOT_TLSGD16_HI",
+	RelocPPC64_GotTlsgd16Ha:     "R_PPC64_GOT_TLSGD16_HA",
+	RelocPPC64_GotTlsld16:       "R_PPC64_GOT_TLSLD16",
+	RelocPPC64_GotTlsld16Lo:     "R_PPC64_GOT_TLSLD16_LO",
+	RelocPPC64_GotTlsld16Hi:     "R_PPC64_GOT_TLSLD16_HI",
+	RelocPPC64_GotTlsld16Ha:     "R_PPC64_GOT_TLSLD16_HA",
+	RelocPPC64_GotTprel16Ds:     "R_PPC64_GOT_TPREL16_DS",
+	RelocPPC64_GotTprel16LoDs:   "R_PPC64_GOT_TPREL16_LO_DS",
+	RelocPPC64_GotTprel16Hi:     "R_PPC64_GOT_TPREL16_HI",
+	RelocPPC64_GotTprel16Ha:     "R_PPC64_GOT_TPREL16_HA",
+	RelocPPC64_GotDtprel16Ds:    "R_PPC64_GOT_DTPREL16_DS",
+	RelocPPC64_GotDtprel16LoDs:  "R_PPC64_GOT_DTPREL16_LO_DS",
+	RelocPPC64_GotDtprel16Hi:    "R_PPC64_GOT_DTPREL16_HI",
+	RelocPPC64_GotDtprel16Ha:    "R_PPC64_GOT_DTPREL16_HA",
+	RelocPPC64_Tprel16Ds:        "R_PPC64_TPREL16_DS",
+	RelocPPC64_Tprel16LoDs:      "R_PPC64_TPREL16_LO_DS",
+	RelocPPC64_Tprel16Higher:    "R_PPC64_TPREL16_HIGHER",
+	RelocPPC64_Tprel16Highera:   "R_PPC64_TPREL16_HIGHERA",
+	RelocPPC64_Tprel16Highest:   "R_PPC64_TPREL16_HIGHEST",
+	RelocPPC64_Tprel16Highesta:  "R_PPC64_TPREL16_HIGHESTA",
+	RelocPPC64_Dtprel16Ds:       "R_PPC64_DTPREL16_DS",
+	RelocPPC64_Dtprel16LoDs:     "R_PPC64_DTPREL16_LO_DS",
+	RelocPPC64_Dtprel16Higher:   "R_PPC64_DTPREL16_HIGHER",
+	RelocPPC64_Dtprel16Highera:  "R_PPC64_DTPREL16_HIGHERA",
+	RelocPPC64_Dtprel16Highest:  "R_PPC64_DTPREL16_HIGHEST",
+	RelocPPC64_Dtprel16Highesta: "R_PPC64_DTPREL16_HIGHESTA",
+	RelocPPC64_Tlsgd:            "R_PPC64_TLSGD",
+	RelocPPC64_Tlsld:            "R_PPC64_TLSLD",
+	RelocPPC64_Tocsave:          "R_PPC64_TOCSAVE",
+	RelocPPC64_Addr16High:       "R_PPC64_ADDR16_HIGH",
+	RelocPPC64_Addr16Higha:      "R_PPC64_ADDR16_HIGHA",
+	RelocPPC64_Tprel16High:      "R_PPC64_TPREL16_HIGH",
+	RelocPPC64_Tprel16Higha:     "R_PPC64_TPREL16_HIGHA",
+	RelocPPC64_Dtprel16High:     "R_PPC64_DTPREL16_HIGH",
+	RelocPPC64_Dtprel16Higha:    "R_PPC64_DTPREL16_HIGHA",
+	RelocPPC64_Rel24Notoc:       "R_PPC64_REL24_NOTOC",
+	RelocPPC64_Addr64Local:      "R_PPC64_ADDR64_LOCAL",
+	RelocPPC64_Entry:            "R_PPC64_ENTRY",
+	RelocPPC64_Pltseq:           "R_PPC64_PLTSEQ",
+	RelocPPC64_Pltcall:          "R_PPC64_PLTCALL",
+	RelocPPC64_PltseqNotoc:      "R_PPC64_PLTSEQ_NOTOC",
+	RelocPPC64_PltcallNotoc:     "R_PPC64_PLTCALL_NOTOC",
+	RelocPPC64_PcrelOpt:         "R_PPC64_PCREL_OPT",
+	RelocPPC64_Rel24P9notoc:     "R_PPC64_REL24_P9NOTOC",
+	RelocPPC64_D34:              "R_PPC64_D34",
+	RelocPPC64_D34Lo:            "R_PPC64_D34_LO",
+	RelocPPC64_D34Hi30:          "R_PPC64_D34_HI30",
+	RelocPPC64_D34Ha30:          "R_PPC64_D34_HA30",
+	RelocPPC64_Pcrel34:          "R_PPC64_PCREL34",
+	RelocPPC64_GotPcrel34:       "R_PPC64_GOT_PCREL34",
+	RelocPPC64_PltPcrel34:       "R_PPC64_PLT_PCREL34",
+	RelocPPC64_PltPcrel34Notoc:  "R_PPC64_PLT_PCREL34_NOTOC",
+	RelocPPC64_Addr16Higher34:   "R_PPC64_ADDR16_HIGHER34",
+	RelocPPC64_Addr16Highera34:  "R_PPC64_ADDR16_HIGHERA34",
+	RelocPPC64_Addr16Highest34:  "R_PPC64_ADDR16_HIGHEST34",
+	RelocPPC64_Addr16Highesta34: "R_PPC64_ADDR16_HIGHESTA34",
+	RelocPPC64_Rel16Higher34:    "R_PPC64_REL16_HIGHER34",
+	RelocPPC64_Rel16Highera34:   "R_PPC64_REL16_HIGHERA34",
+	RelocPPC64_Rel16Highest34:   "R_PPC64_REL16_HIGHEST34",
+	RelocPPC64_Rel16Highesta34:  "R_PPC64_REL16_HIGHESTA34",
+	RelocPPC64_D28:              "R_PPC64_D28",
+	RelocPPC64_Pcrel28:          "R_PPC64_PCREL28",
+	RelocPPC64_Tprel34:          "R_PPC64_TPREL34",
+	RelocPPC64_Dtprel34:         "R_PPC64_DTPREL34",
+	RelocPPC64_GotTlsgdPcrel34:  "R_PPC64_GOT_TLSGD_PCREL34",
+	RelocPPC64_GotTlsldPcrel34:  "R_PPC64_GOT_TLSLD_PCREL34",
+	RelocPPC64_GotTprelPcrel34:  "R_PPC64_GOT_TPREL_PCREL34",
+	RelocPPC64_GotDtprelPcrel34: "R_PPC64_GOT_DTPREL_PCREL34",
+	RelocPPC64_Rel16High:        "R_PPC64_REL16_HIGH",
+	RelocPPC64_Rel16Higha:       "R_PPC64_REL16_HIGHA",
+	RelocPPC64_Rel16Higher:      "R_PPC64_REL16_HIGHER",
+	RelocPPC64_Rel16Highera:     "R_PPC64_REL16_HIGHERA",
+	RelocPPC64_Rel16Highest:     "R_PPC64_REL16_HIGHEST",
+	RelocPPC64_Rel16Highesta:    "R_PPC64_REL16_HIGHESTA",
+	RelocPPC64_Rel16dxHa:        "R_PPC64_REL16DX_HA",
+	RelocPPC64_JmpIrel:          "R_PPC64_JMP_IREL",
+	RelocPPC64_Irelative:        "R_PPC64_IRELATIVE",
+	RelocPPC64_Rel16:            "R_PPC64_REL16",
+	RelocPPC64_Rel16Lo:          "R_PPC64_REL16_LO",
+	RelocPPC64_Rel16Hi:          "R_PPC64_REL16_HI",
+	RelocPPC64_Rel16Ha:          "R_PPC64_REL16_HA",
+	RelocPPC64_GnuVtinherit:     "R_PPC64_GNU_VTINHERIT",
+	RelocPPC64_GnuVtentry:       "R_PPC64_GNU_VTENTRY",
+}
+
+// Relocation types for MIPS.
+const (
+	RelocMIPS_None                   RelocType = RelocType(0)
+	RelocMIPS_16                     RelocType = RelocType(1)
+	RelocMIPS_32                     RelocType = RelocType(2)
+	RelocMIPS_Rel32                  RelocType = RelocType(3)
+	RelocMIPS_26                     RelocType = RelocType(4)
+	RelocMIPS_Hi16                   RelocType = RelocType(5)
+	RelocMIPS_Lo16                   RelocType = RelocType(6)
+	RelocMIPS_Gprel16                RelocType = RelocType(7)
+	RelocMIPS_Literal                RelocType = RelocType(8)
+	RelocMIPS_Got16                  RelocType = RelocType(9)
+	RelocMIPS_Pc16                   RelocType = RelocType(10)
+	RelocMIPS_Call16                 RelocType = RelocType(11)
+	RelocMIPS_Gprel32                RelocType = RelocType(12)
+	RelocMIPS_Unused1                RelocType = RelocType(13)
+	RelocMIPS_Unused2                RelocType = RelocType(14)
+	RelocMIPS_Unused3                RelocType = RelocType(15)
+	RelocMIPS_Shift5                 RelocType = RelocType(16)
+	RelocMIPS_Shift6                 RelocType = RelocType(17)
+	RelocMIPS_64                     RelocType = RelocType(18)
+	RelocMIPS_GotDisp                RelocType = RelocType(19)
+	RelocMIPS_GotPage                RelocType = RelocType(20)
+	RelocMIPS_GotOfst                RelocType = RelocType(21)
+	RelocMIPS_GotHi16                RelocType = RelocType(22)
+	RelocMIPS_GotLo16                RelocType = RelocType(23)
+	RelocMIPS_Sub                    RelocType = RelocType(24)
+	RelocMIPS_InsertA                RelocType = RelocType(25)
+	RelocMIPS_InsertB                RelocType = RelocType(26)
+	RelocMIPS_Delete                 RelocType = RelocType(27)
+	RelocMIPS_Higher                 RelocType = RelocType(28)
+	RelocMIPS_Highest                RelocType = RelocType(29)
+	RelocMIPS_CallHi16               RelocType = RelocType(30)
+	RelocMIPS_CallLo16               RelocType = RelocType(31)
+	RelocMIPS_ScnDisp                RelocType = RelocType(32)
+	RelocMIPS_Rel16                  RelocType = RelocType(33)
+	RelocMIPS_AddImmediate           RelocType = RelocType(34)
+	RelocMIPS_Pjump                  RelocType = RelocType(35)
+	RelocMIPS_Relgot                 RelocType = RelocType(36)
+	RelocMIPS_Jalr                   RelocType = RelocType(37)
+	RelocMIPS_TlsDtpmod32            RelocType = RelocType(38)
+	RelocMIPS_TlsDtprel32            RelocType = RelocType(39)
+	RelocMIPS_TlsDtpmod64            RelocType = RelocType(40)
+	RelocMIPS_TlsDtprel64            RelocType = RelocType(41)
+	RelocMIPS_TlsGd                  RelocType = RelocType(42)
+	RelocMIPS_TlsLdm                 RelocType = RelocType(43)
+	RelocMIPS_TlsDtprelHi16          RelocType = RelocType(44)
+	RelocMIPS_TlsDtprelLo16          RelocType = RelocType(45)
+	RelocMIPS_TlsGottprel            RelocType = RelocType(46)
+	RelocMIPS_TlsTprel32             RelocType = RelocType(47)
+	RelocMIPS_TlsTprel64             RelocType = RelocType(48)
+	RelocMIPS_TlsTprelHi16           RelocType = RelocType(49)
+	RelocMIPS_TlsTprelLo16           RelocType = RelocType(50)
+	RelocMIPS_GlobDat                RelocType = RelocType(51)
+	RelocMIPS_Pc21S2                 RelocType = RelocType(60)
+	RelocMIPS_Pc26S2                 RelocType = RelocType(61)
+	RelocMIPS_Pc18S3                 RelocType = RelocType(62)
+	RelocMIPS_Pc19S2                 RelocType = RelocType(63)
+	RelocMIPS_Pchi16                 RelocType = RelocType(64)
+	RelocMIPS_Pclo16                 RelocType = RelocType(65)
+	RelocMIPS_Mips16_26              RelocType = RelocType(100)
+	RelocMIPS_Mips16Gprel            RelocType = RelocType(101)
+	RelocMIPS_Mips16Got16            RelocType = RelocType(102)
+	RelocMIPS_Mips16Call16           RelocType = RelocType(103)
+	RelocMIPS_Mips16Hi16             RelocType = RelocType(104)
+	RelocMIPS_Mips16Lo16             RelocType = RelocType(105)
+	RelocMIPS_Mips16TlsGd            RelocType = RelocType(106)
+	RelocMIPS_Mips16TlsLdm           RelocType = RelocType(107)
+	RelocMIPS_Mips16TlsDtprelHi16    RelocType = RelocType(108)
+	RelocMIPS_Mips16TlsDtprelLo16    RelocType = RelocType(109)
+	RelocMIPS_Mips16TlsGottprel      RelocType = RelocType(110)
+	RelocMIPS_Mips16TlsTprelHi16     RelocType = RelocType(111)
+	RelocMIPS_Mips16TlsTprelLo16     RelocType = RelocType(112)
+	RelocMIPS_Mips16Pc16S1           RelocType = RelocType(113)
+	RelocMIPS_Copy                   RelocType = RelocType(126)
+	RelocMIPS_JumpSlot               RelocType = RelocType(127)
+	RelocMIPS_Micromips26S1          RelocType = RelocType(133)
+	RelocMIPS_MicromipsHi16          RelocType = RelocType(134)
+	RelocMIPS_MicromipsLo16          RelocType = RelocType(135)
+	RelocMIPS_MicromipsGprel16       RelocType = RelocType(136)
+	RelocMIPS_MicromipsLiteral       RelocType = RelocType(137)
+	RelocMIPS_MicromipsGot16         RelocType = RelocType(138)
+	RelocMIPS_MicromipsPc7S1         RelocType = RelocType(139)
+	RelocMIPS_MicromipsPc10S1        RelocType = RelocType(140)
+	RelocMIPS_MicromipsPc16S1        RelocType = RelocType(141)
+	RelocMIPS_MicromipsCall16        RelocType = RelocType(142)
+	RelocMIPS_MicromipsGotDisp       RelocType = RelocType(145)
+	RelocMIPS_MicromipsGotPage       RelocType = RelocType(146)
+	RelocMIPS_MicromipsGotOfst       RelocType = RelocType(147)
+	RelocMIPS_MicromipsGotHi16       RelocType = RelocType(148)
+	RelocMIPS_MicromipsGotLo16       RelocType = RelocType(149)
+	RelocMIPS_MicromipsSub           RelocType = RelocType(150)
+	RelocMIPS_MicromipsHigher        RelocType = RelocType(151)
+	RelocMIPS_MicromipsHighest       RelocType = RelocType(152)
+	RelocMIPS_MicromipsCallHi16      RelocType = RelocType(153)
+	RelocMIPS_MicromipsCallLo16      RelocType = RelocType(154)
+	RelocMIPS_MicromipsScnDisp       RelocType = RelocType(155)
+	RelocMIPS_MicromipsJalr          RelocType = RelocType(156)
+	RelocMIPS_MicromipsHi0Lo16       RelocType = RelocType(157)
+	RelocMIPS_MicromipsTlsGd         RelocType = RelocType(162)
+	RelocMIPS_MicromipsTlsLdm        RelocType = RelocType(163)
+	RelocMIPS_MicromipsTlsDtprelHi16 RelocType = RelocType(164)
+	RelocMIPS_MicromipsTlsDtprelLo16 RelocType = RelocType(165)
+	RelocMIPS_MicromipsTlsGottprel   RelocType = RelocType(166)
+	RelocMIPS_MicromipsTlsTprelHi16  RelocType = RelocType(169)
+	RelocMIPS_MicromipsTlsTprelLo16  RelocType = RelocType(170)
+	RelocMIPS_MicromipsGprel7S2      RelocType = RelocType(172)
+	RelocMIPS_MicromipsPc23S2        RelocType = RelocType(173)
+	RelocMIPS_Pc32                   RelocType = RelocType(248)
+	RelocMIPS_Eh                     RelocType = RelocType(249)
+	RelocMIPS_GnuRel16S2             RelocType = RelocType(250)
+	RelocMIPS_GnuVtinherit           RelocType = RelocType(253)
+	RelocMIPS_GnuVtentry             RelocType = RelocType(254)
+)
+
+var RelocMIPSStr = map[RelocType]string{
+	RelocMIPS_None:                   "R_MIPS_NONE",
+	RelocMIPS_16:                     "R_MIPS_16",
+	RelocMIPS_32:                     "R_MIPS_32",
+	RelocMIPS_Rel32:                  "R_MIPS_REL32",
+	RelocMIPS_26:                     "R_MIPS_26",
+	RelocMIPS_Hi16:                   "R_MIPS_HI16",
+	RelocMIPS_Lo16:                   "R_MIPS_LO16",
+	RelocMIPS_Gprel16:                "R_MIPS_GPREL16",
+	RelocMIPS_Literal:                "R_MIPS_LITERAL",
+	RelocMIPS_Got16:                  "R_MIPS_GOT16",
+	RelocMIPS_Pc16:                   "R_MIPS_PC16",
+	RelocMIPS_Call16:                 "R_MIPS_CALL16",
+	RelocMIPS_Gprel32:                "R_MIPS_GPREL32",
+	RelocMIPS_Unused1:                "R_MIPS_UNUSED1",
+	RelocMIPS_Unused2:                "R_MIPS_UNUSED2",
+	RelocMIPS_Unused3:                "R_MIPS_UNUSED3",
+	RelocMIPS_Shift5:                 "R_MIPS_SHIFT5",
+	RelocMIPS_Shift6:                 "R_MIPS_SHIFT6",
+	RelocMIPS_64:                     "R_MIPS_64",
+	RelocMIPS_GotDisp:                "R_MIPS_GOT_DISP",
+	RelocMIPS_GotPage:                "R_MIPS_GOT_PAGE",
+	RelocMIPS_GotOfst:                "R_MIPS_GOT_OFST",
+	RelocMIPS_GotHi16:                "R_MIPS_GOT_HI16",
+	RelocMIPS_GotLo16:                "R_MIPS_GOT_LO16",
+	RelocMIPS_Sub:                    "R_MIPS_SUB",
+	RelocMIPS_InsertA:                "R_MIPS_INSERT_A",
+	RelocMIPS_InsertB:                "R_MIPS_INSERT_B",
+	RelocMIPS_Delete:                 "R_MIPS_DELETE",
+	RelocMIPS_Higher:                 "R_MIPS_HIGHER",
+	RelocMIPS_Highest:                "R_MIPS_HIGHEST",
+	RelocMIPS_CallHi16:               "R_MIPS_CALL_HI16",
+	RelocMIPS_CallLo16:               "R_MIPS_CALL_LO16",
+	RelocMIPS_ScnDisp:                "R_MIPS_SCN_DISP",
+	RelocMIPS_Rel16:                  "R_MIPS_REL16",
+	RelocMIPS_AddImmediate:           "R_MIPS_ADD_IMMEDIATE",
+	RelocMIPS_Pjump:                  "R_MIPS_PJUMP",
+	RelocMIPS_Relgot:                 "R_MIPS_RELGOT",
+	RelocMIPS_Jalr:                   "R_MIPS_JALR",
+	RelocMIPS_TlsDtpmod32:            "R_MIPS_TLS_DTPMOD32",
+	RelocMIPS_TlsDtprel32:            "R_MIPS_TLS_DTPREL32",
+	RelocMIPS_TlsDtpmod64:            "R_MIPS_TLS_DTPMOD64",
+	RelocMIPS_TlsDtprel64:            "R_MIPS_TLS_DTPREL64",
+	RelocMIPS_TlsGd:                  "R_MIPS_TLS_GD",
+	RelocMIPS_TlsLdm:                 "R_MIPS_TLS_LDM",
+	RelocMIPS_TlsDtprelHi16:          "R_MIPS_TLS_DTPREL_HI16",
+	RelocMIPS_TlsDtprelLo16:          "R_MIPS_TLS_DTPREL_LO16",
+	RelocMIPS_TlsGottprel:            "R_MIPS_TLS_GOTTPREL",
+	RelocMIPS_TlsTprel32:             "R_MIPS_TLS_TPREL32",
+	RelocMIPS_TlsTprel64:             "R_MIPS_TLS_TPREL64",
+	RelocMIPS_TlsTprelHi16:           "R_MIPS_TLS_TPREL_HI16",
+	RelocMIPS_TlsTprelLo16:           "R_MIPS_TLS_TPREL_LO16",
+	RelocMIPS_GlobDat:                "R_MIPS_GLOB_DAT",
+	RelocMIPS_Pc21S2:                 "R_MIPS_PC21_S2",
+	RelocMIPS_Pc26S2:                 "R_MIPS_PC26_S2",
+	RelocMIPS_Pc18S3:                 "R_MIPS_PC18_S3",
+	RelocMIPS_Pc19S2:                 "R_MIPS_PC19_S2",
+	RelocMIPS_Pchi16:                 "R_MIPS_PCHI16",
+	RelocMIPS_Pclo16:                 "R_MIPS_PCLO16",
+	RelocMIPS_Mips16_26:              "R_MIPS16_26",
+	RelocMIPS_Mips16Gprel:            "R_MIPS16_GPREL",
+	RelocMIPS_Mips16Got16:            "R_MIPS16_GOT16",
+	RelocMIPS_Mips16Call16:           "R_MIPS16_CALL16",
+	RelocMIPS_Mips16Hi16:             "R_MIPS16_HI16",
+	RelocMIPS_Mips16Lo16:             "R_MIPS16_LO16",
+	RelocMIPS_Mips16TlsGd:            "R_MIPS16_TLS_GD",
+	RelocMIPS_Mips16TlsLdm:           "R_MIPS16_TLS_LDM",
+	RelocMIPS_Mips16TlsDtprelHi16:    "R_MIPS16_TLS_DTPREL_HI16",
+	RelocMIPS_Mips16TlsDtprelLo16:    "R_MIPS16_TLS_DTPREL_LO16",
+	RelocMIPS_Mips16TlsGottprel:      "R_MIPS16_TLS_GOTTPREL",
+	RelocMIPS_Mips16TlsTprelHi16:     "R_MIPS16_TLS_TPREL_HI16",
+	RelocMIPS_Mips16TlsTprelLo16:     "R_MIPS16_TLS_TPREL_LO16",
+	RelocMIPS_Mips16Pc16S1:           "R_MIPS16_PC16_S1",
+	RelocMIPS_Copy:                   "R_MIPS_COPY",
+	RelocMIPS_JumpSlot:               "R_MIPS_JUMP_SLOT",
+	RelocMIPS_Micromips26S1:          "R_MICROMIPS_26_S1",
+	RelocMIPS_MicromipsHi16:          "R_MICROMIPS_HI16",
+	RelocMIPS_MicromipsLo16:          "R_MICROMIPS_LO16",
+	RelocMIPS_MicromipsGprel16:       "R_MICROMIPS_GPREL16",
+	RelocMIPS_MicromipsLiteral:       "R_MICROMIPS_LITERAL",
+	RelocMIPS_MicromipsGot16:         "R_MICROMIPS_GOT16",
+	RelocMIPS_MicromipsPc7S1:         "R_MICROMIPS_PC7_S1",
+	RelocMIPS_MicromipsPc10S1:        "R_MICROMIPS_PC10_S1",
+	RelocMIPS_MicromipsPc16S1:        "R_MICROMIPS_PC16_S1",
+	RelocMIPS_MicromipsCall16:        "R_MICROMIPS_CALL16",
+	RelocMIPS_MicromipsGotDisp:       "R_MICROMIPS_GOT_DISP",
+	RelocMIPS_MicromipsGotPage:       "R_MICROMIPS_GOT_PAGE",
+	RelocMIPS_MicromipsGotOfst:       "R_MICROMIPS_GOT_OFST",
+	RelocMIPS_MicromipsGotHi16:       "R_MICROMIPS_GOT_HI16",
+	RelocMIPS_MicromipsGotLo16:       "R_MICROMIPS_GOT_LO16",
+	RelocMIPS_MicromipsSub:           "R_MICROMIPS_SUB",
+	RelocMIPS_MicromipsHigher:        "R_MICROMIPS_HIGHER",
+	RelocMIPS_MicromipsHighest:       "R_MICROMIPS_HIGHEST",
+	RelocMIPS_MicromipsCallHi16:      "R_MICROMIPS_CALL_HI16",
+	RelocMIPS_MicromipsCallLo16:      "R_MICROMIPS_CALL_LO16",
+	RelocMIPS_MicromipsScnDisp:       "R_MICROMIPS_SCN_DISP",
+	RelocMIPS_MicromipsJalr:          "R_MICROMIPS_JALR",
+	RelocMIPS_MicromipsHi0Lo16:       "R_MICROMIPS_HI0_LO16",
+	RelocMIPS_MicromipsTlsGd:         "R_MICROMIPS_TLS_GD",
+	RelocMIPS_MicromipsTlsLdm:        "R_MICROMIPS_TLS_LDM",
+	RelocMIPS_MicromipsTlsDtprelHi16: "R_MICROMIPS_TLS_DTPREL_HI16",
+	RelocMIPS_MicromipsTlsDtprelLo16: "R_MICROMIPS_TLS_DTPREL_LO16",
+	RelocMIPS_MicromipsTlsGottprel:   "R_MICROMIPS_TLS_GOTTPREL",
+	RelocMIPS_MicromipsTlsTprelHi16:  "R_MICROMIPS_TLS_TPREL_HI16",
+	RelocMIPS_MicromipsTlsTprelLo16:  "R_MICROMIPS_TLS_TPREL_LO16",
+	RelocMIPS_MicromipsGprel7S2:      "R_MICROMIPS_GPREL7_S2",
+	RelocMIPS_MicromipsPc23S2:        "R_MICROMIPS_PC23_S2",
+	RelocMIPS_Pc32:                   "R_MIPS_PC32",
+	RelocMIPS_Eh:                     "R_MIPS_EH",
+	RelocMIPS_GnuRel16S2:             "R_MIPS_GNU_REL16_S2",
+	RelocMIPS_GnuVtinherit:           "R_MIPS_GNU_VTINHERIT",
+	RelocMIPS_GnuVtentry:             "R_MIPS_GNU_VTENTRY",
+}
+
+// Relocation types for IBM S/390 and z/Architecture.
+const (
+	RelocS390_None         RelocType = RelocType(0)
+	RelocS390_8            RelocType = RelocType(1)
+	RelocS390_12           RelocType = RelocType(2)
+	RelocS390_16           RelocType = RelocType(3)
+	RelocS390_32           RelocType = RelocType(4)
+	RelocS390_Pc32         RelocType = RelocType(5)
+	RelocS390_Got12        RelocType = RelocType(6)
+	RelocS390_Got32        RelocType = RelocType(7)
+	RelocS390_Plt32        RelocType = RelocType(8)
+	RelocS390_Copy         RelocType = RelocType(9)
+	RelocS390_GlobDat      RelocType = RelocType(10)
+	RelocS390_JmpSlot      RelocType = RelocType(11)
+	RelocS390_Relative     RelocType = RelocType(12)
+	RelocS390_Gotoff32     RelocType = RelocType(13)
+	RelocS390_Gotpc        RelocType = RelocType(14)
+	RelocS390_Got16        RelocType = RelocType(15)
+	RelocS390_Pc16         RelocType = RelocType(16)
+	RelocS390_Pc16dbl      RelocType = RelocType(17)
+	RelocS390_Plt16dbl     RelocType = RelocType(18)
+	RelocS390_Pc32dbl      RelocType = RelocType(19)
+	RelocS390_Plt32dbl     RelocType = RelocType(20)
+	RelocS390_Gotpcdbl     RelocType = RelocType(21)
+	RelocS390_64           RelocType = RelocType(22)
+	RelocS390_Pc64         RelocType = RelocType(23)
+	RelocS390_Got64        RelocType = RelocType(24)
+	RelocS390_Plt64        RelocType = RelocType(25)
+	RelocS390_Gotent       RelocType = RelocType(26)
+	RelocS390_Gotoff16     RelocType = RelocType(27)
+	RelocS390_Gotoff64     RelocType = RelocType(28)
+	RelocS390_Gotplt12     RelocType = RelocType(29)
+	RelocS390_Gotplt16     RelocType = RelocType(30)
+	RelocS390_Gotplt32     RelocType = RelocType(31)
+	RelocS390_Gotplt64     RelocType = RelocType(32)
+	RelocS390_Gotpltent    RelocType = RelocType(33)
+	RelocS390_Pltoff16     RelocType = RelocType(34)
+	RelocS390_Pltoff32     RelocType = RelocType(35)
+	RelocS390_Pltoff64     RelocType = RelocType(36)
+	RelocS390_TlsLoad      RelocType = RelocType(37)
+	RelocS390_TlsGdcall    RelocType = RelocType(38)
+	RelocS390_TlsLdcall    RelocType = RelocType(39)
+	RelocS390_TlsGd32      RelocType = RelocType(40)
+	RelocS390_TlsGd64      RelocType = RelocType(41)
+	RelocS390_TlsGotie12   RelocType = RelocType(42)
+	RelocS390_TlsGotie32   RelocType = RelocType(43)
+	RelocS390_TlsGotie64   RelocType = RelocType(44)
+	RelocS390_TlsLdm32     RelocType = RelocType(45)
+	RelocS390_TlsLdm64     RelocType = RelocType(46)
+	RelocS390_TlsIe32      RelocType = RelocType(47)
+	RelocS390_TlsIe64      RelocType = RelocType(48)
+	RelocS390_TlsIeent     RelocType = RelocType(49)
+	RelocS390_TlsLe32      RelocType = RelocType(50)
+	RelocS390_TlsLe64      RelocType = RelocType(51)
+	RelocS390_TlsLdo32     RelocType = RelocType(52)
+	RelocS390_TlsLdo64     RelocType = RelocType(53)
+	RelocS390_TlsDtpmod    RelocType = RelocType(54)
+	RelocS390_TlsDtpoff    RelocType = RelocType(55)
+	RelocS390_TlsTpoff     RelocType = RelocType(56)
+	RelocS390_20           RelocType = RelocType(57)
+	RelocS390_Got20        RelocType = RelocType(58)
+	RelocS390_Gotplt20     RelocType = RelocType(59)
+	RelocS390_TlsGotie20   RelocType = RelocType(60)
+	RelocS390_Irelative    RelocType = RelocType(61)
+	RelocS390_Pc12dbl      RelocType = RelocType(62)
+	RelocS390_Plt12dbl     RelocType = RelocType(63)
+	RelocS390_Pc24dbl      RelocType = RelocType(64)
+	RelocS390_Plt24dbl     RelocType = RelocType(65)
+	RelocS390_GnuVtinherit RelocType = RelocType(250)
+	RelocS390_GnuVtentry   RelocType = RelocType(251)
+)
+
+var RelocS390Str = map[RelocType]string{
+	RelocS390_None:         "R_390_NONE",
+	RelocS390_8:            "R_390_8",
+	RelocS390_12:           "R_390_12",
+	RelocS390_16:           "R_390_16",
+	RelocS390_32:           "R_390_32",
+	RelocS390_Pc32:         "R_390_PC32",
+	RelocS390_Got12:        "R_390_GOT12",
+	RelocS390_Got32:        "R_390_GOT32",
+	RelocS390_Plt32:        "R_390_PLT32",
+	RelocS390_Copy:         "R_390_COPY",
+	RelocS390_GlobDat:      "R_390_GLOB_DAT",
+	RelocS390_JmpSlot:      "R_390_JMP_SLOT",
+	RelocS390_Relative:     "R_390_RELATIVE",
+	RelocS390_Gotoff32:     "R_390_GOTOFF32",
+	RelocS390_Gotpc:        "R_390_GOTPC",
+	RelocS390_Got16:        "R_390_GOT16",
+	RelocS390_Pc16:         "R_390_PC16",
+	RelocS390_Pc16dbl:      "R_390_PC16DBL",
+	RelocS390_Plt16dbl:     "R_390_PLT16DBL",
+	RelocS390_Pc32dbl:      "R_390_PC32DBL",
+	RelocS390_Plt32dbl:     "R_390_PLT32DBL",
+	RelocS390_Gotpcdbl:     "R_390_GOTPCDBL",
+	RelocS390_64:           "R_390_64",
+	RelocS390_Pc64:         "R_390_PC64",
+	RelocS390_Got64:        "R_390_GOT64",
+	RelocS390_Plt64:        "R_390_PLT64",
+	RelocS390_Gotent:       "R_390_GOTENT",
+	RelocS390_Gotoff16:     "R_390_GOTOFF16",
+	RelocS390_Gotoff64:     "R_390_GOTOFF64",
+	RelocS390_Gotplt12:     "R_390_GOTPLT12",
+	RelocS390_Gotplt16:     "R_390_GOTPLT16",
+	RelocS390_Gotplt32:     "R_390_GOTPLT32",
+	RelocS390_Gotplt64:     "R_390_GOTPLT64",
+	RelocS390_Gotpltent:    "R_390_GOTPLTENT",
+	RelocS390_Pltoff16:     "R_390_PLTOFF16",
+	RelocS390_Pltoff32:     "R_390_PLTOFF32",
+	RelocS390_Pltoff64:     "R_390_PLTOFF64",
+	RelocS390_TlsLoad:      "R_390_TLS_LOAD",
+	RelocS390_TlsGdcall:    "R_390_TLS_GDCALL",
+	RelocS390_TlsLdcall:    "R_390_TLS_LDCALL",
+	RelocS390_TlsGd32:      "R_390_TLS_GD32",
+	RelocS390_TlsGd64:      "R_390_TLS_GD64",
+	RelocS390_TlsGotie12:   "R_390_TLS_GOTIE12",
+	RelocS390_TlsGotie32:   "R_390_TLS_GOTIE32",
+	RelocS390_TlsGotie64:   "R_390_TLS_GOTIE64",
+	RelocS390_TlsLdm32:     "R_390_TLS_LDM32",
+	RelocS390_TlsLdm64:     "R_390_TLS_LDM64",
+	RelocS390_TlsIe32:      "R_390_TLS_IE32",
+	RelocS390_TlsIe64:      "R_390_TLS_IE64",
+	RelocS390_TlsIeent:     "R_390_TLS_IEENT",
+	RelocS390_TlsLe32:      "R_390_TLS_LE32",
+	RelocS390_TlsLe64:      "R_390_TLS_LE64",
+	RelocS390_TlsLdo32:     "R_390_TLS_LDO32",
+	RelocS390_TlsLdo64:     "R_390_TLS_LDO64",
+	RelocS390_TlsDtpmod:    "R_390_TLS_DTPMOD",
+	RelocS390_TlsDtpoff:    "R_390_TLS_DTPOFF",
+	RelocS390_TlsTpoff:     "R_390_TLS_TPOFF",
+	RelocS390_20:           "R_390_20",
+	RelocS390_Got20:        "R_390_GOT20",
+	RelocS390_Gotplt20:     "R_390_GOTPLT20",
+	RelocS390_TlsGotie20:   "R_390_TLS_GOTIE20",
+	RelocS390_Irelative:    "R_390_IRELATIVE",
+	RelocS390_Pc12dbl:      "R_390_PC12DBL",
+	RelocS390_Plt12dbl:     "R_390_PLT12DBL",
+	RelocS390_Pc24dbl:      "R_390_PC24DBL",
+	RelocS390_Plt24dbl:     "R_390_PLT24DBL",
+	RelocS390_GnuVtinherit: "R_390_GNU_VTINHERIT",
+	RelocS390_GnuVtentry:   "R_390_GNU_VTENTRY",
+}
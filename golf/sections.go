@@ -21,8 +21,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
-	"os"
-	"time"
+	"io"
 )
 
 // Value of SectType represent the different types of sections in an ELF file.
@@ -114,17 +113,42 @@ const (
 	SectTypePreInitArray      SectType = SectType(16)
 	SectTypeGroup             SectType = SectType(17)
 	SectTypeExtSectIndeces    SectType = SectType(18)
+	SectTypeRelr              SectType = SectType(19)
 	SectTypeNumDefinedTypes   SectType = SectType(19)
 	SectTypeStartOSSpecific   SectType = SectType(0x60000000)
+	SectTypeGnuHash           SectType = SectType(0x6ffffff6)
+	SectTypeGnuVerDef         SectType = SectType(0x6ffffffd)
+	SectTypeGnuVerNeed        SectType = SectType(0x6ffffffe)
+	SectTypeGnuVerSym         SectType = SectType(0x6fffffff)
 	SectTypeEndOSSpecific     SectType = SectType(0x6fffffff)
 	SectTypeStartProcSpecific SectType = SectType(0x70000000)
+	SectTypeMIPSABIFlags      SectType = SectType(0x7000002a)
 	SectTypeEndProcSpecific   SectType = SectType(0x7fffffff)
 	SectTypeStartAppSpecific  SectType = SectType(0x80000000)
 	SectTypeEndAppSpecific    SectType = SectType(0x8fffffff)
 )
 
+// Flags of a section, found in the flags field of the section header.
 const (
+	SectFlagWrite           uint64 = 0x1
+	SectFlagAlloc           uint64 = 0x2
+	SectFlagExecInstr       uint64 = 0x4
+	SectFlagMerge           uint64 = 0x10
+	SectFlagStrings         uint64 = 0x20
+	SectFlagInfoLink        uint64 = 0x40
+	SectFlagLinkOrder       uint64 = 0x80
+	SectFlagOSNonConforming uint64 = 0x100
+	SectFlagGroup           uint64 = 0x200
+	SectFlagTLS             uint64 = 0x400
+	SectFlagCompressed      uint64 = 0x800
+	SectFlagMaskOS          uint64 = 0x0ff00000
+	SectFlagMaskProc        uint64 = 0xf0000000
+)
+
+const (
+	SectIndexUndefined         uint16 = 0x0000
 	SectIndexSectNameTblExt    uint16 = 0xFFFF
+	SectIndexExtended          uint16 = 0xFFFF
 	SectIndexStartReserved     uint16 = 0xFF00
 	SectIndexStartProcSpecific uint16 = 0xFF00
 	SectIndexStartOSSpecific   uint16 = 0xFF20
@@ -152,6 +176,10 @@ const (
 	// Name of the section which is a string table containing names of symbols
 	// found in the '.dynsym' section.
 	NameDynSymNameTbl = ".dynstr"
+
+	// Name of the section which contains the extended section indeces of
+	// the symbols in the '.symtab' section.
+	NameSymTabSectIndeces = ".symtab_shndx"
 )
 
 type sectHdr32 struct {
@@ -272,20 +300,26 @@ func (sh *sectHdr64) EntrySize() uint64 {
 	return sh.diskData.EntSize
 }
 
-func readSectHdrTbl(f *os.File, header ELFHeader) ([]SectHdr, uint32, error) {
+func readSectHdrTbl(r io.ReaderAt, size int64, header ELFHeader) ([]SectHdr, uint32, error) {
 	elfIdent := header.ELFIdent()
 	class := elfIdent.Class
 	e := elfIdent.Endianess
-	offset := header.SectHdrTblOffset()
-	_, err := f.Seek(int64(offset), 0)
-	if err != nil {
+	offset := int64(header.SectHdrTblOffset())
+	if uint64(offset) > uint64(size) {
+		err := fmt.Errorf(
+			"Section header table offset 0x%x is beyond the end of file.", offset)
 		return nil, 0, err
 	}
+	f := io.NewSectionReader(r, offset, size-offset)
 
+	var err error
 	var sectCount uint64
 	var strTblIndex uint32
 	n := header.SectHdrCount()
-	if n == 0 {
+	if n == 0 && offset == 0 {
+		// There is no section header table, as in core files.
+		return nil, 0, nil
+	} else if n == 0 {
 		if class == Class32 {
 			var sectHdr32 sectHdr32
 			err = binary.Read(f, endianMap[e], &sectHdr32.diskData)
@@ -301,8 +335,8 @@ func readSectHdrTbl(f *os.File, header ELFHeader) ([]SectHdr, uint32, error) {
 			return nil, 0, errors.New("Error reading section header 0.\n" + err.Error())
 		}
 
-		// Reset the file position.
-		_, err = f.Seek(int64(offset), 0)
+		// Reset the reader position.
+		_, err = f.Seek(0, 0)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -311,6 +345,43 @@ func readSectHdrTbl(f *os.File, header ELFHeader) ([]SectHdr, uint32, error) {
 		strTblIndex = uint32(header.StrTblIndex())
 	}
 
+	// The index of the section name string table is stored in the header of
+	// section 0 if it does not fit in the ELF header.
+	if strTblIndex == uint32(SectIndexSectNameTblExt) {
+		if class == Class32 {
+			var sectHdr32 sectHdr32
+			err = binary.Read(f, endianMap[e], &sectHdr32.diskData)
+			strTblIndex = sectHdr32.diskData.Link
+		} else {
+			var sectHdr64 sectHdr64
+			err = binary.Read(f, endianMap[e], &sectHdr64.diskData)
+			strTblIndex = sectHdr64.diskData.Link
+		}
+		if err != nil {
+			return nil, 0, errors.New("Error reading section header 0.\n" + err.Error())
+		}
+
+		_, err = f.Seek(0, 0)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	entrySize := uint64(sectHdr64Size)
+	if class == Class32 {
+		entrySize = sectHdr32Size
+	}
+	if uint64(header.SectHdrTblEntrySize()) != entrySize {
+		err = fmt.Errorf(
+			"Invalid section header size %d.", header.SectHdrTblEntrySize())
+		return nil, 0, err
+	}
+	if sectCount > uint64(size-offset)/entrySize {
+		err = fmt.Errorf(
+			"Section header table with %d entries lies beyond the end of file.", sectCount)
+		return nil, 0, err
+	}
+
 	sectHdrTbl := make([]SectHdr, sectCount)
 	for i := uint64(0); i < sectCount; i++ {
 		if class == Class32 {
@@ -332,11 +403,20 @@ func readSectHdrTbl(f *os.File, header ELFHeader) ([]SectHdr, uint32, error) {
 
 // Section represents a section of an ELF file.
 type Section struct {
-	name     string
-	header   SectHdr
-	data     []byte
-	fileName string
-	modTime  time.Time
+	name   string
+	header SectHdr
+	data   []byte
+	elf    *ELF
+
+	// The header of the section as read from the file. It is nil for
+	// sections added using AddSection.
+	origHeader SectHdr
+
+	// Set to true if the data was replaced using SetData.
+	modified bool
+
+	// Set to true if the section was renamed using SetName.
+	renamed bool
 }
 
 // Returns the header of the section.
@@ -363,81 +443,123 @@ func (section *Section) NewReader() (*bytes.Reader, error) {
 }
 
 // Returns the section data.
-// The section data is cached in memory. Only the first call to Data reads the
-// section data from memory. All subsequent calls return the cached data.
+// If the section is compressed, then the returned data is the decompressed
+// data of the section. Decompressed data is always cached in memory.
+// Otherwise, if the ELF was read using ReadMapped, the returned data is a
+// slice of the memory mapping of the file and should not be modified. In all
+// other cases, the section data is cached in memory. Only the first call to
+// Data reads the section data from the underlying reader. All subsequent
+// calls return the cached data.
 func (section *Section) Data() ([]byte, error) {
-	fileInfo, err := os.Stat(section.fileName)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to stat '%s'.\n%s", section.fileName, err.Error())
-	}
-
-	if section.modTime.Unix() < fileInfo.ModTime().Unix() {
-		err = fmt.Errorf(
-			"File '%s' modified after loading. Cannot read data for section '%s'",
-			section.fileName, section.name)
-		return nil, err
-	}
-
 	if section.data != nil {
 		return section.data, nil
 	}
 
-	file, err := os.Open(section.fileName)
-	if err != nil {
-		err = fmt.Errorf(
-			"Unable to open '%s' to read data for section '%s'.\n%s",
-			section.fileName, section.name, err.Error())
-		return nil, err
-	}
-	defer file.Close()
-
-	_, err = file.Seek(int64(section.header.Offset()), 0)
+	data, err := section.RawData()
 	if err != nil {
-		err = fmt.Errorf(
-			"Unable to seek to section '%s' in '%s' to read raw data.\n%s",
-			section.name, file.Name(), err.Error())
 		return nil, err
 	}
 
-	var data []byte
-	for i := uint64(0); i < section.header.Size(); i++ {
-		var oneByte byte
-		err = binary.Read(file, binary.LittleEndian, &oneByte)
+	if section.IsCompressed() {
+		data, err = section.decompress(data)
 		if err != nil {
-			err = fmt.Errorf(
-				"Error reading raw data from '%s'.\n%s", file.Name(), err.Error())
 			return nil, err
 		}
-		data = append(data, oneByte)
+
+		section.data = data
+		return section.data, nil
+	}
+
+	if section.elf.mapping != nil {
+		// Do not cache slices of the mapping as they become invalid when the
+		// ELF is closed.
+		return data, nil
 	}
 
 	section.data = data
 	return section.data, nil
 }
 
-func newSection(name string, sectHdr SectHdr, fileName string) (*Section, error) {
+// Returns the section data as stored in the file. Unlike Data, compressed
+// section data is returned as is, along with the compression header. The raw
+// data is never cached. For a section whose header was replaced using
+// SetSectHdr, the data is read from the original offset of the section.
+func (section *Section) RawData() ([]byte, error) {
+	offset := section.header.Offset()
+	if section.origHeader != nil {
+		offset = section.origHeader.Offset()
+	}
+
+	data, err := section.elf.readAt(offset, section.header.Size())
+	if err != nil {
+		err = fmt.Errorf(
+			"Error reading raw data of section '%s'.\n%s", section.name, err.Error())
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func newSection(name string, sectHdr SectHdr, elf *ELF) *Section {
 	section := new(Section)
 
 	section.name = name
 	section.header = sectHdr
 	section.data = nil
-	section.fileName = fileName
-
-	fileInfo, err := os.Stat(fileName)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to stat '%s'.\n%s", section.fileName, err.Error())
-	}
-
-	section.modTime = fileInfo.ModTime()
+	section.elf = elf
+	section.origHeader = sectHdr
 
-	return section, nil
+	return section
 }
 
 // StrTbl represents a string table in an ELF file. It is a mapping from byte
 // indeces to strings.
+//
+// Deprecated: StrTbl only maps the offsets at which strings start. Linkers
+// merge strings which are suffixes of other strings, and names at offsets into
+// the middle of a string are missing from the map. Use RawStrTbl instead.
 type StrTbl map[uint32]string
 
+// RawStrTbl represents a string table in an ELF file. Strings are read out on
+// demand from the raw data of the table, so that offsets into the middle of
+// other strings resolve to their suffixes.
+type RawStrTbl struct {
+	data []byte
+}
+
+// Returns a string table over data. The data is not copied.
+func NewRawStrTbl(data []byte) *RawStrTbl {
+	return &RawStrTbl{data}
+}
+
+// Returns the size of the string table in bytes.
+func (t *RawStrTbl) Size() uint32 {
+	return uint32(len(t.data))
+}
+
+// Returns the NULL terminated string starting at offset in the string table.
+func (t *RawStrTbl) ReadStr(offset uint32) (string, error) {
+	if uint64(offset) >= uint64(len(t.data)) {
+		return "", fmt.Errorf("String table offset %d is out of bounds.", offset)
+	}
+
+	end := bytes.IndexByte(t.data[offset:], 0)
+	if end < 0 {
+		return "", fmt.Errorf("String at offset %d is not NULL terminated.", offset)
+	}
+
+	return string(t.data[offset : offset+uint32(end)]), nil
+}
+
+// Returns the string table in data, mapping the offset of each NULL
+// terminated string to the string. Empty strings, like the padding which some
+// linkers add between strings, are mapped as well and do not end the table.
+// Bytes after the last NULL are not part of any string.
 func BuildStrTbl(data []byte) (StrTbl, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("String table is empty.")
+	}
+
 	// Read the first NULL string
 	if data[0] != 0 {
 		err := fmt.Errorf("First byte in the string table is not NULL.")
@@ -445,21 +567,11 @@ func BuildStrTbl(data []byte) (StrTbl, error) {
 	}
 
 	stringMap := make(map[uint32]string)
-	stringMap[uint32(0)] = string(byte(0))
-
-	var str []uint8
+	start := 0
 	for index, char := range data {
-		if index == 0 {
-			continue
-		}
-
-		str = append(str, char)
 		if char == 0 {
-			stringMap[uint32(index-(len(str)-1))] = string(str[0 : len(str)-1])
-			if len(str) == 1 {
-				break
-			}
-			str = nil
+			stringMap[uint32(start)] = string(data[start:index])
+			start = index + 1
 		}
 	}
 
@@ -471,39 +583,55 @@ func BuildStrTbl(data []byte) (StrTbl, error) {
 // slice of sections.
 type SectMap map[string][]*Section
 
-func readSectMap(f *os.File, sectHdrTbl []SectHdr, sectNameTblIndex uint32) (SectMap, error) {
+func readSectMap(
+	elf *ELF, sectHdrTbl []SectHdr, sectNameTblIndex uint32) (SectMap, []*Section, error) {
 	sectMap := make(SectMap, len(sectHdrTbl))
+	if len(sectHdrTbl) == 0 {
+		return sectMap, nil, nil
+	}
 
-	strTblSect, err := newSection("dummy-name", sectHdrTbl[sectNameTblIndex], f.Name())
-	strTblData, err := strTblSect.Data()
-	if err != nil {
-		err = fmt.Errorf(
-			"Error reading string table data from '%s'.\n%s", f.Name(), err.Error())
-		return nil, err
+	if sectNameTblIndex >= uint32(len(sectHdrTbl)) {
+		err := fmt.Errorf(
+			"Section name string table index %d is out of range.", sectNameTblIndex)
+		return nil, nil, err
 	}
-	strTbl, err := BuildStrTbl(strTblData)
-	if err != nil {
-		err = fmt.Errorf(
-			"Unable to build string table from string table data.\n%s",
-			err.Error())
-		return nil, err
+
+	// Sections do not have names if there is no section name string table.
+	var strTbl *RawStrTbl
+	if sectNameTblIndex != uint32(SectIndexUndefined) {
+		if sectHdrTbl[sectNameTblIndex].Type() == SectTypeNoBits {
+			return nil, nil, fmt.Errorf("Section name string table has no data.")
+		}
+
+		strTblSect := newSection("dummy-name", sectHdrTbl[sectNameTblIndex], elf)
+		strTblData, err := strTblSect.Data()
+		if err != nil {
+			err = fmt.Errorf("Error reading section name string table.\n%s", err.Error())
+			return nil, nil, err
+		}
+		if len(strTblData) == 0 {
+			return nil, nil, fmt.Errorf("Section name string table is empty.")
+		}
+		strTbl = NewRawStrTbl(strTblData)
 	}
 
-	for _, sectHdr := range sectHdrTbl {
-		sectName := strTbl[sectHdr.NameIndex()]
+	sections := make([]*Section, len(sectHdrTbl))
+	for i, sectHdr := range sectHdrTbl {
+		// Names with invalid offsets are left empty. Validate reports them.
+		var sectName string
+		if strTbl != nil {
+			sectName, _ = strTbl.ReadStr(sectHdr.NameIndex())
+		}
 		_, exists := sectMap[sectName]
 		if !exists {
 			sectMap[sectName] = make([]*Section, 0)
 		}
-		section, err := newSection(sectName, sectHdr, f.Name())
-		if err != nil {
-			return nil, err
-		}
-
+		section := newSection(sectName, sectHdr, elf)
+		sections[i] = section
 		sectMap[sectName] = append(sectMap[sectName], section)
 	}
 
-	return sectMap, nil
+	return sectMap, sections, nil
 }
 
 // Symbol represents an entry for a symbol in a symbol table of an ELF file.
@@ -621,7 +749,7 @@ func BuildSymTab(data []byte, sectHdr SectHdr, endianess ELFEndianess) (SymTab,
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("Error reading symtab from '%s'.\n%s", err.Error())
+			return nil, fmt.Errorf("Error reading symbol table.\n%s", err.Error())
 		} else {
 			nameIndex := symbol.NameIndex()
 			_, exists := symTab[nameIndex]
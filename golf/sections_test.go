@@ -17,6 +17,8 @@
 package golf
 
 import (
+	"bytes"
+	"reflect"
 	"testing"
 )
 
@@ -103,3 +105,167 @@ func TestSections(t *testing.T) {
 		return
 	}
 }
+
+func TestSectionNames(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_zlib.o")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// Names like '.text' are suffixes of names like '.rel.text', and the
+	// linker stores them only once in the section name string table.
+	expected := []string{
+		"", ".group", ".text", ".rel.text", ".data", ".bss", ".rodata",
+		".data.rel.local", ".rel.data.rel.local", ".text.__x86.get_pc_thunk.ax",
+		".debug_info", ".rel.debug_info", ".debug_abbrev", ".debug_aranges",
+		".rel.debug_aranges", ".debug_line", ".rel.debug_line", ".debug_str",
+		".debug_line_str", ".comment", ".note.GNU-stack", ".eh_frame",
+		".rel.eh_frame", ".symtab", ".strtab", ".shstrtab",
+	}
+	sections := elf.Sections()
+	if len(sections) != len(expected) {
+		t.Errorf("Wrong number of sections: %d", len(sections))
+		return
+	}
+	for i, section := range sections {
+		if section.Name() != expected[i] {
+			t.Errorf("Wrong name of section %d: '%s'", i, section.Name())
+		}
+	}
+	if len(elf.SectMap()[".text"]) != 1 {
+		t.Errorf("Section '.text' is not in the section map.")
+	}
+}
+
+func TestRawStrTbl(t *testing.T) {
+	strTbl := NewRawStrTbl([]byte("\x00.rel.text\x00.data\x00abc"))
+
+	expected := map[uint32]string{
+		0:  "",
+		1:  ".rel.text",
+		5:  ".text",
+		8:  "xt",
+		10: "",
+		11: ".data",
+	}
+	for offset, str := range expected {
+		s, err := strTbl.ReadStr(offset)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if s != str {
+			t.Errorf("Wrong string at offset %d: '%s'", offset, s)
+		}
+	}
+
+	// The last string is not NULL terminated.
+	_, err := strTbl.ReadStr(17)
+	if err == nil {
+		t.Errorf("Expected an error reading a string which is not NULL terminated.")
+	}
+	_, err = strTbl.ReadStr(strTbl.Size())
+	if err == nil {
+		t.Errorf("Expected an error reading beyond the end of the string table.")
+	}
+}
+
+func TestBuildStrTblEmptyStrings(t *testing.T) {
+	// The empty string at offset 7 does not end the table.
+	strTbl, err := BuildStrTbl([]byte("\x00.text\x00\x00.data\x00abc"))
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	expected := StrTbl{0: "", 1: ".text", 7: "", 8: ".data"}
+	if !reflect.DeepEqual(strTbl, expected) {
+		t.Errorf("Wrong string table: %v", strTbl)
+	}
+}
+
+func TestReadMapped(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	mappedELF, err := ReadMapped("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	for name, sections := range elf.SectMap() {
+		mappedSections := mappedELF.SectMap()[name]
+		if len(mappedSections) != len(sections) {
+			t.Errorf("Mismatch in the number of sections named '%s'.", name)
+			continue
+		}
+
+		for i, section := range sections {
+			data, err := section.Data()
+			if err != nil {
+				t.Errorf("Unable to read data of section '%s'.\n%s", name, err.Error())
+				continue
+			}
+
+			mappedData, err := mappedSections[i].Data()
+			if err != nil {
+				t.Errorf(
+					"Unable to read mapped data of section '%s'.\n%s", name, err.Error())
+				continue
+			}
+
+			if !bytes.Equal(data, mappedData) {
+				t.Errorf("Mismatch in the mapped data of section '%s'.", name)
+			}
+		}
+	}
+
+	err = mappedELF.Close()
+	if err != nil {
+		t.Errorf("Error closing mapped ELF.\n%s", err.Error())
+		return
+	}
+
+	_, err = mappedELF.SectMap()[".text"][0].Data()
+	if err == nil {
+		t.Errorf("Expected an error reading section data after Close.")
+	}
+}
+
+func benchmarkSectionData(b *testing.B, read func(string) (*ELF, error)) {
+	for i := 0; i < b.N; i++ {
+		elf, err := read("test_data/linux_x86_64.exe")
+		if err != nil {
+			b.Fatal(err.Error())
+		}
+
+		for _, sections := range elf.SectMap() {
+			for _, section := range sections {
+				if section.SectHdr().Type() == SectTypeNoBits {
+					continue
+				}
+
+				_, err = section.Data()
+				if err != nil {
+					b.Fatal(err.Error())
+				}
+			}
+		}
+
+		elf.Close()
+	}
+}
+
+func BenchmarkSectionData(b *testing.B) {
+	benchmarkSectionData(b, Read)
+}
+
+func BenchmarkSectionDataMapped(b *testing.B) {
+	benchmarkSectionData(b, ReadMapped)
+}
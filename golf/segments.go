@@ -19,7 +19,7 @@ package golf
 import (
 	"encoding/binary"
 	"fmt"
-	"os"
+	"io"
 )
 
 // Set of constants which specify the type of segment in a program/segment
@@ -38,6 +38,7 @@ const (
 	SegTypeGnuEHFrame        = uint32(0x6474e550)
 	SegTypeGnuStack          = uint32(0x6474e551)
 	SegTypeGnuRelRO          = uint32(0x6474e552)
+	SegTypeGnuProperty       = uint32(0x6474e553)
 	SegTypeSunOSStart        = uint32(0x6ffffffa)
 	SegTypeSunWBSS           = uint32(0x6ffffffa)
 	SegTypeSunWStack         = uint32(0x6ffffffb)
@@ -188,12 +189,28 @@ func (hdr *segHdr64) Alignment() uint64 {
 	return hdr.diskData.Alignment
 }
 
-func readSegHdrTbl(file *os.File, header ELFHeader) ([]SegHdr, error) {
-	_, err := file.Seek(int64(header.ProgHdrTblOffset()), 0)
-	if err != nil {
-		err = fmt.Errorf(
-			"Unable to seek to the program header table in '%s'.\n%s",
-			file.Name(), err.Error())
+func readSegHdrTbl(r io.ReaderAt, size int64, header ELFHeader) ([]SegHdr, error) {
+	offset := int64(header.ProgHdrTblOffset())
+	if uint64(offset) > uint64(size) {
+		err := fmt.Errorf(
+			"Program header table offset 0x%x is beyond the end of file.", offset)
+		return nil, err
+	}
+	reader := io.NewSectionReader(r, offset, size-offset)
+
+	count := uint64(header.ProgHdrCount())
+	entrySize := uint64(segHdr64Size)
+	if header.ELFIdent().Class == Class32 {
+		entrySize = segHdr32Size
+	}
+	if count > 0 && uint64(header.ProgHdrTblEntrySize()) != entrySize {
+		err := fmt.Errorf(
+			"Invalid program header size %d.", header.ProgHdrTblEntrySize())
+		return nil, err
+	}
+	if count > uint64(size-offset)/entrySize {
+		err := fmt.Errorf(
+			"Program header table with %d entries lies beyond the end of file.", count)
 		return nil, err
 	}
 
@@ -201,21 +218,19 @@ func readSegHdrTbl(file *os.File, header ELFHeader) ([]SegHdr, error) {
 	for i := uint16(0); i < header.ProgHdrCount(); i++ {
 		endianess := header.ELFIdent().Endianess
 		var hdr SegHdr
+		var err error
 		if header.ELFIdent().Class == Class32 {
 			hdr32 := new(segHdr32)
-			err = binary.Read(file, endianMap[endianess], &hdr32.diskData)
+			err = binary.Read(reader, endianMap[endianess], &hdr32.diskData)
 			hdr = hdr32
 		} else {
 			hdr64 := new(segHdr64)
-			err = binary.Read(file, endianMap[endianess], &hdr64.diskData)
+			err = binary.Read(reader, endianMap[endianess], &hdr64.diskData)
 			hdr = hdr64
 		}
 
 		if err != nil {
-			err = fmt.Errorf(
-				"Error reading segment header from '%s'.\n%s",
-				file.Name(), err.Error())
-			return nil, err
+			return nil, fmt.Errorf("Error reading segment header.\n%s", err.Error())
 		}
 
 		segHdrTbl = append(segHdrTbl, hdr)
@@ -223,3 +238,41 @@ func readSegHdrTbl(file *os.File, header ELFHeader) ([]SegHdr, error) {
 
 	return segHdrTbl, nil
 }
+
+// Returns the data of the segment with header segHdr as present in the ELF
+// file. The size of the returned data is the file size of the segment.
+func (elf *ELF) SegData(segHdr SegHdr) ([]byte, error) {
+	data, err := elf.readAt(segHdr.Offset(), segHdr.FileSize())
+	if err != nil {
+		return nil, fmt.Errorf("Error reading segment data.\n%s", err.Error())
+	}
+
+	return data, nil
+}
+
+// Returns the file offset of the data at virtual address addr. The size bytes
+// starting at addr should all be present in the file part of a single
+// loadable segment.
+func (elf *ELF) addrToOffset(addr uint64, size uint64) (uint64, error) {
+	for _, segHdr := range elf.progHdrTbl {
+		if segHdr.Type() != SegTypeLoad {
+			continue
+		}
+
+		start := segHdr.VirtualAddress()
+		if addr < start || addr-start >= segHdr.FileSize() {
+			continue
+		}
+
+		if size > segHdr.FileSize()-(addr-start) {
+			break
+		}
+
+		return segHdr.Offset() + (addr - start), nil
+	}
+
+	err := fmt.Errorf(
+		"Address range [0x%x, 0x%x) is not in the file part of a loadable segment.",
+		addr, addr+size)
+	return 0, err
+}
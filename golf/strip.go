@@ -0,0 +1,439 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Name of the section which links an ELF file to its separate debug file.
+const NameGnuDebugLink = ".gnu_debuglink"
+
+// Prefixes of the names of sections which are removed by StripDebug.
+var debugSectPrefixes = []string{
+	".debug", ".zdebug", ".gnu.debuglto_", ".line", ".stab", ".gdb_index",
+}
+
+// Returns true if the section with the name holds debug information, like
+// DWARF, stabs or a GDB index.
+func IsDebugSectName(name string) bool {
+	for _, prefix := range debugSectPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Removes the debug sections, as identified by IsDebugSectName, along with the
+// relocation sections which apply to them, like 'strip --strip-debug'. The
+// symbols defined in the removed sections are removed from the symbol table,
+// and the section indeces of the remaining symbols are updated.
+func (elf *ELF) StripDebug() error {
+	remove := make(map[int]bool)
+	for i, section := range elf.sections {
+		if i > 0 && IsDebugSectName(section.name) {
+			remove[i] = true
+		}
+	}
+
+	return elf.removeSections(remove)
+}
+
+// Turns the ELF file into a separate debug file, like
+// 'objcopy --only-keep-debug'. The data of the allocated sections, other than
+// notes, is dropped by making them of type SectTypeNoBits. Their headers are
+// kept so that the debug file describes the same address space as the
+// original file. The file sizes of the segments are reduced to cover only the
+// data which remains in the file.
+func (elf *ELF) OnlyKeepDebug() error {
+	for i, section := range elf.sections {
+		fields := SectHdrFieldsOf(section.header)
+		if i == 0 || fields.Flags&SectFlagAlloc == 0 ||
+			fields.Type == SectTypeNoBits || fields.Type == SectTypeNotes {
+			continue
+		}
+
+		fields.Type = SectTypeNoBits
+		hdr, err := NewSectHdr(section.header.Class(), fields)
+		if err != nil {
+			return err
+		}
+		section.SetSectHdr(hdr)
+	}
+
+	// The parts of the file which remain in the segments are the headers and
+	// the sections which still have data.
+	class := elf.header.ELFIdent().Class
+	kept := []extent{{0, uint64(elf.header.HeaderSize())}}
+	kept = append(kept, extent{
+		elf.header.ProgHdrTblOffset(),
+		uint64(elf.header.ProgHdrCount()) * uint64(elf.header.ProgHdrTblEntrySize())})
+	for _, section := range elf.sections {
+		if section.header.Type() != SectTypeNoBits {
+			kept = append(kept, extent{section.header.Offset(), section.header.Size()})
+		}
+	}
+
+	progHdrTbl := make([]SegHdr, len(elf.progHdrTbl))
+	for i, segHdr := range elf.progHdrTbl {
+		fields := SegHdrFieldsOf(segHdr)
+		seg := extent{fields.Offset, fields.FileSize}
+
+		fileSize := uint64(0)
+		for _, e := range kept {
+			if e.size > 0 && e.offset >= seg.offset && e.end() <= seg.end() &&
+				e.end()-seg.offset > fileSize {
+				fileSize = e.end() - seg.offset
+			}
+		}
+		fields.FileSize = fileSize
+
+		hdr, err := NewSegHdr(class, fields)
+		if err != nil {
+			return err
+		}
+		progHdrTbl[i] = hdr
+	}
+	elf.SetProgHdrTbl(progHdrTbl)
+
+	return nil
+}
+
+// Adds a '.gnu_debuglink' section which links the ELF file to the separate
+// debug file debugFileName, like 'objcopy --add-gnu-debuglink'. The section
+// holds the base name of the debug file and the CRC32 of its contents, so the
+// debug file should be in its final form when this is called.
+func (elf *ELF) AddGnuDebugLink(debugFileName string) error {
+	if _, exists := elf.sectMap[NameGnuDebugLink]; exists {
+		return fmt.Errorf("The ELF file already has a '%s' section.", NameGnuDebugLink)
+	}
+
+	contents, err := ioutil.ReadFile(debugFileName)
+	if err != nil {
+		return fmt.Errorf("Unable to read debug file '%s'.\n%s", debugFileName, err.Error())
+	}
+
+	data := GnuDebugLinkData(filepath.Base(debugFileName), crc32.ChecksumIEEE(contents), elf)
+	hdr, err := NewSectHdr(elf.header.ELFIdent().Class, SectHdrFields{
+		Type:      SectTypeProgBits,
+		Alignment: 4,
+	})
+	if err != nil {
+		return err
+	}
+
+	elf.AddSection(NameGnuDebugLink, hdr, data)
+	return nil
+}
+
+// Returns the contents of a '.gnu_debuglink' section of the ELF file, which is
+// the NULL terminated name padded to a multiple of 4 bytes, followed by the
+// CRC32 in the byte order of the ELF file.
+func GnuDebugLinkData(name string, crc uint32, elf *ELF) []byte {
+	size := alignUp(uint64(len(name))+1, 4)
+	data := make([]byte, size+4)
+	copy(data, name)
+	elf.Endianess().PutUint32(data[size:], crc)
+
+	return data
+}
+
+// Returns the name of the separate debug file and its CRC32 recorded in the
+// '.gnu_debuglink' section of the ELF file. Returns an empty name if the file
+// does not have such a section.
+func (elf *ELF) GnuDebugLink() (string, uint32, error) {
+	sections, exists := elf.sectMap[NameGnuDebugLink]
+	if !exists {
+		return "", 0, nil
+	}
+
+	data, err := sections[0].Data()
+	if err != nil {
+		err = fmt.Errorf("Error reading '%s'.\n%s", NameGnuDebugLink, err.Error())
+		return "", 0, err
+	}
+
+	name, err := NewRawStrTbl(data).ReadStr(0)
+	if err != nil {
+		err = fmt.Errorf("Invalid debug file name in '%s'.\n%s", NameGnuDebugLink, err.Error())
+		return "", 0, err
+	}
+	if name == "" {
+		return "", 0, fmt.Errorf("Empty debug file name in '%s'.", NameGnuDebugLink)
+	}
+
+	crcOffset := alignUp(uint64(len(name))+1, 4)
+	if crcOffset+4 > uint64(len(data)) {
+		return "", 0, fmt.Errorf("CRC is missing in '%s'.", NameGnuDebugLink)
+	}
+
+	return name, elf.Endianess().Uint32(data[crcOffset:]), nil
+}
+
+// Removes the sections at the indeces in remove, along with the relocation
+// sections which apply to them. Symbol tables, relocation sections and
+// section groups which refer to sections or symbols by index are updated.
+func (elf *ELF) removeSections(remove map[int]bool) error {
+	for i, section := range elf.sections {
+		hdr := section.header
+		if (hdr.Type() == SectTypeRel || hdr.Type() == SectTypeRelA) && remove[int(hdr.Info())] {
+			remove[i] = true
+		}
+	}
+	if len(remove) == 0 {
+		return nil
+	}
+	if remove[int(elf.sectNameTblIndex)] {
+		return fmt.Errorf("The section name string table cannot be removed.")
+	}
+
+	// Mapping from the old section indeces to the new ones. Removed sections
+	// map to SectIndexUndefined.
+	sectIndexMap := make([]uint32, len(elf.sections))
+	next := uint32(0)
+	for i := range elf.sections {
+		if remove[i] {
+			continue
+		}
+		sectIndexMap[i] = next
+		next++
+	}
+
+	for i, section := range elf.sections {
+		if remove[i] {
+			continue
+		}
+
+		var err error
+		switch section.header.Type() {
+		case SectTypeExtSectIndeces:
+			err = fmt.Errorf("Removing sections from ELF files with extended section " +
+				"indeces is not supported.")
+		case SectTypeSymTab, SectTypeDynSym:
+			err = elf.remapSymbols(i, sectIndexMap, remove)
+		case SectTypeGroup:
+			err = elf.remapGroup(section, sectIndexMap)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	indeces := make([]int, 0, len(remove))
+	for i := range remove {
+		indeces = append(indeces, i)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(indeces)))
+	for _, i := range indeces {
+		err := elf.RemoveSection(elf.sections[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Updates the section indeces of the symbols in the symbol table section at
+// index symTabIndex. Symbols defined in removed sections are dropped from the
+// symbol table (but not from the dynamic symbol table, where that is an
+// error), in which case the relocation sections linked to the symbol table
+// are updated as well.
+func (elf *ELF) remapSymbols(symTabIndex int, sectIndexMap []uint32, remove map[int]bool) error {
+	section := elf.sections[symTabIndex]
+	hdr := section.header
+	entrySize := hdr.EntrySize()
+	class := hdr.Class()
+	endianess := elf.Endianess()
+
+	var infoOffset, sectIndexOffset uint64
+	if class == Class32 {
+		infoOffset, sectIndexOffset = 12, 14
+	} else {
+		infoOffset, sectIndexOffset = 4, 6
+	}
+	if entrySize < sectIndexOffset+2 {
+		return fmt.Errorf("Invalid entry size %d of symbol table '%s'.", entrySize, section.Name())
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		return err
+	}
+
+	count := uint64(len(data)) / entrySize
+	newData := make([]byte, 0, len(data))
+	symIndexMap := make([]uint32, count)
+	numLocal := uint32(0)
+	for i := uint64(0); i < count; i++ {
+		entry := append([]byte(nil), data[i*entrySize:(i+1)*entrySize]...)
+		sectIndex := endianess.Uint16(entry[sectIndexOffset:])
+		if sectIndex != SectIndexUndefined && sectIndex < SectIndexStartReserved {
+			if int(sectIndex) >= len(sectIndexMap) {
+				return fmt.Errorf(
+					"Invalid section index %d of symbol %d in '%s'.", sectIndex, i, section.Name())
+			}
+
+			if remove[int(sectIndex)] {
+				if hdr.Type() == SectTypeDynSym {
+					return fmt.Errorf(
+						"Dynamic symbol %d is defined in a removed section.", i)
+				}
+				continue
+			}
+			endianess.PutUint16(entry[sectIndexOffset:], uint16(sectIndexMap[sectIndex]))
+		}
+
+		symIndexMap[i] = uint32(len(newData) / int(entrySize))
+		if SymInfoBinding(entry[infoOffset]) == SymBindingLocal {
+			numLocal = symIndexMap[i] + 1
+		}
+		newData = append(newData, entry...)
+	}
+	section.SetData(newData)
+
+	if uint64(len(newData)) == uint64(len(data)) {
+		return nil
+	}
+
+	// Symbols were dropped. The info field of the symbol table is the index
+	// of its first non-local symbol.
+	fields := SectHdrFieldsOf(hdr)
+	fields.Info = numLocal
+	newHdr, err := NewSectHdr(class, fields)
+	if err != nil {
+		return err
+	}
+	section.SetSectHdr(newHdr)
+
+	for i, s := range elf.sections {
+		if remove[i] || s.header.Link() != uint32(symTabIndex) {
+			continue
+		}
+
+		switch s.header.Type() {
+		case SectTypeRel, SectTypeRelA:
+			err = elf.remapRelocSymbols(s, symIndexMap, count)
+		case SectTypeGroup:
+			// The info field of a section group is the index of its
+			// signature symbol.
+			err = remapGroupSignature(s, symIndexMap)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Updates the symbol indeces of the relocations in the relocation section.
+func (elf *ELF) remapRelocSymbols(section *Section, symIndexMap []uint32, numSymbols uint64) error {
+	data, err := section.Data()
+	if err != nil {
+		return err
+	}
+	data = append([]byte(nil), data...)
+
+	entrySize := section.header.EntrySize()
+	if entrySize == 0 {
+		return fmt.Errorf("Invalid entry size of relocation section '%s'.", section.Name())
+	}
+
+	endianess := elf.Endianess()
+	for offset := uint64(0); offset+entrySize <= uint64(len(data)); offset += entrySize {
+		var symIndex uint64
+		if section.header.Class() == Class32 {
+			info := endianess.Uint32(data[offset+4:])
+			symIndex = uint64(info >> 8)
+			if symIndex < numSymbols {
+				info = symIndexMap[symIndex]<<8 | info&0xff
+				endianess.PutUint32(data[offset+4:], info)
+			}
+		} else {
+			info := data[offset+8:]
+			index, _ := elf.relocInfo64(info)
+			symIndex = uint64(index)
+			if symIndex < numSymbols {
+				elf.setRelocSymIndex64(info, symIndexMap[symIndex])
+			}
+		}
+
+		if symIndex >= numSymbols || (symIndex != 0 && symIndexMap[symIndex] == 0) {
+			err = fmt.Errorf(
+				"Relocation at offset 0x%x in '%s' refers to a removed symbol.",
+				offset, section.Name())
+			return err
+		}
+	}
+
+	section.SetData(data)
+	return nil
+}
+
+// Updates the section indeces of the members of the section group.
+func (elf *ELF) remapGroup(section *Section, sectIndexMap []uint32) error {
+	data, err := section.Data()
+	if err != nil {
+		return err
+	}
+
+	endianess := elf.Endianess()
+	newData := append([]byte(nil), data[:minUint64(4, uint64(len(data)))]...)
+	for offset := 4; offset+4 <= len(data); offset += 4 {
+		index := endianess.Uint32(data[offset:])
+		if index >= uint32(len(sectIndexMap)) {
+			return fmt.Errorf("Invalid member %d of section group '%s'.", index, section.Name())
+		}
+		if sectIndexMap[index] == 0 {
+			continue
+		}
+
+		word := make([]byte, 4)
+		endianess.PutUint32(word, sectIndexMap[index])
+		newData = append(newData, word...)
+	}
+
+	section.SetData(newData)
+	return nil
+}
+
+// Updates the index of the signature symbol of the section group.
+func remapGroupSignature(section *Section, symIndexMap []uint32) error {
+	fields := SectHdrFieldsOf(section.header)
+	if uint64(fields.Info) >= uint64(len(symIndexMap)) ||
+		(fields.Info != 0 && symIndexMap[fields.Info] == 0) {
+		return fmt.Errorf("Signature of section group '%s' refers to a removed symbol.",
+			section.Name())
+	}
+	fields.Info = symIndexMap[fields.Info]
+
+	hdr, err := NewSectHdr(section.header.Class(), fields)
+	if err != nil {
+		return err
+	}
+
+	section.SetSectHdr(hdr)
+	return nil
+}
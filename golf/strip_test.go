@@ -0,0 +1,349 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Returns the ELF file written out by elf.
+func writeAndReload(elf *ELF) (*ELF, error) {
+	var buf bytes.Buffer
+	err := elf.Write(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
+
+func TestIsDebugSectName(t *testing.T) {
+	debugNames := []string{".debug_info", ".zdebug_line", ".stab", ".stabstr", ".gdb_index"}
+	for _, name := range debugNames {
+		if !IsDebugSectName(name) {
+			t.Errorf("'%s' is not treated as a debug section.", name)
+		}
+	}
+
+	otherNames := []string{".text", ".data", ".comment", ".symtab", ".gnu_debuglink"}
+	for _, name := range otherNames {
+		if IsDebugSectName(name) {
+			t.Errorf("'%s' is treated as a debug section.", name)
+		}
+	}
+}
+
+func TestStripDebugExe(t *testing.T) {
+	const fileName = "../garf/test_data/single_cu_linux_x86_64.exe"
+	elf, err := Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	symbols, err := elf.Symbols()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	err = elf.StripDebug()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	stripped, err := writeAndReload(elf)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The executable has 35 sections, 5 of which are debug sections.
+	if len(stripped.Sections()) != 35-5 {
+		t.Errorf("Wrong number of sections in the stripped file: %d",
+			len(stripped.Sections()))
+		return
+	}
+	if stripped.Size() >= elf.Size() {
+		t.Errorf("Size of the file did not shrink after stripping.")
+		return
+	}
+
+	// The debug sections are sections 27 to 31, and the only symbols defined
+	// in them are their section symbols. No other symbols are defined in the
+	// sections which follow them.
+	var expectedSymbols []ResolvedSymbol
+	for _, sym := range symbols {
+		if sym.SectIndex < 27 || sym.SectIndex > 31 {
+			expectedSymbols = append(expectedSymbols, sym)
+		}
+	}
+	if len(expectedSymbols) != len(symbols)-5 {
+		t.Errorf("Wrong number of symbols in debug sections.")
+		return
+	}
+
+	strippedSymbols, err := stripped.Symbols()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(strippedSymbols) != len(expectedSymbols) {
+		t.Errorf("Wrong number of symbols in the stripped file: %d", len(strippedSymbols))
+		return
+	}
+	for i, sym := range strippedSymbols {
+		expected := expectedSymbols[i]
+		if sym.Name != expected.Name || sym.Value != expected.Value ||
+			sym.SectIndex != expected.SectIndex {
+			t.Errorf("Symbol %d was changed by stripping.", i)
+			return
+		}
+	}
+}
+
+func TestStripDebugObject(t *testing.T) {
+	const fileName = "test_data/linux_x86_zlib.o"
+	elf, err := Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	err = elf.StripDebug()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	stripped, err := writeAndReload(elf)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The object has 26 sections. 6 are debug sections, 3 of which have
+	// relocation sections.
+	if len(stripped.Sections()) != 26-9 {
+		t.Errorf("Wrong number of sections in the stripped file: %d",
+			len(stripped.Sections()))
+		return
+	}
+
+	symTab := stripped.sectionOfType(SectTypeSymTab)
+	if symTab == nil {
+		t.Errorf("Symbol table is missing in the stripped file.")
+		return
+	}
+	numSymbols := symTab.SectHdr().Size() / symTab.SectHdr().EntrySize()
+
+	symbols, err := stripped.Symbols()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	for _, sym := range symbols {
+		if sym.SectIndex >= uint32(len(stripped.Sections())) &&
+			sym.SectIndex < uint32(SectIndexStartReserved) {
+			t.Errorf("Symbol '%s' has an invalid section index %d.", sym.Name, sym.SectIndex)
+			return
+		}
+	}
+
+	for i, section := range stripped.Sections() {
+		hdr := section.SectHdr()
+		switch hdr.Type() {
+		case SectTypeRel:
+			if hdr.Info() >= uint32(len(stripped.Sections())) {
+				t.Errorf("Relocation section %d applies to an invalid section.", i)
+				return
+			}
+
+			data, err := section.Data()
+			if err != nil {
+				t.Error(err.Error())
+				return
+			}
+			for offset := 0; offset+8 <= len(data); offset += 8 {
+				symIndex := stripped.Endianess().Uint32(data[offset+4:]) >> 8
+				if uint64(symIndex) >= numSymbols {
+					t.Errorf("Relocation in section %d refers to an invalid symbol.", i)
+					return
+				}
+			}
+		case SectTypeGroup:
+			if uint64(hdr.Info()) >= numSymbols {
+				t.Errorf("Signature of section group %d is an invalid symbol.", i)
+				return
+			}
+		}
+	}
+}
+
+func TestOnlyKeepDebug(t *testing.T) {
+	const fileName = "../garf/test_data/single_cu_linux_x86_64.exe"
+	elf, err := Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	var memSizes []uint64
+	for _, segHdr := range elf.ProgHdrTbl() {
+		memSizes = append(memSizes, segHdr.MemSize())
+	}
+
+	err = elf.OnlyKeepDebug()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	debug, err := writeAndReload(elf)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if debug.Size() >= elf.Size() {
+		t.Errorf("Size of the debug file is not less than that of the original file.")
+		return
+	}
+
+	for i, section := range debug.Sections() {
+		hdr := section.SectHdr()
+		if hdr.Flags()&SectFlagAlloc == 0 {
+			continue
+		}
+		if hdr.Type() != SectTypeNoBits && hdr.Type() != SectTypeNotes {
+			t.Errorf("Allocated section %d still has data in the debug file.", i)
+			return
+		}
+	}
+
+	// The numbers match those of 'objcopy --only-keep-debug'.
+	expectedFileSizes := []uint64{0x1f8, 0, 0x298, 0, 0, 0x44, 0, 0, 0}
+	progHdrTbl := debug.ProgHdrTbl()
+	if len(progHdrTbl) != len(expectedFileSizes) {
+		t.Errorf("Wrong number of segments in the debug file: %d", len(progHdrTbl))
+		return
+	}
+	for i, segHdr := range progHdrTbl {
+		if segHdr.FileSize() != expectedFileSizes[i] {
+			t.Errorf("Wrong file size of segment %d: 0x%x", i, segHdr.FileSize())
+		}
+		if segHdr.MemSize() != memSizes[i] {
+			t.Errorf("Memory size of segment %d was changed.", i)
+		}
+	}
+
+	notes, err := debug.Notes()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(notes) == 0 {
+		t.Errorf("Notes are missing in the debug file.")
+	}
+}
+
+func TestAddGnuDebugLink(t *testing.T) {
+	const fileName = "test_data/linux_x86_64.exe"
+	elf, err := Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	dir, err := ioutil.TempDir("", "golf")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	debugData := []byte("golf debug data")
+	debugFileName := filepath.Join(dir, "linux_x86_64.exe.debug")
+	err = ioutil.WriteFile(debugFileName, debugData, 0644)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	err = elf.AddGnuDebugLink(debugFileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	err = elf.AddGnuDebugLink(debugFileName)
+	if err == nil {
+		t.Errorf("Second debug link was added.")
+		return
+	}
+
+	linked, err := writeAndReload(elf)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	sections, exists := linked.SectMap()[NameGnuDebugLink]
+	if !exists {
+		t.Errorf("Section '%s' is missing.", NameGnuDebugLink)
+		return
+	}
+	data, err := sections[0].Data()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	expected := []byte("linux_x86_64.exe.debug\x00\x00")
+	if !bytes.Equal(data[:len(expected)], expected) || len(data) != len(expected)+4 {
+		t.Errorf("Wrong debug link data: %v", data)
+		return
+	}
+	crc := linked.Endianess().Uint32(data[len(expected):])
+	if crc != crc32.ChecksumIEEE(debugData) {
+		t.Errorf("Wrong debug link CRC: 0x%x", crc)
+		return
+	}
+
+	name, crc, err := linked.GnuDebugLink()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if name != "linux_x86_64.exe.debug" || crc != crc32.ChecksumIEEE(debugData) {
+		t.Errorf("Wrong debug link: '%s', 0x%x", name, crc)
+		return
+	}
+	name, _, err = elf.GnuDebugLink()
+	if err != nil || name != "linux_x86_64.exe.debug" {
+		t.Errorf("Wrong debug link of the edited ELF: '%s'", name)
+	}
+}
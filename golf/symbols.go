@@ -0,0 +1,435 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// Values of type SymBinding represent the binding of a symbol.
+type SymBinding uint8
+
+// Values of type SymType represent the type of a symbol.
+type SymType uint8
+
+// Values of type SymVisibility represent the visibility of a symbol.
+type SymVisibility uint8
+
+const (
+	SymBindingLocal             SymBinding = SymBinding(0)
+	SymBindingGlobal            SymBinding = SymBinding(1)
+	SymBindingWeak              SymBinding = SymBinding(2)
+	SymBindingStartOSSpecific   SymBinding = SymBinding(10)
+	SymBindingGnuUnique         SymBinding = SymBinding(10)
+	SymBindingEndOSSpecific     SymBinding = SymBinding(12)
+	SymBindingStartProcSpecific SymBinding = SymBinding(13)
+	SymBindingEndProcSpecific   SymBinding = SymBinding(15)
+)
+
+const (
+	SymTypeNone              SymType = SymType(0)
+	SymTypeObject            SymType = SymType(1)
+	SymTypeFunc              SymType = SymType(2)
+	SymTypeSection           SymType = SymType(3)
+	SymTypeFile              SymType = SymType(4)
+	SymTypeCommon            SymType = SymType(5)
+	SymTypeTLS               SymType = SymType(6)
+	SymTypeStartOSSpecific   SymType = SymType(10)
+	SymTypeGnuIFunc          SymType = SymType(10)
+	SymTypeEndOSSpecific     SymType = SymType(12)
+	SymTypeStartProcSpecific SymType = SymType(13)
+	SymTypeEndProcSpecific   SymType = SymType(15)
+)
+
+const (
+	SymVisibilityDefault   SymVisibility = SymVisibility(0)
+	SymVisibilityInternal  SymVisibility = SymVisibility(1)
+	SymVisibilityHidden    SymVisibility = SymVisibility(2)
+	SymVisibilityProtected SymVisibility = SymVisibility(3)
+)
+
+// Returns the binding encoded in the info field of a symbol table entry.
+func SymInfoBinding(info uint8) SymBinding {
+	return SymBinding(info >> 4)
+}
+
+// Returns the symbol type encoded in the info field of a symbol table entry.
+func SymInfoType(info uint8) SymType {
+	return SymType(info & 0xf)
+}
+
+// Returns the visibility encoded in the other field (which is what the
+// Visibility method of Symbol returns) of a symbol table entry.
+func SymOtherVisibility(other uint8) SymVisibility {
+	return SymVisibility(other & 0x3)
+}
+
+// ResolvedSymbol is a symbol table entry with its name looked up in the
+// linked string table and its info fields decoded.
+type ResolvedSymbol struct {
+	// The name of the symbol.
+	Name string
+
+	// The value (typically the address) of the symbol.
+	Value uint64
+
+	// The size of the symbol.
+	Size uint64
+
+	Binding    SymBinding
+	Type       SymType
+	Visibility SymVisibility
+
+	// The index of the section in which this symbol is defined. Extended
+	// section indeces are resolved using the SHT_SYMTAB_SHNDX section. For
+	// symbols which are not defined in a section, it is one of the reserved
+	// indeces like SectIndexUndefined, SectIndexAbsSym or SectIndexCommonSym.
+	SectIndex uint32
+
+	// The version of the symbol, like 'GLIBC_2.14'. It is set only for
+	// symbols in the dynamic symbol table of ELF files with a '.gnu.version'
+	// section, and only if the symbol is not local or global (unversioned).
+	Version string
+
+	// True if the version of the symbol is hidden. For defined symbols, a
+	// version which is not hidden is the default version of the symbol.
+	VersionHidden bool
+
+	// The needed library which is required to provide the version of the
+	// symbol, like 'libc.so.6', if the version is one of the version
+	// requirements in the '.gnu.version_r' section. Besides undefined symbols,
+	// these include the symbols which the executable defines by copying them
+	// from a library with copy relocations.
+	VersionFile string
+}
+
+// Returns true if the symbol is defined in the ELF file.
+func (sym *ResolvedSymbol) IsDefined() bool {
+	return sym.SectIndex != uint32(SectIndexUndefined)
+}
+
+// symTabIndex is an index over the symbols of a symbol table to speed up
+// look ups by name and address.
+type symTabIndex struct {
+	symbols []ResolvedSymbol
+
+	// Mapping from names to indeces in symbols.
+	byName map[string][]int
+
+	// Indeces into symbols of the symbols which occupy space, sorted by
+	// value.
+	byAddr []int
+
+	// maxEnd[i] is the largest end address of the symbols byAddr[0..i].
+	maxEnd []uint64
+}
+
+func newSymTabIndex(symbols []ResolvedSymbol) *symTabIndex {
+	index := new(symTabIndex)
+	index.symbols = symbols
+	index.byName = make(map[string][]int)
+
+	for i, sym := range symbols {
+		if sym.Name != "" {
+			index.byName[sym.Name] = append(index.byName[sym.Name], i)
+		}
+
+		if sym.Size == 0 || !sym.IsDefined() {
+			continue
+		}
+		if sym.Type == SymTypeSection || sym.Type == SymTypeFile {
+			continue
+		}
+		index.byAddr = append(index.byAddr, i)
+	}
+
+	sort.SliceStable(index.byAddr, func(i, j int) bool {
+		return symbols[index.byAddr[i]].Value < symbols[index.byAddr[j]].Value
+	})
+
+	index.maxEnd = make([]uint64, len(index.byAddr))
+	var maxEnd uint64
+	for i, symIndex := range index.byAddr {
+		sym := symbols[symIndex]
+		if sym.Value+sym.Size > maxEnd {
+			maxEnd = sym.Value + sym.Size
+		}
+		index.maxEnd[i] = maxEnd
+	}
+
+	return index
+}
+
+func (index *symTabIndex) lookupName(name string) []ResolvedSymbol {
+	var symbols []ResolvedSymbol
+	for _, i := range index.byName[name] {
+		symbols = append(symbols, index.symbols[i])
+	}
+
+	return symbols
+}
+
+func (index *symTabIndex) lookupAddr(addr uint64) *ResolvedSymbol {
+	// Find the first symbol which starts after addr, and walk back from
+	// there. The innermost symbol containing addr is found first. We can stop
+	// walking back as soon as no earlier symbol extends beyond addr.
+	n := sort.Search(len(index.byAddr), func(i int) bool {
+		return index.symbols[index.byAddr[i]].Value > addr
+	})
+	for i := n - 1; i >= 0 && index.maxEnd[i] > addr; i-- {
+		sym := &index.symbols[index.byAddr[i]]
+		if addr < sym.Value+sym.Size {
+			return sym
+		}
+	}
+
+	return nil
+}
+
+// Returns the symbols in the symbol table section (the section of type
+// SectTypeSymTab, which is typically named '.symtab'). The symbol at index i
+// in the returned slice is the symbol at index i in the symbol table. Hence,
+// the first symbol is always the null symbol. If the ELF file does not have a
+// symbol table, then an empty slice is returned.
+//
+// The returned slice is cached and shared across calls. It should not be
+// modified.
+func (elf *ELF) Symbols() ([]ResolvedSymbol, error) {
+	if elf.symTab == nil {
+		index, err := elf.readSymTabIndex(SectTypeSymTab)
+		if err != nil {
+			return nil, err
+		}
+		elf.symTab = index
+	}
+
+	return elf.symTab.symbols, nil
+}
+
+// Returns the symbols in the dynamic symbol table section (the section of type
+// SectTypeDynSym, which is typically named '.dynsym'). As with Symbols, the
+// symbol at index i in the returned slice is the symbol at index i in the
+// dynamic symbol table. If the ELF file has symbol versioning sections, then
+// the versions of the symbols are also resolved. If the ELF file does not have
+// a dynamic symbol table, then an empty slice is returned.
+//
+// The returned slice is cached and shared across calls. It should not be
+// modified.
+func (elf *ELF) DynamicSymbols() ([]ResolvedSymbol, error) {
+	if elf.dynSymTab == nil {
+		index, err := elf.readSymTabIndex(SectTypeDynSym)
+		if err != nil {
+			return nil, err
+		}
+
+		err = elf.resolveSymbolVersions(index.symbols)
+		if err != nil {
+			return nil, err
+		}
+		elf.dynSymTab = index
+	}
+
+	return elf.dynSymTab.symbols, nil
+}
+
+// Returns all symbols with the given name. The symbol table is searched first
+// and, only if no symbol is found there, the dynamic symbol table is searched.
+// An empty slice is returned if no symbol with the name exists.
+func (elf *ELF) LookupSymbol(name string) ([]ResolvedSymbol, error) {
+	_, err := elf.Symbols()
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := elf.symTab.lookupName(name)
+	if len(symbols) > 0 {
+		return symbols, nil
+	}
+
+	_, err = elf.DynamicSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	return elf.dynSymTab.lookupName(name), nil
+}
+
+// Returns the symbol whose extent [Value, Value+Size) contains addr. If more
+// than one symbol contains addr, the one starting closest to addr is returned.
+// The symbol table is searched first and the dynamic symbol table is searched
+// only if no symbol is found in the symbol table. Returns nil if no symbol
+// contains addr.
+func (elf *ELF) LookupSymbolByAddr(addr uint64) (*ResolvedSymbol, error) {
+	_, err := elf.Symbols()
+	if err != nil {
+		return nil, err
+	}
+
+	sym := elf.symTab.lookupAddr(addr)
+	if sym != nil {
+		return sym, nil
+	}
+
+	_, err = elf.DynamicSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	return elf.dynSymTab.lookupAddr(addr), nil
+}
+
+func (elf *ELF) readSymTabIndex(sectType SectType) (*symTabIndex, error) {
+	for i, section := range elf.sections {
+		if section.SectHdr().Type() != sectType {
+			continue
+		}
+
+		symbols, err := elf.readResolvedSymbols(uint32(i))
+		if err != nil {
+			return nil, err
+		}
+
+		return newSymTabIndex(symbols), nil
+	}
+
+	return newSymTabIndex(nil), nil
+}
+
+// Reads the symbols from the symbol table section at index sectIndex in the
+// section header table.
+func (elf *ELF) readResolvedSymbols(sectIndex uint32) ([]ResolvedSymbol, error) {
+	section := elf.sections[sectIndex]
+	rawSymbols, err := readSymbols(section, elf.Header().ELFIdent().Endianess)
+	if err != nil {
+		return nil, err
+	}
+
+	link := section.SectHdr().Link()
+	if link >= uint32(len(elf.sections)) {
+		err = fmt.Errorf(
+			"Invalid string table index %d linked to symbol table '%s'.",
+			link, section.Name())
+		return nil, err
+	}
+
+	strData, err := elf.sections[link].Data()
+	if err != nil {
+		err = fmt.Errorf(
+			"Error reading string table of symbol table '%s'.\n%s",
+			section.Name(), err.Error())
+		return nil, err
+	}
+	strTbl := NewRawStrTbl(strData)
+
+	var xindeces []byte
+	for _, s := range elf.sections {
+		hdr := s.SectHdr()
+		if hdr.Type() == SectTypeExtSectIndeces && hdr.Link() == sectIndex {
+			xindeces, err = s.Data()
+			if err != nil {
+				err = fmt.Errorf(
+					"Error reading extended section indeces of '%s'.\n%s",
+					section.Name(), err.Error())
+				return nil, err
+			}
+			break
+		}
+	}
+
+	symbols := make([]ResolvedSymbol, len(rawSymbols))
+	for i, raw := range rawSymbols {
+		sym := &symbols[i]
+
+		sym.Name, err = strTbl.ReadStr(raw.NameIndex())
+		if err != nil {
+			err = fmt.Errorf(
+				"Error reading name of symbol %d in '%s'.\n%s",
+				i, section.Name(), err.Error())
+			return nil, err
+		}
+
+		sym.Value = raw.Addr()
+		sym.Size = raw.Size()
+		sym.Binding = SymInfoBinding(raw.Info())
+		sym.Type = SymInfoType(raw.Info())
+		sym.Visibility = SymOtherVisibility(raw.Visibility())
+		sym.SectIndex = uint32(raw.SectIndex())
+
+		if raw.SectIndex() == SectIndexExtended {
+			offset := 4 * uint64(i)
+			if xindeces == nil || offset+4 > uint64(len(xindeces)) {
+				err = fmt.Errorf(
+					"Missing extended section index for symbol %d in '%s'.",
+					i, section.Name())
+				return nil, err
+			}
+			sym.SectIndex = elf.Endianess().Uint32(xindeces[offset:])
+		}
+	}
+
+	return symbols, nil
+}
+
+// Reads the raw symbol table entries from the symbol table section.
+func readSymbols(section *Section, endianess ELFEndianess) ([]Symbol, error) {
+	sectHdr := section.SectHdr()
+	entrySize := sectHdr.EntrySize()
+	var minSize uint64
+	if sectHdr.Class() == Class32 {
+		minSize = uint64(binary.Size(symbol32{}.diskData))
+	} else {
+		minSize = uint64(binary.Size(symbol64{}.diskData))
+	}
+	if entrySize < minSize {
+		err := fmt.Errorf(
+			"Invalid entry size %d of symbol table '%s'.", entrySize, section.Name())
+		return nil, err
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		err = fmt.Errorf(
+			"Error reading data of symbol table '%s'.\n%s", section.Name(), err.Error())
+		return nil, err
+	}
+
+	count := uint64(len(data)) / entrySize
+	symbols := make([]Symbol, count)
+	for i := uint64(0); i < count; i++ {
+		reader := bytes.NewReader(data[i*entrySize : (i+1)*entrySize])
+		if sectHdr.Class() == Class32 {
+			sym32 := new(symbol32)
+			err = binary.Read(reader, endianMap[endianess], &sym32.diskData)
+			symbols[i] = sym32
+		} else {
+			sym64 := new(symbol64)
+			err = binary.Read(reader, endianMap[endianess], &sym64.diskData)
+			symbols[i] = sym64
+		}
+
+		if err != nil {
+			err = fmt.Errorf(
+				"Error reading symbol %d from '%s'.\n%s", i, section.Name(), err.Error())
+			return nil, err
+		}
+	}
+
+	return symbols, nil
+}
@@ -0,0 +1,214 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"testing"
+)
+
+func testResolvedSymbol(
+	t *testing.T, sym ResolvedSymbol,
+	name string, value uint64, size uint64,
+	binding SymBinding, symType SymType, visibility SymVisibility,
+	sectIndex uint32) {
+	if sym.Name != name {
+		t.Errorf("Wrong symbol name '%s', expecting '%s'.", sym.Name, name)
+		return
+	}
+	if sym.Value != value {
+		t.Errorf("Wrong value 0x%x of symbol '%s'.", sym.Value, name)
+	}
+	if sym.Size != size {
+		t.Errorf("Wrong size %d of symbol '%s'.", sym.Size, name)
+	}
+	if sym.Binding != binding {
+		t.Errorf("Wrong binding %d of symbol '%s'.", sym.Binding, name)
+	}
+	if sym.Type != symType {
+		t.Errorf("Wrong type %d of symbol '%s'.", sym.Type, name)
+	}
+	if sym.Visibility != visibility {
+		t.Errorf("Wrong visibility %d of symbol '%s'.", sym.Visibility, name)
+	}
+	if sym.SectIndex != sectIndex {
+		t.Errorf("Wrong section index %d of symbol '%s'.", sym.SectIndex, name)
+	}
+}
+
+func TestSymbols64(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	symbols, err := elf.Symbols()
+	if err != nil {
+		t.Errorf("Error reading symbols.\n%s", err.Error())
+		return
+	}
+	if len(symbols) != 69 {
+		t.Errorf("Wrong number of symbols %d, expecting 69.", len(symbols))
+		return
+	}
+
+	testResolvedSymbol(
+		t, symbols[0], "", 0, 0,
+		SymBindingLocal, SymTypeNone, SymVisibilityDefault, 0)
+	testResolvedSymbol(
+		t, symbols[13], "", 0x400400, 0,
+		SymBindingLocal, SymTypeSection, SymVisibilityDefault, 13)
+	testResolvedSymbol(
+		t, symbols[41], "main.c", 0, 0,
+		SymBindingLocal, SymTypeFile, SymVisibilityDefault, uint32(SectIndexAbsSym))
+	testResolvedSymbol(
+		t, symbols[58], "__dso_handle", 0x601030, 0,
+		SymBindingGlobal, SymTypeObject, SymVisibilityHidden, 24)
+	testResolvedSymbol(
+		t, symbols[64], "main", 0x4004ed, 11,
+		SymBindingGlobal, SymTypeFunc, SymVisibilityDefault, 13)
+
+	dynSymbols, err := elf.DynamicSymbols()
+	if err != nil {
+		t.Errorf("Error reading dynamic symbols.\n%s", err.Error())
+		return
+	}
+	if len(dynSymbols) != 3 {
+		t.Errorf("Wrong number of dynamic symbols %d, expecting 3.", len(dynSymbols))
+		return
+	}
+
+	testResolvedSymbol(
+		t, dynSymbols[1], "__libc_start_main", 0, 0,
+		SymBindingGlobal, SymTypeFunc, SymVisibilityDefault, 0)
+	testResolvedSymbol(
+		t, dynSymbols[2], "__gmon_start__", 0, 0,
+		SymBindingWeak, SymTypeNone, SymVisibilityDefault, 0)
+	if dynSymbols[2].IsDefined() {
+		t.Errorf("Symbol '__gmon_start__' should be undefined.")
+	}
+}
+
+func TestSymbols32(t *testing.T) {
+	elf, err := Read("test_data/linux_x86.o")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	if elf.Header().ELFIdent().Class != Class32 {
+		t.Errorf("Incorrect class.")
+		return
+	}
+
+	symbols, err := elf.Symbols()
+	if err != nil {
+		t.Errorf("Error reading symbols.\n%s", err.Error())
+		return
+	}
+	if len(symbols) != 9 {
+		t.Errorf("Wrong number of symbols %d, expecting 9.", len(symbols))
+		return
+	}
+
+	testResolvedSymbol(
+		t, symbols[2], "hidden_func", 0, 5,
+		SymBindingGlobal, SymTypeFunc, SymVisibilityHidden, 2)
+	testResolvedSymbol(
+		t, symbols[3], "weak_func", 5, 6,
+		SymBindingWeak, SymTypeFunc, SymVisibilityDefault, 2)
+	testResolvedSymbol(
+		t, symbols[7], "external_func", 0, 0,
+		SymBindingGlobal, SymTypeNone, SymVisibilityDefault, 0)
+	testResolvedSymbol(
+		t, symbols[8], "counter", 0, 4,
+		SymBindingGlobal, SymTypeObject, SymVisibilityDefault, 4)
+
+	dynSymbols, err := elf.DynamicSymbols()
+	if err != nil {
+		t.Errorf("Error reading dynamic symbols.\n%s", err.Error())
+		return
+	}
+	if len(dynSymbols) != 0 {
+		t.Errorf("Unexpected dynamic symbols in a relocatable file.")
+	}
+}
+
+func TestLookupSymbol(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	symbols, err := elf.LookupSymbol("__libc_csu_init")
+	if err != nil {
+		t.Errorf("Error looking up symbol.\n%s", err.Error())
+		return
+	}
+	if len(symbols) != 1 || symbols[0].Value != 0x400500 {
+		t.Errorf("Wrong result looking up '__libc_csu_init'.")
+	}
+
+	symbols, err = elf.LookupSymbol("crtstuff.c")
+	if err != nil {
+		t.Errorf("Error looking up symbol.\n%s", err.Error())
+		return
+	}
+	if len(symbols) != 2 {
+		t.Errorf("Wrong number of symbols named 'crtstuff.c': %d.", len(symbols))
+	}
+
+	symbols, err = elf.LookupSymbol("no_such_symbol")
+	if err != nil {
+		t.Errorf("Error looking up symbol.\n%s", err.Error())
+		return
+	}
+	if len(symbols) != 0 {
+		t.Errorf("Found a non-existent symbol.")
+	}
+
+	sym, err := elf.LookupSymbolByAddr(0x4004f0)
+	if err != nil {
+		t.Errorf("Error looking up symbol by address.\n%s", err.Error())
+		return
+	}
+	if sym == nil || sym.Name != "main" {
+		t.Errorf("Wrong symbol containing address 0x4004f0.")
+	}
+
+	sym, err = elf.LookupSymbolByAddr(0x4004ed + 11)
+	if err != nil {
+		t.Errorf("Error looking up symbol by address.\n%s", err.Error())
+		return
+	}
+	if sym != nil {
+		t.Errorf("Unexpected symbol '%s' containing address 0x4004f8.", sym.Name)
+	}
+
+	sym, err = elf.LookupSymbolByAddr(0x601038)
+	if err != nil {
+		t.Errorf("Error looking up symbol by address.\n%s", err.Error())
+		return
+	}
+	if sym == nil || sym.Name != "completed.6972" {
+		t.Errorf("Wrong symbol containing address 0x601038.")
+	}
+}
@@ -0,0 +1,517 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Values of type FormatErrorKind tell which part of an ELF file is malformed.
+type FormatErrorKind uint8
+
+const (
+	// The ELF header is malformed.
+	FormatErrorHeader FormatErrorKind = FormatErrorKind(1)
+
+	// The program header table or a segment is malformed.
+	FormatErrorSegment FormatErrorKind = FormatErrorKind(2)
+
+	// The section header table or a section is malformed.
+	FormatErrorSection FormatErrorKind = FormatErrorKind(3)
+)
+
+// FormatError is a structural problem in an ELF file found by Validate.
+type FormatError struct {
+	Kind FormatErrorKind
+
+	// The index of the malformed segment or section. It is -1 for problems
+	// which are not specific to a single segment or section.
+	Index int
+
+	// The file offset of the malformed header, table entry or data.
+	Offset uint64
+
+	Msg string
+}
+
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("Offset 0x%x: %s", e.Offset, e.Msg)
+}
+
+// FormatErrors is the list of structural problems in an ELF file. It is the
+// error returned by NewFileStrict and ReadStrict.
+type FormatErrors []*FormatError
+
+func (errs FormatErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// validator collects the structural problems of an ELF file.
+type validator struct {
+	r    io.ReaderAt
+	size uint64
+	errs []*FormatError
+
+	class     ELFClass
+	endianess binary.ByteOrder
+	header    ELFHeader
+}
+
+func (v *validator) report(kind FormatErrorKind, index int, offset uint64, format string, a ...interface{}) {
+	err := &FormatError{kind, index, offset, fmt.Sprintf(format, a...)}
+	v.errs = append(v.errs, err)
+}
+
+// Returns true if the size bytes at offset lie within the file.
+func (v *validator) inFile(offset, size uint64) bool {
+	return offset <= v.size && size <= v.size-offset
+}
+
+// Returns the size bytes at offset, which should lie within the file.
+func (v *validator) read(offset, size uint64) ([]byte, error) {
+	data := make([]byte, size)
+	_, err := v.r.ReadAt(data, int64(offset))
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Returns the word at offset in data. Words are 4 bytes in ELF32 files and 8
+// bytes in ELF64 files.
+func (v *validator) word(data []byte, offset uint64) uint64 {
+	if v.class == Class32 {
+		return uint64(v.endianess.Uint32(data[offset:]))
+	}
+
+	return v.endianess.Uint64(data[offset:])
+}
+
+func isPowerOf2(value uint64) bool {
+	return value&(value-1) == 0
+}
+
+// Validates the ELF file read from r, whose size in bytes is size. Unlike
+// NewFile, it does not stop at the first problem. It returns all the
+// structural problems found in the ELF header, the program header table, the
+// segments, the section header table and the sections. Returns nil if the
+// file is well formed.
+//
+// Problems which make the rest of a table unreadable, like a table which lies
+// beyond the end of the file, stop the validation of that table.
+func Validate(r io.ReaderAt, size int64) []*FormatError {
+	v := &validator{r: r, size: uint64(size)}
+	if v.validateHeader() {
+		v.validateSegments()
+		v.validateSections()
+	}
+
+	return v.errs
+}
+
+// Validates the ELF file whose path is given by fileName. See Validate.
+func ValidateFile(fileName string) ([]*FormatError, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to open file '%s'.\n%s", fileName, err.Error())
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to stat '%s'.\n%s", fileName, err.Error())
+	}
+
+	return Validate(file, fileInfo.Size()), nil
+}
+
+// Same as NewFile, but the ELF file is validated first. If there are
+// structural problems, a nil ELF is returned along with the problems as an
+// error of type FormatErrors.
+func NewFileStrict(r io.ReaderAt, size int64) (*ELF, error) {
+	errs := Validate(r, size)
+	if len(errs) > 0 {
+		return nil, FormatErrors(errs)
+	}
+
+	return NewFile(r, size)
+}
+
+// Same as Read, but the ELF file is validated first. See NewFileStrict.
+func ReadStrict(fileName string) (*ELF, error) {
+	errs, err := ValidateFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, FormatErrors(errs)
+	}
+
+	return Read(fileName)
+}
+
+// Validates the ELF header. Returns false if the header is unusable, in
+// which case the tables are not validated.
+func (v *validator) validateHeader() bool {
+	if v.size < 16 {
+		v.report(FormatErrorHeader, -1, 0, "File is too small for an ELF identification.")
+		return false
+	}
+
+	ident, err := v.read(0, 16)
+	if err != nil {
+		v.report(FormatErrorHeader, -1, 0, "Error reading ELF identification: %s", err.Error())
+		return false
+	}
+	if ident[0] != Mag0 || ident[1] != Mag1 || ident[2] != Mag2 || ident[3] != Mag3 {
+		v.report(FormatErrorHeader, -1, 0, "Invalid ELF magic number.")
+		return false
+	}
+
+	v.class = ELFClass(ident[4])
+	headerSize := uint64(header64Size)
+	switch v.class {
+	case Class32:
+		headerSize = header32Size
+	case Class64:
+	default:
+		v.report(FormatErrorHeader, -1, 4, "Invalid ELF class %d.", ident[4])
+		return false
+	}
+
+	endianess, ok := endianMap[ELFEndianess(ident[5])]
+	if !ok {
+		v.report(FormatErrorHeader, -1, 5, "Invalid ELF data encoding %d.", ident[5])
+		return false
+	}
+	v.endianess = endianess
+
+	if ident[6] != 1 {
+		v.report(FormatErrorHeader, -1, 6, "Invalid ELF identification version %d.", ident[6])
+	}
+
+	if v.size < headerSize {
+		v.report(FormatErrorHeader, -1, 0, "File is too small for an ELF header.")
+		return false
+	}
+	v.header, err = readHeader(v.r, int64(v.size))
+	if err != nil {
+		v.report(FormatErrorHeader, -1, 0, "Error reading ELF header: %s", err.Error())
+		return false
+	}
+
+	if v.header.Version() != 1 {
+		v.report(FormatErrorHeader, -1, 20, "Invalid ELF version %d.", v.header.Version())
+	}
+
+	// Offsets of the header size field, which is followed by the table entry
+	// sizes and counts.
+	fieldsOffset := uint64(52)
+	if v.class == Class32 {
+		fieldsOffset = 40
+	}
+	if uint64(v.header.HeaderSize()) != headerSize {
+		v.report(FormatErrorHeader, -1, fieldsOffset,
+			"Invalid ELF header size %d.", v.header.HeaderSize())
+	}
+
+	return true
+}
+
+// Validates the program header table and the segments.
+func (v *validator) validateSegments() {
+	count := uint64(v.header.ProgHdrCount())
+	if count == 0 {
+		return
+	}
+
+	tblOffset := v.header.ProgHdrTblOffset()
+	entrySize := uint64(segHdr64Size)
+	entrySizeOffset := uint64(54)
+	if v.class == Class32 {
+		entrySize = segHdr32Size
+		entrySizeOffset = 42
+	}
+	if uint64(v.header.ProgHdrTblEntrySize()) != entrySize {
+		v.report(FormatErrorSegment, -1, entrySizeOffset,
+			"Invalid program header size %d.", v.header.ProgHdrTblEntrySize())
+		return
+	}
+	if !v.inFile(tblOffset, count*entrySize) {
+		v.report(FormatErrorSegment, -1, tblOffset,
+			"Program header table with %d entries lies beyond the end of file.", count)
+		return
+	}
+
+	segHdrTbl, err := readSegHdrTbl(v.r, int64(v.size), v.header)
+	if err != nil {
+		v.report(FormatErrorSegment, -1, tblOffset,
+			"Error reading program header table: %s", err.Error())
+		return
+	}
+
+	for i, segHdr := range segHdrTbl {
+		entryOffset := tblOffset + uint64(i)*entrySize
+		if segHdr.FileSize() > 0 && !v.inFile(segHdr.Offset(), segHdr.FileSize()) {
+			v.report(FormatErrorSegment, i, entryOffset,
+				"Segment %d lies beyond the end of file.", i)
+		}
+
+		align := segHdr.Alignment()
+		if !isPowerOf2(align) {
+			v.report(FormatErrorSegment, i, entryOffset,
+				"Alignment 0x%x of segment %d is not a power of 2.", align, i)
+		}
+
+		if segHdr.Type() != SegTypeLoad {
+			continue
+		}
+
+		if segHdr.FileSize() > segHdr.MemSize() {
+			v.report(FormatErrorSegment, i, entryOffset,
+				"File size of loadable segment %d is larger than its memory size.", i)
+		}
+		if segHdr.VirtualAddress()+segHdr.MemSize() < segHdr.VirtualAddress() {
+			v.report(FormatErrorSegment, i, entryOffset,
+				"Loadable segment %d wraps around the address space.", i)
+		}
+		if align > 1 && isPowerOf2(align) &&
+			segHdr.Offset()%align != segHdr.VirtualAddress()%align {
+			v.report(FormatErrorSegment, i, entryOffset,
+				"Offset and address of loadable segment %d are not congruent modulo its alignment.",
+				i)
+		}
+	}
+}
+
+// Returns the size of the entries of sections of type sectType, or 0 if the
+// sections of the type do not have fixed size entries.
+func (v *validator) sectEntrySize(sectType SectType) uint64 {
+	wordSize := uint64(8)
+	if v.class == Class32 {
+		wordSize = 4
+	}
+
+	switch sectType {
+	case SectTypeSymTab, SectTypeDynSym:
+		if v.class == Class32 {
+			return 16
+		}
+		return 24
+	case SectTypeRel:
+		return 2 * wordSize
+	case SectTypeRelA:
+		return 3 * wordSize
+	case SectTypeDynamic:
+		return 2 * wordSize
+	case SectTypeGnuVerSym:
+		return 2
+	}
+
+	return 0
+}
+
+// Returns the type of the section linked to by sections of type sectType, or
+// SectTypeUnused if the sections are not linked or can be linked to sections
+// of any type.
+func linkedSectType(sectType SectType) (SectType, bool) {
+	switch sectType {
+	case SectTypeSymTab, SectTypeDynSym, SectTypeDynamic, SectTypeGnuVerDef,
+		SectTypeGnuVerNeed:
+		return SectTypeStrTab, true
+	case SectTypeRel, SectTypeRelA, SectTypeHashTab, SectTypeGnuHash,
+		SectTypeGnuVerSym, SectTypeGroup:
+		return SectTypeUnused, true
+	}
+
+	return SectTypeUnused, false
+}
+
+// Validates the section header table and the sections.
+func (v *validator) validateSections() {
+	tblOffset := v.header.SectHdrTblOffset()
+	count := uint64(v.header.SectHdrCount())
+	if tblOffset == 0 {
+		if count != 0 {
+			v.report(FormatErrorSection, -1, 0,
+				"File has %d sections but no section header table.", count)
+		}
+		return
+	}
+
+	entrySize := uint64(sectHdr64Size)
+	entrySizeOffset := uint64(58)
+	if v.class == Class32 {
+		entrySize = sectHdr32Size
+		entrySizeOffset = 46
+	}
+	if uint64(v.header.SectHdrTblEntrySize()) != entrySize {
+		v.report(FormatErrorSection, -1, entrySizeOffset,
+			"Invalid section header size %d.", v.header.SectHdrTblEntrySize())
+		return
+	}
+	if !v.inFile(tblOffset, entrySize) {
+		v.report(FormatErrorSection, -1, tblOffset,
+			"Section header table lies beyond the end of file.")
+		return
+	}
+
+	sectHdrTbl, strTblIndex, err := readSectHdrTbl(v.r, int64(v.size), v.header)
+	if err != nil {
+		v.report(FormatErrorSection, -1, tblOffset,
+			"Error reading section header table: %s", err.Error())
+		return
+	}
+	count = uint64(len(sectHdrTbl))
+	if count == 0 {
+		return
+	}
+
+	if sectHdrTbl[0].Type() != SectTypeUnused {
+		v.report(FormatErrorSection, 0, tblOffset, "Section 0 is not a null section.")
+	}
+
+	// The names of the sections are checked only if the section name string
+	// table is valid.
+	var names []byte
+	strTblIndexOffset := entrySizeOffset + 4
+	if strTblIndex >= uint32(count) {
+		v.report(FormatErrorSection, -1, strTblIndexOffset,
+			"Section name string table index %d is out of range.", strTblIndex)
+	} else if strTblIndex != uint32(SectIndexUndefined) {
+		if sectHdrTbl[strTblIndex].Type() != SectTypeStrTab {
+			v.report(FormatErrorSection, int(strTblIndex), strTblIndexOffset,
+				"Section name string table %d is not a string table.", strTblIndex)
+		} else {
+			names = v.validateStrTbl(sectHdrTbl, int(strTblIndex), tblOffset, entrySize)
+		}
+	}
+
+	for i, sectHdr := range sectHdrTbl {
+		if i == 0 {
+			continue
+		}
+
+		entryOffset := tblOffset + uint64(i)*entrySize
+		if names != nil && uint64(sectHdr.NameIndex()) >= uint64(len(names)) {
+			v.report(FormatErrorSection, i, entryOffset,
+				"Name index 0x%x of section %d is out of range.", sectHdr.NameIndex(), i)
+		}
+
+		sectType := sectHdr.Type()
+		if sectType != SectTypeNoBits && sectType != SectTypeUnused &&
+			!v.inFile(sectHdr.Offset(), sectHdr.Size()) {
+			v.report(FormatErrorSection, i, entryOffset,
+				"Section %d lies beyond the end of file.", i)
+		}
+
+		align := sectHdr.Alignment()
+		if !isPowerOf2(align) {
+			v.report(FormatErrorSection, i, entryOffset,
+				"Alignment 0x%x of section %d is not a power of 2.", align, i)
+		} else if align > 1 && sectHdr.Flags()&SectFlagAlloc != 0 &&
+			sectHdr.Address()%align != 0 {
+			v.report(FormatErrorSection, i, entryOffset,
+				"Address of section %d is not aligned.", i)
+		}
+
+		if expected := v.sectEntrySize(sectType); expected != 0 {
+			if sectHdr.EntrySize() != expected {
+				v.report(FormatErrorSection, i, entryOffset,
+					"Invalid entry size %d of section %d.", sectHdr.EntrySize(), i)
+			} else if sectHdr.Size()%expected != 0 {
+				v.report(FormatErrorSection, i, entryOffset,
+					"Size of section %d is not a multiple of its entry size.", i)
+			}
+		}
+
+		linkType, linked := linkedSectType(sectType)
+		link := uint64(sectHdr.Link())
+		if linked && link >= count {
+			v.report(FormatErrorSection, i, entryOffset,
+				"Link %d of section %d is out of range.", link, i)
+		} else if linked && linkType != SectTypeUnused && sectHdrTbl[link].Type() != linkType {
+			v.report(FormatErrorSection, i, entryOffset,
+				"Section %d is linked to section %d of the wrong type.", i, link)
+		}
+
+		if (sectType == SectTypeRel || sectType == SectTypeRelA) &&
+			sectHdr.Flags()&SectFlagInfoLink != 0 && uint64(sectHdr.Info()) >= count {
+			v.report(FormatErrorSection, i, entryOffset,
+				"Relocation section %d applies to section %d which is out of range.",
+				i, sectHdr.Info())
+		}
+
+		if sectType == SectTypeStrTab && i != int(strTblIndex) {
+			v.validateStrTbl(sectHdrTbl, i, tblOffset, entrySize)
+		}
+	}
+}
+
+// Validates the string table in section index. Returns the data of the
+// string table if it is valid.
+func (v *validator) validateStrTbl(
+	sectHdrTbl []SectHdr, index int, tblOffset uint64, entrySize uint64) []byte {
+	sectHdr := sectHdrTbl[index]
+	entryOffset := tblOffset + uint64(index)*entrySize
+	if sectHdr.Size() == 0 || !v.inFile(sectHdr.Offset(), sectHdr.Size()) {
+		// Tables beyond the end of file are reported with the other sections.
+		if sectHdr.Size() == 0 {
+			v.report(FormatErrorSection, index, entryOffset, "String table %d is empty.", index)
+		}
+		return nil
+	}
+
+	// Compressed string tables are not checked.
+	if sectHdr.Flags()&SectFlagCompressed != 0 {
+		return nil
+	}
+
+	data, err := v.read(sectHdr.Offset(), sectHdr.Size())
+	if err != nil {
+		v.report(FormatErrorSection, index, sectHdr.Offset(),
+			"Error reading string table %d: %s", index, err.Error())
+		return nil
+	}
+
+	valid := true
+	if data[0] != 0 {
+		v.report(FormatErrorSection, index, sectHdr.Offset(),
+			"First byte of string table %d is not NULL.", index)
+		valid = false
+	}
+	if data[len(data)-1] != 0 {
+		v.report(FormatErrorSection, index, sectHdr.Offset()+sectHdr.Size()-1,
+			"String table %d is not NULL terminated.", index)
+		valid = false
+	}
+
+	if !valid {
+		return nil
+	}
+	return data
+}
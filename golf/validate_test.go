@@ -0,0 +1,177 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// A malformed variant of 'test_data/linux_x86.o', and the problem which
+// Validate should report for it.
+type malformedFile struct {
+	name   string
+	data   []byte
+	kind   FormatErrorKind
+	index  int
+	offset uint64
+}
+
+// Returns malformed variants of 'test_data/linux_x86.o'. Its section header
+// table is at offset 556 and has 12 entries of 40 bytes each. The section
+// name string table is section 11, and the symbol table is section 9.
+func malformedFiles() ([]malformedFile, error) {
+	orig, err := ioutil.ReadFile("test_data/linux_x86.o")
+	if err != nil {
+		return nil, err
+	}
+
+	const tblOffset = 556
+	e := binary.LittleEndian
+	sectHdr := func(index int) uint64 {
+		return tblOffset + 40*uint64(index)
+	}
+	edit := func(edit func(data []byte)) []byte {
+		data := append([]byte(nil), orig...)
+		edit(data)
+		return data
+	}
+
+	return []malformedFile{
+		{
+			"truncated header",
+			orig[:40],
+			FormatErrorHeader, -1, 0,
+		},
+		{
+			"invalid class",
+			edit(func(data []byte) { data[4] = 7 }),
+			FormatErrorHeader, -1, 4,
+		},
+		{
+			"huge section count",
+			edit(func(data []byte) { e.PutUint16(data[48:], 0xfeff) }),
+			FormatErrorSection, -1, tblOffset,
+		},
+		{
+			"section name table index out of range",
+			edit(func(data []byte) { e.PutUint16(data[50:], 40) }),
+			FormatErrorSection, -1, 50,
+		},
+		{
+			"empty section name table",
+			edit(func(data []byte) { e.PutUint32(data[sectHdr(11)+20:], 0) }),
+			FormatErrorSection, 11, sectHdr(11),
+		},
+		{
+			"section name table without data",
+			edit(func(data []byte) { e.PutUint32(data[sectHdr(11)+4:], uint32(SectTypeNoBits)) }),
+			FormatErrorSection, 11, 50,
+		},
+		{
+			"section beyond the end of file",
+			edit(func(data []byte) { e.PutUint32(data[sectHdr(2)+16:], 0xfffffff0) }),
+			FormatErrorSection, 2, sectHdr(2),
+		},
+		{
+			"symbol table with zero entry size",
+			edit(func(data []byte) { e.PutUint32(data[sectHdr(9)+36:], 0) }),
+			FormatErrorSection, 9, sectHdr(9),
+		},
+		{
+			"symbol table linked to a section which is not a string table",
+			edit(func(data []byte) { e.PutUint32(data[sectHdr(9)+24:], 2) }),
+			FormatErrorSection, 9, sectHdr(9),
+		},
+	}, nil
+}
+
+func TestValidate(t *testing.T) {
+	fileNames, err := filepath.Glob("test_data/*")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	for _, fileName := range fileNames {
+		// Compressed files and archives are not ELF files.
+		if ext := filepath.Ext(fileName); ext == ".gz" || ext == ".a" {
+			continue
+		}
+
+		errs, err := ValidateFile(fileName)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if len(errs) != 0 {
+			t.Errorf("Problems found in valid file '%s': %s", fileName, FormatErrors(errs))
+		}
+	}
+
+	files, err := malformedFiles()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	for _, file := range files {
+		errs := Validate(bytes.NewReader(file.data), int64(len(file.data)))
+		if len(errs) != 1 {
+			t.Errorf("Found %d problems in a file with %s: %s",
+				len(errs), file.name, FormatErrors(errs))
+			continue
+		}
+
+		err := errs[0]
+		if err.Kind != file.kind || err.Index != file.index || err.Offset != file.offset {
+			t.Errorf("Wrong problem found in a file with %s: %v", file.name, err)
+		}
+
+		_, strictErr := NewFileStrict(bytes.NewReader(file.data), int64(len(file.data)))
+		if _, ok := strictErr.(FormatErrors); !ok {
+			t.Errorf("Strict reading did not fail for a file with %s.", file.name)
+		}
+	}
+}
+
+func TestReadMalformed(t *testing.T) {
+	files, err := malformedFiles()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// Files which golf cannot make sense of are rejected. The others can be
+	// read, though some of their parts cannot be decoded.
+	readable := map[string]bool{
+		"symbol table with zero entry size":                            true,
+		"symbol table linked to a section which is not a string table": true,
+		"section beyond the end of file":                               true,
+	}
+	for _, file := range files {
+		elf, err := NewFile(bytes.NewReader(file.data), int64(len(file.data)))
+		if readable[file.name] != (err == nil) {
+			t.Errorf("Wrong result of reading a file with %s: %v", file.name, err)
+			continue
+		}
+		if elf != nil {
+			readAll(elf)
+		}
+	}
+}
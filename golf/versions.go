@@ -0,0 +1,393 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"fmt"
+)
+
+const (
+	// Version index of local symbols.
+	VerIndexLocal uint16 = 0
+
+	// Version index of global (unversioned) symbols.
+	VerIndexGlobal uint16 = 1
+
+	// Bit of a '.gnu.version' entry which marks the version as hidden.
+	VerIndexHidden uint16 = 0x8000
+
+	// Mask of a '.gnu.version' entry which gives the version index.
+	VerIndexMask uint16 = 0x7fff
+)
+
+const (
+	// Flag of the version definition of the file itself.
+	VerFlagBase uint16 = 0x1
+
+	// Flag of weak version definitions and requirements.
+	VerFlagWeak uint16 = 0x2
+)
+
+const (
+	// Size of the Elf32_Verdef and Elf64_Verdef structures.
+	verDefSize = 20
+
+	// Size of the Elf32_Verdaux and Elf64_Verdaux structures.
+	verDefAuxSize = 8
+
+	// Size of the Elf32_Verneed and Elf64_Verneed structures.
+	verNeedSize = 16
+
+	// Size of the Elf32_Vernaux and Elf64_Vernaux structures.
+	verNeedAuxSize = 16
+)
+
+// VerDef is a version definition from the '.gnu.version_d' section.
+type VerDef struct {
+	// The revision of the version definition structure.
+	Revision uint16
+
+	Flags uint16
+
+	// The version index, as used in the '.gnu.version' section.
+	Index uint16
+
+	// The ELF hash of the version name.
+	Hash uint32
+
+	// The name of the version, like 'GLIBC_2.14'. For the definition with the
+	// flag VerFlagBase, it is the name of the file itself.
+	Name string
+
+	// The names of the versions which this version inherits from.
+	Parents []string
+}
+
+// VerNeedAux is a version required from a needed library.
+type VerNeedAux struct {
+	// The name of the version, like 'GLIBC_2.14'.
+	Name string
+
+	// The ELF hash of the version name.
+	Hash uint32
+
+	Flags uint16
+
+	// The version index, as used in the '.gnu.version' section.
+	Index uint16
+}
+
+// VerNeed is an entry of the '.gnu.version_r' section listing the versions
+// required from a needed library.
+type VerNeed struct {
+	// The revision of the version requirement structure.
+	Revision uint16
+
+	// The name of the needed library, like 'libc.so.6'.
+	File string
+
+	// The versions required from the library.
+	Versions []VerNeedAux
+}
+
+// Returns the version definitions in the section of type SectTypeGnuVerDef
+// (typically named '.gnu.version_d'). Returns an empty slice if the ELF file
+// does not have such a section.
+func (elf *ELF) VersionDefinitions() ([]VerDef, error) {
+	section := elf.sectionOfType(SectTypeGnuVerDef)
+	if section == nil {
+		return nil, nil
+	}
+
+	data, strTbl, err := elf.versionSectionData(section)
+	if err != nil {
+		return nil, err
+	}
+
+	endianess := elf.Endianess()
+	var defs []VerDef
+	offset := uint64(0)
+	for i := uint64(0); i < versionEntryCount(section, verDefSize); i++ {
+		if offset+verDefSize > uint64(len(data)) {
+			err = fmt.Errorf("Version definition %d in '%s' is out of bounds.", i, section.Name())
+			return nil, err
+		}
+
+		entry := data[offset:]
+		def := VerDef{
+			Revision: endianess.Uint16(entry),
+			Flags:    endianess.Uint16(entry[2:]),
+			Index:    endianess.Uint16(entry[4:]),
+			Hash:     endianess.Uint32(entry[8:]),
+		}
+		auxCount := endianess.Uint16(entry[6:])
+		auxOffset := offset + uint64(endianess.Uint32(entry[12:]))
+		next := endianess.Uint32(entry[16:])
+
+		for j := uint16(0); j < auxCount; j++ {
+			if auxOffset+verDefAuxSize > uint64(len(data)) {
+				err = fmt.Errorf(
+					"Auxiliary entry %d of version definition %d in '%s' is out of bounds.",
+					j, i, section.Name())
+				return nil, err
+			}
+
+			aux := data[auxOffset:]
+			name, err := strTbl.ReadStr(endianess.Uint32(aux))
+			if err != nil {
+				err = fmt.Errorf(
+					"Error reading name of version definition %d in '%s'.\n%s",
+					i, section.Name(), err.Error())
+				return nil, err
+			}
+			if j == 0 {
+				def.Name = name
+			} else {
+				def.Parents = append(def.Parents, name)
+			}
+
+			auxNext := endianess.Uint32(aux[4:])
+			if auxNext == 0 {
+				break
+			}
+			auxOffset += uint64(auxNext)
+		}
+
+		defs = append(defs, def)
+		if next == 0 {
+			break
+		}
+		offset += uint64(next)
+	}
+
+	return defs, nil
+}
+
+// Returns the version requirements in the section of type SectTypeGnuVerNeed
+// (typically named '.gnu.version_r'). There is one entry for each needed
+// library from which versions are required. Returns an empty slice if the ELF
+// file does not have such a section.
+func (elf *ELF) VersionRequirements() ([]VerNeed, error) {
+	section := elf.sectionOfType(SectTypeGnuVerNeed)
+	if section == nil {
+		return nil, nil
+	}
+
+	data, strTbl, err := elf.versionSectionData(section)
+	if err != nil {
+		return nil, err
+	}
+
+	endianess := elf.Endianess()
+	var needs []VerNeed
+	offset := uint64(0)
+	for i := uint64(0); i < versionEntryCount(section, verNeedSize); i++ {
+		if offset+verNeedSize > uint64(len(data)) {
+			err = fmt.Errorf("Version requirement %d in '%s' is out of bounds.", i, section.Name())
+			return nil, err
+		}
+
+		entry := data[offset:]
+		need := VerNeed{Revision: endianess.Uint16(entry)}
+		need.File, err = strTbl.ReadStr(endianess.Uint32(entry[4:]))
+		if err != nil {
+			err = fmt.Errorf(
+				"Error reading file name of version requirement %d in '%s'.\n%s",
+				i, section.Name(), err.Error())
+			return nil, err
+		}
+		auxCount := endianess.Uint16(entry[2:])
+		auxOffset := offset + uint64(endianess.Uint32(entry[8:]))
+		next := endianess.Uint32(entry[12:])
+
+		for j := uint16(0); j < auxCount; j++ {
+			if auxOffset+verNeedAuxSize > uint64(len(data)) {
+				err = fmt.Errorf(
+					"Auxiliary entry %d of version requirement %d in '%s' is out of bounds.",
+					j, i, section.Name())
+				return nil, err
+			}
+
+			aux := data[auxOffset:]
+			version := VerNeedAux{
+				Hash:  endianess.Uint32(aux),
+				Flags: endianess.Uint16(aux[4:]),
+				Index: endianess.Uint16(aux[6:]),
+			}
+			version.Name, err = strTbl.ReadStr(endianess.Uint32(aux[8:]))
+			if err != nil {
+				err = fmt.Errorf(
+					"Error reading name of version requirement %d in '%s'.\n%s",
+					i, section.Name(), err.Error())
+				return nil, err
+			}
+			need.Versions = append(need.Versions, version)
+
+			auxNext := endianess.Uint32(aux[12:])
+			if auxNext == 0 {
+				break
+			}
+			auxOffset += uint64(auxNext)
+		}
+
+		needs = append(needs, need)
+		if next == 0 {
+			break
+		}
+		offset += uint64(next)
+	}
+
+	return needs, nil
+}
+
+// Returns the entries of the section of type SectTypeGnuVerSym (typically
+// named '.gnu.version'). The entry at index i is the version index of the
+// symbol at index i in the dynamic symbol table, possibly with the
+// VerIndexHidden bit set. Returns an empty slice if the ELF file does not have
+// such a section.
+func (elf *ELF) SymbolVersionIndeces() ([]uint16, error) {
+	section := elf.sectionOfType(SectTypeGnuVerSym)
+	if section == nil {
+		return nil, nil
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		err = fmt.Errorf(
+			"Error reading symbol versions section '%s'.\n%s", section.Name(), err.Error())
+		return nil, err
+	}
+
+	endianess := elf.Endianess()
+	indeces := make([]uint16, len(data)/2)
+	for i := range indeces {
+		indeces[i] = endianess.Uint16(data[2*i:])
+	}
+
+	return indeces, nil
+}
+
+// Sets the version fields of the dynamic symbols using the symbol versioning
+// sections.
+func (elf *ELF) resolveSymbolVersions(symbols []ResolvedSymbol) error {
+	indeces, err := elf.SymbolVersionIndeces()
+	if err != nil || len(indeces) == 0 {
+		return err
+	}
+
+	defs, err := elf.VersionDefinitions()
+	if err != nil {
+		return err
+	}
+
+	needs, err := elf.VersionRequirements()
+	if err != nil {
+		return err
+	}
+
+	type version struct {
+		name string
+		file string
+	}
+	versions := make(map[uint16]version)
+	for _, def := range defs {
+		if def.Flags&VerFlagBase == 0 {
+			versions[def.Index] = version{def.Name, ""}
+		}
+	}
+	for _, need := range needs {
+		for _, v := range need.Versions {
+			versions[v.Index] = version{v.Name, need.File}
+		}
+	}
+
+	for i := range symbols {
+		if i >= len(indeces) {
+			break
+		}
+
+		index := indeces[i] & VerIndexMask
+		if index == VerIndexLocal || index == VerIndexGlobal {
+			continue
+		}
+
+		v, exists := versions[index]
+		if !exists {
+			return fmt.Errorf("Unknown version index %d of dynamic symbol %d.", index, i)
+		}
+
+		sym := &symbols[i]
+		sym.Version = v.name
+		sym.VersionHidden = indeces[i]&VerIndexHidden != 0
+		sym.VersionFile = v.file
+	}
+
+	return nil
+}
+
+// Returns the first section of the given type, or nil if there is no such
+// section.
+func (elf *ELF) sectionOfType(sectType SectType) *Section {
+	for _, section := range elf.sections {
+		if section.SectHdr().Type() == sectType {
+			return section
+		}
+	}
+
+	return nil
+}
+
+// Returns the data of a version definition or requirement section, and the
+// string table linked to it.
+func (elf *ELF) versionSectionData(section *Section) ([]byte, *RawStrTbl, error) {
+	data, err := section.Data()
+	if err != nil {
+		err = fmt.Errorf(
+			"Error reading version section '%s'.\n%s", section.Name(), err.Error())
+		return nil, nil, err
+	}
+
+	link := section.SectHdr().Link()
+	if link == 0 || link >= uint32(len(elf.sections)) {
+		err = fmt.Errorf(
+			"Invalid string table index %d linked to version section '%s'.",
+			link, section.Name())
+		return nil, nil, err
+	}
+
+	strData, err := elf.sections[link].Data()
+	if err != nil {
+		err = fmt.Errorf(
+			"Error reading string table of version section '%s'.\n%s",
+			section.Name(), err.Error())
+		return nil, nil, err
+	}
+
+	return data, NewRawStrTbl(strData), nil
+}
+
+// Returns the number of entries in a version definition or requirement
+// section, which is given by the info field of the section header. If the
+// info field is not set, then the number of entries which can fit in the
+// section is returned, so that the chain of entries is still bounded.
+func versionEntryCount(section *Section, entrySize uint64) uint64 {
+	if info := section.SectHdr().Info(); info != 0 {
+		return uint64(info)
+	}
+
+	return section.SectHdr().Size() / entrySize
+}
@@ -0,0 +1,207 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionDefinitions(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	defs, err := elf.VersionDefinitions()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(defs) != 3 {
+		t.Errorf("Wrong number of version definitions: %d", len(defs))
+		return
+	}
+
+	if defs[0].Name != "libgolf.so.1" || defs[0].Flags != VerFlagBase || defs[0].Index != 1 {
+		t.Errorf("Wrong base version definition: %+v", defs[0])
+		return
+	}
+	if defs[1].Name != "GOLF_1.0" || defs[1].Index != 2 || len(defs[1].Parents) != 0 {
+		t.Errorf("Wrong version definition: %+v", defs[1])
+		return
+	}
+	if defs[2].Name != "GOLF_2.0" || defs[2].Index != 3 ||
+		!reflect.DeepEqual(defs[2].Parents, []string{"GOLF_1.0"}) {
+		t.Errorf("Wrong version definition: %+v", defs[2])
+		return
+	}
+}
+
+func TestVersionRequirements(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	needs, err := elf.VersionRequirements()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(needs) != 1 || needs[0].File != "libc.so.6" {
+		t.Errorf("Wrong version requirements: %+v", needs)
+		return
+	}
+
+	versions := needs[0].Versions
+	if len(versions) != 2 {
+		t.Errorf("Wrong number of versions required from libc.so.6.")
+		return
+	}
+	if versions[0].Name != "GLIBC_2.14" || versions[0].Index != 5 {
+		t.Errorf("Wrong required version: %+v", versions[0])
+		return
+	}
+	if versions[1].Name != "GLIBC_2.2.5" || versions[1].Index != 4 {
+		t.Errorf("Wrong required version: %+v", versions[1])
+		return
+	}
+
+	defs, err := elf.VersionDefinitions()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(defs) != 3 {
+		t.Errorf("Wrong number of version definitions: %d", len(defs))
+	}
+}
+
+func TestSymbolVersions(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.so")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	symbols, err := elf.DynamicSymbols()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(symbols) != 13 {
+		t.Errorf("Wrong number of dynamic symbols: %d", len(symbols))
+		return
+	}
+
+	expected := []struct {
+		name    string
+		version string
+		hidden  bool
+		file    string
+	}{
+		{"", "", false, ""},
+		{"_ITM_deregisterTMCloneTable", "", false, ""},
+		{"puts", "GLIBC_2.2.5", false, "libc.so.6"},
+		{"__gmon_start__", "", false, ""},
+		{"memcpy", "GLIBC_2.14", false, "libc.so.6"},
+		{"_ITM_registerTMCloneTable", "", false, ""},
+		{"__cxa_finalize", "GLIBC_2.2.5", false, "libc.so.6"},
+		{"GOLF_2.0", "GOLF_2.0", false, ""},
+		{"GOLF_1.0", "GOLF_1.0", false, ""},
+		{"golf_counter", "GOLF_2.0", false, ""},
+		{"golf_add", "GOLF_1.0", true, ""},
+		{"golf_add", "GOLF_2.0", false, ""},
+		{"golf_copy", "GOLF_2.0", false, ""},
+	}
+	for i, e := range expected {
+		sym := symbols[i]
+		if sym.Name != e.name || sym.Version != e.version ||
+			sym.VersionHidden != e.hidden || sym.VersionFile != e.file {
+			t.Errorf("Wrong version of dynamic symbol %d: %+v", i, sym)
+		}
+	}
+
+	symbols, err = elf.Symbols()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	for _, sym := range symbols {
+		if sym.Version != "" {
+			t.Errorf("Symbol '%s' in the symbol table has a version.", sym.Name)
+			return
+		}
+	}
+}
+
+func TestCopiedSymbolVersion(t *testing.T) {
+	elf, err := Read("../deps/test_data/sysroot/usr/bin/symapp")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	symbols, err := elf.DynamicSymbols()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// 'v_data' is defined by the copy relocation of the executable, with the
+	// version it requires from 'libv.so.1'.
+	sym := symbols[7]
+	if sym.Name != "v_data" || !sym.IsDefined() || sym.Version != "V_2" ||
+		sym.VersionFile != "libv.so.1" {
+		t.Errorf("Wrong version of copied symbol: %+v", sym)
+	}
+}
+
+func TestNoVersionDefinitions(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	defs, err := elf.VersionDefinitions()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(defs) != 0 {
+		t.Errorf("Unexpected version definitions.")
+		return
+	}
+
+	indeces, err := elf.SymbolVersionIndeces()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if !reflect.DeepEqual(indeces, []uint16{0, 2, 0}) {
+		t.Errorf("Wrong symbol version indeces: %v", indeces)
+	}
+}
@@ -0,0 +1,741 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// Section indeces at or above this value are stored in the header of
+	// section 0 when using extended section numbering.
+	sectIndexLoReserve = 0xff00
+
+	// Sizes of the headers and table entries of the two ELF classes.
+	header32Size  = 52
+	header64Size  = 64
+	segHdr32Size  = 32
+	segHdr64Size  = 56
+	sectHdr32Size = 40
+	sectHdr64Size = 64
+
+	// The largest alignment of a section which is moved. It bounds the
+	// padding added to the output file for sections of malformed files.
+	maxMovedSectAlign = 1 << 24
+)
+
+// An extent is a range of bytes in an ELF file.
+type extent struct {
+	offset uint64
+	size   uint64
+}
+
+func (e extent) end() uint64 {
+	return e.offset + e.size
+}
+
+func (e extent) overlaps(other extent) bool {
+	return e.offset < other.end() && other.offset < e.end()
+}
+
+// A layoutItem is a part of the ELF file, like the data of a section or the
+// section header table, which is placed in the output file by Write.
+type layoutItem struct {
+	// The extent of the item in the input file, if it was present there.
+	orig    extent
+	hasOrig bool
+
+	// The size and alignment of the item in the output file.
+	size  uint64
+	align uint64
+
+	// The offset of the item in the output file.
+	offset uint64
+
+	// The index of the section whose data is the item, -1 for the section
+	// header table, or -2 for the program header table.
+	sectIndex int
+}
+
+// Writes the ELF file to w. Sections, and the section and program header
+// tables, which were not modified are written as they were read. Sections
+// which are part of a segment are written at the offsets in their headers.
+// All other sections, and the section header table, are written at their
+// original offsets if the preceding parts of the file did not change, and
+// are packed after the preceding parts, respecting their alignment, otherwise.
+// The section name string table is rebuilt if a section was renamed or added,
+// and the header fields describing the layout are recomputed. An ELF file
+// which was not modified is written back byte for byte.
+//
+// The ELF should not be closed before it is written, as the data of the
+// sections which were not modified is read from the underlying file.
+func (elf *ELF) Write(w io.Writer) error {
+	data, err := elf.serialize()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// Writes the ELF file to the file named fileName, which is created with
+// permissions perm if it does not exist. The data is first written to a
+// temporary file which is then renamed, so fileName can be the file from
+// which the ELF was read.
+func (elf *ELF) WriteFile(fileName string, perm os.FileMode) error {
+	data, err := elf.serialize()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(fileName), filepath.Base(fileName)+".tmp")
+	if err != nil {
+		return fmt.Errorf("Unable to create temporary file.\n%s", err.Error())
+	}
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(perm)
+	}
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), fileName)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("Unable to write file '%s'.\n%s", fileName, err.Error())
+	}
+
+	return nil
+}
+
+// Serializes the ELF file as described in Write.
+func (elf *ELF) serialize() ([]byte, error) {
+	class := elf.header.ELFIdent().Class
+	byteOrder := elf.Endianess()
+
+	var hdrSize, segHdrSize, sectHdrSize, tblAlign uint64
+	if class == Class32 {
+		hdrSize, segHdrSize, sectHdrSize, tblAlign = header32Size, segHdr32Size, sectHdr32Size, 4
+	} else {
+		hdrSize, segHdrSize, sectHdrSize, tblAlign = header64Size, segHdr64Size, sectHdr64Size, 8
+	}
+
+	origSections := elf.origSections
+	if origSections == nil {
+		origSections = elf.sections
+	}
+
+	nameIndeces, sectNameTblData, err := elf.sectionNames()
+	if err != nil {
+		return nil, err
+	}
+
+	// The data of the sections in the output file. It is nil for sections
+	// whose data is read from the input file.
+	sectData := make([][]byte, len(elf.sections))
+	sectSizes := make([]uint64, len(elf.sections))
+	for i, section := range elf.sections {
+		switch {
+		case uint32(i) == elf.sectNameTblIndex && sectNameTblData != nil:
+			sectData[i] = sectNameTblData
+			sectSizes[i] = uint64(len(sectNameTblData))
+		case section.modified:
+			sectData[i] = section.data
+			sectSizes[i] = uint64(len(section.data))
+		default:
+			sectSizes[i] = section.header.Size()
+			orig := section.origHeader
+			if orig != nil && orig.Type() != SectTypeNoBits &&
+				(orig.Offset() > uint64(elf.size) ||
+					section.header.Size() > uint64(elf.size)-orig.Offset()) {
+				err = fmt.Errorf(
+					"Data of section '%s' lies beyond the end of file.", section.Name())
+				return nil, err
+			}
+		}
+	}
+
+	// The pinned extents are the parts of the output file whose offsets are
+	// fixed: the ELF header, the data of the segments and possibly the
+	// program header table.
+	var segExtents []extent
+	for i, segHdr := range elf.progHdrTbl {
+		// Only the segment added for patching, and the segments in it, can
+		// hold data which is not in the input file.
+		inPatchSeg := elf.patchSegOffset != 0 && segHdr.Offset() >= elf.patchSegOffset
+		if segHdr.FileSize() > 0 && !inPatchSeg &&
+			(segHdr.Offset() > uint64(elf.size) ||
+				segHdr.FileSize() > uint64(elf.size)-segHdr.Offset()) {
+			return nil, fmt.Errorf("Data of segment %d lies beyond the end of file.", i)
+		}
+		if segHdr.FileSize() > 0 {
+			segExtents = append(segExtents, extent{segHdr.Offset(), segHdr.FileSize()})
+		}
+	}
+	inSegment := func(offset, size uint64) bool {
+		for _, e := range segExtents {
+			if offset >= e.offset && offset < e.end() && offset+size <= e.end() {
+				return true
+			}
+		}
+		return false
+	}
+	startsInSegment := func(offset uint64) bool {
+		for _, e := range segExtents {
+			if offset >= e.offset && offset < e.end() {
+				return true
+			}
+		}
+		return false
+	}
+
+	pinned := []extent{{0, hdrSize}}
+
+	var origProgHdrTbl extent
+	if elf.header.ProgHdrCount() > 0 {
+		origProgHdrTbl = extent{
+			elf.header.ProgHdrTblOffset(), uint64(elf.header.ProgHdrCount()) * segHdrSize}
+	}
+
+	progHdrTbl := extent{0, uint64(len(elf.progHdrTbl)) * segHdrSize}
+	progHdrTblPinned := true
+	if progHdrTbl.size > 0 {
+		var phdrSeg SegHdr
+		for _, segHdr := range elf.progHdrTbl {
+			if segHdr.Type() == SegTypeProgHdr {
+				phdrSeg = segHdr
+				break
+			}
+		}
+
+		switch {
+		case phdrSeg != nil && phdrSeg.FileSize() >= progHdrTbl.size:
+			progHdrTbl.offset = phdrSeg.Offset()
+		case origProgHdrTbl.size >= progHdrTbl.size:
+			progHdrTbl.offset = origProgHdrTbl.offset
+		default:
+			progHdrTblPinned = false
+		}
+		if progHdrTblPinned {
+			pinned = append(pinned, progHdrTbl)
+		}
+	}
+
+	var items []*layoutItem
+	for i, section := range elf.sections {
+		hdr := section.header
+		if i == 0 || hdr.Type() == SectTypeNoBits {
+			continue
+		}
+
+		if section.origHeader != nil || hdr.Offset() != 0 {
+			if inSegment(hdr.Offset(), sectSizes[i]) {
+				e := extent{hdr.Offset(), sectSizes[i]}
+				for _, p := range pinned {
+					if e.overlaps(p) {
+						err = fmt.Errorf(
+							"Section '%s' overlaps another part of a segment.", section.Name())
+						return nil, err
+					}
+				}
+				pinned = append(pinned, e)
+				continue
+			}
+
+			// An allocated section which grew past the end of its segment
+			// cannot be moved, as it would no longer be loaded at its
+			// address.
+			if hdr.Flags()&SectFlagAlloc != 0 && startsInSegment(hdr.Offset()) {
+				err = fmt.Errorf(
+					"Allocated section '%s' does not fit in its segment.", section.Name())
+				return nil, err
+			}
+		}
+
+		item := &layoutItem{size: sectSizes[i], align: hdr.Alignment(), sectIndex: i}
+		if orig := section.origHeader; orig != nil {
+			item.orig = extent{orig.Offset(), orig.Size()}
+			item.hasOrig = true
+		}
+		items = append(items, item)
+	}
+
+	if len(elf.sections) > 0 {
+		item := &layoutItem{
+			size:      uint64(len(elf.sections)) * sectHdrSize,
+			align:     tblAlign,
+			sectIndex: -1,
+		}
+		if elf.header.SectHdrTblOffset() != 0 {
+			item.orig = extent{
+				elf.header.SectHdrTblOffset(), uint64(len(origSections)) * sectHdrSize}
+			item.hasOrig = true
+		}
+		items = append(items, item)
+	}
+
+	var progHdrTblItem *layoutItem
+	if !progHdrTblPinned {
+		progHdrTblItem = &layoutItem{size: progHdrTbl.size, align: tblAlign, sectIndex: -2}
+		items = append(items, progHdrTblItem)
+	}
+
+	// Parts of the file which cannot hold items are the pinned extents and
+	// the segments.
+	occupied := append(append([]extent(nil), pinned...), segExtents...)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].hasOrig != items[j].hasOrig {
+			return items[i].hasOrig
+		}
+		return items[i].hasOrig && items[i].orig.offset < items[j].orig.offset
+	})
+
+	// The vacated extents are the parts of the input file which held data
+	// that has been removed or moved. Parts of segments are not considered
+	// as the data of the segments is always copied to the output file.
+	var vacated []extent
+	vacate := func(e extent) {
+		if !inSegment(e.offset, e.size) {
+			vacated = append(vacated, e)
+		}
+	}
+	for _, section := range origSections {
+		orig := section.origHeader
+		if orig.Type() == SectTypeNoBits {
+			continue
+		}
+
+		i := elf.sectionIndex(section)
+		switch {
+		case i < 0 || section.header.Type() == SectTypeNoBits:
+			vacate(extent{orig.Offset(), orig.Size()})
+		case sectSizes[i] < orig.Size():
+			vacate(extent{orig.Offset() + sectSizes[i], orig.Size() - sectSizes[i]})
+		}
+	}
+	if !progHdrTblPinned || progHdrTbl.offset != origProgHdrTbl.offset {
+		vacate(origProgHdrTbl)
+	}
+
+	// The offset at which the layout of the output file starts to differ from
+	// the layout of the input file.
+	disturbedAt := uint64(math.MaxUint64)
+	if len(vacated) > 0 {
+		disturbedAt = vacated[0].offset
+		for _, v := range vacated {
+			if v.offset < disturbedAt {
+				disturbedAt = v.offset
+			}
+		}
+	}
+
+	cursor := uint64(0)
+	for _, item := range items {
+		if item.hasOrig && item.size == item.orig.size && item.orig.offset >= cursor {
+			keep := true
+			gap := extent{cursor, item.orig.offset - cursor}
+			target := extent{item.orig.offset, item.size}
+			for _, v := range vacated {
+				if gap.overlaps(v) {
+					keep = false
+				}
+			}
+			for _, o := range occupied {
+				if target.overlaps(o) {
+					keep = false
+				}
+			}
+			if keep {
+				item.offset = item.orig.offset
+				cursor = target.end()
+				continue
+			}
+		}
+
+		if !isPowerOf2(item.align) || item.align > maxMovedSectAlign {
+			err = fmt.Errorf(
+				"Invalid alignment 0x%x of section %d.", item.align, item.sectIndex)
+			return nil, err
+		}
+		item.offset = freeOffset(cursor, item.size, item.align, occupied)
+		cursor = item.offset + item.size
+		if item.hasOrig {
+			vacated = append(vacated, item.orig)
+			if item.orig.offset < disturbedAt {
+				disturbedAt = item.orig.offset
+			}
+		}
+		if item.offset < disturbedAt {
+			disturbedAt = item.offset
+		}
+	}
+
+	fileSize := uint64(0)
+	for _, p := range pinned {
+		if p.end() > fileSize {
+			fileSize = p.end()
+		}
+	}
+	for _, e := range segExtents {
+		if e.end() > fileSize {
+			fileSize = e.end()
+		}
+	}
+	for _, item := range items {
+		if item.offset+item.size > fileSize {
+			fileSize = item.offset + item.size
+		}
+	}
+	if disturbedAt == math.MaxUint64 && uint64(elf.size) > fileSize {
+		fileSize = uint64(elf.size)
+	}
+
+	out := make([]byte, fileSize)
+
+	// Copy the data of the segments, which includes the bytes not covered by
+	// sections, and the bytes of the input file which are not part of the
+	// headers or sections, like padding, as long as they are in an
+	// undisturbed part of the file. Sections and headers are written over
+	// these bytes.
+	var copyExtents []extent
+	copyExtents = append(copyExtents, segExtents...)
+	covered := []extent{{0, hdrSize}, origProgHdrTbl}
+	for _, section := range origSections {
+		if section.origHeader.Type() != SectTypeNoBits {
+			covered = append(covered, extent{section.origHeader.Offset(), section.origHeader.Size()})
+		}
+	}
+	covered = append(covered, extent{
+		elf.header.SectHdrTblOffset(), uint64(len(origSections)) * sectHdrSize})
+	for _, orphan := range complementExtents(covered, uint64(elf.size)) {
+		if orphan.offset < disturbedAt {
+			copyExtents = append(copyExtents, clipExtent(orphan, extent{0, disturbedAt}))
+		}
+	}
+	for _, e := range copyExtents {
+		e = clipExtent(e, extent{0, minUint64(fileSize, uint64(elf.size))})
+		if e.size == 0 {
+			continue
+		}
+
+		data, err := elf.readAt(e.offset, e.size)
+		if err != nil {
+			return nil, err
+		}
+		copy(out[e.offset:], data)
+	}
+
+	// Section data and the section header table.
+	var sectHdrTblOffset uint64
+	sectOffsets := make([]uint64, len(elf.sections))
+	for i, section := range elf.sections {
+		sectOffsets[i] = section.header.Offset()
+	}
+	for _, item := range items {
+		switch {
+		case item.sectIndex == -1:
+			sectHdrTblOffset = item.offset
+		case item.sectIndex == -2:
+			progHdrTbl.offset = item.offset
+		default:
+			sectOffsets[item.sectIndex] = item.offset
+		}
+	}
+
+	for i, section := range elf.sections {
+		if i == 0 || section.header.Type() == SectTypeNoBits || sectSizes[i] == 0 {
+			continue
+		}
+
+		data := sectData[i]
+		if data == nil {
+			data, err = section.RawData()
+			if err != nil {
+				return nil, err
+			}
+		}
+		copy(out[sectOffsets[i]:], data)
+	}
+
+	var sectHdrBuf bytes.Buffer
+	numSections := uint64(len(elf.sections))
+	for i, section := range elf.sections {
+		fields := SectHdrFieldsOf(section.header)
+		fields.NameIndex = nameIndeces[i]
+		if section.header.Type() != SectTypeNoBits {
+			fields.Offset = sectOffsets[i]
+			fields.Size = sectSizes[i]
+		}
+		if i == 0 {
+			fields.Size, fields.Link = 0, 0
+			if numSections >= sectIndexLoReserve {
+				fields.Size = numSections
+			}
+			if elf.sectNameTblIndex >= sectIndexLoReserve {
+				fields.Link = elf.sectNameTblIndex
+			}
+		}
+
+		hdr, err := NewSectHdr(class, fields)
+		if err != nil {
+			return nil, fmt.Errorf("Error encoding header of section %d.\n%s", i, err.Error())
+		}
+		err = writeSectHdr(&sectHdrBuf, hdr, byteOrder)
+		if err != nil {
+			return nil, err
+		}
+	}
+	copy(out[sectHdrTblOffset:], sectHdrBuf.Bytes())
+
+	// The program header table.
+	var segHdrBuf bytes.Buffer
+	for _, segHdr := range elf.progHdrTbl {
+		if segHdr.Type() == SegTypeProgHdr && !progHdrTblPinned {
+			fields := SegHdrFieldsOf(segHdr)
+			fields.Offset = progHdrTbl.offset
+			fields.FileSize = progHdrTbl.size
+			fields.MemSize = progHdrTbl.size
+			segHdr, err = NewSegHdr(class, fields)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = writeSegHdr(&segHdrBuf, segHdr, byteOrder)
+		if err != nil {
+			return nil, err
+		}
+	}
+	copy(out[progHdrTbl.offset:], segHdrBuf.Bytes())
+
+	// The ELF header.
+	var hdrBuf bytes.Buffer
+	err = elf.writeHeader(
+		&hdrBuf, progHdrTbl.offset, uint16(len(elf.progHdrTbl)), sectHdrTblOffset, byteOrder)
+	if err != nil {
+		return nil, err
+	}
+	copy(out, hdrBuf.Bytes())
+
+	return out, nil
+}
+
+// Returns the name indeces of the sections in the output file. If sections
+// were renamed or added, the section name string table is rebuilt and its
+// data is returned. Otherwise, the returned data is nil and the original name
+// indeces are used.
+func (elf *ELF) sectionNames() ([]uint32, []byte, error) {
+	nameIndeces := make([]uint32, len(elf.sections))
+	rebuild := false
+	for i, section := range elf.sections {
+		nameIndeces[i] = section.header.NameIndex()
+		if section.renamed {
+			rebuild = true
+		}
+	}
+	if !rebuild {
+		return nameIndeces, nil, nil
+	}
+	if elf.sectNameTblIndex == 0 || elf.sectNameTblIndex >= uint32(len(elf.sections)) {
+		return nil, nil, fmt.Errorf("Cannot rename sections without a section name table.")
+	}
+
+	data := []byte{0}
+	offsets := map[string]uint32{"": 0}
+	for i, section := range elf.sections {
+		name := section.name
+		offset, exists := offsets[name]
+		if !exists {
+			offset = uint32(len(data))
+			offsets[name] = offset
+			data = append(data, name...)
+			data = append(data, 0)
+		}
+		nameIndeces[i] = offset
+	}
+
+	return nameIndeces, data, nil
+}
+
+func (elf *ELF) writeHeader(
+	w io.Writer, progHdrTblOffset uint64, progHdrCount uint16, sectHdrTblOffset uint64,
+	byteOrder binary.ByteOrder) error {
+	numSections := uint64(len(elf.sections))
+	sectCount := uint16(numSections)
+	if numSections >= sectIndexLoReserve {
+		sectCount = 0
+	}
+	strTblIndex := uint16(elf.sectNameTblIndex)
+	if elf.sectNameTblIndex >= sectIndexLoReserve {
+		strTblIndex = SectIndexExtended
+	}
+	if progHdrCount == 0 {
+		progHdrTblOffset = 0
+	}
+
+	var ident ELFIdent
+	var platformSpecific interface{}
+	switch header := elf.header.(type) {
+	case *header32:
+		if !fitsUint32(progHdrTblOffset, sectHdrTblOffset) {
+			return fmt.Errorf("Header table offsets do not fit in a 32-bit header.")
+		}
+
+		h := *header
+		h.platformSpecific.ProgHdrTblOffset = uint32(progHdrTblOffset)
+		h.platformSpecific.SectHdrTblOffset = uint32(sectHdrTblOffset)
+		h.platformSpecific.HeaderSize = header32Size
+		h.platformSpecific.ProgHdrTblEntrySize = segHdr32Size
+		h.platformSpecific.ProgHdrTblEntryCount = progHdrCount
+		h.platformSpecific.SectHdrTblEntrySize = sectHdr32Size
+		h.platformSpecific.SectHdrTblEntryCount = sectCount
+		h.platformSpecific.StrTblIndex = strTblIndex
+		if numSections == 0 {
+			h.platformSpecific.SectHdrTblEntrySize = header.platformSpecific.SectHdrTblEntrySize
+		}
+		if progHdrCount == 0 {
+			h.platformSpecific.ProgHdrTblEntrySize = header.platformSpecific.ProgHdrTblEntrySize
+		}
+		ident, platformSpecific = h.ident, &h.platformSpecific
+	case *header64:
+		h := *header
+		h.platformSpecific.ProgHdrTblOffset = progHdrTblOffset
+		h.platformSpecific.SectHdrTblOffset = sectHdrTblOffset
+		h.platformSpecific.HeaderSize = header64Size
+		h.platformSpecific.ProgHdrTblEntrySize = segHdr64Size
+		h.platformSpecific.ProgHdrTblEntryCount = progHdrCount
+		h.platformSpecific.SectHdrTblEntrySize = sectHdr64Size
+		h.platformSpecific.SectHdrTblEntryCount = sectCount
+		h.platformSpecific.StrTblIndex = strTblIndex
+		if numSections == 0 {
+			h.platformSpecific.SectHdrTblEntrySize = header.platformSpecific.SectHdrTblEntrySize
+		}
+		if progHdrCount == 0 {
+			h.platformSpecific.ProgHdrTblEntrySize = header.platformSpecific.ProgHdrTblEntrySize
+		}
+		ident, platformSpecific = h.ident, &h.platformSpecific
+	default:
+		return fmt.Errorf("Unknown ELF header type.")
+	}
+
+	err := binary.Write(w, byteOrder, &ident)
+	if err != nil {
+		return err
+	}
+
+	return binary.Write(w, byteOrder, platformSpecific)
+}
+
+func writeSectHdr(w io.Writer, hdr SectHdr, byteOrder binary.ByteOrder) error {
+	switch h := hdr.(type) {
+	case *sectHdr32:
+		return binary.Write(w, byteOrder, &h.diskData)
+	case *sectHdr64:
+		return binary.Write(w, byteOrder, &h.diskData)
+	default:
+		return fmt.Errorf("Unknown section header type.")
+	}
+}
+
+func writeSegHdr(w io.Writer, hdr SegHdr, byteOrder binary.ByteOrder) error {
+	switch h := hdr.(type) {
+	case *segHdr32:
+		return binary.Write(w, byteOrder, &h.diskData)
+	case *segHdr64:
+		return binary.Write(w, byteOrder, &h.diskData)
+	default:
+		return fmt.Errorf("Unknown segment header type.")
+	}
+}
+
+// Returns the lowest offset at or after cursor, aligned to align, at which
+// size bytes do not overlap any of the pinned extents.
+func freeOffset(cursor, size, align uint64, pinned []extent) uint64 {
+	if align == 0 {
+		align = 1
+	}
+
+	offset := alignUp(cursor, align)
+	for {
+		moved := false
+		for _, p := range pinned {
+			if (extent{offset, size}).overlaps(p) || (size == 0 && offset > p.offset && offset < p.end()) {
+				offset = alignUp(p.end(), align)
+				moved = true
+			}
+		}
+		if !moved {
+			return offset
+		}
+	}
+}
+
+// Returns the parts of [0, size) which are not covered by the extents.
+func complementExtents(extents []extent, size uint64) []extent {
+	sorted := append([]extent(nil), extents...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].offset < sorted[j].offset })
+
+	var result []extent
+	cursor := uint64(0)
+	for _, e := range sorted {
+		if e.size == 0 {
+			continue
+		}
+		if e.offset > cursor {
+			result = append(result, extent{cursor, minUint64(e.offset, size) - cursor})
+		}
+		if e.end() > cursor {
+			cursor = e.end()
+		}
+		if cursor >= size {
+			return result
+		}
+	}
+	if cursor < size {
+		result = append(result, extent{cursor, size - cursor})
+	}
+
+	return result
+}
+
+// Returns the part of e which lies in bounds.
+func clipExtent(e, bounds extent) extent {
+	start := e.offset
+	if bounds.offset > start {
+		start = bounds.offset
+	}
+	end := minUint64(e.end(), bounds.end())
+	if end <= start {
+		return extent{}
+	}
+
+	return extent{start, end - start}
+}
@@ -0,0 +1,261 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package golf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteRoundTrip(t *testing.T) {
+	fileNames := []string{
+		"test_data/linux_x86_64.exe",
+		"test_data/linux_x86_64.so",
+		"test_data/linux_x86_64_cet.exe",
+		"test_data/linux_x86.o",
+		"test_data/linux_x86_zlib.o",
+		"test_data/big_endian_32.o",
+		"test_data/big_endian_64.o",
+		"../garf/test_data/single_cu_linux_x86_64.exe",
+		"../garf/test_data/multiple_cu_linux_x86_64.exe",
+	}
+	for _, fileName := range fileNames {
+		expected, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+
+		elf, err := Read(fileName)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+
+		var buf bytes.Buffer
+		err = elf.Write(&buf)
+		elf.Close()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("Unmodified '%s' was not written back byte for byte.", fileName)
+		}
+	}
+}
+
+func TestWriteEdited(t *testing.T) {
+	const fileName = "../garf/test_data/single_cu_linux_x86_64.exe"
+	elf, err := Read(fileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	text := elf.SectMap()[".text"][0]
+	textData, err := text.Data()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	var debugSections []*Section
+	for _, section := range elf.Sections() {
+		if strings.HasPrefix(section.Name(), ".debug_") {
+			debugSections = append(debugSections, section)
+		}
+	}
+	if len(debugSections) == 0 {
+		t.Errorf("No debug sections in '%s'.", fileName)
+		return
+	}
+	for _, section := range debugSections {
+		err = elf.RemoveSection(section)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	comment := elf.SectMap()[".comment"][0]
+	comment.SetData([]byte("golf\x00"))
+
+	hdr, err := NewSectHdr(Class64, SectHdrFields{Type: SectTypeProgBits, Alignment: 4})
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	elf.AddSection(".golf", hdr, []byte{1, 2, 3, 4, 5})
+
+	dir, err := ioutil.TempDir("", "golf")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	outFileName := filepath.Join(dir, "edited.exe")
+	err = elf.WriteFile(outFileName, 0755)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	edited, err := Read(outFileName)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer edited.Close()
+
+	if edited.Size() >= elf.Size() {
+		t.Errorf("Size of the file did not shrink after removing debug sections.")
+		return
+	}
+	if len(edited.Sections()) != len(elf.Sections()) {
+		t.Errorf("Wrong number of sections in the edited file.")
+		return
+	}
+	if edited.SectNameTblIndex() != elf.SectNameTblIndex() {
+		t.Errorf("Wrong section name table index in the edited file.")
+		return
+	}
+
+	expected := map[string][]byte{
+		".text":    textData,
+		".comment": []byte("golf\x00"),
+		".golf":    []byte{1, 2, 3, 4, 5},
+	}
+	for name, data := range expected {
+		sections, exists := edited.SectMap()[name]
+		if !exists {
+			t.Errorf("Section '%s' is missing in the edited file.", name)
+			return
+		}
+
+		editedData, err := sections[0].Data()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if !bytes.Equal(editedData, data) {
+			t.Errorf("Wrong data of section '%s' in the edited file.", name)
+		}
+	}
+
+	for _, section := range edited.Sections() {
+		if strings.HasPrefix(section.Name(), ".debug_") {
+			t.Errorf("Section '%s' was not removed.", section.Name())
+		}
+	}
+}
+
+func TestWriteGrownAllocSection(t *testing.T) {
+	elf, err := Read("test_data/linux_x86_64.exe")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	defer elf.Close()
+
+	// '.rodata' is in the first loadable segment, which it no longer fits
+	// when grown by 0x2000 bytes.
+	rodata := elf.SectMap()[".rodata"][0]
+	data, err := rodata.Data()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	rodata.SetData(append(data, make([]byte, 0x2000)...))
+
+	var buf bytes.Buffer
+	err = elf.Write(&buf)
+	if err == nil {
+		t.Errorf("Expected an error writing a grown '.rodata'.")
+		return
+	}
+
+	rodata.SetData(data[:2])
+	buf.Reset()
+	err = elf.Write(&buf)
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestWriteRenamedBigEndian(t *testing.T) {
+	for _, fileName := range []string{"test_data/big_endian_32.o", "test_data/big_endian_64.o"} {
+		elf, err := Read(fileName)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		defer elf.Close()
+
+		elf.SectMap()[".data"][0].SetName(".golf_data")
+
+		var buf bytes.Buffer
+		err = elf.Write(&buf)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+
+		renamed, err := NewFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+
+		sections, exists := renamed.SectMap()[".golf_data"]
+		if !exists {
+			t.Errorf("Renamed section is missing in '%s'.", fileName)
+			return
+		}
+		data, err := sections[0].Data()
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if string(data) != "hello big endian golf\x00" {
+			t.Errorf("Wrong data of renamed section in '%s'.", fileName)
+		}
+	}
+}
+
+func TestNewSectHdr32Overflow(t *testing.T) {
+	_, err := NewSectHdr(Class32, SectHdrFields{Size: 1 << 32})
+	if err == nil {
+		t.Errorf("Expected an error creating a 32-bit header with a 64-bit size.")
+	}
+
+	hdr, err := NewSectHdr(Class32, SectHdrFields{Type: SectTypeNoBits, Size: 16})
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if hdr.Class() != Class32 || hdr.Type() != SectTypeNoBits || hdr.Size() != 16 {
+		t.Errorf("Wrong 32-bit section header.")
+	}
+}
@@ -0,0 +1,379 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package size
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+import (
+	"eureka/golf"
+)
+
+// A span is the range of bytes [begin, end) labeled with the name of the row
+// to which they are attributed.
+type span struct {
+	begin uint64
+	end   uint64
+	label string
+}
+
+// A heap of indeces into a slice of spans. The span with the smallest index is
+// at the top.
+type spanHeap []int
+
+func (h spanHeap) Len() int            { return len(h) }
+func (h spanHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h spanHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *spanHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+func (h *spanHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Returns the number of bytes in [0, size) attributed to each label of the
+// spans, and the number of bytes which are not covered by any span. A byte
+// covered by more than one span is attributed to the one which comes first in
+// spans.
+func attribute(spans []span, size uint64) (map[string]uint64, uint64) {
+	attributed := make(map[string]uint64)
+
+	// Sweep over the boundaries of the spans, keeping the spans which cover
+	// the current position in a heap.
+	var order []int
+	points := []uint64{0, size}
+	for i, s := range spans {
+		if s.end > size {
+			s.end = size
+		}
+		if s.begin >= s.end {
+			continue
+		}
+		spans[i] = s
+		order = append(order, i)
+		points = append(points, s.begin, s.end)
+	}
+	sort.Slice(order, func(i, j int) bool { return spans[order[i]].begin < spans[order[j]].begin })
+	sort.Slice(points, func(i, j int) bool { return points[i] < points[j] })
+
+	var active spanHeap
+	var uncovered uint64
+	next := 0
+	for i := 0; i+1 < len(points); i++ {
+		pos, end := points[i], points[i+1]
+		if pos == end {
+			continue
+		}
+
+		for next < len(order) && spans[order[next]].begin <= pos {
+			heap.Push(&active, order[next])
+			next++
+		}
+		for len(active) > 0 && spans[active[0]].end <= pos {
+			heap.Pop(&active)
+		}
+
+		if len(active) > 0 {
+			attributed[spans[active[0]].label] += end - pos
+		} else {
+			uncovered += end - pos
+		}
+	}
+
+	return attributed, uncovered
+}
+
+// Collects the rows of a report, in the order in which their names are first
+// added.
+type builder struct {
+	dim   Dimension
+	rows  []Row
+	index map[string]int
+}
+
+func newBuilder(dim Dimension) *builder {
+	return &builder{dim: dim, index: make(map[string]int)}
+}
+
+func (b *builder) add(name string, fileSize, vmSize uint64) {
+	if fileSize == 0 && vmSize == 0 {
+		return
+	}
+
+	i, exists := b.index[name]
+	if !exists {
+		i = len(b.rows)
+		b.index[name] = i
+		b.rows = append(b.rows, Row{Name: name})
+	}
+	b.rows[i].FileSize += fileSize
+	b.rows[i].VMSize += vmSize
+}
+
+func (b *builder) report() *Report {
+	report := &Report{Dimension: b.dim, Rows: b.rows}
+	if report.Rows == nil {
+		report.Rows = []Row{}
+	}
+	for _, row := range report.Rows {
+		report.FileSize += row.FileSize
+		report.VMSize += row.VMSize
+	}
+
+	return report
+}
+
+// Returns the spans of the ELF header and the program and section header
+// tables in the file.
+func headerSpans(elf *golf.ELF) []span {
+	header := elf.Header()
+	progHdrTblSize := uint64(header.ProgHdrTblEntrySize()) * uint64(len(elf.ProgHdrTbl()))
+	sectHdrTblSize := uint64(header.SectHdrTblEntrySize()) * uint64(len(elf.SectHdrTbl()))
+
+	spans := []span{{0, uint64(header.HeaderSize()), RowHeaders}}
+	if progHdrTblSize > 0 {
+		offset := header.ProgHdrTblOffset()
+		spans = append(spans, span{offset, offset + progHdrTblSize, RowHeaders})
+	}
+	if sectHdrTblSize > 0 {
+		offset := header.SectHdrTblOffset()
+		spans = append(spans, span{offset, offset + sectHdrTblSize, RowHeaders})
+	}
+
+	return spans
+}
+
+// Returns the number of bytes of the file which the section occupies.
+func sectFileSize(hdr golf.SectHdr) uint64 {
+	if hdr.Type() == golf.SectTypeNoBits {
+		return 0
+	}
+	return hdr.Size()
+}
+
+// Returns the number of bytes of the memory image of the file which the
+// section occupies. The '.tbss' section only occupies the TLS blocks of
+// threads, and overlaps the sections which follow it.
+func sectVMSize(hdr golf.SectHdr) uint64 {
+	if hdr.Flags()&golf.SectFlagAlloc == 0 {
+		return 0
+	}
+	if hdr.Type() == golf.SectTypeNoBits && hdr.Flags()&golf.SectFlagTLS != 0 {
+		return 0
+	}
+	return hdr.Size()
+}
+
+// Returns the name of the row to which the bytes of a section which are not
+// covered by any span are attributed.
+func sectRowName(section *golf.Section) string {
+	return fmt.Sprintf("[section %s]", section.Name())
+}
+
+// Builds a report in which the bytes of each section are attributed to the
+// labels of its spans, given by section index, and the others to a row for
+// the section. The bytes of the headers, and those which are not part of any
+// section, are attributed to RowHeaders and RowUnmapped.
+func attributeSections(elf *golf.ELF, dim Dimension, sectSpans map[int][]span) *Report {
+	b := newBuilder(dim)
+
+	fileSpans := headerSpans(elf)
+	for i, section := range elf.Sections() {
+		hdr := section.SectHdr()
+		if i == 0 || hdr.Type() == golf.SectTypeUnused {
+			continue
+		}
+
+		fileSize, vmSize := sectFileSize(hdr), sectVMSize(hdr)
+		if fileSize > 0 {
+			offset := hdr.Offset()
+			fileSpans = append(fileSpans, span{offset, offset + fileSize, ""})
+		}
+
+		attributed, uncovered := attribute(sectSpans[i], hdr.Size())
+		for _, s := range sectSpans[i] {
+			// Add the rows in the order of the spans.
+			if n, exists := attributed[s.label]; exists {
+				b.add(s.label, occupied(n, fileSize, hdr.Size()), occupied(n, vmSize, hdr.Size()))
+				delete(attributed, s.label)
+			}
+		}
+		b.add(sectRowName(section),
+			occupied(uncovered, fileSize, hdr.Size()), occupied(uncovered, vmSize, hdr.Size()))
+	}
+
+	attributed, unmapped := attribute(fileSpans, uint64(elf.Size()))
+	b.add(RowHeaders, attributed[RowHeaders], 0)
+	b.add(RowUnmapped, unmapped, 0)
+
+	return b.report()
+}
+
+// Returns n if size is the same as total, and 0 otherwise. It is used to
+// attribute n bytes of a section of the given total size to the file or the
+// memory image, depending on whether the section occupies size bytes of it.
+func occupied(n, size, total uint64) uint64 {
+	if size != total {
+		return 0
+	}
+	return n
+}
+
+// Returns the report of the bytes of each section of the ELF file.
+func Sections(elf *golf.ELF) (*Report, error) {
+	sectSpans := make(map[int][]span)
+	for i, section := range elf.Sections() {
+		size := section.SectHdr().Size()
+		sectSpans[i] = []span{{0, size, section.Name()}}
+	}
+
+	return attributeSections(elf, DimSections, sectSpans), nil
+}
+
+// Returns the name of a loadable segment, like 'LOAD #2 [RW]'.
+func segRowName(index int, segHdr golf.SegHdr) string {
+	flags := ""
+	if segHdr.Flags()&golf.SegFlagsReadable != 0 {
+		flags += "R"
+	}
+	if segHdr.Flags()&golf.SegFlagsWritable != 0 {
+		flags += "W"
+	}
+	if segHdr.Flags()&golf.SegFlagsExecutable != 0 {
+		flags += "X"
+	}
+
+	return fmt.Sprintf("LOAD #%d [%s]", index, flags)
+}
+
+// Returns the report of the bytes of each loadable segment of the ELF file.
+// The segments are numbered in the order of the program header table. The
+// bytes of the file which are not part of any loadable segment are attributed
+// to RowUnmapped.
+func Segments(elf *golf.ELF) (*Report, error) {
+	b := newBuilder(DimSegments)
+
+	var fileSpans []span
+	index := 0
+	for _, segHdr := range elf.ProgHdrTbl() {
+		if segHdr.Type() != golf.SegTypeLoad {
+			continue
+		}
+
+		name := segRowName(index, segHdr)
+		offset := segHdr.Offset()
+		fileSpans = append(fileSpans, span{offset, offset + segHdr.FileSize(), name})
+		b.add(name, 0, segHdr.MemSize())
+		index++
+	}
+
+	attributed, unmapped := attribute(fileSpans, uint64(elf.Size()))
+	for _, s := range fileSpans {
+		b.add(s.label, attributed[s.label], 0)
+		delete(attributed, s.label)
+	}
+	b.add(RowUnmapped, unmapped, 0)
+
+	return b.report(), nil
+}
+
+// Adds the spans of the sections covered by the range of addresses
+// [low, high) to sectSpans, in offsets relative to the start of the sections.
+// Only allocated sections are considered. The addresses of TLS sections are
+// those of the TLS initialization image, which overlap other sections, so
+// they are considered only if tls is true.
+func addressSpans(
+	elf *golf.ELF, low, high uint64, label string, tls bool, sectSpans map[int][]span) {
+	for i, section := range elf.Sections() {
+		hdr := section.SectHdr()
+		if hdr.Flags()&golf.SectFlagAlloc == 0 || hdr.Size() == 0 {
+			continue
+		}
+		if (hdr.Flags()&golf.SectFlagTLS != 0) != tls {
+			continue
+		}
+
+		begin, end := hdr.Address(), hdr.Address()+hdr.Size()
+		if low > begin {
+			begin = low
+		}
+		if high < end {
+			end = high
+		}
+		if begin < end {
+			sectSpans[i] = append(
+				sectSpans[i], span{begin - hdr.Address(), end - hdr.Address(), label})
+		}
+	}
+}
+
+// Returns the report of the bytes of each symbol of the ELF file. The symbol
+// table is used if the file has one, and the dynamic symbol table otherwise.
+// Symbols of the same name, like static functions of different compile units,
+// are reported as one row.
+func Symbols(elf *golf.ELF) (*Report, error) {
+	symbols, err := elf.Symbols()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading symbols.\n%s", err.Error())
+	}
+	if len(symbols) == 0 {
+		symbols, err = elf.DynamicSymbols()
+		if err != nil {
+			return nil, fmt.Errorf("Error reading dynamic symbols.\n%s", err.Error())
+		}
+	}
+
+	// The values of TLS symbols are offsets into the TLS segment.
+	var tlsAddress uint64
+	for _, segHdr := range elf.ProgHdrTbl() {
+		if segHdr.Type() == golf.SegTypeTLS {
+			tlsAddress = segHdr.VirtualAddress()
+		}
+	}
+
+	relocatable := elf.Header().Type() == golf.TypeRelocatable
+	sections := elf.Sections()
+	sectSpans := make(map[int][]span)
+	for _, sym := range symbols {
+		if sym.Size == 0 || sym.Type == golf.SymTypeSection || sym.Type == golf.SymTypeFile {
+			continue
+		}
+		if sym.SectIndex == 0 || int(sym.SectIndex) >= len(sections) {
+			continue
+		}
+
+		// The values of symbols in relocatable files are offsets into their
+		// sections.
+		if relocatable {
+			i := int(sym.SectIndex)
+			sectSpans[i] = append(sectSpans[i], span{sym.Value, sym.Value + sym.Size, sym.Name})
+			continue
+		}
+
+		address := sym.Value
+		if sym.Type == golf.SymTypeTLS {
+			address += tlsAddress
+		}
+		addressSpans(elf, address, address+sym.Size, sym.Name, sym.Type == golf.SymTypeTLS, sectSpans)
+	}
+
+	return attributeSections(elf, DimSymbols, sectSpans), nil
+}
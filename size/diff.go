@@ -0,0 +1,292 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package size
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+import (
+	"eureka/demangle"
+)
+
+// DiffRow is the change in the size of a row between two reports. A row which
+// is only in the new report has old sizes of 0, and a row which is only in the
+// old report has new sizes of 0.
+type DiffRow struct {
+	Name string `json:"name"`
+
+	OldFileSize uint64 `json:"old_file_size"`
+	NewFileSize uint64 `json:"new_file_size"`
+	OldVMSize   uint64 `json:"old_vm_size"`
+	NewVMSize   uint64 `json:"new_vm_size"`
+
+	// The new sizes minus the old sizes. They are negative if the row
+	// shrank.
+	FileDelta int64 `json:"file_delta"`
+	VMDelta   int64 `json:"vm_delta"`
+}
+
+// Returns the diff row of the sizes of a row in two reports.
+func newDiffRow(name string, oldFileSize, newFileSize, oldVMSize, newVMSize uint64) DiffRow {
+	return DiffRow{
+		Name:        name,
+		OldFileSize: oldFileSize,
+		NewFileSize: newFileSize,
+		OldVMSize:   oldVMSize,
+		NewVMSize:   newVMSize,
+		FileDelta:   int64(newFileSize) - int64(oldFileSize),
+		VMDelta:     int64(newVMSize) - int64(oldVMSize),
+	}
+}
+
+// Diff is the change in the sizes of the rows of two reports of the same
+// dimension, like those of two builds of the same binary.
+type Diff struct {
+	OldFileName string    `json:"old_file"`
+	NewFileName string    `json:"new_file"`
+	Dimension   Dimension `json:"dimension"`
+
+	// The rows whose sizes changed. Rows whose sizes are the same in both
+	// reports are left out.
+	Rows []DiffRow `json:"rows"`
+
+	// The change in the total sizes.
+	Total DiffRow `json:"total"`
+}
+
+// Returns the diff of the reports old and new, which should be of the same
+// dimension. Rows are matched by name, so symbols should be demangled in
+// both reports or in neither. See Report.Demangle.
+func DiffReports(old, new *Report) (*Diff, error) {
+	if old.Dimension != new.Dimension {
+		return nil, fmt.Errorf(
+			"Cannot diff reports of dimensions '%s' and '%s'.", old.Dimension, new.Dimension)
+	}
+
+	diff := &Diff{
+		OldFileName: old.FileName,
+		NewFileName: new.FileName,
+		Dimension:   old.Dimension,
+		Rows:        []DiffRow{},
+		Total:       newDiffRow("TOTAL", old.FileSize, new.FileSize, old.VMSize, new.VMSize),
+	}
+
+	oldRows, newRows := rowsByName(old), rowsByName(new)
+	for _, oldRow := range old.Rows {
+		newRow := newRows[oldRow.Name]
+		row := newDiffRow(oldRow.Name,
+			oldRow.FileSize, newRow.FileSize, oldRow.VMSize, newRow.VMSize)
+		if row.FileDelta != 0 || row.VMDelta != 0 {
+			diff.Rows = append(diff.Rows, row)
+		}
+	}
+	for _, newRow := range new.Rows {
+		if _, exists := oldRows[newRow.Name]; !exists {
+			diff.Rows = append(diff.Rows,
+				newDiffRow(newRow.Name, 0, newRow.FileSize, 0, newRow.VMSize))
+		}
+	}
+
+	diff.Sort()
+	return diff, nil
+}
+
+// Returns the rows of the report by name. As with Report.Row, the first of
+// the rows with the same name is returned.
+func rowsByName(report *Report) map[string]Row {
+	rows := make(map[string]Row, len(report.Rows))
+	for _, row := range report.Rows {
+		if _, exists := rows[row.Name]; !exists {
+			rows[row.Name] = row
+		}
+	}
+
+	return rows
+}
+
+// Returns the absolute value of n.
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Returns the larger of the absolute values of the deltas of the row.
+func (row *DiffRow) magnitude() int64 {
+	if abs(row.FileDelta) > abs(row.VMDelta) {
+		return abs(row.FileDelta)
+	}
+	return abs(row.VMDelta)
+}
+
+// Sorts the rows of the diff by the larger of the absolute values of their
+// file and VM deltas, largest first. Rows of the same magnitude are sorted by
+// name.
+func (diff *Diff) Sort() {
+	sort.SliceStable(diff.Rows, func(i, j int) bool {
+		mi, mj := diff.Rows[i].magnitude(), diff.Rows[j].magnitude()
+		if mi != mj {
+			return mi > mj
+		}
+		return diff.Rows[i].Name < diff.Rows[j].Name
+	})
+}
+
+// Returns a size delta in a human readable form with its sign, like '+1.5Ki'
+// or '-12'.
+func FormatDelta(n int64) string {
+	switch {
+	case n > 0:
+		return "+" + FormatSize(uint64(n))
+	case n < 0:
+		return "-" + FormatSize(uint64(-n))
+	}
+	return "0"
+}
+
+// Returns the relative change from old to new, like '+12.5%'. Rows which are
+// new or deleted are marked '[NEW]' and '[DEL]'.
+func formatChange(old, new uint64) string {
+	switch {
+	case old == new:
+		return ""
+	case old == 0:
+		return "[NEW]"
+	case new == 0:
+		return "[DEL]"
+	}
+	return fmt.Sprintf("%+.1f%%", (float64(new)-float64(old))*100/float64(old))
+}
+
+// Writes the rows of the diff as a table, in their current order. If limit is
+// positive, only the first limit rows are written, and the others are summed
+// up in a row named like '[12 Others]'.
+func (diff *Diff) WriteTable(w io.Writer, limit int) error {
+	rows := diff.Rows
+	if limit > 0 && len(rows) > limit {
+		var others DiffRow
+		for _, row := range rows[limit:] {
+			others = newDiffRow("", others.OldFileSize+row.OldFileSize,
+				others.NewFileSize+row.NewFileSize, others.OldVMSize+row.OldVMSize,
+				others.NewVMSize+row.NewVMSize)
+		}
+		others.Name = fmt.Sprintf("[%d Others]", len(rows)-limit)
+		rows = append(rows[:limit:limit], others)
+	}
+
+	_, err := fmt.Fprintf(w, "%10s %8s %10s %8s  %s\n",
+		"FILE SIZE", "", "VM SIZE", "", diff.Dimension)
+	if err != nil {
+		return err
+	}
+	rows = append(rows, diff.Total)
+	for _, row := range rows {
+		_, err = fmt.Fprintf(w, "%10s %8s %10s %8s  %s\n",
+			FormatDelta(row.FileDelta), formatChange(row.OldFileSize, row.NewFileSize),
+			FormatDelta(row.VMDelta), formatChange(row.OldVMSize, row.NewVMSize), row.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Writes the diff as JSON.
+func (diff *Diff) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// Thresholds are the limits on the growth of a binary. A negative limit is not
+// checked.
+type Thresholds struct {
+	// The number of bytes by which the file, or its memory image, can grow.
+	FileGrowth int64
+	VMGrowth   int64
+
+	// The percentage of the old file size by which the file can grow.
+	FileGrowthPercent float64
+
+	// The number of bytes by which the file size or VM size of any row can
+	// grow.
+	RowGrowth int64
+}
+
+// Returns thresholds with no limits.
+func NoThresholds() Thresholds {
+	return Thresholds{FileGrowth: -1, VMGrowth: -1, FileGrowthPercent: -1, RowGrowth: -1}
+}
+
+// Returns the violations of the thresholds by the diff, or an empty list if
+// the growth is within the limits.
+func (diff *Diff) Exceeds(th Thresholds) []string {
+	violations := []string{}
+	total := diff.Total
+
+	if th.FileGrowth >= 0 && total.FileDelta > th.FileGrowth {
+		violations = append(violations, fmt.Sprintf(
+			"The file size grew by %d bytes, more than the limit of %d bytes.",
+			total.FileDelta, th.FileGrowth))
+	}
+	if th.VMGrowth >= 0 && total.VMDelta > th.VMGrowth {
+		violations = append(violations, fmt.Sprintf(
+			"The VM size grew by %d bytes, more than the limit of %d bytes.",
+			total.VMDelta, th.VMGrowth))
+	}
+	if th.FileGrowthPercent >= 0 && total.FileDelta > 0 {
+		growth := float64(total.FileDelta) * 100 / float64(total.OldFileSize)
+		if growth > th.FileGrowthPercent {
+			violations = append(violations, fmt.Sprintf(
+				"The file size grew by %.2f%%, more than the limit of %.2f%%.",
+				growth, th.FileGrowthPercent))
+		}
+	}
+	if th.RowGrowth >= 0 {
+		for _, row := range diff.Rows {
+			if row.FileDelta > th.RowGrowth || row.VMDelta > th.RowGrowth {
+				violations = append(violations, fmt.Sprintf(
+					"'%s' in %s grew by %d bytes of file size and %d bytes of VM size, "+
+						"more than the limit of %d bytes.",
+					row.Name, diff.Dimension, row.FileDelta, row.VMDelta, th.RowGrowth))
+			}
+		}
+	}
+
+	return violations
+}
+
+// Replaces the names of the rows of the report with their demangled forms.
+// Rows whose names demangle to the same name, like those of the complete and
+// base object constructors of a class, are merged.
+func (report *Report) Demangle() {
+	b := newBuilder(report.Dimension)
+	for _, row := range report.Rows {
+		b.add(demangle.Filter(row.Name), row.FileSize, row.VMSize)
+	}
+	report.Rows = b.report().Rows
+}
@@ -0,0 +1,218 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package size
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 'test_data/diff_old_x86_64.exe' and 'test_data/diff_new_x86_64.exe' are
+// two builds of the C++ files 'main.cc' and 'compute.cc'. In the new build,
+// 'buffer' is 4096 bytes larger, 'shapes::compute(int)' is larger, and
+// 'shapes::legacy(int)' is replaced by 'shapes::scale(int)', which uses a new
+// instance of the template 'shapes::lookup' and a new table 'wide_table'.
+const diffOldTestFile = "test_data/diff_old_x86_64.exe"
+const diffNewTestFile = "test_data/diff_new_x86_64.exe"
+const diffOldTestFileSize = 19688
+const diffNewTestFileSize = 20296
+
+// Returns the diff of the old and new test files along the dimension.
+func diffTestFiles(dim Dimension, demangleNames bool) (*Diff, error) {
+	old, err := Analyze(diffOldTestFile, dim)
+	if err != nil {
+		return nil, err
+	}
+	new, err := Analyze(diffNewTestFile, dim)
+	if err != nil {
+		return nil, err
+	}
+
+	if demangleNames {
+		old.Demangle()
+		new.Demangle()
+	}
+	return DiffReports(old, new)
+}
+
+func TestDiffSymbols(t *testing.T) {
+	diff, err := diffTestFiles(DimSymbols, true)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if diff.Total.OldFileSize != diffOldTestFileSize || diff.Total.NewFileSize != diffNewTestFileSize ||
+		diff.Total.FileDelta != diffNewTestFileSize-diffOldTestFileSize {
+		t.Errorf("Wrong total of symbols diff: %v", diff.Total)
+		return
+	}
+
+	expected := []DiffRow{
+		{"buffer", 0, 0, 4096, 8192, 0, 4096},
+		{"shapes::compute(int)", 57, 112, 57, 112, 55, 55},
+		{"wide_table", 0, 64, 0, 64, 64, 64},
+		{"shapes::scale(int)", 0, 41, 0, 41, 41, 41},
+		{"long shapes::lookup<long>(long const*, int)", 0, 20, 0, 20, 20, 20},
+		{"shapes::legacy(int)", 10, 0, 10, 0, -10, -10},
+	}
+	rows := make(map[string]DiffRow)
+	for _, row := range diff.Rows {
+		rows[row.Name] = row
+	}
+	for _, e := range expected {
+		if row, exists := rows[e.Name]; !exists || !reflect.DeepEqual(row, e) {
+			t.Errorf("Wrong row '%s' of symbols diff: %v", e.Name, row)
+			return
+		}
+	}
+	if _, exists := rows["int shapes::lookup<int>(int const*, int)"]; exists {
+		t.Errorf("Unchanged row in symbols diff.")
+		return
+	}
+	if diff.Rows[0].Name != "buffer" {
+		t.Errorf("Wrong first row of symbols diff: %s", diff.Rows[0].Name)
+	}
+}
+
+func TestDiffCompUnits(t *testing.T) {
+	diff, err := diffTestFiles(DimCompUnits, false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	var row *DiffRow
+	for i := range diff.Rows {
+		if diff.Rows[i].Name == "compute.cc" {
+			row = &diff.Rows[i]
+		}
+		if diff.Rows[i].Name == "main.cc" {
+			t.Errorf("Unchanged compile unit in diff: %v", diff.Rows[i])
+			return
+		}
+	}
+	if row == nil || row.OldFileSize != 67 || row.NewFileSize != 173 {
+		t.Errorf("Wrong row of compile unit 'compute.cc': %v", row)
+	}
+}
+
+func TestDiffDimensions(t *testing.T) {
+	_, err := DiffReports(&Report{Dimension: DimSections}, &Report{Dimension: DimSymbols})
+	if err == nil {
+		t.Errorf("Reports of different dimensions were diffed.")
+	}
+}
+
+func TestExceeds(t *testing.T) {
+	diff, err := diffTestFiles(DimSections, false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if violations := diff.Exceeds(NoThresholds()); len(violations) != 0 {
+		t.Errorf("Violations of no thresholds: %v", violations)
+		return
+	}
+
+	th := NoThresholds()
+	th.FileGrowth = 608
+	th.VMGrowth = 8192
+	th.FileGrowthPercent = 5
+	th.RowGrowth = 4096
+	if violations := diff.Exceeds(th); len(violations) != 0 {
+		t.Errorf("Violations of thresholds which are not exceeded: %v", violations)
+		return
+	}
+
+	th = Thresholds{FileGrowth: 607, VMGrowth: 4096, FileGrowthPercent: 3, RowGrowth: 200}
+	violations := diff.Exceeds(th)
+	if len(violations) != 4 ||
+		!strings.HasPrefix(violations[0], "The file size grew by 608 bytes") ||
+		!strings.HasPrefix(violations[1], "The VM size grew by ") ||
+		!strings.HasPrefix(violations[2], "The file size grew by 3.09%") ||
+		!strings.HasPrefix(violations[3], "'.bss' in sections grew by 0 bytes of file size and 4096") {
+		t.Errorf("Wrong violations: %q", violations)
+	}
+}
+
+func TestReportDemangle(t *testing.T) {
+	report := &Report{
+		Dimension: DimSymbols,
+		Rows: []Row{
+			{"_ZN1AC2Ev", 10, 10},
+			{"main", 20, 20},
+			{"_ZN1AC1Ev", 5, 5},
+			{"[section .text]", 1, 1},
+		},
+		FileSize: 36,
+		VMSize:   36,
+	}
+
+	report.Demangle()
+	expected := []Row{{"A::A()", 15, 15}, {"main", 20, 20}, {"[section .text]", 1, 1}}
+	if !reflect.DeepEqual(report.Rows, expected) || report.FileSize != 36 {
+		t.Errorf("Wrong demangled report: %v", report)
+	}
+}
+
+func TestDiffOutput(t *testing.T) {
+	diff, err := diffTestFiles(DimSymbols, true)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	err = diff.WriteTable(&buf, 3)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 || !strings.Contains(lines[1], "+4.0Ki  +100.0%  buffer") ||
+		!strings.HasSuffix(lines[4], " Others]") ||
+		!strings.HasPrefix(strings.TrimSpace(lines[5]), "+608    +3.1%") {
+		t.Errorf("Wrong table:\n%s", buf.String())
+		return
+	}
+
+	buf.Reset()
+	err = diff.WriteJSON(&buf)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	var decoded Diff
+	err = json.Unmarshal(buf.Bytes(), &decoded)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if !reflect.DeepEqual(&decoded, diff) {
+		t.Errorf("Wrong JSON diff:\n%s", buf.String())
+		return
+	}
+
+	if FormatDelta(-1536) != "-1.5Ki" || FormatDelta(0) != "0" || FormatDelta(12) != "+12" {
+		t.Errorf("Wrong formatted deltas.")
+	}
+}
@@ -0,0 +1,208 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package size
+
+import (
+	"fmt"
+	"path"
+)
+
+import (
+	"eureka/garf"
+	"eureka/golf"
+)
+
+// Returns the name of a compile unit, which is the name of its primary source
+// file.
+func unitName(index int, unitDIE *garf.DIE) string {
+	if name, ok := unitDIE.Attributes[garf.DW_AT_name].Value.(string); ok {
+		return name
+	}
+	return fmt.Sprintf("[unit %d]", index)
+}
+
+// Returns the DIEs of the compile units of the DWARF data, without their
+// children. The DIE of a unit which cannot be read, like one using forms which
+// are not supported, is nil, and the bytes of the unit are left unattributed.
+func compUnitDIEs(dwData *garf.DwData) ([]*garf.DIE, error) {
+	if dwData.ELFData().Header().Type() == golf.TypeRelocatable {
+		return nil, fmt.Errorf(
+			"The addresses of compile units are not known in relocatable files.")
+	}
+
+	compUnits, err := dwData.CompUnits()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading compile units.\n%s", err.Error())
+	}
+
+	dies := make([]*garf.DIE, len(compUnits))
+	for i, u := range compUnits {
+		die, err := u.UnitDIE()
+		if err == nil {
+			dies[i] = die
+		}
+	}
+
+	return dies, nil
+}
+
+// Adds the spans of the address ranges of the DIE, labeled with label.
+func dieSpans(elf *golf.ELF, die *garf.DIE, label string, sectSpans map[int][]span) error {
+	ranges, err := die.AddressRanges()
+	if err != nil {
+		return err
+	}
+
+	for _, r := range ranges {
+		addressSpans(elf, r.Low, r.High, label, false, sectSpans)
+	}
+
+	return nil
+}
+
+// Returns the report of the bytes of each compile unit, from the address
+// ranges given by the DW_AT_low_pc and DW_AT_high_pc, or DW_AT_ranges
+// attributes of the compile units. Compile units are named by their primary
+// source file. The symbols and sections are those of the ELF file of the
+// DWARF data, which could be a stripped file whose DWARF is in a separate
+// debug file.
+func CompUnits(dwData *garf.DwData) (*Report, error) {
+	dies, err := compUnitDIEs(dwData)
+	if err != nil {
+		return nil, err
+	}
+
+	elf := dwData.ELFData()
+	sectSpans := make(map[int][]span)
+	for i, die := range dies {
+		// The bytes of a unit whose address ranges cannot be read are left
+		// unattributed.
+		if die != nil {
+			dieSpans(elf, die, unitName(i, die), sectSpans)
+		}
+	}
+
+	return attributeSections(elf, DimCompUnits, sectSpans), nil
+}
+
+// Returns the path of the file declaring a DIE, from the file names of the
+// line number info of its unit. Only the line number info of DWARF 4 and
+// earlier has file names.
+func declFile(die *garf.DIE, lnInfo *garf.LnInfo) (string, bool) {
+	if lnInfo == nil || lnInfo.Version >= 5 {
+		return "", false
+	}
+
+	// The declaration of a function defined out of line, or the abstract
+	// instance of an inlined function, has the file.
+	for _, at := range []garf.DwAt{garf.DW_AT_specification, garf.DW_AT_abstract_origin} {
+		if _, exists := die.Attributes[garf.DW_AT_decl_file]; exists {
+			break
+		}
+		if ref, ok := die.Attributes[at].Value.(*garf.DIE); ok && ref != nil {
+			die = ref
+		}
+	}
+
+	index, ok := die.Attributes[garf.DW_AT_decl_file].Value.(uint32)
+	if !ok || index == 0 || int(index) > len(lnInfo.Files) {
+		return "", false
+	}
+
+	file := lnInfo.Files[index-1]
+	if path.IsAbs(file.Path) || file.DirIndex == 0 || int(file.DirIndex) > len(lnInfo.Directories) {
+		return file.Path, true
+	}
+	return path.Join(lnInfo.Directories[file.DirIndex-1], file.Path), true
+}
+
+// Adds the spans of the functions in the DIE tree, labeled with the files
+// declaring them.
+func functionSpans(
+	elf *golf.ELF, die *garf.DIE, lnInfo *garf.LnInfo, sectSpans map[int][]span) error {
+	if die.Tag == garf.DW_TAG_subprogram {
+		if file, ok := declFile(die, lnInfo); ok {
+			err := dieSpans(elf, die, file, sectSpans)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, child := range die.Children {
+		err := functionSpans(elf, child, lnInfo, sectSpans)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the report of the bytes of each source file. The bytes of each
+// function are attributed to the file declaring it, like a header file
+// defining an inline function, and the other bytes of a compile unit to its
+// primary source file. For units of DWARF 5, whose line number info is not
+// supported, all bytes are attributed to the primary source file.
+func SourceFiles(dwData *garf.DwData) (*Report, error) {
+	dies, err := compUnitDIEs(dwData)
+	if err != nil {
+		return nil, err
+	}
+
+	elf := dwData.ELFData()
+	sectSpans := make(map[int][]span)
+
+	// The spans of the functions come before those of the compile units, so
+	// that they take precedence.
+	for _, die := range dies {
+		if die == nil {
+			continue
+		}
+
+		// The bytes of the functions of units whose DIE trees cannot be
+		// read, like those with unsupported DWARF expressions, are
+		// attributed to their primary source files.
+		tree, err := die.Unit.DIETree()
+		if err != nil {
+			continue
+		}
+
+		// Units without line number info only have their primary source
+		// file.
+		lnInfo, err := die.Unit.LineNumberInfo()
+		if err != nil {
+			lnInfo = nil
+		}
+		funcSpans := make(map[int][]span)
+		if functionSpans(elf, tree, lnInfo, funcSpans) != nil {
+			continue
+		}
+		for i, spans := range funcSpans {
+			sectSpans[i] = append(sectSpans[i], spans...)
+		}
+	}
+	for i, die := range dies {
+		// The bytes of a unit whose address ranges cannot be read are left
+		// unattributed.
+		if die != nil {
+			dieSpans(elf, die, unitName(i, die), sectSpans)
+		}
+	}
+
+	return attributeSections(elf, DimSourceFiles, sectSpans), nil
+}
@@ -0,0 +1,268 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+// Package size attributes the bytes of ELF files to their sections, segments,
+// symbols, compile units and source files, like the bloaty tool. Each byte of
+// the file, and of the memory image of its loaded sections, is attributed to
+// exactly one row of a report, so that the rows add up to the size of the
+// file. Bytes which are not covered by any symbol or compile unit are
+// attributed to a row for their section, like '[section .text]'.
+package size
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+import (
+	"eureka/garf"
+	"eureka/golf"
+)
+
+// Dimension is the kind of the rows of a report.
+type Dimension string
+
+const (
+	DimSections    Dimension = "sections"
+	DimSegments    Dimension = "segments"
+	DimSymbols     Dimension = "symbols"
+	DimCompUnits   Dimension = "compileunits"
+	DimSourceFiles Dimension = "sourcefiles"
+)
+
+// The names of the rows for bytes which are not part of a section or segment.
+const (
+	// The ELF header, and the program and section header tables.
+	RowHeaders = "[ELF Headers]"
+
+	// Bytes of the file which are not part of any section or header, like
+	// padding. In a segments report, bytes which are not part of any
+	// loadable segment.
+	RowUnmapped = "[Unmapped]"
+)
+
+// Row is the number of bytes attributed to a name.
+type Row struct {
+	Name string `json:"name"`
+
+	// The number of bytes in the file.
+	FileSize uint64 `json:"file_size"`
+
+	// The number of bytes in the memory image of the file when loaded.
+	VMSize uint64 `json:"vm_size"`
+}
+
+// Report is the attribution of the bytes of an ELF file along a dimension.
+type Report struct {
+	FileName  string    `json:"file"`
+	Dimension Dimension `json:"dimension"`
+	Rows      []Row     `json:"rows"`
+
+	// The sums of the sizes of all the rows. The file size is the size of
+	// the file.
+	FileSize uint64 `json:"file_size"`
+	VMSize   uint64 `json:"vm_size"`
+}
+
+// SortKey is the order of the rows of a report.
+type SortKey string
+
+const (
+	// Largest file size first.
+	SortByFileSize SortKey = "file"
+
+	// Largest VM size first.
+	SortByVMSize SortKey = "vm"
+
+	// Largest of the file size and VM size first.
+	SortByBoth SortKey = "both"
+
+	// Alphabetical order of the names.
+	SortByName SortKey = "name"
+)
+
+// Sorts the rows of the report. Rows of the same size are sorted by name.
+func (report *Report) Sort(key SortKey) {
+	size := func(row Row) uint64 {
+		switch key {
+		case SortByFileSize:
+			return row.FileSize
+		case SortByVMSize:
+			return row.VMSize
+		case SortByBoth:
+			if row.FileSize > row.VMSize {
+				return row.FileSize
+			}
+			return row.VMSize
+		}
+		return 0
+	}
+
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		si, sj := size(report.Rows[i]), size(report.Rows[j])
+		if si != sj {
+			return si > sj
+		}
+		return report.Rows[i].Name < report.Rows[j].Name
+	})
+}
+
+// Returns the row with the name, or nil if the report does not have one.
+func (report *Report) Row(name string) *Row {
+	for i := range report.Rows {
+		if report.Rows[i].Name == name {
+			return &report.Rows[i]
+		}
+	}
+
+	return nil
+}
+
+// Returns the percentage of n in total.
+func percent(n, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// Returns the size in bytes in a human readable form, like '1.5Ki'.
+func FormatSize(n uint64) string {
+	units := []string{"", "Ki", "Mi", "Gi", "Ti"}
+	value := float64(n)
+	i := 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+
+	if i == 0 {
+		return fmt.Sprintf("%d", n)
+	}
+	return fmt.Sprintf("%.1f%s", value, units[i])
+}
+
+// Writes the rows of the report as a table, in their current order. If limit
+// is positive, only the first limit rows are written, and the others are
+// summed up in a row named like '[12 Others]'.
+func (report *Report) WriteTable(w io.Writer, limit int) error {
+	rows := report.Rows
+	if limit > 0 && len(rows) > limit {
+		others := Row{Name: fmt.Sprintf("[%d Others]", len(rows)-limit)}
+		for _, row := range rows[limit:] {
+			others.FileSize += row.FileSize
+			others.VMSize += row.VMSize
+		}
+		rows = append(rows[:limit:limit], others)
+	}
+
+	_, err := fmt.Fprintf(w, "%10s %6s %10s %6s  %s\n", "FILE SIZE", "", "VM SIZE", "", report.Dimension)
+	if err != nil {
+		return err
+	}
+	rows = append(rows, Row{"TOTAL", report.FileSize, report.VMSize})
+	for _, row := range rows {
+		_, err = fmt.Fprintf(w, "%10s %5.1f%% %10s %5.1f%%  %s\n",
+			FormatSize(row.FileSize), percent(row.FileSize, report.FileSize),
+			FormatSize(row.VMSize), percent(row.VMSize, report.VMSize), row.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Writes the report as JSON.
+func (report *Report) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// Returns the report of the ELF file whose path is fileName along the
+// dimension. The DWARF of compile units and source files is loaded from the
+// separate debug file of a stripped file if it is found.
+func Analyze(fileName string, dim Dimension) (*Report, error) {
+	var report *Report
+	var err error
+
+	switch dim {
+	case DimCompUnits, DimSourceFiles:
+		var dwData *garf.DwData
+		dwData, err = garf.LoadDwData(fileName)
+		if err != nil {
+			return nil, err
+		}
+		defer dwData.Close()
+
+		if dim == DimCompUnits {
+			report, err = CompUnits(dwData)
+		} else {
+			report, err = SourceFiles(dwData)
+		}
+	case DimSections, DimSegments, DimSymbols:
+		var elf *golf.ELF
+		elf, err = golf.Read(fileName)
+		if err != nil {
+			return nil, err
+		}
+		defer elf.Close()
+
+		switch dim {
+		case DimSections:
+			report, err = Sections(elf)
+		case DimSegments:
+			report, err = Segments(elf)
+		default:
+			report, err = Symbols(elf)
+		}
+	default:
+		return nil, fmt.Errorf("Unknown dimension '%s'.", dim)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Error analyzing the size of '%s'.\n%s", fileName, err.Error())
+	}
+
+	report.FileName = fileName
+	return report, nil
+}
+
+// Returns true if the DWARF of the compile unit and source file dimensions is
+// available for the ELF file whose path is fileName, either in the file or in
+// its separate debug file.
+func HasDebugInfo(fileName string) (bool, error) {
+	dwData, err := garf.LoadDwData(fileName)
+	if err != nil {
+		return false, err
+	}
+	defer dwData.Close()
+
+	// Debug sections compressed in the GNU format are named '.zdebug_*'.
+	sectMap := dwData.DebugELFData().SectMap()
+	_, exists := sectMap[".debug_info"]
+	if !exists {
+		_, exists = sectMap[".zdebug_info"]
+	}
+	return exists, nil
+}
@@ -0,0 +1,293 @@
+///////////////////////////////////////////////////////////////////////////
+// Copyright 2016 Siva Chandra
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+///////////////////////////////////////////////////////////////////////////
+
+package size
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// 'test_data/size_x86_64.exe' is built from 'main.c' and 'compute.c', which
+// both include 'table.h' defining a static function 'lookup' which is not
+// inlined.
+const sizeTestFile = "test_data/size_x86_64.exe"
+const sizeTestFileSize = 18608
+
+// The size of the ELF header, and of 13 program headers and 34 section
+// headers.
+const sizeTestHeaders = 64 + 13*56 + 34*64
+
+type expectedRow struct {
+	name     string
+	fileSize uint64
+	vmSize   uint64
+}
+
+func checkRows(t *testing.T, report *Report, rows []expectedRow) bool {
+	if report.FileSize != sizeTestFileSize {
+		t.Errorf("Wrong total file size of %s report: %d", report.Dimension, report.FileSize)
+		return false
+	}
+
+	var fileSize, vmSize uint64
+	for _, row := range report.Rows {
+		fileSize += row.FileSize
+		vmSize += row.VMSize
+	}
+	if fileSize != report.FileSize || vmSize != report.VMSize {
+		t.Errorf("The rows of %s report do not add up to the totals.", report.Dimension)
+		return false
+	}
+
+	for _, expected := range rows {
+		row := report.Row(expected.name)
+		if row == nil || row.FileSize != expected.fileSize || row.VMSize != expected.vmSize {
+			t.Errorf("Wrong row '%s' of %s report: %v", expected.name, report.Dimension, row)
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestSections(t *testing.T) {
+	report, err := Analyze(sizeTestFile, DimSections)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	checkRows(t, report, []expectedRow{
+		{".text", 0x178, 0x178},
+		{".bss", 0, 0x1028},
+		{".debug_info", 0x2be, 0},
+		{RowHeaders, sizeTestHeaders, 0},
+	})
+}
+
+func TestSegments(t *testing.T) {
+	report, err := Analyze(sizeTestFile, DimSegments)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	ok := checkRows(t, report, []expectedRow{
+		{"LOAD #0 [R]", 0x498, 0x498},
+		{"LOAD #1 [RX]", 0x1a1, 0x1a1},
+		{"LOAD #2 [R]", 0x16c, 0x16c},
+		{"LOAD #3 [RW]", 0x1d8, 0x1210},
+	})
+	if ok && len(report.Rows) != 5 {
+		t.Errorf("Wrong number of rows of segments report: %d", len(report.Rows))
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	report, err := Analyze(sizeTestFile, DimSymbols)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// The two copies of 'lookup' are reported as one row. The functions of
+	// the C runtime, '_start' and '_dl_relocate_static_pie', are in '.text'
+	// too, and the others do not have a size.
+	const textSymbolsSize = 61 + 57 + 28 + 34 + 1
+	checkRows(t, report, []expectedRow{
+		{"main", 61, 61},
+		{"compute", 57, 57},
+		{"lookup", 28, 28},
+		{"main_table", 32, 32},
+		{"buffer", 0, 4096},
+		{"counter", 0, 4},
+		{"[section .text]", 0x178 - textSymbolsSize, 0x178 - textSymbolsSize},
+		{"[section .symtab]", 0x3d8, 0},
+		{RowHeaders, sizeTestHeaders, 0},
+	})
+}
+
+func TestSymbolsRelocatable(t *testing.T) {
+	report, err := Analyze("../golf/test_data/linux_x86.o", DimSymbols)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if report.Row("exported_func") == nil || report.VMSize == 0 {
+		t.Errorf("Wrong symbols report of relocatable file: %v", report.Rows)
+	}
+}
+
+func TestCompUnits(t *testing.T) {
+	report, err := Analyze(sizeTestFile, DimCompUnits)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// Each unit has a copy of 'lookup'.
+	checkRows(t, report, []expectedRow{
+		{"main.c", 61 + 14, 61 + 14},
+		{"compute.c", 57 + 14, 57 + 14},
+		{"[section .text]", 0x178 - 75 - 71, 0x178 - 75 - 71},
+	})
+
+	_, err = Analyze("../golf/test_data/linux_x86.o", DimCompUnits)
+	if err == nil {
+		t.Errorf("Expected an error attributing a relocatable file to compile units.")
+	}
+}
+
+func TestSourceFiles(t *testing.T) {
+	report, err := Analyze(sizeTestFile, DimSourceFiles)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	checkRows(t, report, []expectedRow{
+		{"main.c", 61, 61},
+		{"compute.c", 57, 57},
+		{"table.h", 28, 28},
+	})
+}
+
+func TestUnsupportedDWARF(t *testing.T) {
+	// 'add.c' and 'main.c' have DWARF 5, and the DIE tree of 'add.c' cannot
+	// be read. The unit DIE of 'main.c' cannot be read, so its bytes are not
+	// attributed. See the garf tests for the units of the file.
+	fileName := "../garf/test_data/mixed_dwarf_linux_x86_64.exe"
+	for _, dim := range []Dimension{DimCompUnits, DimSourceFiles} {
+		report, err := Analyze(fileName, dim)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+
+		expected := []expectedRow{
+			{"tls.c", 20, 20},
+			{"add.c", 4, 4},
+			{"[section .text]", 0x121 - 24, 0x121 - 24},
+		}
+		for _, e := range expected {
+			row := report.Row(e.name)
+			if row == nil || row.FileSize != e.fileSize || row.VMSize != e.vmSize {
+				t.Errorf("Wrong row '%s' of %s report: %v", e.name, dim, row)
+				return
+			}
+		}
+		if report.Row("main.c") != nil {
+			t.Errorf("Unexpected row 'main.c' in %s report.", dim)
+			return
+		}
+	}
+}
+
+func TestHasDebugInfo(t *testing.T) {
+	expected := map[string]bool{
+		sizeTestFile:                             true,
+		"../golf/test_data/linux_x86_64_cet.exe": false,
+
+		// The debug info is in '.zdebug_info'.
+		"../garf/test_data/single_cu_linux_x86_64_zlib_gnu.exe": true,
+	}
+	for fileName, debugInfo := range expected {
+		exists, err := HasDebugInfo(fileName)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if exists != debugInfo {
+			t.Errorf("Wrong debug info of '%s': %v", fileName, exists)
+		}
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	// Overlapping bytes are attributed to the first span, and spans are
+	// clipped to the size.
+	spans := []span{{4, 8, "a"}, {0, 6, "b"}, {7, 20, "c"}, {12, 14, "a"}}
+	attributed, uncovered := attribute(spans, 16)
+	if attributed["a"] != 4 || attributed["b"] != 4 || attributed["c"] != 8 || uncovered != 0 {
+		t.Errorf("Wrong attribution of overlapping spans: %v %d", attributed, uncovered)
+		return
+	}
+
+	attributed, uncovered = attribute([]span{{2, 4, "a"}, {8, 9, "b"}}, 10)
+	if attributed["a"] != 2 || attributed["b"] != 1 || uncovered != 7 {
+		t.Errorf("Wrong attribution of disjoint spans: %v %d", attributed, uncovered)
+	}
+}
+
+func TestOutput(t *testing.T) {
+	report, err := Analyze(sizeTestFile, DimSymbols)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	report.Sort(SortByVMSize)
+	if report.Rows[0].Name != "buffer" {
+		t.Errorf("Wrong first row when sorted by VM size: %s", report.Rows[0].Name)
+		return
+	}
+	report.Sort(SortByFileSize)
+	if report.Rows[0].Name != RowUnmapped {
+		t.Errorf("Wrong first row when sorted by file size: %s", report.Rows[0].Name)
+		return
+	}
+	report.Sort(SortByName)
+	if report.Rows[0].Name != RowHeaders {
+		t.Errorf("Wrong first row when sorted by name: %s", report.Rows[0].Name)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = report.WriteTable(&buf, 3)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	others := lines[len(lines)-2]
+	total := lines[len(lines)-1]
+	if len(lines) != 6 || !strings.HasSuffix(others, " Others]") ||
+		!strings.HasPrefix(strings.TrimSpace(total), "18.2Ki 100.0%") {
+		t.Errorf("Wrong table:\n%s", buf.String())
+		return
+	}
+
+	buf.Reset()
+	err = report.WriteJSON(&buf)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	var decoded Report
+	err = json.Unmarshal(buf.Bytes(), &decoded)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if decoded.FileSize != sizeTestFileSize || decoded.Dimension != DimSymbols ||
+		len(decoded.Rows) != len(report.Rows) || decoded.FileName != sizeTestFile {
+		t.Errorf("Wrong JSON report:\n%s", buf.String())
+	}
+}